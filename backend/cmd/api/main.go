@@ -10,7 +10,11 @@
 package main
 
 import (
+	"flag"
+	"os"
+
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
 	"github.com/redpanda-data/console/backend/pkg/api"
 	"github.com/redpanda-data/console/backend/pkg/config"
@@ -28,6 +32,30 @@ func main() {
 		startupLogger.Fatal("failed to validate config", zap.Error(err))
 	}
 
+	// Arguments that remain after parsing all flags are treated as subcommand
+	if args := flag.Args(); len(args) > 0 {
+		commandLogger := newCommandLogger()
+		if err := runCommand(&cfg, commandLogger, args); err != nil {
+			commandLogger.Fatal("failed to run command", zap.Error(err))
+		}
+		return
+	}
+
 	a := api.New(&cfg)
 	a.Start()
 }
+
+// newCommandLogger returns a logger like zap.NewExample, but writing to stderr, so that
+// subcommands can write their output (e.g. a schema registry backup) to stdout.
+func newCommandLogger() *zap.Logger {
+	encoderCfg := zapcore.EncoderConfig{
+		MessageKey:     "msg",
+		LevelKey:       "level",
+		NameKey:        "logger",
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeDuration: zapcore.StringDurationEncoder,
+	}
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderCfg), zapcore.Lock(os.Stderr), zap.DebugLevel)
+	return zap.New(core)
+}
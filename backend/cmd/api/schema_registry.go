@@ -0,0 +1,127 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"go.uber.org/zap"
+
+	"github.com/redpanda-data/console/backend/pkg/config"
+	"github.com/redpanda-data/console/backend/pkg/schema"
+)
+
+// runCommand executes a subcommand instead of starting the HTTP server. Subcommands
+// are passed after all config flags, e.g.: console -config.filepath=console.yaml schema-registry export
+func runCommand(cfg *config.Config, logger *zap.Logger, args []string) error {
+	switch args[0] {
+	case "schema-registry":
+		return runSchemaRegistryCommand(cfg, logger, args[1:])
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+func runSchemaRegistryCommand(cfg *config.Config, logger *zap.Logger, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing schema-registry subcommand, must be one of: export, import")
+	}
+	if !cfg.Kafka.Schema.Enabled {
+		return fmt.Errorf("schema registry is not configured")
+	}
+
+	svc, err := schema.NewService(cfg.Kafka.Schema, logger)
+	if err != nil {
+		return fmt.Errorf("failed to create schema service: %w", err)
+	}
+
+	switch args[0] {
+	case "export":
+		fs := flag.NewFlagSet("schema-registry export", flag.ExitOnError)
+		output := fs.String("output", "", "File to write the backup to. Defaults to stdout")
+		_ = fs.Parse(args[1:]) // Errors are handled by flag.ExitOnError
+
+		backup, err := svc.ExportBackup()
+		if err != nil {
+			return err
+		}
+		var w io.Writer = os.Stdout
+		if *output != "" {
+			f, err := os.Create(*output)
+			if err != nil {
+				return fmt.Errorf("failed to create output file: %w", err)
+			}
+			defer f.Close()
+			w = f
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(backup); err != nil {
+			return fmt.Errorf("failed to write backup: %w", err)
+		}
+
+		versionCount := 0
+		for _, subject := range backup.Subjects {
+			versionCount += len(subject.Versions)
+		}
+		logger.Info("exported schema registry",
+			zap.Int("subject_count", len(backup.Subjects)),
+			zap.Int("schema_version_count", versionCount))
+		return nil
+
+	case "import":
+		fs := flag.NewFlagSet("schema-registry import", flag.ExitOnError)
+		input := fs.String("input", "", "File to read the backup from")
+		dryRun := fs.Bool("dry-run", false, "Only report what would be imported including all conflicts")
+		preserveIDs := fs.Bool("preserve-ids", true, "Register schemas with their original IDs and versions using the IMPORT mode")
+		allowNonEmpty := fs.Bool("allow-non-empty", false, "Force the IMPORT mode even if the target registry already contains schemas")
+		applyMode := fs.Bool("apply-mode", false, "Set the global mode of the backup after the import instead of keeping the current mode")
+		_ = fs.Parse(args[1:]) // Errors are handled by flag.ExitOnError
+		if *input == "" {
+			return fmt.Errorf("input must be set")
+		}
+
+		f, err := os.Open(*input)
+		if err != nil {
+			return fmt.Errorf("failed to open input file: %w", err)
+		}
+		defer f.Close()
+		var backup schema.Backup
+		if err := json.NewDecoder(f).Decode(&backup); err != nil {
+			return fmt.Errorf("failed to decode backup: %w", err)
+		}
+
+		report, err := svc.ImportBackup(&backup, schema.ImportOptions{
+			DryRun:          *dryRun,
+			PreserveIDs:     *preserveIDs,
+			AllowNonEmpty:   *allowNonEmpty,
+			ApplyGlobalMode: *applyMode,
+		})
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return fmt.Errorf("failed to write import report: %w", err)
+		}
+		if report.ErrorCount > 0 {
+			return fmt.Errorf("import finished with %d errors", report.ErrorCount)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown schema-registry subcommand %q, must be one of: export, import", args[0])
+	}
+}
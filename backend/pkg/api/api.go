@@ -19,12 +19,14 @@ import (
 
 	"github.com/cloudhut/common/logging"
 	"github.com/cloudhut/common/rest"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 
 	"github.com/redpanda-data/console/backend/pkg/config"
 	"github.com/redpanda-data/console/backend/pkg/connect"
 	"github.com/redpanda-data/console/backend/pkg/console"
 	"github.com/redpanda-data/console/backend/pkg/embed"
+	"github.com/redpanda-data/console/backend/pkg/exporter"
 	"github.com/redpanda-data/console/backend/pkg/git"
 	"github.com/redpanda-data/console/backend/pkg/redpanda"
 	"github.com/redpanda-data/console/backend/pkg/version"
@@ -83,6 +85,17 @@ func New(cfg *config.Config, opts ...Option) *API {
 		}
 	}
 
+	// The exporter publishes its metrics on the default registry that is served via /admin/metrics
+	if consoleSvc != nil && cfg.Console.Exporter.Enabled {
+		collector, err := exporter.NewCollector(cfg.Console.Exporter, cfg.MetricsNamespace, consoleSvc, logger)
+		if err != nil {
+			logger.Fatal("failed to create prometheus exporter", zap.Error(err))
+		}
+		if err := prometheus.Register(collector); err != nil {
+			logger.Fatal("failed to register prometheus exporter", zap.Error(err))
+		}
+	}
+
 	// Use default frontend resources from embeds. They may be overridden via functional options.
 	// We don't use hooks here because we may want to use the API struct without providing all hooks.
 	fsys, err := fs.Sub(embed.FrontendFiles, "frontend")
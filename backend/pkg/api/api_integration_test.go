@@ -291,6 +291,29 @@ func (a *assertHooks) CanDeleteTopicRecords(_ context.Context, topic string) (bo
 	return rv.BoolValue, rv.Err
 }
 
+func (a *assertHooks) CanCreateTopicPartitions(_ context.Context, topic string) (bool, *rest.Error) {
+	if !a.isCallAllowed(topic) {
+		assertHookCall(a.t)
+	}
+	rv := a.getCallReturnValue(topic)
+	return rv.BoolValue, rv.Err
+}
+
+func (a *assertHooks) CanElectLeaders(_ context.Context, topic string) (bool, *rest.Error) {
+	if !a.isCallAllowed(topic) {
+		assertHookCall(a.t)
+	}
+	rv := a.getCallReturnValue(topic)
+	return rv.BoolValue, rv.Err
+}
+func (a *assertHooks) CanElectUncleanLeaders(_ context.Context, topic string) (bool, *rest.Error) {
+	if !a.isCallAllowed(topic) {
+		assertHookCall(a.t)
+	}
+	rv := a.getCallReturnValue(topic)
+	return rv.BoolValue, rv.Err
+}
+
 func (a *assertHooks) CanViewTopicPartitions(_ context.Context, topic string) (bool, *rest.Error) {
 	if !a.isCallAllowed(topic) {
 		assertHookCall(a.t)
@@ -377,6 +400,22 @@ func (a *assertHooks) CanListQuotas(_ context.Context) (bool, *rest.Error) {
 	return rv.BoolValue, rv.Err
 }
 
+func (a *assertHooks) CanAlterQuotas(_ context.Context, _ []console.QuotaEntityComponent) (bool, *rest.Error) {
+	if !a.isCallAllowed("any") {
+		assertHookCall(a.t)
+	}
+	rv := a.getCallReturnValue("any")
+	return rv.BoolValue, rv.Err
+}
+
+func (a *assertHooks) CanDeleteQuotas(_ context.Context, _ []console.QuotaEntityComponent) (bool, *rest.Error) {
+	if !a.isCallAllowed("any") {
+		assertHookCall(a.t)
+	}
+	rv := a.getCallReturnValue("any")
+	return rv.BoolValue, rv.Err
+}
+
 func (a *assertHooks) CanSeeConsumerGroup(_ context.Context, topic string) (bool, *rest.Error) {
 	if !a.isCallAllowed(topic) {
 		assertHookCall(a.t)
@@ -401,6 +440,22 @@ func (a *assertHooks) CanDeleteConsumerGroup(_ context.Context, topic string) (b
 	return rv.BoolValue, rv.Err
 }
 
+func (a *assertHooks) CanImportSchemaRegistry(_ context.Context) (bool, *rest.Error) {
+	if !a.isCallAllowed("any") {
+		assertHookCall(a.t)
+	}
+	rv := a.getCallReturnValue("any")
+	return rv.BoolValue, rv.Err
+}
+
+func (a *assertHooks) CanRegisterSchema(_ context.Context, subject string) (bool, *rest.Error) {
+	if !a.isCallAllowed(subject) {
+		assertHookCall(a.t)
+	}
+	rv := a.getCallReturnValue(subject)
+	return rv.BoolValue, rv.Err
+}
+
 func (a *assertHooks) AllowedConsumerGroupActions(_ context.Context, _ string) ([]string, *rest.Error) {
 	if !a.isCallAllowed("any") {
 		assertHookCall(a.t)
@@ -417,6 +472,78 @@ func (a *assertHooks) CanPatchPartitionReassignments(_ context.Context) (bool, *
 	return rv.BoolValue, rv.Err
 }
 
+func (a *assertHooks) CanViewReplicaLogDirs(_ context.Context, _ int32) (bool, *rest.Error) {
+	if !a.isCallAllowed("any") {
+		assertHookCall(a.t)
+	}
+	rv := a.getCallReturnValue("any")
+	return rv.BoolValue, rv.Err
+}
+
+func (a *assertHooks) CanAlterReplicaLogDirs(_ context.Context, _ int32) (bool, *rest.Error) {
+	if !a.isCallAllowed("any") {
+		assertHookCall(a.t)
+	}
+	rv := a.getCallReturnValue("any")
+	return rv.BoolValue, rv.Err
+}
+
+func (a *assertHooks) CanListTransactions(_ context.Context) (bool, *rest.Error) {
+	if !a.isCallAllowed("any") {
+		assertHookCall(a.t)
+	}
+	rv := a.getCallReturnValue("any")
+	return rv.BoolValue, rv.Err
+}
+
+func (a *assertHooks) CanViewClusterHealth(_ context.Context) (bool, *rest.Error) {
+	if !a.isCallAllowed("any") {
+		assertHookCall(a.t)
+	}
+	rv := a.getCallReturnValue("any")
+	return rv.BoolValue, rv.Err
+}
+
+func (a *assertHooks) CanViewTopicState(_ context.Context) (bool, *rest.Error) {
+	if !a.isCallAllowed("any") {
+		assertHookCall(a.t)
+	}
+	rv := a.getCallReturnValue("any")
+	return rv.BoolValue, rv.Err
+}
+
+func (a *assertHooks) CanApplyTopicState(_ context.Context) (bool, *rest.Error) {
+	if !a.isCallAllowed("any") {
+		assertHookCall(a.t)
+	}
+	rv := a.getCallReturnValue("any")
+	return rv.BoolValue, rv.Err
+}
+
+func (a *assertHooks) CanExportClusterSnapshot(_ context.Context) (bool, *rest.Error) {
+	if !a.isCallAllowed("any") {
+		assertHookCall(a.t)
+	}
+	rv := a.getCallReturnValue("any")
+	return rv.BoolValue, rv.Err
+}
+
+func (a *assertHooks) CanImportClusterSnapshot(_ context.Context) (bool, *rest.Error) {
+	if !a.isCallAllowed("any") {
+		assertHookCall(a.t)
+	}
+	rv := a.getCallReturnValue("any")
+	return rv.BoolValue, rv.Err
+}
+
+func (a *assertHooks) CanAbortTransaction(_ context.Context, topicName string) (bool, *rest.Error) {
+	if !a.isCallAllowed(topicName) {
+		assertHookCall(a.t)
+	}
+	rv := a.getCallReturnValue(topicName)
+	return rv.BoolValue, rv.Err
+}
+
 func (a *assertHooks) CanPatchConfigs(_ context.Context) (bool, *rest.Error) {
 	if !a.isCallAllowed("any") {
 		assertHookCall(a.t)
@@ -518,3 +645,10 @@ func (a *assertHooks) EnabledConnectClusterFeatures(_ context.Context, _ string)
 	}
 	return nil
 }
+
+func (a *assertHooks) Requester(_ context.Context) string {
+	if !a.isCallAllowed("any") {
+		assertHookCall(a.t)
+	}
+	return ""
+}
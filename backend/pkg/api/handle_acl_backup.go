@@ -0,0 +1,217 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/cloudhut/common/rest"
+	"gopkg.in/yaml.v3"
+
+	"github.com/redpanda-data/console/backend/pkg/console"
+)
+
+// maxACLExportBytes is the maximum size of an ACL export that can be imported via the API.
+const maxACLExportBytes = 10 * 1024 * 1024
+
+func (api *API) handleExportACLs() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		format := rest.GetQueryParam(r, "format")
+		if format == "" {
+			format = "json"
+		}
+		if format != "json" && format != "yaml" {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      fmt.Errorf("unsupported export format %q", format),
+				Status:   http.StatusBadRequest,
+				Message:  "Export format must be either json or yaml",
+				IsSilent: true,
+			})
+			return
+		}
+
+		// 2. Check if logged in user is allowed to list ACLs
+		isAllowed, restErr := api.Hooks.Authorization.CanListACLs(r.Context())
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !isAllowed {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      fmt.Errorf("requester is not allowed to list ACLs"),
+				Status:   http.StatusForbidden,
+				Message:  "You are not allowed to list ACLs",
+				IsSilent: true,
+			})
+			return
+		}
+
+		// 3. Export ACLs without the ones of protected principals
+		export, restErr := api.ConsoleSvc.ExportACLs(r.Context())
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		filtered := make([]console.ACLBinding, 0, len(export.ACLs))
+		for _, binding := range export.ACLs {
+			if api.Hooks.Authorization.IsProtectedKafkaUser(binding.Principal) {
+				continue
+			}
+			filtered = append(filtered, binding)
+		}
+		export.ACLs = filtered
+
+		filename := fmt.Sprintf("acls-%s.%s", export.CreatedAt.Format("20060102-150405"), format)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		if format == "json" {
+			rest.SendResponse(w, r, api.Logger, http.StatusOK, export)
+			return
+		}
+
+		out, err := yaml.Marshal(export)
+		if err != nil {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      fmt.Errorf("failed to encode ACL export as yaml: %w", err),
+				Status:   http.StatusInternalServerError,
+				Message:  fmt.Sprintf("Failed to encode ACL export as yaml: %v", err.Error()),
+				IsSilent: false,
+			})
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml")
+		w.WriteHeader(http.StatusOK)
+		if _, err := w.Write(out); err != nil {
+			api.Logger.Error("failed to write ACL export")
+		}
+	}
+}
+
+// authorizeACLChanges checks whether the logged in user is allowed to create (and optionally delete) ACLs
+// for all given bindings. A REST error is sent if not.
+func (api *API) authorizeACLChanges(w http.ResponseWriter, r *http.Request, bindings []console.ACLBinding, withDelete bool) bool {
+	isAllowed, restErr := api.Hooks.Authorization.CanCreateACL(r.Context())
+	if restErr != nil {
+		rest.SendRESTError(w, r, api.Logger, restErr)
+		return false
+	}
+	if isAllowed && withDelete {
+		isAllowed, restErr = api.Hooks.Authorization.CanDeleteACL(r.Context())
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return false
+		}
+	}
+	if !isAllowed {
+		rest.SendRESTError(w, r, api.Logger, &rest.Error{
+			Err:      fmt.Errorf("requester is not allowed to change ACLs"),
+			Status:   http.StatusForbidden,
+			Message:  "You are not allowed to change ACLs",
+			IsSilent: true,
+		})
+		return false
+	}
+
+	for _, binding := range bindings {
+		if api.Hooks.Authorization.IsProtectedKafkaUser(binding.Principal) {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      fmt.Errorf("requester targets a protected Kafka principal to change ACLs"),
+				Status:   http.StatusForbidden,
+				Message:  fmt.Sprintf("You are not allowed to change ACLs for the protected principal '%v'", binding.Principal),
+				IsSilent: false,
+			})
+			return false
+		}
+	}
+
+	return true
+}
+
+func (api *API) handleImportACLs() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request. YAML is a superset of JSON, so both export formats are accepted.
+		validateOnly := rest.GetQueryParam(r, "validateOnly") == "true"
+		var export console.ACLExport
+		r.Body = http.MaxBytesReader(w, r.Body, maxACLExportBytes)
+		if err := yaml.NewDecoder(r.Body).Decode(&export); err != nil {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      err,
+				Status:   http.StatusBadRequest,
+				Message:  fmt.Sprintf("Failed to decode ACL export: %v", err.Error()),
+				IsSilent: false,
+			})
+			return
+		}
+		if err := export.Validate(); err != nil {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      err,
+				Status:   http.StatusBadRequest,
+				Message:  fmt.Sprintf("Validating the ACL export failed: %v", err.Error()),
+				IsSilent: false,
+			})
+			return
+		}
+
+		// 2. Check if logged in user is allowed to create and delete ACLs
+		if !api.authorizeACLChanges(w, r, export.ACLs, true) {
+			return
+		}
+
+		// 3. Import ACLs
+		res, restErr := api.ConsoleSvc.ImportACLs(r.Context(), &export, console.ACLImportOptions{
+			ValidateOnly:         validateOnly,
+			IsProtectedPrincipal: api.Hooks.Authorization.IsProtectedKafkaUser,
+		})
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
+	}
+}
+
+type applyACLTemplateRequest struct {
+	console.ACLTemplate
+	ValidateOnly bool `json:"validateOnly"`
+}
+
+// OK validates the user input for the apply ACL template request.
+func (a *applyACLTemplateRequest) OK() error {
+	return a.ACLTemplate.Validate()
+}
+
+func (api *API) handleApplyACLTemplate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var req applyACLTemplateRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		bindings := req.Expand()
+
+		// 2. Check if logged in user is allowed to create ACLs
+		if !api.authorizeACLChanges(w, r, bindings, false) {
+			return
+		}
+
+		// 3. Create missing ACLs
+		res, restErr := api.ConsoleSvc.CreateACLBindings(r.Context(), bindings, req.ValidateOnly)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
+	}
+}
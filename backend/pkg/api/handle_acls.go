@@ -12,6 +12,7 @@ package api
 import (
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/cloudhut/common/rest"
 	"github.com/gorilla/schema"
@@ -334,3 +335,171 @@ func (api *API) handleCreateACL() http.HandlerFunc {
 		rest.SendResponse(w, r, api.Logger, http.StatusOK, nil)
 	}
 }
+
+// authorizeACLPermissionsQuery checks whether the logged in user is allowed to list ACLs and whether the
+// queried principal is not protected. A REST error is sent if not.
+func (api *API) authorizeACLPermissionsQuery(w http.ResponseWriter, r *http.Request, principal string) bool {
+	isAllowed, restErr := api.Hooks.Authorization.CanListACLs(r.Context())
+	if restErr != nil {
+		rest.SendRESTError(w, r, api.Logger, restErr)
+		return false
+	}
+	if !isAllowed {
+		rest.SendRESTError(w, r, api.Logger, &rest.Error{
+			Err:      fmt.Errorf("requester is not allowed to list ACLs"),
+			Status:   http.StatusForbidden,
+			Message:  "You are not allowed to list ACLs",
+			IsSilent: true,
+		})
+		return false
+	}
+
+	if api.Hooks.Authorization.IsProtectedKafkaUser(principal) {
+		rest.SendRESTError(w, r, api.Logger, &rest.Error{
+			Err:      fmt.Errorf("requester targets a protected Kafka principal to compute permissions"),
+			Status:   http.StatusForbidden,
+			Message:  "You are not allowed to view permissions of this protected principal",
+			IsSilent: false,
+		})
+		return false
+	}
+
+	return true
+}
+
+type getEffectiveACLPermissionsRequest struct {
+	// Principal whose permissions shall be computed, e.g. "User:alice".
+	Principal string `schema:"principal"`
+
+	// Host the principal connects from. If empty, only ACLs for all hosts apply.
+	Host string `schema:"host"`
+}
+
+// OK validates the user input for the effective permissions request.
+func (g *getEffectiveACLPermissionsRequest) OK() error {
+	if !strings.Contains(g.Principal, ":") {
+		return fmt.Errorf("principal must be set in the format <type>:<name>, e.g. User:alice")
+	}
+	return nil
+}
+
+func (api *API) handleGetEffectiveACLPermissions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request from url parameters
+		req := &getEffectiveACLPermissionsRequest{}
+		err := schema.NewDecoder().Decode(req, r.URL.Query())
+		if err == nil {
+			err = req.OK()
+		}
+		if err != nil {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      err,
+				Status:   http.StatusBadRequest,
+				Message:  fmt.Sprintf("Failed to parse request parameters: %v", err.Error()),
+				IsSilent: false,
+			})
+			return
+		}
+
+		// 2. Check if logged in user is allowed to list ACLs
+		if !api.authorizeACLPermissionsQuery(w, r, req.Principal) {
+			return
+		}
+
+		// 3. Compute permissions
+		permissions, restErr := api.ConsoleSvc.GetEffectiveACLPermissions(r.Context(), req.Principal, req.Host)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, permissions)
+	}
+}
+
+type explainACLRequest struct {
+	// Principal that wants to run the operation, e.g. "User:alice".
+	Principal string `schema:"principal"`
+
+	// Host the principal connects from. If empty, only ACLs for all hosts apply.
+	Host string `schema:"host"`
+
+	// ResourceType of the resource the operation is run on, e.g. TOPIC.
+	ResourceType kmsg.ACLResourceType `schema:"resourceType"`
+
+	// ResourceName of the resource. For CLUSTER it defaults to "kafka-cluster".
+	ResourceName string `schema:"resourceName"`
+
+	// Operation that shall be authorized, e.g. READ.
+	Operation kmsg.ACLOperation `schema:"operation"`
+
+	// AllowEveryoneIfNoACLFound should match the broker config allow.everyone.if.no.acl.found.
+	AllowEveryoneIfNoACLFound bool `schema:"allowEveryoneIfNoAclFound"`
+}
+
+// OK validates the user input for the explain request.
+func (e *explainACLRequest) OK() error {
+	if !strings.Contains(e.Principal, ":") {
+		return fmt.Errorf("principal must be set in the format <type>:<name>, e.g. User:alice")
+	}
+
+	switch e.ResourceType {
+	case kmsg.ACLResourceTypeUnknown, kmsg.ACLResourceTypeAny:
+		return fmt.Errorf("resource type must be a specific resource type, but found: %q", e.ResourceType)
+	case kmsg.ACLResourceTypeCluster:
+		if e.ResourceName == "" {
+			e.ResourceName = "kafka-cluster"
+		}
+	}
+	if e.ResourceName == "" {
+		return fmt.Errorf("resource name must be set")
+	}
+
+	switch e.Operation {
+	case kmsg.ACLOperationUnknown, kmsg.ACLOperationAny, kmsg.ACLOperationAll:
+		return fmt.Errorf("operation must be a specific operation, but found: %q", e.Operation)
+	}
+
+	return nil
+}
+
+func (api *API) handleExplainACL() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request from url parameters
+		req := &explainACLRequest{}
+		err := schema.NewDecoder().Decode(req, r.URL.Query())
+		if err == nil {
+			err = req.OK()
+		}
+		if err != nil {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      err,
+				Status:   http.StatusBadRequest,
+				Message:  fmt.Sprintf("Failed to parse request parameters: %v", err.Error()),
+				IsSilent: false,
+			})
+			return
+		}
+
+		// 2. Check if logged in user is allowed to list ACLs
+		if !api.authorizeACLPermissionsQuery(w, r, req.Principal) {
+			return
+		}
+
+		// 3. Explain decision
+		res, restErr := api.ConsoleSvc.ExplainACL(r.Context(), console.ExplainACLRequest{
+			Principal:                 req.Principal,
+			Host:                      req.Host,
+			ResourceType:              req.ResourceType,
+			ResourceName:              req.ResourceName,
+			Operation:                 req.Operation,
+			AllowEveryoneIfNoACLFound: req.AllowEveryoneIfNoACLFound,
+		})
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
+	}
+}
@@ -79,3 +79,101 @@ func (api *API) handleBrokerConfig() http.HandlerFunc {
 		rest.SendResponse(w, r, api.Logger, http.StatusOK, response)
 	}
 }
+
+// parseBrokerIDParam parses the broker id URL parameter. A REST error is sent if it is invalid.
+func (api *API) parseBrokerIDParam(w http.ResponseWriter, r *http.Request) (int32, bool) {
+	brokerID, err := strconv.ParseInt(rest.GetURLParam(r, "brokerID"), 10, 32)
+	if err != nil {
+		rest.SendRESTError(w, r, api.Logger, &rest.Error{
+			Err:      fmt.Errorf("failed to parse broker id: %w", err),
+			Status:   http.StatusBadRequest,
+			Message:  "Broker ID must be a valid int32",
+			IsSilent: true,
+		})
+		return 0, false
+	}
+	return int32(brokerID), true
+}
+
+func (api *API) handleGetDynamicBrokerConfig(isClusterDefault bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var brokerID *int32
+		if !isClusterDefault {
+			id, ok := api.parseBrokerIDParam(w, r)
+			if !ok {
+				return
+			}
+			brokerID = &id
+		}
+
+		cfg, restErr := api.ConsoleSvc.GetDynamicBrokerConfig(r.Context(), brokerID)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, cfg)
+	}
+}
+
+type patchDynamicBrokerConfigRequest struct {
+	Configs      []console.BrokerConfigChange `json:"configs"`
+	ValidateOnly bool                         `json:"validateOnly"`
+}
+
+func (p *patchDynamicBrokerConfigRequest) OK() error {
+	if len(p.Configs) == 0 {
+		return fmt.Errorf("at least one config must be set")
+	}
+	for i, cfg := range p.Configs {
+		if cfg.Name == "" {
+			return fmt.Errorf("config name must be specified for config with index '%d'", i)
+		}
+	}
+	return nil
+}
+
+func (api *API) handlePatchDynamicBrokerConfig(isClusterDefault bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var brokerID *int32
+		if !isClusterDefault {
+			id, ok := api.parseBrokerIDParam(w, r)
+			if !ok {
+				return
+			}
+			brokerID = &id
+		}
+		var req patchDynamicBrokerConfigRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Check if logged in user is allowed to alter configs
+		isAllowed, restErr := api.Hooks.Authorization.CanPatchConfigs(r.Context())
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !isAllowed {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      fmt.Errorf("requester has no permissions to alter broker configs"),
+				Status:   http.StatusForbidden,
+				Message:  "You don't have permissions to alter broker configs",
+				IsSilent: false,
+			})
+			return
+		}
+
+		// 3. Validate and alter configs
+		res, restErr := api.ConsoleSvc.AlterDynamicBrokerConfig(r.Context(), brokerID, req.Configs, req.ValidateOnly)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/cloudhut/common/rest"
+
+	"github.com/redpanda-data/console/backend/pkg/console"
+)
+
+func (api *API) handleGetClusterHealth() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Check if logged in user is allowed to view the cluster health
+		isAllowed, restErr := api.Hooks.Authorization.CanViewClusterHealth(r.Context())
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !isAllowed {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      fmt.Errorf("requester has no permissions to view the cluster health"),
+				Status:   http.StatusForbidden,
+				Message:  "You don't have permissions to view the cluster health",
+				IsSilent: false,
+			})
+			return
+		}
+
+		// 2. Evaluate health rules
+		health, restErr := api.ConsoleSvc.GetClusterHealth(r.Context())
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 3. Only include affected topics and consumer groups the user is allowed to see. Findings
+		// without any visible resources are omitted.
+		restErr = health.FilterResources(func(resource console.HealthFindingResource) (bool, *rest.Error) {
+			return api.canSeeHealthFindingResource(r, resource)
+		})
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, health)
+	}
+}
+
+func (api *API) canSeeHealthFindingResource(r *http.Request, resource console.HealthFindingResource) (bool, *rest.Error) {
+	switch resource.Type {
+	case console.HealthResourceTypeTopic, console.HealthResourceTypePartition:
+		return api.Hooks.Authorization.CanSeeTopic(r.Context(), resource.Name)
+	case console.HealthResourceTypeConsumerGroup:
+		return api.Hooks.Authorization.CanSeeConsumerGroup(r.Context(), resource.Name)
+	default:
+		return true, nil
+	}
+}
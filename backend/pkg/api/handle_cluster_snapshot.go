@@ -0,0 +1,152 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cloudhut/common/rest"
+
+	"github.com/redpanda-data/console/backend/pkg/console"
+)
+
+type importClusterSnapshotRequest struct {
+	Snapshot          *console.ClusterSnapshot `json:"snapshot"`
+	Scopes            []string                 `json:"scopes"`
+	DryRun            bool                     `json:"dryRun"`
+	PreserveSchemaIDs bool                     `json:"preserveSchemaIds"`
+}
+
+// OK validates the user input for the import cluster snapshot request.
+func (i *importClusterSnapshotRequest) OK() error {
+	if i.Snapshot == nil {
+		return fmt.Errorf("snapshot must be set")
+	}
+	if _, err := console.ParseClusterSnapshotScopes(i.Scopes); err != nil {
+		return err
+	}
+
+	return i.Snapshot.Validate()
+}
+
+func (api *API) handleExportClusterSnapshot() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var requestedScopes []string
+		if scopes := rest.GetQueryParam(r, "scopes"); scopes != "" {
+			requestedScopes = strings.Split(scopes, ",")
+		}
+		includeSecrets := rest.GetQueryParam(r, "includeSecrets") == "true"
+		scopes, err := console.ParseClusterSnapshotScopes(requestedScopes)
+		if err != nil {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      err,
+				Status:   http.StatusBadRequest,
+				Message:  err.Error(),
+				IsSilent: false,
+			})
+			return
+		}
+
+		// 2. Check if logged in user is allowed to export the cluster snapshot
+		isAllowed, restErr := api.Hooks.Authorization.CanExportClusterSnapshot(r.Context())
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !isAllowed {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      fmt.Errorf("requester has no permissions to export the cluster snapshot"),
+				Status:   http.StatusForbidden,
+				Message:  "You don't have permissions to export the cluster snapshot",
+				IsSilent: false,
+			})
+			return
+		}
+
+		// 3. Export snapshot. Each resource is checked with the same hook as if it was listed on its
+		// own, exporting connector secrets requires the permission to edit the connect cluster.
+		snapshot, restErr := api.ConsoleSvc.ExportClusterSnapshot(r.Context(), console.ClusterSnapshotExportOptions{
+			Scopes:         scopes,
+			IncludeSecrets: includeSecrets,
+			Authorizer:     api.Hooks.Authorization,
+		})
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		filename := fmt.Sprintf("cluster-snapshot-%s.json", snapshot.CreatedAt.Format("20060102-150405"))
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, snapshot)
+	}
+}
+
+func (api *API) handleImportClusterSnapshot() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request. Snapshots contain schema registry backups and may therefore
+		// be as large as these.
+		var req importClusterSnapshotRequest
+		r.Body = http.MaxBytesReader(w, r.Body, maxSchemaBackupBytes)
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      err,
+				Status:   http.StatusBadRequest,
+				Message:  fmt.Sprintf("Failed to decode cluster snapshot: %v", err.Error()),
+				IsSilent: false,
+			})
+			return
+		}
+		if err := req.OK(); err != nil {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      err,
+				Status:   http.StatusBadRequest,
+				Message:  fmt.Sprintf("Validating the cluster snapshot failed: %v", err.Error()),
+				IsSilent: false,
+			})
+			return
+		}
+
+		// 2. Check if logged in user is allowed to import the cluster snapshot
+		isAllowed, restErr := api.Hooks.Authorization.CanImportClusterSnapshot(r.Context())
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !isAllowed {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      fmt.Errorf("requester has no permissions to import the cluster snapshot"),
+				Status:   http.StatusForbidden,
+				Message:  "You don't have permissions to import the cluster snapshot",
+				IsSilent: false,
+			})
+			return
+		}
+
+		// 3. Import snapshot. Each resource is checked with the same hook as if it was created
+		// on its own, resources that are not allowed are reported as forbidden.
+		report, restErr := api.ConsoleSvc.ImportClusterSnapshot(r.Context(), req.Snapshot, console.ClusterSnapshotImportOptions{
+			Scopes:               req.Scopes,
+			DryRun:               req.DryRun,
+			PreserveSchemaIDs:    req.PreserveSchemaIDs,
+			IsProtectedPrincipal: api.Hooks.Authorization.IsProtectedKafkaUser,
+			Authorizer:           api.Hooks.Authorization,
+		})
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, report)
+	}
+}
@@ -28,7 +28,9 @@ type GetConsumerGroupsResponse struct {
 
 func (api *API) handleGetConsumerGroups() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		describedGroups, restErr := api.ConsoleSvc.GetConsumerGroupsOverview(r.Context(), nil)
+		// Estimating time lags requires fetching records from Kafka, hence it is opt-in
+		opts := console.ConsumerGroupsOverviewOptions{EstimateTimeLag: rest.GetQueryParam(r, "estimateTimeLag") == "true"}
+		describedGroups, restErr := api.ConsoleSvc.GetConsumerGroupsOverview(r.Context(), nil, opts)
 		if restErr != nil {
 			rest.SendRESTError(w, r, api.Logger, restErr)
 			return
@@ -84,7 +86,8 @@ func (api *API) handleGetConsumerGroup() http.HandlerFunc {
 			return
 		}
 
-		describedGroups, restErr := api.ConsoleSvc.GetConsumerGroupsOverview(r.Context(), []string{groupID})
+		opts := console.ConsumerGroupsOverviewOptions{EstimateTimeLag: rest.GetQueryParam(r, "estimateTimeLag") == "true"}
+		describedGroups, restErr := api.ConsoleSvc.GetConsumerGroupsOverview(r.Context(), []string{groupID}, opts)
 		if restErr != nil {
 			rest.SendRESTError(w, r, api.Logger, restErr)
 			return
@@ -0,0 +1,150 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cloudhut/common/rest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/redpanda-data/console/backend/pkg/console"
+)
+
+type resetConsumerGroupOffsetsRequest struct {
+	Strategy console.OffsetResetStrategy `json:"strategy"`
+
+	// Topics whose offsets shall be reset. All topics with committed offsets if empty.
+	Topics []string `json:"topics"`
+
+	// Timestamp in unix milliseconds for the timestamp strategy.
+	Timestamp int64 `json:"timestamp"`
+	// Duration such as "1h30m" for the duration strategy.
+	Duration string `json:"duration"`
+	// ShiftBy is the number of offsets to shift by for the shiftBy strategy.
+	ShiftBy int64 `json:"shiftBy"`
+	// SourceGroupID is the group whose offsets are copied for the copyFromGroup strategy.
+	SourceGroupID string `json:"sourceGroupId"`
+
+	DryRun bool `json:"dryRun"`
+	Force  bool `json:"force"`
+}
+
+// OK validates the user input for the reset offsets request.
+func (r *resetConsumerGroupOffsetsRequest) OK() error {
+	if !r.Strategy.IsValid() {
+		return fmt.Errorf("strategy must be one of earliest, latest, timestamp, duration, shiftBy or copyFromGroup")
+	}
+
+	switch r.Strategy {
+	case console.OffsetResetStrategyTimestamp:
+		if r.Timestamp < 0 {
+			return fmt.Errorf("timestamp must be a positive unix timestamp in milliseconds")
+		}
+	case console.OffsetResetStrategyDuration:
+		d, err := time.ParseDuration(r.Duration)
+		if err != nil {
+			return fmt.Errorf("failed to parse duration: %w", err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("duration must be positive")
+		}
+	case console.OffsetResetStrategyShiftBy:
+		if r.ShiftBy == 0 {
+			return fmt.Errorf("shiftBy must not be 0")
+		}
+	case console.OffsetResetStrategyCopyFromGroup:
+		if r.SourceGroupID == "" {
+			return fmt.Errorf("source group id must be set")
+		}
+	}
+
+	for _, topic := range r.Topics {
+		if topic == "" {
+			return fmt.Errorf("topic names must not be empty")
+		}
+	}
+
+	return nil
+}
+
+func (api *API) handleResetConsumerGroupOffsets() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		groupID := rest.GetURLParam(r, "groupId")
+		var req resetConsumerGroupOffsetsRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Check if logged-in user is allowed to edit the group, a dry run only requires
+		// permissions to view the group.
+		isAllowed, restErr := api.Hooks.Authorization.CanSeeConsumerGroup(r.Context(), groupID)
+		if restErr == nil && isAllowed && !req.DryRun {
+			isAllowed, restErr = api.Hooks.Authorization.CanEditConsumerGroup(r.Context(), groupID)
+		}
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !isAllowed {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:          fmt.Errorf("requester has no permissions to reset the consumer group offsets"),
+				Status:       http.StatusForbidden,
+				Message:      "You don't have permissions to reset the offsets of this consumer group",
+				InternalLogs: []zapcore.Field{zap.String("group_id", groupID)},
+				IsSilent:     false,
+			})
+			return
+		}
+		if req.Strategy == console.OffsetResetStrategyCopyFromGroup {
+			canSeeSource, restErr := api.Hooks.Authorization.CanSeeConsumerGroup(r.Context(), req.SourceGroupID)
+			if restErr != nil {
+				rest.SendRESTError(w, r, api.Logger, restErr)
+				return
+			}
+			if !canSeeSource {
+				rest.SendRESTError(w, r, api.Logger, &rest.Error{
+					Err:          fmt.Errorf("requester has no permissions to view the source consumer group"),
+					Status:       http.StatusForbidden,
+					Message:      "You don't have permissions to view the consumer group whose offsets shall be copied",
+					InternalLogs: []zapcore.Field{zap.String("group_id", req.SourceGroupID)},
+					IsSilent:     false,
+				})
+				return
+			}
+		}
+
+		// 3. Compute and optionally commit new offsets. Duration has already been validated.
+		duration, _ := time.ParseDuration(req.Duration)
+		res, restErr := api.ConsoleSvc.ResetConsumerGroupOffsets(r.Context(), console.ResetConsumerGroupOffsetsRequest{
+			GroupID:       groupID,
+			Strategy:      req.Strategy,
+			Topics:        req.Topics,
+			Timestamp:     req.Timestamp,
+			Duration:      duration,
+			ShiftBy:       req.ShiftBy,
+			SourceGroupID: req.SourceGroupID,
+			DryRun:        req.DryRun,
+			Force:         req.Force,
+		})
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/cloudhut/common/rest"
+
+	"github.com/redpanda-data/console/backend/pkg/console"
+)
+
+type electLeadersRequest struct {
+	// ElectionType is either PREFERRED or UNCLEAN.
+	ElectionType console.ElectionType `json:"electionType"`
+
+	// AllPartitions triggers leader elections for all partitions in the cluster. Topics must
+	// not be set if this is true.
+	AllPartitions bool `json:"allPartitions"`
+
+	Topics []struct {
+		TopicName string `json:"topicName"`
+
+		// Partitions whose leaders shall be elected. Leave empty to elect the leaders of all partitions.
+		Partitions []int32 `json:"partitions"`
+	} `json:"topics"`
+}
+
+func (e *electLeadersRequest) OK() error {
+	if _, err := e.ElectionType.Int8(); err != nil {
+		return fmt.Errorf("election type must be either %v or %v", console.ElectionTypePreferred, console.ElectionTypeUnclean)
+	}
+
+	if e.AllPartitions {
+		if len(e.Topics) > 0 {
+			return fmt.Errorf("topics must not be set if leaders shall be elected for all partitions")
+		}
+		return nil
+	}
+
+	if len(e.Topics) == 0 {
+		return fmt.Errorf("at least one topic must be set")
+	}
+	seen := make(map[string]struct{}, len(e.Topics))
+	for _, topic := range e.Topics {
+		if topic.TopicName == "" {
+			return fmt.Errorf("topic name must be set")
+		}
+		if _, exists := seen[topic.TopicName]; exists {
+			return fmt.Errorf("topic '%v' must only be specified once", topic.TopicName)
+		}
+		seen[topic.TopicName] = struct{}{}
+		for _, partitionID := range topic.Partitions {
+			if partitionID < 0 {
+				return fmt.Errorf("topic '%v' has an invalid partition id '%v'", topic.TopicName, partitionID)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (api *API) handleElectLeaders() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var req electLeadersRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Check if logged in user is allowed to elect leaders for all affected topics
+		topicNames := make([]string, len(req.Topics))
+		for i, topic := range req.Topics {
+			topicNames[i] = topic.TopicName
+		}
+		if req.AllPartitions {
+			var err error
+			topicNames, err = api.ConsoleSvc.GetAllTopicNames(r.Context(), nil)
+			if err != nil {
+				rest.SendRESTError(w, r, api.Logger, &rest.Error{
+					Err:      err,
+					Status:   http.StatusServiceUnavailable,
+					Message:  fmt.Sprintf("Failed to list topic names: %v", err.Error()),
+					IsSilent: false,
+				})
+				return
+			}
+		}
+		// Unclean elections may lose data, hence they are authorized separately
+		canElectLeaders := api.Hooks.Authorization.CanElectLeaders
+		electionDescription := "elect leaders"
+		if req.ElectionType == console.ElectionTypeUnclean {
+			canElectLeaders = api.Hooks.Authorization.CanElectUncleanLeaders
+			electionDescription = "elect unclean leaders"
+		}
+		for _, topicName := range topicNames {
+			isAllowed, restErr := canElectLeaders(r.Context(), topicName)
+			if restErr != nil {
+				rest.SendRESTError(w, r, api.Logger, restErr)
+				return
+			}
+			if !isAllowed {
+				rest.SendRESTError(w, r, api.Logger, &rest.Error{
+					Err:      fmt.Errorf("requester has no permissions to %v for topic '%v'", electionDescription, topicName),
+					Status:   http.StatusForbidden,
+					Message:  fmt.Sprintf("You don't have permissions to %v for topic '%v'", electionDescription, topicName),
+					IsSilent: false,
+				})
+				return
+			}
+		}
+
+		// 3. Trigger leader elections
+		electReq := console.ElectLeadersRequest{ElectionType: req.ElectionType}
+		if !req.AllPartitions {
+			electReq.Topics = make([]console.ElectLeadersRequestTopic, len(req.Topics))
+			for i, topic := range req.Topics {
+				electReq.Topics[i] = console.ElectLeadersRequestTopic{
+					TopicName:  topic.TopicName,
+					Partitions: topic.Partitions,
+				}
+			}
+		}
+		res, restErr := api.ConsoleSvc.ElectLeaders(r.Context(), electReq)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cloudhut/common/rest"
+	gorillaschema "github.com/gorilla/schema"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/redpanda-data/console/backend/pkg/console"
+	"github.com/redpanda-data/console/backend/pkg/history"
+)
+
+type getHistoryRequest struct {
+	// Since is the duration (e.g. "1h") of the requested time window. All retained samples
+	// are returned if empty.
+	Since string `schema:"since"`
+
+	// Topic limits the consumer group history to a single topic.
+	Topic string `schema:"topic"`
+}
+
+// sinceTime returns the start of the requested time window.
+func (g *getHistoryRequest) sinceTime() (time.Time, error) {
+	if g.Since == "" {
+		return time.Time{}, nil
+	}
+	d, err := time.ParseDuration(g.Since)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse since: %w", err)
+	}
+	if d <= 0 {
+		return time.Time{}, fmt.Errorf("since must be a positive duration")
+	}
+	return time.Now().Add(-d), nil
+}
+
+// decodeHistoryRequest parses the query parameters of history requests and sends an error
+// response if they are invalid.
+func (api *API) decodeHistoryRequest(w http.ResponseWriter, r *http.Request) (*getHistoryRequest, time.Time, bool) {
+	req := &getHistoryRequest{}
+	err := gorillaschema.NewDecoder().Decode(req, r.URL.Query())
+	if err == nil {
+		var since time.Time
+		since, err = req.sinceTime()
+		if err == nil {
+			return req, since, true
+		}
+	}
+
+	rest.SendRESTError(w, r, api.Logger, &rest.Error{
+		Err:      err,
+		Status:   http.StatusBadRequest,
+		Message:  fmt.Sprintf("Failed to parse request parameters: %v", err.Error()),
+		IsSilent: false,
+	})
+	return nil, time.Time{}, false
+}
+
+func (api *API) handleGetTopicHistory() http.HandlerFunc {
+	type response struct {
+		*history.TopicSeries
+		IsConfigured bool `json:"isConfigured"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		topicName := rest.GetURLParam(r, "topicName")
+		_, since, ok := api.decodeHistoryRequest(w, r)
+		if !ok {
+			return
+		}
+
+		canSee, restErr := api.Hooks.Authorization.CanSeeTopic(r.Context(), topicName)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !canSee {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:          fmt.Errorf("requester has no permissions to view topic"),
+				Status:       http.StatusForbidden,
+				Message:      "You don't have permissions to view this topic",
+				InternalLogs: []zapcore.Field{zap.String("topic_name", topicName)},
+				IsSilent:     false,
+			})
+			return
+		}
+
+		series, err := api.ConsoleSvc.GetTopicHistory(topicName, since)
+		if err != nil {
+			if errors.Is(err, console.ErrHistoryNotEnabled) {
+				rest.SendResponse(w, r, api.Logger, http.StatusOK, response{IsConfigured: false})
+				return
+			}
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      err,
+				Status:   http.StatusInternalServerError,
+				Message:  fmt.Sprintf("Failed to get topic history: %v", err.Error()),
+				IsSilent: false,
+			})
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, response{TopicSeries: series, IsConfigured: true})
+	}
+}
+
+func (api *API) handleGetConsumerGroupHistory() http.HandlerFunc {
+	type response struct {
+		*history.GroupSeries
+		IsConfigured bool `json:"isConfigured"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		groupID := rest.GetURLParam(r, "groupId")
+		req, since, ok := api.decodeHistoryRequest(w, r)
+		if !ok {
+			return
+		}
+
+		canSee, restErr := api.Hooks.Authorization.CanSeeConsumerGroup(r.Context(), groupID)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !canSee {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:          fmt.Errorf("requester has no permissions to view consumer group"),
+				Status:       http.StatusForbidden,
+				Message:      "You don't have permissions to view this consumer group",
+				InternalLogs: []zapcore.Field{zap.String("group_id", groupID)},
+				IsSilent:     false,
+			})
+			return
+		}
+
+		series, err := api.ConsoleSvc.GetConsumerGroupHistory(groupID, req.Topic, since)
+		if err != nil {
+			if errors.Is(err, console.ErrHistoryNotEnabled) {
+				rest.SendResponse(w, r, api.Logger, http.StatusOK, response{IsConfigured: false})
+				return
+			}
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      err,
+				Status:   http.StatusInternalServerError,
+				Message:  fmt.Sprintf("Failed to get consumer group history: %v", err.Error()),
+				IsSilent: false,
+			})
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, response{GroupSeries: series, IsConfigured: true})
+	}
+}
@@ -0,0 +1,282 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/cloudhut/common/rest"
+
+	"github.com/redpanda-data/console/backend/pkg/console"
+)
+
+// authorizePartitionReassignments sends a REST error and returns false if the logged in user is not
+// allowed to manage partition reassignments.
+func (api *API) authorizePartitionReassignments(w http.ResponseWriter, r *http.Request) bool {
+	isAllowed, restErr := api.Hooks.Authorization.CanPatchPartitionReassignments(r.Context())
+	if restErr != nil {
+		rest.SendRESTError(w, r, api.Logger, restErr)
+		return false
+	}
+	if !isAllowed {
+		rest.SendRESTError(w, r, api.Logger, &rest.Error{
+			Err:      fmt.Errorf("requester has no permissions to patch partition assignments"),
+			Status:   http.StatusForbidden,
+			Message:  "You don't have permissions to reassign partitions",
+			IsSilent: false,
+		})
+		return false
+	}
+	return true
+}
+
+type planPartitionReassignmentsRequest struct {
+	Goal              console.ReassignmentGoal `json:"goal"`
+	TopicNames        []string                 `json:"topicNames"`
+	BrokerIDs         []int32                  `json:"brokerIds"`
+	ReplicationFactor int                      `json:"replicationFactor"`
+}
+
+func (p *planPartitionReassignmentsRequest) OK() error {
+	switch p.Goal {
+	case console.ReassignmentGoalBalanceReplicas:
+	case console.ReassignmentGoalDrainBrokers:
+		if len(p.BrokerIDs) == 0 {
+			return fmt.Errorf("at least one broker id must be set to drain brokers")
+		}
+	case console.ReassignmentGoalChangeReplicationFactor:
+		if p.ReplicationFactor < 1 {
+			return fmt.Errorf("replication factor must be at least 1")
+		}
+	default:
+		return fmt.Errorf("goal must be one of %v, %v or %v", console.ReassignmentGoalBalanceReplicas,
+			console.ReassignmentGoalDrainBrokers, console.ReassignmentGoalChangeReplicationFactor)
+	}
+
+	return nil
+}
+
+func (api *API) handlePlanPartitionReassignments() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var req planPartitionReassignmentsRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Check if logged in user is allowed to reassign partitions
+		if !api.authorizePartitionReassignments(w, r) {
+			return
+		}
+
+		// 3. Compute plan
+		plan, restErr := api.ConsoleSvc.PlanPartitionReassignments(r.Context(), console.PlanPartitionReassignmentsRequest{
+			Goal:              req.Goal,
+			TopicNames:        req.TopicNames,
+			BrokerIDs:         req.BrokerIDs,
+			ReplicationFactor: req.ReplicationFactor,
+		})
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, plan)
+	}
+}
+
+func (api *API) handleGetPartitionReassignmentsProgress() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Check if logged in user is allowed to reassign partitions
+		if !api.authorizePartitionReassignments(w, r) {
+			return
+		}
+
+		// 2. Compute progress of in-flight reassignments
+		progress, restErr := api.ConsoleSvc.GetPartitionReassignmentsProgress(r.Context())
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, progress)
+	}
+}
+
+type cancelPartitionReassignmentsRequest struct {
+	// Topics whose reassignments shall be cancelled.
+	Topics []struct {
+		TopicName  string  `json:"topicName"`
+		Partitions []int32 `json:"partitions"`
+	} `json:"topics"`
+
+	// All cancels all in-flight reassignments in the cluster. It must be set explicitly,
+	// so that an empty request does not cancel everything.
+	All bool `json:"all"`
+}
+
+func (c *cancelPartitionReassignmentsRequest) OK() error {
+	if c.All && len(c.Topics) > 0 {
+		return fmt.Errorf("either topics or all must be set, but not both")
+	}
+	if !c.All && len(c.Topics) == 0 {
+		return fmt.Errorf("at least one topic must be set, or all must be set to true to cancel all reassignments")
+	}
+	for _, topic := range c.Topics {
+		if topic.TopicName == "" {
+			return fmt.Errorf("topic name must be set")
+		}
+		if len(topic.Partitions) == 0 {
+			return fmt.Errorf("topic '%v' has no partitions set whose reassignments shall be cancelled", topic.TopicName)
+		}
+	}
+
+	return nil
+}
+
+func (api *API) handleCancelPartitionReassignments() http.HandlerFunc {
+	type response struct {
+		ReassignPartitionsResponse []console.AlterPartitionReassignmentsResponse `json:"reassignPartitionsResponses"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var req cancelPartitionReassignmentsRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Check if logged in user is allowed to reassign partitions
+		if !api.authorizePartitionReassignments(w, r) {
+			return
+		}
+
+		// 3. Cancel reassignments
+		var topics []console.PartitionReassignments
+		if !req.All {
+			topics = make([]console.PartitionReassignments, len(req.Topics))
+			for i, topic := range req.Topics {
+				partitions := make([]console.PartitionReassignmentsPartition, len(topic.Partitions))
+				for j, partitionID := range topic.Partitions {
+					partitions[j] = console.PartitionReassignmentsPartition{PartitionID: partitionID}
+				}
+				topics[i] = console.PartitionReassignments{TopicName: topic.TopicName, Partitions: partitions}
+			}
+		}
+		res, restErr := api.ConsoleSvc.CancelPartitionReassignments(r.Context(), topics)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, response{ReassignPartitionsResponse: res})
+	}
+}
+
+type setReplicationThrottlesRequest struct {
+	RateBytesPerSecond int64                                    `json:"rateBytesPerSecond"`
+	Topics             []console.PartitionReassignmentPlanTopic `json:"topics"`
+}
+
+func (s *setReplicationThrottlesRequest) OK() error {
+	if s.RateBytesPerSecond < 1 {
+		return fmt.Errorf("rate must be at least 1 byte per second")
+	}
+	if len(s.Topics) == 0 {
+		return fmt.Errorf("at least one topic must be set")
+	}
+	for _, topic := range s.Topics {
+		if topic.TopicName == "" {
+			return fmt.Errorf("topic name must be set")
+		}
+		if len(topic.Partitions) == 0 {
+			return fmt.Errorf("topic '%v' has no partitions set that shall be throttled", topic.TopicName)
+		}
+	}
+
+	return nil
+}
+
+func (api *API) handleSetReplicationThrottles() http.HandlerFunc {
+	type response struct {
+		PatchedConfigs []console.IncrementalAlterConfigsResourceResponse `json:"patchedConfigs"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var req setReplicationThrottlesRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Check if logged in user is allowed to reassign partitions
+		if !api.authorizePartitionReassignments(w, r) {
+			return
+		}
+
+		// 3. Set throttles
+		res, restErr := api.ConsoleSvc.SetReplicationThrottles(r.Context(), console.SetReplicationThrottlesRequest{
+			RateBytesPerSecond: req.RateBytesPerSecond,
+			Topics:             req.Topics,
+		})
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, response{PatchedConfigs: res})
+	}
+}
+
+type clearReplicationThrottlesRequest struct {
+	// TopicNames whose throttled replicas shall be cleared. If empty, they are cleared on all topics.
+	TopicNames []string `json:"topicNames"`
+}
+
+func (*clearReplicationThrottlesRequest) OK() error {
+	return nil
+}
+
+func (api *API) handleClearReplicationThrottles() http.HandlerFunc {
+	type response struct {
+		PatchedConfigs []console.IncrementalAlterConfigsResourceResponse `json:"patchedConfigs"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var req clearReplicationThrottlesRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Check if logged in user is allowed to reassign partitions
+		if !api.authorizePartitionReassignments(w, r) {
+			return
+		}
+
+		// 3. Clear throttles
+		res, restErr := api.ConsoleSvc.ClearReplicationThrottles(r.Context(), req.TopicNames)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, response{PatchedConfigs: res})
+	}
+}
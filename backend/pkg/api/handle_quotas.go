@@ -14,6 +14,8 @@ import (
 	"net/http"
 
 	"github.com/cloudhut/common/rest"
+
+	"github.com/redpanda-data/console/backend/pkg/console"
 )
 
 func (api *API) handleGetQuotas() http.HandlerFunc {
@@ -39,3 +41,184 @@ func (api *API) handleGetQuotas() http.HandlerFunc {
 		rest.SendResponse(w, r, api.Logger, http.StatusOK, quotas)
 	}
 }
+
+// quotaKeysByEntityType lists the quota keys that can be set for each entity type.
+var quotaKeysByEntityType = map[string][]string{
+	console.QuotaEntityTypeUser: {
+		console.QuotaKeyProducerByteRate, console.QuotaKeyConsumerByteRate,
+		console.QuotaKeyRequestPercentage, console.QuotaKeyControllerMutationRate,
+	},
+	console.QuotaEntityTypeClientID: {
+		console.QuotaKeyProducerByteRate, console.QuotaKeyConsumerByteRate,
+		console.QuotaKeyRequestPercentage, console.QuotaKeyControllerMutationRate,
+	},
+	console.QuotaEntityTypeIP: {console.QuotaKeyConnectionCreationRate},
+}
+
+// validateQuotaEntity checks that the entity is one of user, client-id, user + client-id or ip.
+func validateQuotaEntity(entity []console.QuotaEntityComponent) error {
+	switch len(entity) {
+	case 1:
+		if _, exists := quotaKeysByEntityType[entity[0].Type]; !exists {
+			return fmt.Errorf("entity type %q is not supported, must be one of user, client-id or ip", entity[0].Type)
+		}
+	case 2:
+		if entity[0].Type == entity[1].Type ||
+			(entity[0].Type != console.QuotaEntityTypeUser && entity[0].Type != console.QuotaEntityTypeClientID) ||
+			(entity[1].Type != console.QuotaEntityTypeUser && entity[1].Type != console.QuotaEntityTypeClientID) {
+			return fmt.Errorf("an entity with two components must consist of a user and a client-id")
+		}
+	default:
+		return fmt.Errorf("entity must consist of one or two components")
+	}
+
+	for _, component := range entity {
+		if component.Name != nil && *component.Name == "" {
+			return fmt.Errorf("entity name for type %q must not be empty, omit it to refer to the default entity", component.Type)
+		}
+	}
+
+	return nil
+}
+
+// validateQuotaKey checks that the given quota key can be set for the entity.
+func validateQuotaKey(entity []console.QuotaEntityComponent, key string) error {
+	for _, allowedKey := range quotaKeysByEntityType[entity[0].Type] {
+		if key == allowedKey {
+			return nil
+		}
+	}
+	return fmt.Errorf("quota key %q is not supported for entity type %q", key, entity[0].Type)
+}
+
+type alterQuotasRequest struct {
+	Entity       []console.QuotaEntityComponent `json:"entity"`
+	Set          []console.QuotaResponseSetting `json:"set"`
+	Remove       []string                       `json:"remove"`
+	ValidateOnly bool                           `json:"validateOnly"`
+}
+
+func (a *alterQuotasRequest) OK() error {
+	if err := validateQuotaEntity(a.Entity); err != nil {
+		return err
+	}
+	if len(a.Set) == 0 && len(a.Remove) == 0 {
+		return fmt.Errorf("at least one quota must be set or removed")
+	}
+
+	seen := make(map[string]struct{}, len(a.Set)+len(a.Remove))
+	for _, setting := range a.Set {
+		if err := validateQuotaKey(a.Entity, setting.Key); err != nil {
+			return err
+		}
+		if setting.Value < 0 {
+			return fmt.Errorf("value for quota key %q must not be negative", setting.Key)
+		}
+		if setting.Key == console.QuotaKeyRequestPercentage && setting.Value == 0 {
+			return fmt.Errorf("value for quota key %q must be greater than 0", setting.Key)
+		}
+		if _, exists := seen[setting.Key]; exists {
+			return fmt.Errorf("quota key %q must only be specified once", setting.Key)
+		}
+		seen[setting.Key] = struct{}{}
+	}
+	for _, key := range a.Remove {
+		if err := validateQuotaKey(a.Entity, key); err != nil {
+			return err
+		}
+		if _, exists := seen[key]; exists {
+			return fmt.Errorf("quota key %q must only be specified once", key)
+		}
+		seen[key] = struct{}{}
+	}
+
+	return nil
+}
+
+func (api *API) handleAlterQuotas() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var req alterQuotasRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Check if logged in user is allowed to alter quotas of the given entity
+		isAllowed, restErr := api.Hooks.Authorization.CanAlterQuotas(r.Context(), req.Entity)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !isAllowed {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      fmt.Errorf("requester is not allowed to alter quotas"),
+				Status:   http.StatusForbidden,
+				Message:  "You don't have permissions to alter quotas",
+				IsSilent: false,
+			})
+			return
+		}
+
+		// 3. Alter quotas
+		res, restErr := api.ConsoleSvc.AlterQuotas(r.Context(), console.AlterQuotasRequest{
+			Entity:       req.Entity,
+			Set:          req.Set,
+			Remove:       req.Remove,
+			ValidateOnly: req.ValidateOnly,
+		})
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
+	}
+}
+
+type deleteQuotasRequest struct {
+	Entity       []console.QuotaEntityComponent `json:"entity"`
+	ValidateOnly bool                           `json:"validateOnly"`
+}
+
+func (d *deleteQuotasRequest) OK() error {
+	return validateQuotaEntity(d.Entity)
+}
+
+func (api *API) handleDeleteQuotas() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var req deleteQuotasRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Check if logged in user is allowed to delete quotas of the given entity
+		isAllowed, restErr := api.Hooks.Authorization.CanDeleteQuotas(r.Context(), req.Entity)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !isAllowed {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      fmt.Errorf("requester is not allowed to delete quotas"),
+				Status:   http.StatusForbidden,
+				Message:  "You don't have permissions to delete quotas",
+				IsSilent: false,
+			})
+			return
+		}
+
+		// 3. Remove all quota values of the entity
+		res, restErr := api.ConsoleSvc.DeleteQuotas(r.Context(), req.Entity, req.ValidateOnly)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
+	}
+}
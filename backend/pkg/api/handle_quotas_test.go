@@ -0,0 +1,57 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/redpanda-data/console/backend/pkg/console"
+)
+
+func TestAlterQuotasRequestOK(t *testing.T) {
+	name := func(s string) *string { return &s }
+	user := console.QuotaEntityComponent{Type: console.QuotaEntityTypeUser, Name: name("alice")}
+	defaultClient := console.QuotaEntityComponent{Type: console.QuotaEntityTypeClientID}
+	ip := console.QuotaEntityComponent{Type: console.QuotaEntityTypeIP, Name: name("10.0.0.1")}
+	rate := func(key string, value float64) []console.QuotaResponseSetting {
+		return []console.QuotaResponseSetting{{Key: key, Value: value}}
+	}
+
+	tests := map[string]struct {
+		input   alterQuotasRequest
+		wantErr bool
+	}{
+		"user":                    {input: alterQuotasRequest{Entity: []console.QuotaEntityComponent{user}, Set: rate("producer_byte_rate", 1024)}},
+		"user and default client": {input: alterQuotasRequest{Entity: []console.QuotaEntityComponent{user, defaultClient}, Remove: []string{"consumer_byte_rate"}}},
+		"ip":                      {input: alterQuotasRequest{Entity: []console.QuotaEntityComponent{ip}, Set: rate("connection_creation_rate", 10)}},
+		"no entity":               {input: alterQuotasRequest{Set: rate("producer_byte_rate", 1024)}, wantErr: true},
+		"unknown entity type":     {input: alterQuotasRequest{Entity: []console.QuotaEntityComponent{{Type: "group"}}, Set: rate("producer_byte_rate", 1)}, wantErr: true},
+		"ip combined with user":   {input: alterQuotasRequest{Entity: []console.QuotaEntityComponent{user, ip}, Set: rate("producer_byte_rate", 1)}, wantErr: true},
+		"byte rate for ip":        {input: alterQuotasRequest{Entity: []console.QuotaEntityComponent{ip}, Set: rate("producer_byte_rate", 1)}, wantErr: true},
+		"negative value":          {input: alterQuotasRequest{Entity: []console.QuotaEntityComponent{user}, Set: rate("consumer_byte_rate", -1)}, wantErr: true},
+		"empty name":              {input: alterQuotasRequest{Entity: []console.QuotaEntityComponent{{Type: "user", Name: name("")}}, Set: rate("producer_byte_rate", 1)}, wantErr: true},
+		"no operations":           {input: alterQuotasRequest{Entity: []console.QuotaEntityComponent{user}}, wantErr: true},
+		"key set and removed":     {input: alterQuotasRequest{Entity: []console.QuotaEntityComponent{user}, Set: rate("producer_byte_rate", 1), Remove: []string{"producer_byte_rate"}}, wantErr: true},
+		"zero request percentage": {input: alterQuotasRequest{Entity: []console.QuotaEntityComponent{user}, Set: rate("request_percentage", 0)}, wantErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.input.OK()
+			if test.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/cloudhut/common/rest"
+
+	"github.com/redpanda-data/console/backend/pkg/console"
+)
+
+// parseBrokerIDAndAuthorizeLogDirs parses the broker id URL parameter and checks whether the logged in
+// user is allowed to view or, if alter is set, to move replicas between the broker's log dirs. A REST
+// error is sent if not.
+func (api *API) parseBrokerIDAndAuthorizeLogDirs(w http.ResponseWriter, r *http.Request, alter bool) (int32, bool) {
+	brokerID, ok := api.parseBrokerIDParam(w, r)
+	if !ok {
+		return 0, false
+	}
+
+	authorize := api.Hooks.Authorization.CanViewReplicaLogDirs
+	action := "view the replica log dirs of this broker"
+	if alter {
+		authorize = api.Hooks.Authorization.CanAlterReplicaLogDirs
+		action = "move replicas between log dirs of this broker"
+	}
+	isAllowed, restErr := authorize(r.Context(), brokerID)
+	if restErr != nil {
+		rest.SendRESTError(w, r, api.Logger, restErr)
+		return 0, false
+	}
+	if !isAllowed {
+		rest.SendRESTError(w, r, api.Logger, &rest.Error{
+			Err:      fmt.Errorf("requester has no permissions to %v (broker %d)", action, brokerID),
+			Status:   http.StatusForbidden,
+			Message:  fmt.Sprintf("You don't have permissions to %v", action),
+			IsSilent: false,
+		})
+		return 0, false
+	}
+
+	return brokerID, true
+}
+
+func (api *API) handlePlanLogDirBalance() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		brokerID, ok := api.parseBrokerIDAndAuthorizeLogDirs(w, r, false)
+		if !ok {
+			return
+		}
+
+		plan, restErr := api.ConsoleSvc.PlanLogDirBalance(r.Context(), brokerID)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, plan)
+	}
+}
+
+func (api *API) handleGetReplicaLogDirMovesProgress() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		brokerID, ok := api.parseBrokerIDAndAuthorizeLogDirs(w, r, false)
+		if !ok {
+			return
+		}
+
+		progress, restErr := api.ConsoleSvc.GetReplicaLogDirMovesProgress(r.Context(), brokerID)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, progress)
+	}
+}
+
+type moveReplicaLogDirsRequest struct {
+	Moves []console.ReplicaLogDirMove `json:"moves"`
+}
+
+func (m *moveReplicaLogDirsRequest) OK() error {
+	if len(m.Moves) == 0 {
+		return fmt.Errorf("at least one replica move must be set")
+	}
+
+	seen := make(map[string]struct{}, len(m.Moves))
+	for _, move := range m.Moves {
+		if move.TopicName == "" {
+			return fmt.Errorf("topic name must be set")
+		}
+		if move.TargetLogDir == "" {
+			return fmt.Errorf("target log dir must be set for topic '%v' partition %d", move.TopicName, move.PartitionID)
+		}
+		key := fmt.Sprintf("%v-%d", move.TopicName, move.PartitionID)
+		if _, exists := seen[key]; exists {
+			return fmt.Errorf("topic '%v' partition %d must only be moved once", move.TopicName, move.PartitionID)
+		}
+		seen[key] = struct{}{}
+	}
+
+	return nil
+}
+
+func (api *API) handleMoveReplicaLogDirs() http.HandlerFunc {
+	type response struct {
+		Moves []console.ReplicaLogDirMoveResult `json:"moves"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var req moveReplicaLogDirsRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Check if logged in user is allowed to move replicas on this broker
+		brokerID, ok := api.parseBrokerIDAndAuthorizeLogDirs(w, r, true)
+		if !ok {
+			return
+		}
+
+		// 3. Submit moves
+		res, restErr := api.ConsoleSvc.MoveReplicaLogDirs(r.Context(), brokerID, req.Moves)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, response{Moves: res})
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/cloudhut/common/rest"
 
 	"github.com/redpanda-data/console/backend/pkg/console"
+	"github.com/redpanda-data/console/backend/pkg/schema"
 )
 
 func (api *API) handleGetSchemaOverview() http.HandlerFunc {
@@ -90,3 +91,41 @@ func (api *API) handleGetSchemaDetails() http.HandlerFunc {
 		})
 	}
 }
+
+func (api *API) handleGetSchemaReferenceGraph() http.HandlerFunc {
+	type response struct {
+		ReferenceGraph *schema.ReferenceGraph `json:"referenceGraph"`
+		IsConfigured   bool                   `json:"isConfigured"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		subject := rest.GetURLParam(r, "subject")
+		if subjectUnescaped, err := url.PathUnescape(subject); err == nil {
+			subject = subjectUnescaped
+		}
+		version := rest.GetURLParam(r, "version")
+		graph, err := api.ConsoleSvc.GetSchemaReferenceGraph(r.Context(), subject, version)
+		if err != nil {
+			if errors.Is(err, console.ErrSchemaRegistryNotConfigured) {
+				rest.SendResponse(w, r, api.Logger, http.StatusOK, &response{
+					ReferenceGraph: nil,
+					IsConfigured:   false,
+				})
+				return
+			}
+
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      err,
+				Status:   http.StatusServiceUnavailable,
+				Message:  fmt.Sprintf("Schema reference graph request has failed: %v", err.Error()),
+				IsSilent: false,
+			})
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, &response{
+			ReferenceGraph: graph,
+			IsConfigured:   true,
+		})
+	}
+}
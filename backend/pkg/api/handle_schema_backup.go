@@ -0,0 +1,131 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/cloudhut/common/rest"
+
+	"github.com/redpanda-data/console/backend/pkg/console"
+	"github.com/redpanda-data/console/backend/pkg/schema"
+)
+
+// maxSchemaBackupBytes is the maximum size of a backup that can be imported via the API. Backups
+// may be much larger than the default request size limit of 1MB, hence we don't use rest.Decode.
+const maxSchemaBackupBytes = 100 * 1024 * 1024
+
+type importSchemaRegistryRequest struct {
+	Backup          *schema.Backup `json:"backup"`
+	DryRun          bool           `json:"dryRun"`
+	PreserveIDs     bool           `json:"preserveIds"`
+	AllowNonEmpty   bool           `json:"allowNonEmpty"`
+	ApplyGlobalMode bool           `json:"applyGlobalMode"`
+}
+
+// OK validates the user input for the import schema registry request.
+func (i *importSchemaRegistryRequest) OK() error {
+	if i.Backup == nil {
+		return fmt.Errorf("backup must be set")
+	}
+
+	return i.Backup.Validate()
+}
+
+func (api *API) handleExportSchemaRegistry() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		backup, err := api.ConsoleSvc.ExportSchemaRegistry(r.Context())
+		if err != nil {
+			status := http.StatusServiceUnavailable
+			if errors.Is(err, console.ErrSchemaRegistryNotConfigured) {
+				status = http.StatusNotFound
+			}
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      err,
+				Status:   status,
+				Message:  fmt.Sprintf("Schema registry export has failed: %v", err.Error()),
+				IsSilent: false,
+			})
+			return
+		}
+
+		filename := fmt.Sprintf("schema-registry-backup-%s.json", backup.CreatedAt.Format("20060102-150405"))
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, backup)
+	}
+}
+
+func (api *API) handleImportSchemaRegistry() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var req importSchemaRegistryRequest
+		r.Body = http.MaxBytesReader(w, r.Body, maxSchemaBackupBytes)
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      err,
+				Status:   http.StatusBadRequest,
+				Message:  fmt.Sprintf("Failed to decode schema registry backup: %v", err.Error()),
+				IsSilent: false,
+			})
+			return
+		}
+		if err := req.OK(); err != nil {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      err,
+				Status:   http.StatusBadRequest,
+				Message:  fmt.Sprintf("Validating the schema registry backup failed: %v", err.Error()),
+				IsSilent: false,
+			})
+			return
+		}
+
+		// 2. Check if logged in user is allowed to import schemas
+		isAllowed, restErr := api.Hooks.Authorization.CanImportSchemaRegistry(r.Context())
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !isAllowed {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      fmt.Errorf("requester is not allowed to import schemas"),
+				Status:   http.StatusForbidden,
+				Message:  "You are not allowed to import schemas",
+				IsSilent: false,
+			})
+			return
+		}
+
+		// 3. Import backup
+		report, err := api.ConsoleSvc.ImportSchemaRegistry(r.Context(), req.Backup, schema.ImportOptions{
+			DryRun:          req.DryRun,
+			PreserveIDs:     req.PreserveIDs,
+			AllowNonEmpty:   req.AllowNonEmpty,
+			ApplyGlobalMode: req.ApplyGlobalMode,
+		})
+		if err != nil {
+			status := http.StatusServiceUnavailable
+			if errors.Is(err, console.ErrSchemaRegistryNotConfigured) {
+				status = http.StatusNotFound
+			}
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      err,
+				Status:   status,
+				Message:  fmt.Sprintf("Schema registry import has failed: %v", err.Error()),
+				IsSilent: false,
+			})
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, report)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/gorilla/schema"
+
+	"github.com/redpanda-data/console/backend/pkg/console"
+	"github.com/redpanda-data/console/backend/pkg/example"
+)
+
+type getExamplePayloadRequest struct {
+	// Seed for the generator. If not set, a random seed is used and returned as part of the response.
+	Seed *int64 `schema:"seed"`
+
+	// MessageType is the Protobuf message type that shall be generated (only for Protobuf schemas).
+	MessageType string `schema:"messageType"`
+
+	// OptionalFields is one of "random" (default), "all" or "none".
+	OptionalFields string `schema:"optionalFields"`
+}
+
+// OK validates the user input for the get example payload request.
+func (g *getExamplePayloadRequest) OK() error {
+	switch example.OptionalFields(g.OptionalFields) {
+	case "", example.OptionalFieldsRandom, example.OptionalFieldsAll, example.OptionalFieldsNone:
+		return nil
+	default:
+		return fmt.Errorf("optionalFields must be one of %q, %q or %q",
+			example.OptionalFieldsRandom, example.OptionalFieldsAll, example.OptionalFieldsNone)
+	}
+}
+
+func (g *getExamplePayloadRequest) toOptions() console.ExampleOptions {
+	return console.ExampleOptions{
+		Seed:           g.Seed,
+		MessageType:    g.MessageType,
+		OptionalFields: example.OptionalFields(g.OptionalFields),
+	}
+}
+
+func parseExamplePayloadRequest(r *http.Request) (*getExamplePayloadRequest, *rest.Error) {
+	decoder := schema.NewDecoder()
+	decoder.IgnoreUnknownKeys(true)
+	req := &getExamplePayloadRequest{}
+	if err := decoder.Decode(req, r.URL.Query()); err != nil {
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusBadRequest,
+			Message:  fmt.Sprintf("Failed to parse request parameters: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+	if err := req.OK(); err != nil {
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusBadRequest,
+			Message:  fmt.Sprintf("Failed to validate request parameters: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+	return req, nil
+}
+
+func (api *API) handleGetSchemaExample() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, restErr := parseExamplePayloadRequest(r)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		subject := rest.GetURLParam(r, "subject")
+		if subjectUnescaped, err := url.PathUnescape(subject); err == nil {
+			subject = subjectUnescaped
+		}
+		version := rest.GetURLParam(r, "version")
+
+		payload, restErr := api.ConsoleSvc.GetSchemaExample(r.Context(), subject, version, req.toOptions())
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, payload)
+	}
+}
+
+func (api *API) handleGetProtoTypeExample() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, restErr := parseExamplePayloadRequest(r)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		protoType := rest.GetURLParam(r, "protoType")
+		payload, restErr := api.ConsoleSvc.GetProtoTypeExample(r.Context(), protoType, req.toOptions())
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, payload)
+	}
+}
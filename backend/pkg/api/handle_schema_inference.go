@@ -0,0 +1,210 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/cloudhut/common/rest"
+	gorillaschema "github.com/gorilla/schema"
+
+	"github.com/redpanda-data/console/backend/pkg/console"
+	"github.com/redpanda-data/console/backend/pkg/schema"
+)
+
+const (
+	defaultInferSchemaSampleSize = 500
+	maxInferSchemaSampleSize     = 5000
+)
+
+type inferSchemaRequest struct {
+	SampleSize int    `schema:"sampleSize"`
+	UseKeys    bool   `schema:"useKeys"`
+	SchemaType string `schema:"schemaType"`
+	RecordName string `schema:"recordName"`
+	Namespace  string `schema:"namespace"`
+}
+
+// OK validates the user input for the infer schema request.
+func (i *inferSchemaRequest) OK() error {
+	if i.SampleSize < 0 || i.SampleSize > maxInferSchemaSampleSize {
+		return fmt.Errorf("sample size must be between 1 and %d", maxInferSchemaSampleSize)
+	}
+
+	switch i.SchemaType {
+	case "JSON":
+	case "AVRO":
+		if i.RecordName == "" {
+			return fmt.Errorf("record name must be set for avro schemas")
+		}
+	default:
+		return fmt.Errorf("schema type must be either JSON or AVRO")
+	}
+
+	return nil
+}
+
+func (api *API) handleInferTopicSchema() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		topicName := rest.GetURLParam(r, "topicName")
+		decoder := gorillaschema.NewDecoder()
+		req := &inferSchemaRequest{}
+		if err := decoder.Decode(req, r.URL.Query()); err != nil {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      err,
+				Status:   http.StatusBadRequest,
+				Message:  fmt.Sprintf("Failed to parse request parameters: %v", err.Error()),
+				IsSilent: false,
+			})
+			return
+		}
+		req.SchemaType = strings.ToUpper(req.SchemaType)
+		if req.SchemaType == "" {
+			req.SchemaType = "JSON"
+		}
+		if req.SampleSize == 0 {
+			req.SampleSize = defaultInferSchemaSampleSize
+		}
+		if err := req.OK(); err != nil {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      err,
+				Status:   http.StatusBadRequest,
+				Message:  fmt.Sprintf("Failed to validate request parameters: %v", err.Error()),
+				IsSilent: false,
+			})
+			return
+		}
+
+		// 2. Check if logged in user is allowed to view the messages that will be sampled
+		canViewMessages, restErr := api.Hooks.Authorization.CanViewTopicMessages(r.Context(), &ListMessagesRequest{
+			TopicName:   topicName,
+			StartOffset: console.StartOffsetRecent,
+			PartitionID: -1,
+			MaxResults:  req.SampleSize,
+		})
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !canViewMessages {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      fmt.Errorf("requester has no permissions to view messages in the requested topic"),
+				Status:   http.StatusForbidden,
+				Message:  "You don't have permissions to view messages in this topic",
+				IsSilent: false,
+			})
+			return
+		}
+
+		// 3. Sample records and infer schema
+		res, restErr := api.ConsoleSvc.InferSchema(r.Context(), console.InferSchemaRequest{
+			TopicName:  topicName,
+			SampleSize: req.SampleSize,
+			UseKeys:    req.UseKeys,
+			SchemaType: req.SchemaType,
+			RecordName: req.RecordName,
+			Namespace:  req.Namespace,
+		})
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
+	}
+}
+
+type registerSchemaRequest struct {
+	Schema     string             `json:"schema"`
+	SchemaType string             `json:"schemaType"`
+	References []schema.Reference `json:"references"`
+}
+
+// OK validates the user input for the register schema request.
+func (r *registerSchemaRequest) OK() error {
+	if r.Schema == "" {
+		return fmt.Errorf("schema must be set")
+	}
+
+	switch r.SchemaType {
+	case "", "AVRO", "PROTOBUF", "JSON":
+	default:
+		return fmt.Errorf("schema type must be one of AVRO, PROTOBUF or JSON")
+	}
+
+	return nil
+}
+
+func (api *API) handleRegisterSchema() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		subject := rest.GetURLParam(r, "subject")
+		if subjectUnescaped, err := url.PathUnescape(subject); err == nil {
+			subject = subjectUnescaped
+		}
+		var req registerSchemaRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Check if logged in user is allowed to register schemas for this subject
+		isAllowed, restErr := api.Hooks.Authorization.CanRegisterSchema(r.Context(), subject)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !isAllowed {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      fmt.Errorf("requester is not allowed to register schemas for subject %q", subject),
+				Status:   http.StatusForbidden,
+				Message:  "You are not allowed to register schemas for this subject",
+				IsSilent: false,
+			})
+			return
+		}
+
+		// 3. Register schema
+		res, err := api.ConsoleSvc.RegisterSchema(r.Context(), subject, schema.RegisterSchemaRequest{
+			Schema:     req.Schema,
+			SchemaType: req.SchemaType,
+			References: req.References,
+		})
+		if err != nil {
+			status := http.StatusServiceUnavailable
+			var registryErr *schema.RestError
+			switch {
+			case errors.Is(err, console.ErrSchemaRegistryNotConfigured):
+				status = http.StatusNotFound
+			case errors.As(err, &registryErr) && registryErr.ErrorCode == http.StatusConflict:
+				// The schema is incompatible with an earlier schema
+				status = http.StatusConflict
+			case errors.As(err, &registryErr) && registryErr.ErrorCode/100 == http.StatusUnprocessableEntity:
+				// The schema is invalid (error codes 42201 and following)
+				status = http.StatusUnprocessableEntity
+			}
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      err,
+				Status:   status,
+				Message:  fmt.Sprintf("Failed to register schema: %v", err.Error()),
+				IsSilent: false,
+			})
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
+	}
+}
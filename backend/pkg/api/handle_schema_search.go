@@ -0,0 +1,106 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/cloudhut/common/rest"
+	gorillaschema "github.com/gorilla/schema"
+
+	"github.com/redpanda-data/console/backend/pkg/console"
+	"github.com/redpanda-data/console/backend/pkg/schema"
+)
+
+type searchSchemasRequest struct {
+	Text       string `schema:"q"`
+	Kind       string `schema:"kind"`
+	Name       string `schema:"name"`
+	Type       string `schema:"type"`
+	Namespace  string `schema:"namespace"`
+	SchemaType string `schema:"schemaType"`
+	Subject    string `schema:"subject"`
+	Limit      int    `schema:"limit"`
+
+	// Refresh rebuilds the search index before searching.
+	Refresh bool `schema:"refresh"`
+}
+
+func (s *searchSchemasRequest) toQuery() schema.SearchQuery {
+	return schema.SearchQuery{
+		Text:       s.Text,
+		Kind:       schema.SearchElementKind(s.Kind),
+		Name:       s.Name,
+		Type:       s.Type,
+		Namespace:  s.Namespace,
+		SchemaType: s.SchemaType,
+		Subject:    s.Subject,
+		Limit:      s.Limit,
+	}
+}
+
+func (api *API) handleSearchSchemas() http.HandlerFunc {
+	type response struct {
+		*schema.SearchResult
+		IsConfigured bool `json:"isConfigured"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		decoder := gorillaschema.NewDecoder()
+		req := &searchSchemasRequest{}
+		if err := decoder.Decode(req, r.URL.Query()); err != nil {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      err,
+				Status:   http.StatusBadRequest,
+				Message:  fmt.Sprintf("Failed to parse request parameters: %v", err.Error()),
+				IsSilent: false,
+			})
+			return
+		}
+		query := req.toQuery()
+		if err := query.Validate(); err != nil {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      err,
+				Status:   http.StatusBadRequest,
+				Message:  fmt.Sprintf("Invalid search query: %v", err.Error()),
+				IsSilent: false,
+			})
+			return
+		}
+
+		// 2. Search schemas
+		result, err := api.ConsoleSvc.SearchSchemas(r.Context(), query, req.Refresh)
+		if err != nil {
+			if errors.Is(err, console.ErrSchemaRegistryNotConfigured) {
+				rest.SendResponse(w, r, api.Logger, http.StatusOK, &response{
+					SearchResult: nil,
+					IsConfigured: false,
+				})
+				return
+			}
+
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      err,
+				Status:   http.StatusServiceUnavailable,
+				Message:  fmt.Sprintf("Schema search has failed: %v", err.Error()),
+				IsSilent: false,
+			})
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, &response{
+			SearchResult: result,
+			IsConfigured: true,
+		})
+	}
+}
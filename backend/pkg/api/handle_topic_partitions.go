@@ -0,0 +1,97 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/cloudhut/common/rest"
+
+	"github.com/redpanda-data/console/backend/pkg/console"
+)
+
+// createTopicPartitionsRequest defines the expected JSON body to increase the partition count
+// of a topic.
+type createTopicPartitionsRequest struct {
+	// PartitionCount is the new total number of partitions.
+	PartitionCount int32 `json:"partitionCount"`
+
+	// Assignments optionally contains the replica broker ids for each new partition.
+	Assignments [][]int32 `json:"assignments"`
+
+	// ValidateOnly validates the request without creating any partitions.
+	ValidateOnly bool `json:"validateOnly"`
+}
+
+// OK validates the individual fields.
+func (c *createTopicPartitionsRequest) OK() error {
+	if c.PartitionCount < 1 {
+		return fmt.Errorf("partition count must be at least 1")
+	}
+
+	for i, replicas := range c.Assignments {
+		if len(replicas) == 0 {
+			return fmt.Errorf("assignment at index %d must contain at least one replica", i)
+		}
+		seen := make(map[int32]struct{}, len(replicas))
+		for _, brokerID := range replicas {
+			if _, exists := seen[brokerID]; exists {
+				return fmt.Errorf("assignment at index %d contains broker id %d more than once", i, brokerID)
+			}
+			seen[brokerID] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+func (api *API) handleCreateTopicPartitions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		topicName := rest.GetURLParam(r, "topicName")
+		var req createTopicPartitionsRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Check if logged in user is allowed to add partitions to the given topic
+		canCreate, restErr := api.Hooks.Authorization.CanCreateTopicPartitions(r.Context(), topicName)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !canCreate {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      fmt.Errorf("requester has no permissions to create partitions for this topic"),
+				Status:   http.StatusForbidden,
+				Message:  "You don't have permissions to create partitions for this topic",
+				IsSilent: false,
+			})
+			return
+		}
+
+		// 3. Increase partition count
+		res, restErr := api.ConsoleSvc.CreateTopicPartitions(r.Context(), console.CreateTopicPartitionsRequest{
+			TopicName:      topicName,
+			PartitionCount: req.PartitionCount,
+			Assignments:    req.Assignments,
+			ValidateOnly:   req.ValidateOnly,
+		})
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/cloudhut/common/rest"
+
+	"github.com/redpanda-data/console/backend/pkg/console"
+)
+
+func (api *API) handlePlanTopicState() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Check if logged in user is allowed to view the topic state
+		isAllowed, restErr := api.Hooks.Authorization.CanViewTopicState(r.Context())
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !isAllowed {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      fmt.Errorf("requester has no permissions to view the topic state"),
+				Status:   http.StatusForbidden,
+				Message:  "You don't have permissions to view the topic state",
+				IsSilent: false,
+			})
+			return
+		}
+
+		// 2. Compare desired with live state
+		overrideDeletionLimit := rest.GetQueryParam(r, "overrideDeletionLimit") == "true"
+		plan, restErr := api.ConsoleSvc.PlanTopicState(r.Context(), overrideDeletionLimit)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, plan)
+	}
+}
+
+type applyTopicStateRequest struct {
+	// PlanID is the ID of the reviewed plan. The plan is only applied if it hasn't changed since.
+	PlanID string `json:"planId"`
+
+	// Comment is recorded in the audit log, e.g. who approved the plan and why.
+	Comment string `json:"comment"`
+
+	// OverrideDeletionLimit allows plans to delete more topics than configured. It must
+	// match the value the plan has been reviewed with.
+	OverrideDeletionLimit bool `json:"overrideDeletionLimit"`
+}
+
+func (a *applyTopicStateRequest) OK() error {
+	if a.PlanID == "" {
+		return fmt.Errorf("plan id must be set")
+	}
+	return nil
+}
+
+func (api *API) handleApplyTopicState() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var req applyTopicStateRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Check if logged in user is allowed to apply the topic state
+		isAllowed, restErr := api.Hooks.Authorization.CanApplyTopicState(r.Context())
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !isAllowed {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      fmt.Errorf("requester has no permissions to apply the topic state"),
+				Status:   http.StatusForbidden,
+				Message:  "You don't have permissions to apply the topic state",
+				IsSilent: false,
+			})
+			return
+		}
+
+		// 3. Apply plan
+		entry, restErr := api.ConsoleSvc.ApplyTopicState(r.Context(), console.ApplyTopicStateRequest{
+			PlanID:                req.PlanID,
+			Comment:               req.Comment,
+			Requester:             api.Hooks.Console.Requester(r.Context()),
+			OverrideDeletionLimit: req.OverrideDeletionLimit,
+		})
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, entry)
+	}
+}
+
+func (api *API) handleGetTopicStateAuditLog() http.HandlerFunc {
+	type response struct {
+		Entries []console.TopicStateAuditLogEntry `json:"entries"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Check if logged in user is allowed to view the topic state
+		isAllowed, restErr := api.Hooks.Authorization.CanViewTopicState(r.Context())
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !isAllowed {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      fmt.Errorf("requester has no permissions to view the topic state"),
+				Status:   http.StatusForbidden,
+				Message:  "You don't have permissions to view the topic state",
+				IsSilent: false,
+			})
+			return
+		}
+
+		// 2. Return applied plans
+		entries, restErr := api.ConsoleSvc.GetTopicStateAuditLog()
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, response{Entries: entries})
+	}
+}
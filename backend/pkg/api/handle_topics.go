@@ -125,7 +125,8 @@ func (api *API) handleGetPartitions() http.HandlerFunc {
 // handleGetTopicConfig returns all set configuration options for a specific topic
 func (api *API) handleGetTopicConfig() http.HandlerFunc {
 	type response struct {
-		TopicDescription *console.TopicConfig `json:"topicDescription"`
+		TopicDescription *console.TopicConfig       `json:"topicDescription"`
+		LintFindings     []console.TopicLintFinding `json:"lintFindings"`
 	}
 
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -157,6 +158,7 @@ func (api *API) handleGetTopicConfig() http.HandlerFunc {
 
 		res := response{
 			TopicDescription: description,
+			LintFindings:     api.ConsoleSvc.LintTopicConfig(description),
 		}
 		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
 	}
@@ -461,6 +463,76 @@ func (api *API) handleGetTopicsConfigs() http.HandlerFunc {
 	}
 }
 
+// handleLintTopicsConfigs returns a lint report for the requested topics. If no topics are
+// requested, all topics whose config the user is allowed to view are linted.
+func (api *API) handleLintTopicsConfigs() http.HandlerFunc {
+	type response struct {
+		Reports []console.TopicLintReport `json:"reports"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse optional topic filter
+		var topicNames []string
+		requestedTopicNames := rest.GetQueryParam(r, "topicNames")
+		if requestedTopicNames != "" {
+			topicNames = strings.Split(requestedTopicNames, ",")
+		}
+		isFiltered := len(topicNames) > 0
+
+		// 2. Fetch all topic names from metadata as no topic filter has been specified
+		if !isFiltered {
+			var err error
+			topicNames, err = api.ConsoleSvc.GetAllTopicNames(r.Context(), nil)
+			if err != nil {
+				rest.SendRESTError(w, r, api.Logger, &rest.Error{
+					Err:      fmt.Errorf("failed to request metadata to fetch topic names: %w", err),
+					Status:   http.StatusServiceUnavailable,
+					Message:  fmt.Sprintf("Failed to fetch metadata from brokers to fetch topicNames '%v'", err.Error()),
+					IsSilent: false,
+				})
+				return
+			}
+		}
+
+		// 3. Check if user is allowed to view the config for these topics. Explicitly requested
+		// topics must all be allowed, otherwise topics the user can't view are skipped.
+		allowedTopicNames := make([]string, 0, len(topicNames))
+		for _, topicName := range topicNames {
+			canView, restErr := api.Hooks.Authorization.CanViewTopicConfig(r.Context(), topicName)
+			if restErr != nil {
+				rest.SendRESTError(w, r, api.Logger, restErr)
+				return
+			}
+			if canView {
+				allowedTopicNames = append(allowedTopicNames, topicName)
+				continue
+			}
+			if isFiltered {
+				rest.SendRESTError(w, r, api.Logger, &rest.Error{
+					Err:      fmt.Errorf("requester has no permissions to view config for one of the requested topics"),
+					Status:   http.StatusForbidden,
+					Message:  fmt.Sprintf("You don't have permissions to view the config for topic '%v'", topicName),
+					IsSilent: false,
+				})
+				return
+			}
+		}
+
+		// 4. Lint topic configs
+		reports := make([]console.TopicLintReport, 0)
+		if len(allowedTopicNames) > 0 {
+			var restErr *rest.Error
+			reports, restErr = api.ConsoleSvc.LintTopicsConfigs(r.Context(), allowedTopicNames)
+			if restErr != nil {
+				rest.SendRESTError(w, r, api.Logger, restErr)
+				return
+			}
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, response{Reports: reports})
+	}
+}
+
 // handleGetTopicConsumers returns all consumers along with their summed lag which consume the given topic
 func (api *API) handleGetTopicConsumers() http.HandlerFunc {
 	type response struct {
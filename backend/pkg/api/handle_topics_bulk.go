@@ -0,0 +1,303 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kmsg"
+
+	"github.com/redpanda-data/console/backend/pkg/console"
+)
+
+// bulkTopicRequest is the common part of all bulk topic operations. Running an operation
+// requires the confirmation token of a previous dry run with the same selection and parameters.
+type bulkTopicRequest struct {
+	Selector          console.TopicSelector `json:"selector"`
+	DryRun            bool                  `json:"dryRun"`
+	ConfirmationToken string                `json:"confirmationToken"`
+}
+
+// OK validates the user input for a bulk topic operation.
+func (b *bulkTopicRequest) OK() error {
+	if err := b.Selector.Validate(); err != nil {
+		return fmt.Errorf("invalid topic selector: %w", err)
+	}
+	if !b.DryRun && b.ConfirmationToken == "" {
+		return fmt.Errorf("a dry run is mandatory, pass the confirmation token of the dry run to run the operation")
+	}
+	return nil
+}
+
+type bulkEditTopicsConfigRequest struct {
+	bulkTopicRequest
+	editTopicConfigRequest
+}
+
+// OK validates the user input for the bulk edit topics config request.
+func (b *bulkEditTopicsConfigRequest) OK() error {
+	if err := b.bulkTopicRequest.OK(); err != nil {
+		return err
+	}
+	return b.editTopicConfigRequest.OK()
+}
+
+type bulkDeleteTopicsRecordsRequest struct {
+	bulkTopicRequest
+	console.RecordsDeletionTarget
+}
+
+// OK validates the user input for the bulk delete topics records request.
+func (b *bulkDeleteTopicsRecordsRequest) OK() error {
+	if err := b.bulkTopicRequest.OK(); err != nil {
+		return err
+	}
+	return b.RecordsDeletionTarget.Validate()
+}
+
+type bulkTopicResponse struct {
+	Operation           string                    `json:"operation"`
+	DryRun              bool                      `json:"dryRun"`
+	ConfirmationToken   string                    `json:"confirmationToken"`
+	SelectedTopicNames  []string                  `json:"-"`
+	UnmatchedTopicNames []string                  `json:"unmatchedTopicNames"`
+	Results             []console.BulkTopicResult `json:"results"`
+}
+
+func (api *API) handleBulkDeleteTopics() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var req bulkTopicRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Select topics and check if logged in user is allowed to delete each of them
+		res, allowedTopics, ok := api.prepareBulkTopicOperation(w, r, req, console.BulkTopicOperationDelete,
+			api.Hooks.Authorization.CanDeleteTopic)
+		if !ok || !api.confirmBulkTopicOperation(w, r, req, res, nil) {
+			return
+		}
+
+		// 3. Delete topics
+		if req.DryRun {
+			res.Results = append(res.Results, dryRunBulkTopicResults(allowedTopics)...)
+		} else if len(allowedTopics) > 0 {
+			res.Results = append(res.Results, api.ConsoleSvc.DeleteTopics(r.Context(), allowedTopics)...)
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
+	}
+}
+
+func (api *API) handleBulkEditTopicsConfig() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var req bulkEditTopicsConfigRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Select topics and check if logged in user is allowed to edit each topic's config
+		res, allowedTopics, ok := api.prepareBulkTopicOperation(w, r, req.bulkTopicRequest, console.BulkTopicOperationEditConfigs,
+			api.Hooks.Authorization.CanEditTopicConfig)
+		if !ok || !api.confirmBulkTopicOperation(w, r, req.bulkTopicRequest, res, req.Configs) {
+			return
+		}
+
+		// 3. Edit topic configs. A dry run lets the brokers validate the changes.
+		if len(allowedTopics) > 0 {
+			configs := make([]kmsg.IncrementalAlterConfigsRequestResourceConfig, 0, len(req.Configs))
+			for _, cfg := range req.Configs {
+				resourceCfg := kmsg.NewIncrementalAlterConfigsRequestResourceConfig()
+				resourceCfg.Name = cfg.Key
+				resourceCfg.Op = cfg.Op
+				resourceCfg.Value = cfg.Value
+				configs = append(configs, resourceCfg)
+			}
+
+			results := api.ConsoleSvc.EditTopicsConfigs(r.Context(), allowedTopics, configs, req.DryRun)
+			if req.DryRun {
+				for i := range results {
+					if results[i].Status == console.BulkTopicStatusSucceeded {
+						results[i].Status = console.BulkTopicStatusDryRun
+					}
+				}
+			}
+			res.Results = append(res.Results, results...)
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
+	}
+}
+
+func (api *API) handleBulkDeleteTopicsRecords() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var req bulkDeleteTopicsRecordsRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Select topics and check if logged in user is allowed to delete records in each of them
+		res, allowedTopics, ok := api.prepareBulkTopicOperation(w, r, req.bulkTopicRequest, console.BulkTopicOperationDeleteRecords,
+			api.Hooks.Authorization.CanDeleteTopicRecords)
+		if !ok {
+			return
+		}
+
+		// 3. Resolve the offsets to delete records before. The resolved offsets are part of the
+		// confirmation token, so that records produced after the dry run are never deleted.
+		plan := make([]console.BulkTopicResult, 0)
+		if len(allowedTopics) > 0 {
+			plan, restErr = api.ConsoleSvc.PlanTopicsRecordsDeletion(r.Context(), allowedTopics, req.RecordsDeletionTarget)
+			if restErr != nil {
+				rest.SendRESTError(w, r, api.Logger, restErr)
+				return
+			}
+		}
+		params := struct {
+			Target       console.RecordsDeletionTarget `json:"target"`
+			DeleteBefore map[string]map[int32]int64    `json:"deleteBefore"`
+		}{req.RecordsDeletionTarget, recordsDeletionOffsets(plan)}
+		if !api.confirmBulkTopicOperation(w, r, req.bulkTopicRequest, res, params) {
+			return
+		}
+
+		// 4. Delete the records
+		if req.DryRun {
+			for i := range plan {
+				plan[i].Status = console.BulkTopicStatusDryRun
+			}
+			res.Results = append(res.Results, plan...)
+		} else if len(plan) > 0 {
+			res.Results = append(res.Results, api.ConsoleSvc.DeleteTopicsRecords(r.Context(), plan)...)
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
+	}
+}
+
+// prepareBulkTopicOperation selects the topics and checks whether the logged in user is allowed to
+// run the operation on each topic. It returns the response including all forbidden topics along
+// with the allowed topics. The confirmation token must be verified with confirmBulkTopicOperation
+// afterwards. If ok is false, an error has already been sent.
+func (api *API) prepareBulkTopicOperation(
+	w http.ResponseWriter,
+	r *http.Request,
+	req bulkTopicRequest,
+	operation string,
+	isAllowed func(ctx context.Context, topicName string) (bool, *rest.Error),
+) (res *bulkTopicResponse, allowedTopics []string, ok bool) {
+	selection, restErr := api.ConsoleSvc.SelectTopics(r.Context(), req.Selector)
+	if restErr != nil {
+		rest.SendRESTError(w, r, api.Logger, restErr)
+		return nil, nil, false
+	}
+
+	// Kowl business hook - topics the user can't see are treated as if they didn't exist, so that
+	// neither a regex nor a list of topic names reveals them
+	visibleTopics := make([]string, 0, len(selection.TopicNames))
+	for _, topicName := range selection.TopicNames {
+		canSee, restErr := api.Hooks.Authorization.CanSeeTopic(r.Context(), topicName)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return nil, nil, false
+		}
+		if canSee {
+			visibleTopics = append(visibleTopics, topicName)
+		} else if len(req.Selector.TopicNames) > 0 {
+			selection.UnmatchedTopicNames = append(selection.UnmatchedTopicNames, topicName)
+		}
+	}
+	selection.TopicNames = visibleTopics
+	sort.Strings(selection.UnmatchedTopicNames)
+
+	res = &bulkTopicResponse{
+		Operation:           operation,
+		DryRun:              req.DryRun,
+		SelectedTopicNames:  selection.TopicNames,
+		UnmatchedTopicNames: selection.UnmatchedTopicNames,
+		Results:             make([]console.BulkTopicResult, 0, len(selection.TopicNames)),
+	}
+	allowedTopics = make([]string, 0, len(selection.TopicNames))
+	for _, topicName := range selection.TopicNames {
+		allowed, restErr := isAllowed(r.Context(), topicName)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return nil, nil, false
+		}
+		if !allowed {
+			res.Results = append(res.Results, console.BulkTopicResult{
+				TopicName: topicName,
+				Status:    console.BulkTopicStatusForbidden,
+				Error:     "You don't have permissions to run this operation on this topic",
+			})
+			continue
+		}
+		allowedTopics = append(allowedTopics, topicName)
+	}
+	return res, allowedTopics, true
+}
+
+// confirmBulkTopicOperation computes the confirmation token of the selected topics, the operation
+// and its parameters and adds it to the response. Unless it's a dry run, the token must match the
+// token passed by the client. If false is returned, an error has already been sent.
+func (api *API) confirmBulkTopicOperation(w http.ResponseWriter, r *http.Request, req bulkTopicRequest, res *bulkTopicResponse, params interface{}) bool {
+	token := api.ConsoleSvc.BulkTopicOperationToken(res.Operation, res.SelectedTopicNames, params)
+	if !req.DryRun && !hmac.Equal([]byte(token), []byte(req.ConfirmationToken)) {
+		rest.SendRESTError(w, r, api.Logger, &rest.Error{
+			Err:      fmt.Errorf("confirmation token %q does not match the current token", req.ConfirmationToken),
+			Status:   http.StatusConflict,
+			Message:  "The selected topics, the operation or the affected records differ from the dry run, please run and review a new dry run",
+			IsSilent: false,
+		})
+		return false
+	}
+	res.ConfirmationToken = token
+	return true
+}
+
+// recordsDeletionOffsets returns the planned offset to delete records before for each partition
+// without planning errors.
+func recordsDeletionOffsets(plan []console.BulkTopicResult) map[string]map[int32]int64 {
+	offsets := make(map[string]map[int32]int64, len(plan))
+	for _, topic := range plan {
+		for _, partition := range topic.Partitions {
+			if partition.Error != "" {
+				continue
+			}
+			if _, exists := offsets[topic.TopicName]; !exists {
+				offsets[topic.TopicName] = make(map[int32]int64)
+			}
+			offsets[topic.TopicName][partition.PartitionID] = partition.DeleteBefore
+		}
+	}
+	return offsets
+}
+
+func dryRunBulkTopicResults(topicNames []string) []console.BulkTopicResult {
+	results := make([]console.BulkTopicResult, len(topicNames))
+	for i, topicName := range topicNames {
+		results[i] = console.BulkTopicResult{TopicName: topicName, Status: console.BulkTopicStatusDryRun}
+	}
+	return results
+}
@@ -0,0 +1,69 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kmsg"
+
+	"github.com/redpanda-data/console/backend/pkg/console"
+)
+
+func TestBulkEditTopicsConfigRequest(t *testing.T) {
+	var req bulkEditTopicsConfigRequest
+	err := json.Unmarshal([]byte(`{
+		"selector": {"prefix": "test-"},
+		"dryRun": true,
+		"configs": [{"key": "retention.ms", "op": "SET", "value": "3600000"}]
+	}`), &req)
+	require.NoError(t, err)
+
+	assert.Equal(t, "test-", req.Selector.Prefix)
+	assert.True(t, req.DryRun)
+	require.Len(t, req.Configs, 1)
+	assert.Equal(t, kmsg.IncrementalAlterConfigOpSet, req.Configs[0].Op)
+	assert.NoError(t, req.OK())
+
+	// Running the operation requires the token of a dry run
+	req.DryRun = false
+	assert.Error(t, req.OK())
+	req.ConfirmationToken = "0123456789abcdef"
+	assert.NoError(t, req.OK())
+}
+
+func TestBulkDeleteTopicsRecordsRequest(t *testing.T) {
+	var req bulkDeleteTopicsRecordsRequest
+	err := json.Unmarshal([]byte(`{"selector": {"regex": "^test-.*"}, "dryRun": true, "timestamp": 1685577600000}`), &req)
+	require.NoError(t, err)
+
+	require.NotNil(t, req.Timestamp)
+	assert.Equal(t, int64(1685577600000), *req.Timestamp)
+	assert.NoError(t, req.OK())
+
+	req.Selector.TopicNames = []string{"orders"}
+	assert.Error(t, req.OK())
+}
+
+func TestRecordsDeletionOffsets(t *testing.T) {
+	offsets := recordsDeletionOffsets([]console.BulkTopicResult{
+		{TopicName: "orders", Partitions: []console.BulkTopicPartitionResult{
+			{PartitionID: 0, LowWaterMark: 5, HighWaterMark: 100, DeleteBefore: 100},
+			{PartitionID: 1, Error: "failed to list watermarks"},
+		}},
+		{TopicName: "empty", Partitions: []console.BulkTopicPartitionResult{}},
+	})
+
+	// Partitions with errors are not deleted, hence they are not part of the confirmation
+	assert.Equal(t, map[string]map[int32]int64{"orders": {0: 100}}, offsets)
+}
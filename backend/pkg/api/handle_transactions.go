@@ -0,0 +1,237 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudhut/common/rest"
+
+	"github.com/redpanda-data/console/backend/pkg/console"
+)
+
+// authorizeListTransactions checks whether the logged in user is allowed to inspect transactions.
+// A REST error is sent if not.
+func (api *API) authorizeListTransactions(w http.ResponseWriter, r *http.Request) bool {
+	isAllowed, restErr := api.Hooks.Authorization.CanListTransactions(r.Context())
+	if restErr != nil {
+		rest.SendRESTError(w, r, api.Logger, restErr)
+		return false
+	}
+	if !isAllowed {
+		rest.SendRESTError(w, r, api.Logger, &rest.Error{
+			Err:      fmt.Errorf("requester has no permissions to list transactions"),
+			Status:   http.StatusForbidden,
+			Message:  "You don't have permissions to list transactions",
+			IsSilent: false,
+		})
+		return false
+	}
+	return true
+}
+
+func (api *API) handleListTransactions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse optional state filter
+		var states []string
+		requestedStates := rest.GetQueryParam(r, "states")
+		if requestedStates != "" {
+			states = strings.Split(requestedStates, ",")
+		}
+
+		// 2. Check if logged in user is allowed to list transactions
+		if !api.authorizeListTransactions(w, r) {
+			return
+		}
+
+		// 3. List and describe transactions
+		overview, restErr := api.ConsoleSvc.ListTransactions(r.Context(), states)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// Kowl business hook - only include topics the user is allowed to see
+		for i, txn := range overview.Transactions {
+			visibleTopics := make([]console.TransactionTopic, 0, len(txn.Topics))
+			for _, topic := range txn.Topics {
+				canSee, restErr := api.Hooks.Authorization.CanSeeTopic(r.Context(), topic.TopicName)
+				if restErr != nil {
+					rest.SendRESTError(w, r, api.Logger, restErr)
+					return
+				}
+				if canSee {
+					visibleTopics = append(visibleTopics, topic)
+				}
+			}
+			overview.Transactions[i].Topics = visibleTopics
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, overview)
+	}
+}
+
+func (api *API) handleGetTopicProducers() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		topicName := rest.GetURLParam(r, "topicName")
+
+		// Check if logged in user is allowed to view partitions of the given topic
+		canView, restErr := api.Hooks.Authorization.CanViewTopicPartitions(r.Context(), topicName)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !canView {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      fmt.Errorf("requester has no permissions to view partitions for the requested topic"),
+				Status:   http.StatusForbidden,
+				Message:  "You don't have permissions to view partitions for that topic",
+				IsSilent: false,
+			})
+			return
+		}
+
+		producers, restErr := api.ConsoleSvc.GetTopicProducers(r.Context(), topicName)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, producers)
+	}
+}
+
+func (api *API) handleFindHangingTransactions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var topicNames []string
+		requestedTopicNames := rest.GetQueryParam(r, "topicNames")
+		if requestedTopicNames != "" {
+			topicNames = strings.Split(requestedTopicNames, ",")
+		}
+
+		maxTransactionTimeout := console.DefaultMaxTransactionTimeout
+		if timeoutStr := rest.GetQueryParam(r, "maxTransactionTimeoutMs"); timeoutStr != "" {
+			timeoutMs, err := strconv.ParseInt(timeoutStr, 10, 64)
+			if err != nil || timeoutMs <= 0 {
+				rest.SendRESTError(w, r, api.Logger, &rest.Error{
+					Err:      fmt.Errorf("failed to parse max transaction timeout %q", timeoutStr),
+					Status:   http.StatusBadRequest,
+					Message:  "maxTransactionTimeoutMs must be a positive number",
+					IsSilent: true,
+				})
+				return
+			}
+			maxTransactionTimeout = time.Duration(timeoutMs) * time.Millisecond
+		}
+
+		// 2. Check if logged in user is allowed to list transactions
+		if !api.authorizeListTransactions(w, r) {
+			return
+		}
+
+		// 3. Search hanging transactions
+		hanging, restErr := api.ConsoleSvc.FindHangingTransactions(r.Context(), topicNames, maxTransactionTimeout)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// Kowl business hook - only include topics the user is allowed to see
+		visible := make([]console.HangingTransaction, 0, len(hanging.Transactions))
+		for _, txn := range hanging.Transactions {
+			canSee, restErr := api.Hooks.Authorization.CanSeeTopic(r.Context(), txn.TopicName)
+			if restErr != nil {
+				rest.SendRESTError(w, r, api.Logger, restErr)
+				return
+			}
+			if canSee {
+				visible = append(visible, txn)
+			}
+		}
+		hanging.Transactions = visible
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, hanging)
+	}
+}
+
+type abortTransactionRequest struct {
+	TopicName   string `json:"topicName"`
+	PartitionID int32  `json:"partitionId"`
+	StartOffset int64  `json:"startOffset"`
+
+	// MaxTransactionTimeoutMs is used to check whether the transaction is hanging. Defaults to
+	// Kafka's default for transaction.max.timeout.ms.
+	MaxTransactionTimeoutMs int64 `json:"maxTransactionTimeoutMs"`
+
+	// Force aborts the transaction even if it is not hanging.
+	Force bool `json:"force"`
+}
+
+func (a *abortTransactionRequest) OK() error {
+	if a.TopicName == "" {
+		return fmt.Errorf("topic name must be set")
+	}
+	if a.PartitionID < 0 {
+		return fmt.Errorf("partition id must not be negative")
+	}
+	if a.StartOffset < 0 {
+		return fmt.Errorf("start offset of the transaction must not be negative")
+	}
+	if a.MaxTransactionTimeoutMs < 0 {
+		return fmt.Errorf("max transaction timeout must not be negative")
+	}
+	return nil
+}
+
+func (api *API) handleAbortTransaction() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var req abortTransactionRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Check if logged in user is allowed to abort transactions on this topic
+		isAllowed, restErr := api.Hooks.Authorization.CanAbortTransaction(r.Context(), req.TopicName)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !isAllowed {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      fmt.Errorf("requester has no permissions to abort transactions on topic %q", req.TopicName),
+				Status:   http.StatusForbidden,
+				Message:  "You don't have permissions to abort transactions on this topic",
+				IsSilent: false,
+			})
+			return
+		}
+
+		// 3. Abort transaction
+		maxTransactionTimeout := console.DefaultMaxTransactionTimeout
+		if req.MaxTransactionTimeoutMs > 0 {
+			maxTransactionTimeout = time.Duration(req.MaxTransactionTimeoutMs) * time.Millisecond
+		}
+		res, restErr := api.ConsoleSvc.AbortTransaction(r.Context(), req.TopicName, req.PartitionID, req.StartOffset, maxTransactionTimeout, req.Force)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, res)
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cloudhut/common/rest"
+
+	"github.com/redpanda-data/console/backend/pkg/console"
+)
+
+type deleteUnusedResourcesRequest struct {
+	TopicNames       []string `json:"topicNames"`
+	ConsumerGroupIDs []string `json:"consumerGroupIds"`
+
+	// DryRun defaults to true, resources are only deleted if it is explicitly set to false.
+	DryRun            *bool `json:"dryRun"`
+	TopicInactiveDays int   `json:"topicInactiveDays"`
+}
+
+// OK validates the user input for the delete unused resources request.
+func (d *deleteUnusedResourcesRequest) OK() error {
+	if len(d.TopicNames) == 0 && len(d.ConsumerGroupIDs) == 0 {
+		return fmt.Errorf("at least one topic name or consumer group id must be set")
+	}
+	_, err := unusedResourcesOptions(d.TopicInactiveDays)
+	return err
+}
+
+func (d *deleteUnusedResourcesRequest) isDryRun() bool {
+	return d.DryRun == nil || *d.DryRun
+}
+
+// unusedResourcesOptions converts the topic inactivity window given in days. Zero selects the
+// default. Consumer groups are only reported as stale if they have never committed offsets,
+// hence there is no window for them.
+func unusedResourcesOptions(topicInactiveDays int) (console.UnusedResourcesOptions, error) {
+	if topicInactiveDays < 0 {
+		return console.UnusedResourcesOptions{}, fmt.Errorf("inactive days must not be negative")
+	}
+	opts := console.UnusedResourcesOptions{TopicInactivityWindow: console.DefaultTopicInactivityWindow}
+	if topicInactiveDays > 0 {
+		opts.TopicInactivityWindow = time.Duration(topicInactiveDays) * 24 * time.Hour
+	}
+	return opts, nil
+}
+
+func (api *API) handleGetUnusedResources() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		topicInactiveDays := 0
+		if value := rest.GetQueryParam(r, "topicInactiveDays"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				rest.SendRESTError(w, r, api.Logger, &rest.Error{
+					Err:      fmt.Errorf("failed to parse topicInactiveDays: %w", err),
+					Status:   http.StatusBadRequest,
+					Message:  "Query parameter topicInactiveDays must be a number of days",
+					IsSilent: false,
+				})
+				return
+			}
+			topicInactiveDays = parsed
+		}
+		opts, err := unusedResourcesOptions(topicInactiveDays)
+		if err != nil {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:      err,
+				Status:   http.StatusBadRequest,
+				Message:  err.Error(),
+				IsSilent: false,
+			})
+			return
+		}
+
+		// 2. Analyze topics and consumer groups
+		report, restErr := api.ConsoleSvc.GetUnusedResources(r.Context(), opts)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 3. Only include topics and consumer groups the user is allowed to see, also in warnings
+		restErr = report.FilterResources(func(resourceType string, name string) (bool, *rest.Error) {
+			return api.canSeeHealthFindingResource(r, console.HealthFindingResource{Type: resourceType, Name: name})
+		})
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, report)
+	}
+}
+
+func (api *API) handleDeleteUnusedResources() http.HandlerFunc {
+	type response struct {
+		DryRun  bool                             `json:"dryRun"`
+		Results []console.UnusedResourceDeletion `json:"results"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		var req deleteUnusedResourcesRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		opts, _ := unusedResourcesOptions(req.TopicInactiveDays)
+
+		// 2. Check if logged in user is allowed to delete each of the topics and consumer groups
+		forbidden := make([]console.UnusedResourceDeletion, 0)
+		allowedGroups := make([]string, 0, len(req.ConsumerGroupIDs))
+		for _, groupID := range req.ConsumerGroupIDs {
+			isAllowed, restErr := api.Hooks.Authorization.CanDeleteConsumerGroup(r.Context(), groupID)
+			if restErr != nil {
+				rest.SendRESTError(w, r, api.Logger, restErr)
+				return
+			}
+			if !isAllowed {
+				forbidden = append(forbidden, console.UnusedResourceDeletion{
+					Type:    console.HealthResourceTypeConsumerGroup,
+					Name:    groupID,
+					Skipped: "You don't have permissions to delete this consumer group",
+				})
+				continue
+			}
+			allowedGroups = append(allowedGroups, groupID)
+		}
+		allowedTopics := make([]string, 0, len(req.TopicNames))
+		for _, topicName := range req.TopicNames {
+			isAllowed, restErr := api.Hooks.Authorization.CanDeleteTopic(r.Context(), topicName)
+			if restErr != nil {
+				rest.SendRESTError(w, r, api.Logger, restErr)
+				return
+			}
+			if !isAllowed {
+				forbidden = append(forbidden, console.UnusedResourceDeletion{
+					Type:    console.HealthResourceTypeTopic,
+					Name:    topicName,
+					Skipped: "You don't have permissions to delete this topic",
+				})
+				continue
+			}
+			allowedTopics = append(allowedTopics, topicName)
+		}
+
+		// 3. Delete all allowed resources that are still unused
+		results, restErr := api.ConsoleSvc.DeleteUnusedResources(r.Context(), allowedTopics, allowedGroups, opts, req.isDryRun())
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		rest.SendResponse(w, r, api.Logger, http.StatusOK, response{
+			DryRun:  req.isDryRun(),
+			Results: append(results, forbidden...),
+		})
+	}
+}
@@ -15,8 +15,52 @@ import (
 
 	"github.com/cloudhut/common/rest"
 	"github.com/redpanda-data/redpanda/src/go/rpk/pkg/api/admin"
+
+	"github.com/redpanda-data/console/backend/pkg/console"
 )
 
+// errUserManagementNotSupported is sent if users can neither be managed via the Redpanda Admin API nor via
+// SCRAM requests on the Kafka API.
+var errUserManagementNotSupported = &rest.Error{
+	Err:     fmt.Errorf("redpanda Admin API is not enabled and the Kafka API does not support SCRAM requests"),
+	Status:  http.StatusServiceUnavailable,
+	Message: "Redpanda Admin API is not enabled and the Kafka cluster does not support managing SCRAM users",
+}
+
+// isKafkaUserManagementSupported checks via the endpoint compatibility whether users can be managed with
+// SCRAM requests on the Kafka API. The second return value is false if a REST error has been sent.
+func (api *API) isKafkaUserManagementSupported(w http.ResponseWriter, r *http.Request, method string) (bool, bool) {
+	compatibility, err := api.ConsoleSvc.GetEndpointCompatibility(r.Context())
+	if err != nil {
+		rest.SendRESTError(w, r, api.Logger, &rest.Error{
+			Err:     err,
+			Status:  http.StatusServiceUnavailable,
+			Message: fmt.Sprintf("Failed to check whether the Kafka cluster supports managing users: %v", err.Error()),
+		})
+		return false, false
+	}
+	return compatibility.IsSupported("/api/users", method), true
+}
+
+// validateSCRAMCredentials validates the password, mechanism and iterations of a user request.
+func validateSCRAMCredentials(password, mechanism string, iterations int32) error {
+	if password == "" {
+		return fmt.Errorf("password must be set")
+	}
+
+	switch mechanism {
+	case admin.ScramSha256, admin.ScramSha512:
+	default:
+		return fmt.Errorf("mechanism must be either SCRAM-SHA-256 or SCRAM-SHA-512")
+	}
+
+	if iterations != 0 && (iterations < console.SCRAMMinIterations || iterations > console.SCRAMMaxIterations) {
+		return fmt.Errorf("iterations must be between %d and %d", console.SCRAMMinIterations, console.SCRAMMaxIterations)
+	}
+
+	return nil
+}
+
 // handleGetUsers returns a list of Kafka users. Via the Kafka API we can only return users if our
 // target Kafka cluster version is >2.7.0. If the target Kafka cluster version is <2.7.0, we return an
 // empty array.
@@ -27,6 +71,8 @@ func (api *API) handleGetUsers() http.HandlerFunc {
 	type response struct {
 		Users      []string `json:"users"`
 		IsComplete bool     `json:"isComplete"`
+		// SCRAMUsers contains the mechanisms and iterations of each user, if the users were listed via the Kafka API.
+		SCRAMUsers []console.SCRAMUser `json:"scramUsers,omitempty"`
 	}
 	return func(w http.ResponseWriter, r *http.Request) {
 		// 1. Check if logged-in user is allowed to list Kafka users
@@ -70,6 +116,34 @@ func (api *API) handleGetUsers() http.HandlerFunc {
 			return
 		}
 
+		// 2. List SCRAM users via the Kafka API if the cluster supports it
+		isSupported, ok := api.isKafkaUserManagementSupported(w, r, http.MethodGet)
+		if !ok {
+			return
+		}
+		if isSupported {
+			scramUsers, restErr := api.ConsoleSvc.ListSCRAMUsers(r.Context())
+			if restErr != nil {
+				rest.SendRESTError(w, r, api.Logger, restErr)
+				return
+			}
+			filteredUsers := make([]string, 0, len(scramUsers))
+			filteredSCRAMUsers := make([]console.SCRAMUser, 0, len(scramUsers))
+			for _, user := range scramUsers {
+				if api.Hooks.Authorization.IsProtectedKafkaUser(user.Name) {
+					continue
+				}
+				filteredUsers = append(filteredUsers, user.Name)
+				filteredSCRAMUsers = append(filteredSCRAMUsers, user)
+			}
+			rest.SendResponse(w, r, api.Logger, http.StatusOK, &response{
+				Users:      filteredUsers,
+				IsComplete: false,
+				SCRAMUsers: filteredSCRAMUsers,
+			})
+			return
+		}
+
 		// We can't return any users.
 		rest.SendResponse(w, r, api.Logger, http.StatusOK, &response{Users: []string{}, IsComplete: false})
 	}
@@ -79,23 +153,16 @@ type createUserRequest struct {
 	Username  string `json:"username"`
 	Password  string `json:"password"`
 	Mechanism string `json:"mechanism"`
+	// Iterations is only used when the user is created via the Kafka API, defaults to 4096.
+	Iterations int32 `json:"iterations"`
 }
 
 func (c *createUserRequest) OK() error {
 	if c.Username == "" {
 		return fmt.Errorf("username must be set")
 	}
-	if c.Password == "" {
-		return fmt.Errorf("password must be set")
-	}
 
-	switch c.Mechanism {
-	case admin.ScramSha256, admin.ScramSha512:
-	default:
-		return fmt.Errorf("mechanism must be either SCRAM-SHA-256 or SCRAM-SHA-512")
-	}
-
-	return nil
+	return validateSCRAMCredentials(c.Password, c.Mechanism, c.Iterations)
 }
 
 func (api *API) handleCreateUser() http.HandlerFunc {
@@ -152,12 +219,23 @@ func (api *API) handleCreateUser() http.HandlerFunc {
 			return
 		}
 
-		// 5. Return an error if we can't create any users
-		rest.SendRESTError(w, r, api.Logger, &rest.Error{
-			Err:     fmt.Errorf("redpanda Admin API is not enabled"),
-			Status:  http.StatusServiceUnavailable,
-			Message: "Redpanda Admin API is not enabled",
-		})
+		// 5. Create user via the Kafka API if the cluster supports it
+		isSupported, ok := api.isKafkaUserManagementSupported(w, r, http.MethodPost)
+		if !ok {
+			return
+		}
+		if isSupported {
+			restErr := api.ConsoleSvc.CreateSCRAMUser(r.Context(), req.Username, req.Password, req.Mechanism, req.Iterations)
+			if restErr != nil {
+				rest.SendRESTError(w, r, api.Logger, restErr)
+				return
+			}
+			rest.SendResponse(w, r, api.Logger, http.StatusOK, nil)
+			return
+		}
+
+		// 6. Return an error if we can't create any users
+		rest.SendRESTError(w, r, api.Logger, errUserManagementNotSupported)
 	}
 }
 
@@ -217,11 +295,116 @@ func (api *API) handleDeleteUser() http.HandlerFunc {
 			return
 		}
 
-		// 5. Return an error if we can't delete any users
-		rest.SendRESTError(w, r, api.Logger, &rest.Error{
-			Err:     fmt.Errorf("redpanda Admin API is not enabled"),
-			Status:  http.StatusServiceUnavailable,
-			Message: "Redpanda Admin API is not enabled",
-		})
+		// 5. Delete user via the Kafka API if the cluster supports it
+		isSupported, ok := api.isKafkaUserManagementSupported(w, r, http.MethodDelete)
+		if !ok {
+			return
+		}
+		if isSupported {
+			restErr := api.ConsoleSvc.DeleteSCRAMUser(r.Context(), principalID)
+			if restErr != nil {
+				rest.SendRESTError(w, r, api.Logger, restErr)
+				return
+			}
+			rest.SendResponse(w, r, api.Logger, http.StatusOK, nil)
+			return
+		}
+
+		// 6. Return an error if we can't delete any users
+		rest.SendRESTError(w, r, api.Logger, errUserManagementNotSupported)
+	}
+}
+
+type updateUserRequest struct {
+	Password  string `json:"password"`
+	Mechanism string `json:"mechanism"`
+	// Iterations is only used when the user is updated via the Kafka API, defaults to 4096.
+	Iterations int32 `json:"iterations"`
+}
+
+func (u *updateUserRequest) OK() error {
+	return validateSCRAMCredentials(u.Password, u.Mechanism, u.Iterations)
+}
+
+func (api *API) handleUpdateUser() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// 1. Parse and validate request
+		principalID := rest.GetURLParam(r, "principalID")
+		if principalID == "" {
+			rest.SendRESTError(w, r, api.Logger, &rest.Error{
+				Err:     fmt.Errorf("user must be set"),
+				Status:  http.StatusBadRequest,
+				Message: "User must be set",
+			})
+			return
+		}
+		var req updateUserRequest
+		restErr := rest.Decode(w, r, &req)
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 2. Check if logged-in user is allowed to set passwords, which is the same as creating Kafka users
+		canCreate, restErr := api.Hooks.Authorization.CanCreateKafkaUsers(r.Context())
+		if restErr != nil {
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+		if !canCreate {
+			restErr := &rest.Error{
+				Err:      fmt.Errorf("requester has no permissions to update Kafka users"),
+				Status:   http.StatusForbidden,
+				Message:  "You don't have permissions to update Kafka users.",
+				IsSilent: false,
+			}
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 3. Check if targeted user is a protected user
+		if api.Hooks.Authorization.IsProtectedKafkaUser(principalID) {
+			restErr := &rest.Error{
+				Err:      fmt.Errorf("requester tried to update a protected Kafka user"),
+				Status:   http.StatusForbidden,
+				Message:  "You are not allowed to update this protected Kafka user",
+				IsSilent: false,
+			}
+			rest.SendRESTError(w, r, api.Logger, restErr)
+			return
+		}
+
+		// 4. Update user
+		if api.Cfg.Redpanda.AdminAPI.Enabled {
+			err := api.RedpandaSvc.UpdateUser(r.Context(), principalID, req.Password, req.Mechanism)
+			if err != nil {
+				rest.SendRESTError(w, r, api.Logger, &rest.Error{
+					Err:     err,
+					Status:  http.StatusServiceUnavailable,
+					Message: fmt.Sprintf("Failed to update user via Redpanda Admin API: %v", err.Error()),
+				})
+				return
+			}
+			rest.SendResponse(w, r, api.Logger, http.StatusOK, nil)
+			return
+		}
+
+		// 5. Update user via the Kafka API if the cluster supports it
+		isSupported, ok := api.isKafkaUserManagementSupported(w, r, http.MethodPut)
+		if !ok {
+			return
+		}
+		if isSupported {
+			restErr := api.ConsoleSvc.UpdateSCRAMUserPassword(r.Context(), principalID, req.Password, req.Mechanism, req.Iterations)
+			if restErr != nil {
+				rest.SendRESTError(w, r, api.Logger, restErr)
+				return
+			}
+			rest.SendResponse(w, r, api.Logger, http.StatusOK, nil)
+			return
+		}
+
+		// 6. Return an error if we can't update any users
+		rest.SendRESTError(w, r, api.Logger, errUserManagementNotSupported)
 	}
 }
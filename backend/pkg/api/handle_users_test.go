@@ -0,0 +1,40 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateUserRequestOK(t *testing.T) {
+	tests := map[string]struct {
+		input   createUserRequest
+		wantErr bool
+	}{
+		"valid":                 {input: createUserRequest{Username: "alice", Password: "secret", Mechanism: "SCRAM-SHA-256"}},
+		"valid with iterations": {input: createUserRequest{Username: "alice", Password: "secret", Mechanism: "SCRAM-SHA-512", Iterations: 8192}},
+		"missing username":      {input: createUserRequest{Password: "secret", Mechanism: "SCRAM-SHA-256"}, wantErr: true},
+		"missing password":      {input: createUserRequest{Username: "alice", Mechanism: "SCRAM-SHA-256"}, wantErr: true},
+		"unknown mechanism":     {input: createUserRequest{Username: "alice", Password: "secret", Mechanism: "PLAIN"}, wantErr: true},
+		"too few iterations":    {input: createUserRequest{Username: "alice", Password: "secret", Mechanism: "SCRAM-SHA-256", Iterations: 1000}, wantErr: true},
+		"too many iterations":   {input: createUserRequest{Username: "alice", Password: "secret", Mechanism: "SCRAM-SHA-256", Iterations: 20000}, wantErr: true},
+	}
+
+	for name, tc := range tests {
+		err := tc.input.OK()
+		if tc.wantErr {
+			assert.Error(t, err, name)
+		} else {
+			assert.NoError(t, err, name)
+		}
+	}
+}
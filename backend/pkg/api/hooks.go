@@ -54,6 +54,9 @@ type AuthorizationHooks interface {
 	CanSeeTopic(ctx context.Context, topicName string) (bool, *rest.Error)
 	CanCreateTopic(ctx context.Context, topicName string) (bool, *rest.Error)
 	CanEditTopicConfig(ctx context.Context, topicName string) (bool, *rest.Error)
+	CanCreateTopicPartitions(ctx context.Context, topicName string) (bool, *rest.Error)
+	CanElectLeaders(ctx context.Context, topicName string) (bool, *rest.Error)
+	CanElectUncleanLeaders(ctx context.Context, topicName string) (bool, *rest.Error)
 	CanDeleteTopic(ctx context.Context, topicName string) (bool, *rest.Error)
 	CanPublishTopicRecords(ctx context.Context, topicName string) (bool, *rest.Error)
 	CanDeleteTopicRecords(ctx context.Context, topicName string) (bool, *rest.Error)
@@ -72,6 +75,8 @@ type AuthorizationHooks interface {
 
 	// Quotas Hookas
 	CanListQuotas(ctx context.Context) (bool, *rest.Error)
+	CanAlterQuotas(ctx context.Context, entity []console.QuotaEntityComponent) (bool, *rest.Error)
+	CanDeleteQuotas(ctx context.Context, entity []console.QuotaEntityComponent) (bool, *rest.Error)
 
 	// ConsumerGroup Hooks
 	CanSeeConsumerGroup(ctx context.Context, groupName string) (bool, *rest.Error)
@@ -79,9 +84,22 @@ type AuthorizationHooks interface {
 	CanDeleteConsumerGroup(ctx context.Context, groupName string) (bool, *rest.Error)
 	AllowedConsumerGroupActions(ctx context.Context, groupName string) ([]string, *rest.Error)
 
+	// Schema Registry Hooks
+	CanImportSchemaRegistry(ctx context.Context) (bool, *rest.Error)
+	CanRegisterSchema(ctx context.Context, subject string) (bool, *rest.Error)
+
 	// Operations Hooks
 	CanPatchPartitionReassignments(ctx context.Context) (bool, *rest.Error)
+	CanViewReplicaLogDirs(ctx context.Context, brokerID int32) (bool, *rest.Error)
+	CanAlterReplicaLogDirs(ctx context.Context, brokerID int32) (bool, *rest.Error)
+	CanListTransactions(ctx context.Context) (bool, *rest.Error)
+	CanAbortTransaction(ctx context.Context, topicName string) (bool, *rest.Error)
 	CanPatchConfigs(ctx context.Context) (bool, *rest.Error)
+	CanViewClusterHealth(ctx context.Context) (bool, *rest.Error)
+	CanViewTopicState(ctx context.Context) (bool, *rest.Error)
+	CanApplyTopicState(ctx context.Context) (bool, *rest.Error)
+	CanExportClusterSnapshot(ctx context.Context) (bool, *rest.Error)
+	CanImportClusterSnapshot(ctx context.Context) (bool, *rest.Error)
 
 	// Kafka Connect Hooks
 	CanViewConnectCluster(ctx context.Context, clusterName string) (bool, *rest.Error)
@@ -121,6 +139,10 @@ type ConsoleHooks interface {
 	// The response of this hook will be merged into the response that was originally
 	// composed by Console.
 	EndpointCompatibility() []console.EndpointCompatibilityEndpoint
+
+	// Requester returns the name of the authenticated user of the request. It is recorded in
+	// audit logs, e.g. of applied topic state plans. Empty if the user is unknown.
+	Requester(ctx context.Context) string
 }
 
 // defaultHooks is the default hook which is used if you don't attach your own hooks
@@ -154,6 +176,18 @@ func (*defaultHooks) CanEditTopicConfig(_ context.Context, _ string) (bool, *res
 	return true, nil
 }
 
+func (*defaultHooks) CanCreateTopicPartitions(_ context.Context, _ string) (bool, *rest.Error) {
+	return true, nil
+}
+
+func (*defaultHooks) CanElectLeaders(_ context.Context, _ string) (bool, *rest.Error) {
+	return true, nil
+}
+
+func (*defaultHooks) CanElectUncleanLeaders(_ context.Context, _ string) (bool, *rest.Error) {
+	return true, nil
+}
+
 func (*defaultHooks) CanDeleteTopic(_ context.Context, _ string) (bool, *rest.Error) {
 	return true, nil
 }
@@ -207,6 +241,14 @@ func (*defaultHooks) CanListQuotas(_ context.Context) (bool, *rest.Error) {
 	return true, nil
 }
 
+func (*defaultHooks) CanAlterQuotas(_ context.Context, _ []console.QuotaEntityComponent) (bool, *rest.Error) {
+	return true, nil
+}
+
+func (*defaultHooks) CanDeleteQuotas(_ context.Context, _ []console.QuotaEntityComponent) (bool, *rest.Error) {
+	return true, nil
+}
+
 func (*defaultHooks) CanSeeConsumerGroup(_ context.Context, _ string) (bool, *rest.Error) {
 	return true, nil
 }
@@ -224,10 +266,54 @@ func (*defaultHooks) AllowedConsumerGroupActions(_ context.Context, _ string) ([
 	return []string{"all"}, nil
 }
 
+func (*defaultHooks) CanImportSchemaRegistry(_ context.Context) (bool, *rest.Error) {
+	return true, nil
+}
+
+func (*defaultHooks) CanRegisterSchema(_ context.Context, _ string) (bool, *rest.Error) {
+	return true, nil
+}
+
 func (*defaultHooks) CanPatchPartitionReassignments(_ context.Context) (bool, *rest.Error) {
 	return true, nil
 }
 
+func (*defaultHooks) CanViewReplicaLogDirs(_ context.Context, _ int32) (bool, *rest.Error) {
+	return true, nil
+}
+
+func (*defaultHooks) CanAlterReplicaLogDirs(_ context.Context, _ int32) (bool, *rest.Error) {
+	return true, nil
+}
+
+func (*defaultHooks) CanListTransactions(_ context.Context) (bool, *rest.Error) {
+	return true, nil
+}
+
+func (*defaultHooks) CanAbortTransaction(_ context.Context, _ string) (bool, *rest.Error) {
+	return true, nil
+}
+
+func (*defaultHooks) CanViewClusterHealth(_ context.Context) (bool, *rest.Error) {
+	return true, nil
+}
+
+func (*defaultHooks) CanViewTopicState(_ context.Context) (bool, *rest.Error) {
+	return true, nil
+}
+
+func (*defaultHooks) CanApplyTopicState(_ context.Context) (bool, *rest.Error) {
+	return true, nil
+}
+
+func (*defaultHooks) CanExportClusterSnapshot(_ context.Context) (bool, *rest.Error) {
+	return true, nil
+}
+
+func (*defaultHooks) CanImportClusterSnapshot(_ context.Context) (bool, *rest.Error) {
+	return true, nil
+}
+
 func (*defaultHooks) CanPatchConfigs(_ context.Context) (bool, *rest.Error) {
 	return true, nil
 }
@@ -281,3 +367,7 @@ func (*defaultHooks) EndpointCompatibility() []console.EndpointCompatibilityEndp
 func (*defaultHooks) EnabledConnectClusterFeatures(_ context.Context, _ string) []connect.ClusterFeature {
 	return nil
 }
+
+func (*defaultHooks) Requester(_ context.Context) string {
+	return ""
+}
@@ -88,41 +88,76 @@ func (api *API) routes() *chi.Mux {
 				// Overview
 				r.Get("/cluster/overview", api.handleOverview())
 				r.Get("/cluster", api.handleDescribeCluster())
+				r.Get("/cluster/health", api.handleGetClusterHealth())
+				r.Get("/cluster/snapshot", api.handleExportClusterSnapshot())
+				r.Post("/cluster/snapshot/import", api.handleImportClusterSnapshot())
+				r.Get("/cluster/unused-resources", api.handleGetUnusedResources())
+				r.Post("/cluster/unused-resources/delete", api.handleDeleteUnusedResources())
+				r.Get("/cluster/dynamic-config", api.handleGetDynamicBrokerConfig(true))
+				r.Patch("/cluster/dynamic-config", api.handlePatchDynamicBrokerConfig(true))
 				r.Get("/brokers", api.handleGetBrokers())
 				r.Get("/brokers/{brokerID}/config", api.handleBrokerConfig())
+				r.Get("/brokers/{brokerID}/dynamic-config", api.handleGetDynamicBrokerConfig(false))
+				r.Patch("/brokers/{brokerID}/dynamic-config", api.handlePatchDynamicBrokerConfig(false))
+				r.Get("/brokers/{brokerID}/log-dirs/balance-plan", api.handlePlanLogDirBalance())
+				r.Get("/brokers/{brokerID}/log-dirs/moves", api.handleGetReplicaLogDirMovesProgress())
+				r.Post("/brokers/{brokerID}/log-dirs/moves", api.handleMoveReplicaLogDirs())
 				r.Get("/api-versions", api.handleGetAPIVersions())
 
 				// ACLs
 				r.Get("/acls", api.handleGetACLsOverview())
 				r.Post("/acls", api.handleCreateACL())
 				r.Delete("/acls", api.handleDeleteACLs())
+				r.Get("/acls/effective-permissions", api.handleGetEffectiveACLPermissions())
+				r.Get("/acls/explain", api.handleExplainACL())
+				r.Get("/acls/export", api.handleExportACLs())
+				r.Post("/acls/import", api.handleImportACLs())
+				r.Post("/acls/templates", api.handleApplyACLTemplate())
 
 				// Kafka Users/Principals
 				r.Get("/users", api.handleGetUsers())
 				r.Post("/users", api.handleCreateUser())
+				r.Put("/users/{principalID}", api.handleUpdateUser())
 				r.Delete("/users/{principalID}", api.handleDeleteUser())
 
 				// Topics
 				r.Get("/topics-configs", api.handleGetTopicsConfigs())
+				r.Get("/topics-configs/lint", api.handleLintTopicsConfigs())
 				r.Get("/topics-offsets", api.handleGetTopicsOffsets())
 				r.Post("/topics-records", api.handlePublishTopicsRecords())
+				r.Post("/topics-bulk/delete", api.handleBulkDeleteTopics())
+				r.Patch("/topics-bulk/configuration", api.handleBulkEditTopicsConfig())
+				r.Post("/topics-bulk/delete-records", api.handleBulkDeleteTopicsRecords())
 				r.Get("/topics", api.handleGetTopics())
 				r.Post("/topics", api.handleCreateTopic())
 				r.Delete("/topics/{topicName}", api.handleDeleteTopic())
 				r.Delete("/topics/{topicName}/records", api.handleDeleteTopicRecords())
 				r.Get("/topics/{topicName}/partitions", api.handleGetPartitions())
+				r.Patch("/topics/{topicName}/partitions", api.handleCreateTopicPartitions())
+				r.Get("/topics/{topicName}/producers", api.handleGetTopicProducers())
 				r.Get("/topics/{topicName}/configuration", api.handleGetTopicConfig())
 				r.Patch("/topics/{topicName}/configuration", api.handleEditTopicConfig())
 				r.Get("/topics/{topicName}/consumers", api.handleGetTopicConsumers())
 				r.Get("/topics/{topicName}/documentation", api.handleGetTopicDocumentation())
+				r.Get("/topics/{topicName}/inferred-schema", api.handleInferTopicSchema())
+				r.Get("/topics/{topicName}/history", api.handleGetTopicHistory())
+
+				// Declarative topic management
+				r.Get("/topic-state/plan", api.handlePlanTopicState())
+				r.Post("/topic-state/apply", api.handleApplyTopicState())
+				r.Get("/topic-state/audit-log", api.handleGetTopicStateAuditLog())
 
 				// Quotas
 				r.Get("/quotas", api.handleGetQuotas())
+				r.Put("/quotas", api.handleAlterQuotas())
+				r.Delete("/quotas", api.handleDeleteQuotas())
 
 				// Consumer Groups
 				r.Get("/consumer-groups", api.handleGetConsumerGroups())
 				r.Get("/consumer-groups/{groupId}", api.handleGetConsumerGroup())
 				r.Patch("/consumer-groups/{groupId}", api.handlePatchConsumerGroup())
+				r.Post("/consumer-groups/{groupId}/reset-offsets", api.handleResetConsumerGroupOffsets())
+				r.Get("/consumer-groups/{groupId}/history", api.handleGetConsumerGroupHistory())
 				r.Delete("/consumer-groups/{groupId}/offsets", api.handleDeleteConsumerGroupOffsets())
 				r.Delete("/consumer-groups/{groupId}", api.handleDeleteConsumerGroup())
 
@@ -130,11 +165,29 @@ func (api *API) routes() *chi.Mux {
 				r.Get("/operations/topic-details", api.handleGetAllTopicDetails())
 				r.Get("/operations/reassign-partitions", api.handleGetPartitionReassignments())
 				r.Patch("/operations/reassign-partitions", api.handlePatchPartitionAssignments())
+				r.Delete("/operations/reassign-partitions", api.handleCancelPartitionReassignments())
+				r.Post("/operations/reassign-partitions/plan", api.handlePlanPartitionReassignments())
+				r.Get("/operations/reassign-partitions/progress", api.handleGetPartitionReassignmentsProgress())
+				r.Put("/operations/reassign-partitions/throttle", api.handleSetReplicationThrottles())
+				r.Delete("/operations/reassign-partitions/throttle", api.handleClearReplicationThrottles())
+				r.Post("/operations/elect-leaders", api.handleElectLeaders())
 				r.Patch("/operations/configs", api.handlePatchConfigs())
 
+				// Transactions
+				r.Get("/transactions", api.handleListTransactions())
+				r.Get("/transactions/hanging", api.handleFindHangingTransactions())
+				r.Post("/transactions/abort", api.handleAbortTransaction())
+
 				// Schema Registry
 				r.Get("/schemas", api.handleGetSchemaOverview())
+				r.Get("/schemas/search", api.handleSearchSchemas())
 				r.Get("/schemas/subjects/{subject}/versions/{version}", api.handleGetSchemaDetails())
+				r.Post("/schemas/subjects/{subject}/versions", api.handleRegisterSchema())
+				r.Get("/schemas/subjects/{subject}/versions/{version}/reference-graph", api.handleGetSchemaReferenceGraph())
+				r.Get("/schemas/subjects/{subject}/versions/{version}/example", api.handleGetSchemaExample())
+				r.Get("/proto-types/{protoType}/example", api.handleGetProtoTypeExample())
+				r.Get("/schemas/export", api.handleExportSchemaRegistry())
+				r.Post("/schemas/import", api.handleImportSchemaRegistry())
 
 				// Kafka Connect
 				r.Get("/kafka-connect/connectors", api.handleGetConnectors())
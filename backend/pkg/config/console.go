@@ -21,17 +21,27 @@ type Console struct {
 	// implementation that satisfies the Console interface.
 	Enabled            bool                      `yaml:"enabled"`
 	TopicDocumentation ConsoleTopicDocumentation `yaml:"topicDocumentation"`
+	History            ConsoleHistory            `yaml:"history"`
+	Exporter           ConsoleExporter           `yaml:"exporter"`
+	Health             ConsoleHealth             `yaml:"health"`
+	TopicLint          ConsoleTopicLint          `yaml:"topicLint"`
+	TopicState         ConsoleTopicState         `yaml:"topicState"`
 }
 
 // SetDefaults for Console configs.
 func (c *Console) SetDefaults() {
 	c.Enabled = true
 	c.TopicDocumentation.SetDefaults()
+	c.History.SetDefaults()
+	c.Exporter.SetDefaults()
+	c.Health.SetDefaults()
+	c.TopicState.SetDefaults()
 }
 
 // RegisterFlags for sensitive Console configurations.
 func (c *Console) RegisterFlags(f *flag.FlagSet) {
 	c.TopicDocumentation.RegisterFlags(f)
+	c.TopicState.RegisterFlags(f)
 }
 
 // Validate Console configurations.
@@ -41,5 +51,25 @@ func (c *Console) Validate() error {
 		return fmt.Errorf("failed to validate topic documentation config: %w", err)
 	}
 
+	err = c.History.Validate()
+	if err != nil {
+		return fmt.Errorf("failed to validate history config: %w", err)
+	}
+
+	err = c.Exporter.Validate()
+	if err != nil {
+		return fmt.Errorf("failed to validate exporter config: %w", err)
+	}
+
+	err = c.Health.Validate()
+	if err != nil {
+		return fmt.Errorf("failed to validate health config: %w", err)
+	}
+
+	err = c.TopicState.Validate()
+	if err != nil {
+		return fmt.Errorf("failed to validate topic state config: %w", err)
+	}
+
 	return nil
 }
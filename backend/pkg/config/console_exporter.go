@@ -0,0 +1,78 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// ConsoleExporter configures the Prometheus exporter that publishes topic, partition,
+// broker and consumer group metrics on the /admin/metrics endpoint.
+type ConsoleExporter struct {
+	Enabled bool `yaml:"enabled"`
+
+	// CacheMaxAge is how long the collected values are reused across scrapes, so that
+	// multiple Prometheus instances don't multiply the load on the Kafka cluster.
+	CacheMaxAge time.Duration `yaml:"cacheMaxAge"`
+
+	// PartitionMetrics enables metrics with a partition label. These have the highest
+	// cardinality and may be disabled for large clusters.
+	PartitionMetrics bool `yaml:"partitionMetrics"`
+
+	// Topics and Groups are filters that limit the exported topics and consumer groups.
+	Topics ConsoleExporterFilter `yaml:"topics"`
+	Groups ConsoleExporterFilter `yaml:"groups"`
+}
+
+// ConsoleExporterFilter contains allow and deny expressions. Expressions wrapped in slashes
+// are treated as regex, all others as literal. A name is exported if it matches at least one
+// allow expression (or no allow expressions are configured) and no deny expression.
+type ConsoleExporterFilter struct {
+	Allow []string `yaml:"allow"`
+	Deny  []string `yaml:"deny"`
+}
+
+// SetDefaults for ConsoleExporter.
+func (c *ConsoleExporter) SetDefaults() {
+	c.Enabled = false
+	c.CacheMaxAge = 30 * time.Second
+	c.PartitionMetrics = true
+}
+
+// Validate configuration options for the Prometheus exporter.
+func (c *ConsoleExporter) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.CacheMaxAge < 0 {
+		return fmt.Errorf("exporter cache max age must not be negative")
+	}
+	if err := c.Topics.Validate(); err != nil {
+		return fmt.Errorf("failed to validate topic filter: %w", err)
+	}
+	if err := c.Groups.Validate(); err != nil {
+		return fmt.Errorf("failed to validate group filter: %w", err)
+	}
+
+	return nil
+}
+
+// Validate that all expressions can be compiled.
+func (c *ConsoleExporterFilter) Validate() error {
+	if _, err := CompileRegexes(c.Allow); err != nil {
+		return fmt.Errorf("failed to compile allow expressions: %w", err)
+	}
+	if _, err := CompileRegexes(c.Deny); err != nil {
+		return fmt.Errorf("failed to compile deny expressions: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// ConsoleHealth configures the rules that are evaluated by the cluster health analysis.
+type ConsoleHealth struct {
+	// DisabledRules contains the IDs of rules that shall not be evaluated,
+	// e.g. "SINGLE_REPLICA_TOPIC" for development clusters.
+	DisabledRules []string `yaml:"disabledRules"`
+
+	// SkewThresholdPercent is how much a broker's leader or replica count may deviate
+	// from the average across all brokers before it is reported as skewed.
+	SkewThresholdPercent float64 `yaml:"skewThresholdPercent"`
+
+	// DiskUsageWarningPercent and DiskUsageCriticalPercent are the used capacity of a
+	// broker's log dir volume at which a warning or critical finding is reported.
+	DiskUsageWarningPercent  float64 `yaml:"diskUsageWarningPercent"`
+	DiskUsageCriticalPercent float64 `yaml:"diskUsageCriticalPercent"`
+
+	// RecentCommitWindow is the period in which an offset commit is considered recent.
+	// Empty consumer groups that committed within this window likely lost their consumers.
+	RecentCommitWindow time.Duration `yaml:"recentCommitWindow"`
+}
+
+// SetDefaults for ConsoleHealth.
+func (c *ConsoleHealth) SetDefaults() {
+	c.SkewThresholdPercent = 20
+	c.DiskUsageWarningPercent = 80
+	c.DiskUsageCriticalPercent = 90
+	c.RecentCommitWindow = time.Hour
+}
+
+// Validate configuration options for the cluster health analysis.
+func (c *ConsoleHealth) Validate() error {
+	if c.SkewThresholdPercent <= 0 {
+		return fmt.Errorf("skew threshold percent must be greater than 0")
+	}
+	if c.DiskUsageWarningPercent <= 0 || c.DiskUsageWarningPercent > 100 {
+		return fmt.Errorf("disk usage warning percent must be between 0 and 100")
+	}
+	if c.DiskUsageCriticalPercent <= 0 || c.DiskUsageCriticalPercent > 100 {
+		return fmt.Errorf("disk usage critical percent must be between 0 and 100")
+	}
+	if c.DiskUsageWarningPercent > c.DiskUsageCriticalPercent {
+		return fmt.Errorf("disk usage warning percent must not be greater than the critical percent")
+	}
+	if c.RecentCommitWindow <= 0 {
+		return fmt.Errorf("recent commit window must be greater than 0")
+	}
+
+	return nil
+}
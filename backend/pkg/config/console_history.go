@@ -0,0 +1,56 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// ConsoleHistory configures the background collector that periodically samples partition
+// watermarks and committed group offsets, so that throughput and lag trends can be shown.
+type ConsoleHistory struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Interval specifies how often partition watermarks and group offsets are sampled.
+	Interval time.Duration `yaml:"interval"`
+
+	// Retention specifies how long samples are kept in memory.
+	Retention time.Duration `yaml:"retention"`
+
+	// PersistenceFilepath is the file samples are persisted to, so that the history survives
+	// restarts. Persistence is disabled if empty.
+	PersistenceFilepath string `yaml:"persistenceFilepath"`
+}
+
+// SetDefaults for ConsoleHistory.
+func (c *ConsoleHistory) SetDefaults() {
+	c.Enabled = false
+	c.Interval = 30 * time.Second
+	c.Retention = 6 * time.Hour
+}
+
+// Validate configuration options for the history collector.
+func (c *ConsoleHistory) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Interval < 5*time.Second {
+		return fmt.Errorf("history interval must be at least 5s")
+	}
+	if c.Retention < c.Interval {
+		return fmt.Errorf("history retention must be greater than the interval")
+	}
+	if c.Retention/c.Interval > 100_000 {
+		return fmt.Errorf("history retention must not exceed 100000 intervals")
+	}
+
+	return nil
+}
@@ -0,0 +1,17 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package config
+
+// ConsoleTopicLint configures the rules that topic configurations are linted against.
+type ConsoleTopicLint struct {
+	// RulesFilepath is a YAML rule file that replaces the default rules. The default
+	// rules are used if empty.
+	RulesFilepath string `yaml:"rulesFilepath"`
+}
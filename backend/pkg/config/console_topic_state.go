@@ -0,0 +1,75 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package config
+
+import (
+	"flag"
+	"fmt"
+)
+
+// ConsoleTopicState configures declarative topic management. Desired-state YAML files
+// for topics are pulled from a git repository and compared with the live cluster, so
+// that drift can be shown and plans can be applied.
+type ConsoleTopicState struct {
+	Enabled bool `yaml:"enabled"`
+	Git     Git  `yaml:"git"`
+
+	// IgnoredTopics are topics that are never managed, e.g. internal topics. Expressions
+	// wrapped in slashes are treated as regex, all others as literal.
+	IgnoredTopics []string `yaml:"ignoredTopics"`
+
+	// ApplyEnabled allows applying plans via the API. Otherwise only plans and drift
+	// can be viewed.
+	ApplyEnabled bool `yaml:"applyEnabled"`
+
+	// AllowDeletions deletes topics that are listed as deleted in the repository when a
+	// plan is applied. Topics that are merely missing in the repository are never deleted.
+	AllowDeletions bool `yaml:"allowDeletions"`
+
+	// MaxDeletionsPerPlan is the number of topics a single plan may delete. Plans with more
+	// deletions only delete topics if the limit is explicitly overridden.
+	MaxDeletionsPerPlan int `yaml:"maxDeletionsPerPlan"`
+
+	// AuditLogFilepath is a file every applied plan is appended to as JSON line. Applied
+	// plans are only kept in memory if empty.
+	AuditLogFilepath string `yaml:"auditLogFilepath"`
+}
+
+// RegisterFlags with sensitive configuration options for the topic state feature.
+func (c *ConsoleTopicState) RegisterFlags(f *flag.FlagSet) {
+	c.Git.RegisterFlagsWithPrefix(f, "console.topic-state.")
+}
+
+// SetDefaults for ConsoleTopicState.
+func (c *ConsoleTopicState) SetDefaults() {
+	c.Git.SetDefaults()
+	c.Git.AllowedFileExtensions = []string{"yaml", "yml"}
+	c.Git.IndexByFullFilepath = true
+	c.IgnoredTopics = []string{"/^__.*/", "_schemas"}
+	c.MaxDeletionsPerPlan = 5
+}
+
+// Validate configuration options for the topic state feature.
+func (c *ConsoleTopicState) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if !c.Git.Enabled {
+		return fmt.Errorf("topic state is enabled, but git service is disabled")
+	}
+	if _, err := CompileRegexes(c.IgnoredTopics); err != nil {
+		return fmt.Errorf("failed to compile ignored topics: %w", err)
+	}
+	if c.MaxDeletionsPerPlan < 0 {
+		return fmt.Errorf("max deletions per plan must not be negative")
+	}
+
+	return c.Git.Validate()
+}
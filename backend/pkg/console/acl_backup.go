@@ -0,0 +1,378 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// ACLExportVersion is the version of the ACL export format.
+const ACLExportVersion = 1
+
+// ACLExport contains all ACL bindings of a cluster in a format that can be reviewed, stored as code
+// and imported again.
+type ACLExport struct {
+	Version   int          `json:"version" yaml:"version"`
+	CreatedAt time.Time    `json:"createdAt" yaml:"createdAt"`
+	ACLs      []ACLBinding `json:"acls" yaml:"acls"`
+}
+
+// ACLBinding is a single ACL entry: a resource pattern along with the principal, host, operation and
+// permission type.
+type ACLBinding struct {
+	ResourceType        kmsg.ACLResourceType        `json:"resourceType" yaml:"resourceType"`
+	ResourceName        string                      `json:"resourceName" yaml:"resourceName"`
+	ResourcePatternType kmsg.ACLResourcePatternType `json:"resourcePatternType" yaml:"resourcePatternType"`
+	Principal           string                      `json:"principal" yaml:"principal"`
+	Host                string                      `json:"host" yaml:"host"`
+	Operation           kmsg.ACLOperation           `json:"operation" yaml:"operation"`
+	PermissionType      kmsg.ACLPermissionType      `json:"permissionType" yaml:"permissionType"`
+}
+
+// Validate checks whether the binding can be created in Kafka.
+func (b *ACLBinding) Validate() error {
+	switch b.ResourceType {
+	case kmsg.ACLResourceTypeUnknown, kmsg.ACLResourceTypeAny:
+		return fmt.Errorf("resource type must be a specific resource type, but found: %q", b.ResourceType)
+	}
+	if b.ResourceName == "" {
+		return fmt.Errorf("resource name must be set")
+	}
+	switch b.ResourcePatternType {
+	case kmsg.ACLResourcePatternTypeLiteral, kmsg.ACLResourcePatternTypePrefixed:
+	default:
+		return fmt.Errorf("resource pattern type must be either LITERAL or PREFIXED, but found: %q", b.ResourcePatternType)
+	}
+	if !strings.Contains(b.Principal, ":") {
+		return fmt.Errorf("principal must be set in the format <type>:<name>, but found: %q", b.Principal)
+	}
+	if b.Host == "" {
+		return fmt.Errorf("host must be set, use '*' to match all hosts")
+	}
+	switch b.Operation {
+	case kmsg.ACLOperationUnknown, kmsg.ACLOperationAny:
+		return fmt.Errorf("operation must be a specific operation or ALL, but found: %q", b.Operation)
+	}
+	switch b.PermissionType {
+	case kmsg.ACLPermissionTypeAllow, kmsg.ACLPermissionTypeDeny:
+	default:
+		return fmt.Errorf("permission type must be either ALLOW or DENY, but found: %q", b.PermissionType)
+	}
+	return nil
+}
+
+func (b *ACLBinding) String() string {
+	return fmt.Sprintf("%v %v %v:%v:%v for %v from %v", b.PermissionType, b.Operation, b.ResourceType,
+		b.ResourcePatternType, b.ResourceName, b.Principal, b.Host)
+}
+
+// Validate checks the export version and all bindings. Duplicate bindings are rejected, so that
+// reviewed files stay unambiguous.
+func (e *ACLExport) Validate() error {
+	if e.Version != ACLExportVersion {
+		return fmt.Errorf("unsupported ACL export version %d, expected %d", e.Version, ACLExportVersion)
+	}
+
+	seen := make(map[ACLBinding]struct{}, len(e.ACLs))
+	for i := range e.ACLs {
+		binding := e.ACLs[i]
+		if err := binding.Validate(); err != nil {
+			return fmt.Errorf("acl %d (%v) is invalid: %w", i, binding.String(), err)
+		}
+		if _, exists := seen[binding]; exists {
+			return fmt.Errorf("acl %d (%v) is listed more than once", i, binding.String())
+		}
+		seen[binding] = struct{}{}
+	}
+	return nil
+}
+
+// ACLImportOptions controls how an ACL export is imported.
+type ACLImportOptions struct {
+	// ValidateOnly computes the diff without changing any ACLs.
+	ValidateOnly bool
+
+	// IsProtectedPrincipal reports principals whose ACLs must neither be created nor deleted.
+	// Existing ACLs of protected principals are left untouched.
+	IsProtectedPrincipal func(principal string) bool
+}
+
+// ACLImportResult is the diff between the imported and the existing ACLs along with the
+// outcome of applying it.
+type ACLImportResult struct {
+	ValidateOnly bool              `json:"validateOnly"`
+	Creations    []ACLBinding      `json:"creations"`
+	Deletions    []ACLBinding      `json:"deletions"`
+	Unchanged    int               `json:"unchanged"`
+	Errors       []ACLBindingError `json:"errors"`
+}
+
+// ACLBindingError is the error that occurred while creating or deleting a single binding.
+type ACLBindingError struct {
+	ACL   ACLBinding `json:"acl"`
+	Error string     `json:"error"`
+}
+
+// ExportACLs returns all ACL bindings of the cluster.
+func (s *Service) ExportACLs(ctx context.Context) (*ACLExport, *rest.Error) {
+	bindings, restErr := s.listACLBindings(ctx)
+	if restErr != nil {
+		return nil, restErr
+	}
+
+	return &ACLExport{
+		Version:   ACLExportVersion,
+		CreatedAt: time.Now().UTC(),
+		ACLs:      bindings,
+	}, nil
+}
+
+// ImportACLs makes the cluster's ACLs match the given export: Bindings that are only listed in the
+// export are created, bindings that are not listed in the export are deleted.
+func (s *Service) ImportACLs(ctx context.Context, export *ACLExport, opts ACLImportOptions) (*ACLImportResult, *rest.Error) {
+	current, restErr := s.listACLBindings(ctx)
+	if restErr != nil {
+		return nil, restErr
+	}
+
+	res := diffACLBindings(current, export.ACLs, opts.IsProtectedPrincipal)
+	res.ValidateOnly = opts.ValidateOnly
+	if opts.ValidateOnly {
+		return res, nil
+	}
+
+	if restErr := s.createACLBindings(ctx, res); restErr != nil {
+		return nil, restErr
+	}
+	if restErr := s.deleteACLBindings(ctx, res); restErr != nil {
+		return nil, restErr
+	}
+	return res, nil
+}
+
+// CreateACLBindings creates all given bindings that do not exist yet. Existing bindings are
+// never deleted.
+func (s *Service) CreateACLBindings(ctx context.Context, bindings []ACLBinding, validateOnly bool) (*ACLImportResult, *rest.Error) {
+	current, restErr := s.listACLBindings(ctx)
+	if restErr != nil {
+		return nil, restErr
+	}
+
+	res := diffACLBindings(current, bindings, nil)
+	res.Deletions = make([]ACLBinding, 0)
+	res.ValidateOnly = validateOnly
+	if validateOnly {
+		return res, nil
+	}
+
+	if restErr := s.createACLBindings(ctx, res); restErr != nil {
+		return nil, restErr
+	}
+	return res, nil
+}
+
+// diffACLBindings computes which bindings must be created and deleted so that current matches desired.
+// Bindings of protected principals are ignored on both sides.
+func diffACLBindings(current []ACLBinding, desired []ACLBinding, isProtected func(string) bool) *ACLImportResult {
+	res := &ACLImportResult{
+		Creations: make([]ACLBinding, 0),
+		Deletions: make([]ACLBinding, 0),
+		Errors:    make([]ACLBindingError, 0),
+	}
+	skip := func(b ACLBinding) bool {
+		return isProtected != nil && isProtected(b.Principal)
+	}
+
+	existing := make(map[ACLBinding]struct{}, len(current))
+	for _, binding := range current {
+		existing[binding] = struct{}{}
+	}
+	wanted := make(map[ACLBinding]struct{}, len(desired))
+	for _, binding := range desired {
+		if skip(binding) {
+			continue
+		}
+		wanted[binding] = struct{}{}
+		if _, exists := existing[binding]; exists {
+			res.Unchanged++
+			continue
+		}
+		res.Creations = append(res.Creations, binding)
+	}
+	for _, binding := range current {
+		if skip(binding) {
+			continue
+		}
+		if _, exists := wanted[binding]; !exists {
+			res.Deletions = append(res.Deletions, binding)
+		}
+	}
+
+	sortACLBindings(res.Creations)
+	sortACLBindings(res.Deletions)
+	return res
+}
+
+func (s *Service) listACLBindings(ctx context.Context) ([]ACLBinding, *rest.Error) {
+	req := kmsg.NewDescribeACLsRequest()
+	req.ResourceType = kmsg.ACLResourceTypeAny
+	req.ResourcePatternType = kmsg.ACLResourcePatternTypeAny
+	req.Operation = kmsg.ACLOperationAny
+	req.PermissionType = kmsg.ACLPermissionTypeAny
+
+	res, err := s.kafkaSvc.ListACLs(ctx, req)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("failed to list ACLs: %w", err),
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to list ACLs: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+	if err := kerr.ErrorForCode(res.ErrorCode); err != nil {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("failed to list ACLs: %w", err),
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to list ACLs: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	bindings := make([]ACLBinding, 0)
+	for _, resource := range res.Resources {
+		for _, acl := range resource.ACLs {
+			bindings = append(bindings, ACLBinding{
+				ResourceType:        resource.ResourceType,
+				ResourceName:        resource.ResourceName,
+				ResourcePatternType: resource.ResourcePatternType,
+				Principal:           acl.Principal,
+				Host:                acl.Host,
+				Operation:           acl.Operation,
+				PermissionType:      acl.PermissionType,
+			})
+		}
+	}
+	sortACLBindings(bindings)
+	return bindings, nil
+}
+
+func (s *Service) createACLBindings(ctx context.Context, res *ACLImportResult) *rest.Error {
+	if len(res.Creations) == 0 {
+		return nil
+	}
+
+	creations := make([]kmsg.CreateACLsRequestCreation, len(res.Creations))
+	for i, binding := range res.Creations {
+		creation := kmsg.NewCreateACLsRequestCreation()
+		creation.ResourceType = binding.ResourceType
+		creation.ResourceName = binding.ResourceName
+		creation.ResourcePatternType = binding.ResourcePatternType
+		creation.Principal = binding.Principal
+		creation.Host = binding.Host
+		creation.Operation = binding.Operation
+		creation.PermissionType = binding.PermissionType
+		creations[i] = creation
+	}
+
+	createRes, err := s.kafkaSvc.CreateACLs(ctx, creations)
+	if err != nil {
+		return &rest.Error{
+			Err:      fmt.Errorf("failed to create ACLs: %w", err),
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to create ACLs: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+	for i, result := range createRes.Results {
+		if i >= len(res.Creations) {
+			break
+		}
+		if err := kerr.ErrorForCode(result.ErrorCode); err != nil {
+			res.Errors = append(res.Errors, ACLBindingError{ACL: res.Creations[i], Error: err.Error()})
+		}
+	}
+	return nil
+}
+
+func (s *Service) deleteACLBindings(ctx context.Context, res *ACLImportResult) *rest.Error {
+	if len(res.Deletions) == 0 {
+		return nil
+	}
+
+	filters := make([]kmsg.DeleteACLsRequestFilter, len(res.Deletions))
+	for i, binding := range res.Deletions {
+		binding := binding
+		filter := kmsg.NewDeleteACLsRequestFilter()
+		filter.ResourceType = binding.ResourceType
+		filter.ResourceName = &binding.ResourceName
+		filter.ResourcePatternType = binding.ResourcePatternType
+		filter.Principal = &binding.Principal
+		filter.Host = &binding.Host
+		filter.Operation = binding.Operation
+		filter.PermissionType = binding.PermissionType
+		filters[i] = filter
+	}
+
+	deleteRes, err := s.kafkaSvc.DeleteACLs(ctx, filters)
+	if err != nil {
+		return &rest.Error{
+			Err:      fmt.Errorf("failed to delete ACLs: %w", err),
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to delete ACLs: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+	for i, result := range deleteRes.Results {
+		if i >= len(res.Deletions) {
+			break
+		}
+		if err := kerr.ErrorForCode(result.ErrorCode); err != nil {
+			res.Errors = append(res.Errors, ACLBindingError{ACL: res.Deletions[i], Error: err.Error()})
+			continue
+		}
+		for _, matching := range result.MatchingACLs {
+			if err := kerr.ErrorForCode(matching.ErrorCode); err != nil {
+				res.Errors = append(res.Errors, ACLBindingError{ACL: res.Deletions[i], Error: err.Error()})
+			}
+		}
+	}
+	return nil
+}
+
+func sortACLBindings(bindings []ACLBinding) {
+	sort.Slice(bindings, func(i, j int) bool {
+		a, b := bindings[i], bindings[j]
+		switch {
+		case a.ResourceType != b.ResourceType:
+			return a.ResourceType < b.ResourceType
+		case a.ResourceName != b.ResourceName:
+			return a.ResourceName < b.ResourceName
+		case a.ResourcePatternType != b.ResourcePatternType:
+			return a.ResourcePatternType < b.ResourcePatternType
+		case a.Principal != b.Principal:
+			return a.Principal < b.Principal
+		case a.Host != b.Host:
+			return a.Host < b.Host
+		case a.Operation != b.Operation:
+			return a.Operation < b.Operation
+		default:
+			return a.PermissionType < b.PermissionType
+		}
+	})
+}
@@ -0,0 +1,124 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"gopkg.in/yaml.v3"
+)
+
+func testACLBinding(principal string, op kmsg.ACLOperation) ACLBinding {
+	return ACLBinding{
+		ResourceType:        kmsg.ACLResourceTypeTopic,
+		ResourceName:        "orders",
+		ResourcePatternType: kmsg.ACLResourcePatternTypeLiteral,
+		Principal:           principal,
+		Host:                "*",
+		Operation:           op,
+		PermissionType:      kmsg.ACLPermissionTypeAllow,
+	}
+}
+
+func TestDiffACLBindings(t *testing.T) {
+	current := []ACLBinding{
+		testACLBinding("User:alice", kmsg.ACLOperationRead),
+		testACLBinding("User:alice", kmsg.ACLOperationWrite),
+		testACLBinding("User:admin", kmsg.ACLOperationAll),
+	}
+	desired := []ACLBinding{
+		testACLBinding("User:alice", kmsg.ACLOperationRead),
+		testACLBinding("User:bob", kmsg.ACLOperationRead),
+	}
+	isProtected := func(principal string) bool { return principal == "User:admin" }
+
+	res := diffACLBindings(current, desired, isProtected)
+
+	assert.Equal(t, []ACLBinding{testACLBinding("User:bob", kmsg.ACLOperationRead)}, res.Creations)
+	assert.Equal(t, []ACLBinding{testACLBinding("User:alice", kmsg.ACLOperationWrite)}, res.Deletions)
+	assert.Equal(t, 1, res.Unchanged)
+}
+
+func TestACLExportValidate(t *testing.T) {
+	export := ACLExport{Version: ACLExportVersion, ACLs: []ACLBinding{testACLBinding("User:alice", kmsg.ACLOperationRead)}}
+	require.NoError(t, export.Validate())
+
+	export.ACLs = append(export.ACLs, testACLBinding("User:alice", kmsg.ACLOperationRead))
+	assert.Error(t, export.Validate())
+
+	export.ACLs = []ACLBinding{testACLBinding("alice", kmsg.ACLOperationRead)}
+	assert.Error(t, export.Validate())
+}
+
+func TestACLExportEncoding(t *testing.T) {
+	export := ACLExport{
+		Version:   ACLExportVersion,
+		CreatedAt: time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC),
+		ACLs:      []ACLBinding{testACLBinding("User:alice", kmsg.ACLOperationDescribeConfigs)},
+	}
+
+	t.Run("yaml", func(t *testing.T) {
+		out, err := yaml.Marshal(export)
+		require.NoError(t, err)
+		assert.Contains(t, string(out), "operation: DESCRIBE_CONFIGS")
+
+		var decoded ACLExport
+		require.NoError(t, yaml.Unmarshal(out, &decoded))
+		assert.Equal(t, export, decoded)
+	})
+
+	t.Run("json decoded as yaml", func(t *testing.T) {
+		out, err := json.Marshal(export)
+		require.NoError(t, err)
+
+		var decoded ACLExport
+		require.NoError(t, yaml.Unmarshal(out, &decoded))
+		assert.Equal(t, export, decoded)
+	})
+}
+
+func TestACLTemplateExpand(t *testing.T) {
+	template := ACLTemplate{
+		Principal:                  "User:payments",
+		Producer:                   true,
+		Consumer:                   true,
+		TopicName:                  "payments.",
+		TopicPatternType:           kmsg.ACLResourcePatternTypePrefixed,
+		GroupID:                    "payments",
+		TransactionalID:            "payments-",
+		TransactionalIDPatternType: kmsg.ACLResourcePatternTypePrefixed,
+		Idempotent:                 true,
+	}
+	require.NoError(t, template.Validate())
+
+	bindings := template.Expand()
+
+	ops := make(map[kmsg.ACLResourceType][]kmsg.ACLOperation)
+	for _, b := range bindings {
+		assert.Equal(t, "*", b.Host)
+		assert.Equal(t, kmsg.ACLPermissionTypeAllow, b.PermissionType)
+		ops[b.ResourceType] = append(ops[b.ResourceType], b.Operation)
+	}
+	assert.Equal(t, map[kmsg.ACLResourceType][]kmsg.ACLOperation{
+		kmsg.ACLResourceTypeTopic:           {kmsg.ACLOperationWrite, kmsg.ACLOperationDescribe, kmsg.ACLOperationCreate, kmsg.ACLOperationRead},
+		kmsg.ACLResourceTypeTransactionalId: {kmsg.ACLOperationWrite, kmsg.ACLOperationDescribe},
+		kmsg.ACLResourceTypeCluster:         {kmsg.ACLOperationIdempotentWrite},
+		kmsg.ACLResourceTypeGroup:           {kmsg.ACLOperationRead},
+	}, ops)
+	assert.Equal(t, kmsg.ACLResourcePatternTypeLiteral, bindings[len(bindings)-1].ResourcePatternType)
+
+	template.GroupID = ""
+	assert.Error(t, template.Validate())
+}
@@ -0,0 +1,330 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Reasons for an authorization decision, see Kafka's AclAuthorizer.
+const (
+	ACLDecisionReasonAuthorizerDisabled = "AUTHORIZER_DISABLED"
+	ACLDecisionReasonDenyRuleMatched    = "DENY_RULE_MATCHED"
+	ACLDecisionReasonAllowRuleMatched   = "ALLOW_RULE_MATCHED"
+	ACLDecisionReasonNoMatchingRule     = "NO_MATCHING_ALLOW_RULE"
+	ACLDecisionReasonNoACLsFound        = "NO_ACLS_FOUND"
+)
+
+const (
+	aclWildcardResource  = "*"
+	aclWildcardHost      = "*"
+	aclWildcardPrincipal = "User:*"
+)
+
+// aclOperationsByResourceType lists the operations that Kafka checks for each resource type.
+var aclOperationsByResourceType = map[kmsg.ACLResourceType][]kmsg.ACLOperation{
+	kmsg.ACLResourceTypeTopic: {
+		kmsg.ACLOperationRead, kmsg.ACLOperationWrite, kmsg.ACLOperationCreate, kmsg.ACLOperationDelete,
+		kmsg.ACLOperationAlter, kmsg.ACLOperationDescribe, kmsg.ACLOperationDescribeConfigs, kmsg.ACLOperationAlterConfigs,
+	},
+	kmsg.ACLResourceTypeGroup: {
+		kmsg.ACLOperationRead, kmsg.ACLOperationDelete, kmsg.ACLOperationDescribe,
+	},
+	kmsg.ACLResourceTypeCluster: {
+		kmsg.ACLOperationCreate, kmsg.ACLOperationAlter, kmsg.ACLOperationDescribe, kmsg.ACLOperationClusterAction,
+		kmsg.ACLOperationDescribeConfigs, kmsg.ACLOperationAlterConfigs, kmsg.ACLOperationIdempotentWrite,
+	},
+	kmsg.ACLResourceTypeTransactionalId: {
+		kmsg.ACLOperationWrite, kmsg.ACLOperationDescribe,
+	},
+	kmsg.ACLResourceTypeDelegationToken: {
+		kmsg.ACLOperationDescribe,
+	},
+	kmsg.ACLResourceTypeUser: {
+		kmsg.ACLOperationCreateTokens, kmsg.ACLOperationDescribeTokens,
+	},
+}
+
+// ACLMatchingRule is a single ACL that was considered for an authorization decision.
+type ACLMatchingRule struct {
+	ResourceType        string `json:"resourceType"`
+	ResourceName        string `json:"resourceName"`
+	ResourcePatternType string `json:"resourcePatternType"`
+	Principal           string `json:"principal"`
+	Host                string `json:"host"`
+	Operation           string `json:"operation"`
+	PermissionType      string `json:"permissionType"`
+}
+
+// ACLDecision is the result of authorizing a single operation on a resource.
+type ACLDecision struct {
+	Operation string `json:"operation"`
+	Allowed   bool   `json:"allowed"`
+	Reason    string `json:"reason"`
+	// MatchingRules are the rules that decided the authorization. Deny rules take precedence
+	// over allow rules, so if a deny rule matched, only deny rules are listed.
+	MatchingRules []ACLMatchingRule `json:"matchingRules"`
+}
+
+// EffectiveACLResource contains the decision for each operation of a resource pattern.
+type EffectiveACLResource struct {
+	ResourceType        string        `json:"resourceType"`
+	ResourceName        string        `json:"resourceName"`
+	ResourcePatternType string        `json:"resourcePatternType"`
+	Permissions         []ACLDecision `json:"permissions"`
+}
+
+// EffectiveACLPermissions lists what a principal can do on all resources that have ACLs
+// which apply to the principal.
+type EffectiveACLPermissions struct {
+	Principal           string                 `json:"principal"`
+	Host                string                 `json:"host"`
+	IsAuthorizerEnabled bool                   `json:"isAuthorizerEnabled"`
+	Resources           []EffectiveACLResource `json:"resources"`
+}
+
+// ExplainACLRequest asks whether a principal is allowed to run an operation on a resource.
+type ExplainACLRequest struct {
+	Principal    string
+	Host         string
+	ResourceType kmsg.ACLResourceType
+	ResourceName string
+	Operation    kmsg.ACLOperation
+	// AllowEveryoneIfNoACLFound mirrors the broker config allow.everyone.if.no.acl.found.
+	AllowEveryoneIfNoACLFound bool
+}
+
+// ExplainACLResponse is the authorization decision for an ExplainACLRequest.
+type ExplainACLResponse struct {
+	Principal    string `json:"principal"`
+	Host         string `json:"host"`
+	ResourceType string `json:"resourceType"`
+	ResourceName string `json:"resourceName"`
+	ACLDecision
+}
+
+// GetEffectiveACLPermissions computes the effective permissions of a principal connecting from the given host
+// by applying Kafka's authorizer semantics to all stored ACLs. If host is empty, only rules for all hosts apply.
+// Resource patterns are evaluated for their own name, so a PREFIXED pattern shows what the principal can
+// do on resources that start with the prefix and do not have more specific rules. Super users are not
+// considered, as they are configured on the brokers.
+func (s *Service) GetEffectiveACLPermissions(ctx context.Context, principal string, host string) (*EffectiveACLPermissions, *rest.Error) {
+	overview, restErr := s.listAllACLsForAuthorization(ctx)
+	if restErr != nil {
+		return nil, restErr
+	}
+
+	res := &EffectiveACLPermissions{
+		Principal:           principal,
+		Host:                host,
+		IsAuthorizerEnabled: overview.IsAuthorizerEnabled,
+		Resources:           effectiveACLPermissions(overview.ACLResources, principal, host),
+	}
+	return res, nil
+}
+
+// ExplainACL decides whether a principal is allowed to run an operation on a resource and returns the rules
+// that decided it.
+func (s *Service) ExplainACL(ctx context.Context, req ExplainACLRequest) (*ExplainACLResponse, *rest.Error) {
+	overview, restErr := s.listAllACLsForAuthorization(ctx)
+	if restErr != nil {
+		return nil, restErr
+	}
+
+	res := &ExplainACLResponse{
+		Principal:    req.Principal,
+		Host:         req.Host,
+		ResourceType: req.ResourceType.String(),
+		ResourceName: req.ResourceName,
+	}
+	if !overview.IsAuthorizerEnabled {
+		res.ACLDecision = ACLDecision{
+			Operation:     req.Operation.String(),
+			Allowed:       true,
+			Reason:        ACLDecisionReasonAuthorizerDisabled,
+			MatchingRules: make([]ACLMatchingRule, 0),
+		}
+		return res, nil
+	}
+
+	res.ACLDecision = authorizeACL(overview.ACLResources, req.Principal, req.Host, req.ResourceType.String(),
+		req.ResourceName, req.Operation, req.AllowEveryoneIfNoACLFound)
+	return res, nil
+}
+
+func (s *Service) listAllACLsForAuthorization(ctx context.Context) (*ACLOverview, *rest.Error) {
+	req := kmsg.NewDescribeACLsRequest()
+	req.ResourceType = kmsg.ACLResourceTypeAny
+	req.ResourcePatternType = kmsg.ACLResourcePatternTypeAny
+	req.Operation = kmsg.ACLOperationAny
+	req.PermissionType = kmsg.ACLPermissionTypeAny
+
+	overview, err := s.ListAllACLs(ctx, req)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Could not list ACLs: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+	return overview, nil
+}
+
+// effectiveACLPermissions evaluates all operations for every resource pattern that has at least one rule
+// applying to the principal and host.
+func effectiveACLPermissions(resources []*ACLResource, principal string, host string) []EffectiveACLResource {
+	effective := make([]EffectiveACLResource, 0)
+	for _, resource := range resources {
+		appliesToPrincipal := false
+		for _, rule := range resource.ACLs {
+			if aclPrincipalMatches(rule.Principal, principal) && aclHostMatches(rule.Host, host) {
+				appliesToPrincipal = true
+				break
+			}
+		}
+		if !appliesToPrincipal {
+			continue
+		}
+
+		resourceType, err := kmsg.ParseACLResourceType(resource.ResourceType)
+		if err != nil {
+			continue
+		}
+		operations := aclOperationsByResourceType[resourceType]
+		permissions := make([]ACLDecision, 0, len(operations))
+		for _, op := range operations {
+			permissions = append(permissions, authorizeACL(resources, principal, host, resource.ResourceType, resource.ResourceName, op, false))
+		}
+		effective = append(effective, EffectiveACLResource{
+			ResourceType:        resource.ResourceType,
+			ResourceName:        resource.ResourceName,
+			ResourcePatternType: resource.ResourcePatternType,
+			Permissions:         permissions,
+		})
+	}
+
+	sort.Slice(effective, func(i, j int) bool {
+		if effective[i].ResourceType != effective[j].ResourceType {
+			return effective[i].ResourceType < effective[j].ResourceType
+		}
+		if effective[i].ResourceName != effective[j].ResourceName {
+			return effective[i].ResourceName < effective[j].ResourceName
+		}
+		return effective[i].ResourcePatternType < effective[j].ResourcePatternType
+	})
+	return effective
+}
+
+// authorizeACL decides whether the principal connecting from host may run the operation on the resource,
+// the same way Kafka's AclAuthorizer does:
+//  1. Only rules whose resource pattern matches the resource are considered: LITERAL rules with the
+//     same name or the wildcard name "*" and PREFIXED rules whose name is a prefix of the resource name.
+//  2. If there are no such rules at all, the decision depends on allow.everyone.if.no.acl.found.
+//  3. A DENY rule for the principal (or User:*), host (or *) and operation (or ALL) denies the operation.
+//  4. Otherwise an ALLOW rule for the principal, host and operation allows it. DESCRIBE is also allowed
+//     by READ, WRITE, DELETE and ALTER, DESCRIBE_CONFIGS is also allowed by ALTER_CONFIGS.
+func authorizeACL(resources []*ACLResource, principal, host, resourceType, resourceName string, operation kmsg.ACLOperation, allowEveryoneIfNoACLFound bool) ACLDecision {
+	decision := ACLDecision{Operation: operation.String(), MatchingRules: make([]ACLMatchingRule, 0)}
+
+	var denies, allows []ACLMatchingRule
+	resourceHasACLs := false
+	for _, resource := range resources {
+		if resource.ResourceType != resourceType || !aclResourcePatternMatches(resource, resourceName) {
+			continue
+		}
+		for _, rule := range resource.ACLs {
+			resourceHasACLs = true
+			if !aclPrincipalMatches(rule.Principal, principal) || !aclHostMatches(rule.Host, host) {
+				continue
+			}
+			matchingRule := ACLMatchingRule{
+				ResourceType:        resource.ResourceType,
+				ResourceName:        resource.ResourceName,
+				ResourcePatternType: resource.ResourcePatternType,
+				Principal:           rule.Principal,
+				Host:                rule.Host,
+				Operation:           rule.Operation,
+				PermissionType:      rule.PermissionType,
+			}
+			switch rule.PermissionType {
+			case kmsg.ACLPermissionTypeDeny.String():
+				if rule.Operation == operation.String() || rule.Operation == kmsg.ACLOperationAll.String() {
+					denies = append(denies, matchingRule)
+				}
+			case kmsg.ACLPermissionTypeAllow.String():
+				if aclOperationImplies(rule.Operation, operation) {
+					allows = append(allows, matchingRule)
+				}
+			}
+		}
+	}
+
+	switch {
+	case !resourceHasACLs:
+		decision.Allowed = allowEveryoneIfNoACLFound
+		decision.Reason = ACLDecisionReasonNoACLsFound
+	case len(denies) > 0:
+		decision.Reason = ACLDecisionReasonDenyRuleMatched
+		decision.MatchingRules = denies
+	case len(allows) > 0:
+		decision.Allowed = true
+		decision.Reason = ACLDecisionReasonAllowRuleMatched
+		decision.MatchingRules = allows
+	default:
+		decision.Reason = ACLDecisionReasonNoMatchingRule
+	}
+	return decision
+}
+
+func aclResourcePatternMatches(resource *ACLResource, resourceName string) bool {
+	switch resource.ResourcePatternType {
+	case kmsg.ACLResourcePatternTypeLiteral.String():
+		return resource.ResourceName == resourceName || resource.ResourceName == aclWildcardResource
+	case kmsg.ACLResourcePatternTypePrefixed.String():
+		return strings.HasPrefix(resourceName, resource.ResourceName)
+	default:
+		return false
+	}
+}
+
+func aclPrincipalMatches(rulePrincipal, principal string) bool {
+	return rulePrincipal == principal || rulePrincipal == aclWildcardPrincipal
+}
+
+func aclHostMatches(ruleHost, host string) bool {
+	return ruleHost == aclWildcardHost || (host != "" && ruleHost == host)
+}
+
+// aclOperationImplies returns whether an ALLOW rule for ruleOperation also allows the given operation.
+func aclOperationImplies(ruleOperation string, operation kmsg.ACLOperation) bool {
+	if ruleOperation == operation.String() || ruleOperation == kmsg.ACLOperationAll.String() {
+		return true
+	}
+
+	switch operation {
+	case kmsg.ACLOperationDescribe:
+		switch ruleOperation {
+		case kmsg.ACLOperationRead.String(), kmsg.ACLOperationWrite.String(),
+			kmsg.ACLOperationDelete.String(), kmsg.ACLOperationAlter.String():
+			return true
+		}
+	case kmsg.ACLOperationDescribeConfigs:
+		return ruleOperation == kmsg.ACLOperationAlterConfigs.String()
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestAuthorizeACL(t *testing.T) {
+	resources := []*ACLResource{
+		{
+			ResourceType:        "TOPIC",
+			ResourceName:        "orders",
+			ResourcePatternType: "PREFIXED",
+			ACLs: []*ACLRule{
+				{Principal: "User:alice", Host: "*", Operation: "READ", PermissionType: "ALLOW"},
+				{Principal: "User:alice", Host: "10.0.0.1", Operation: "WRITE", PermissionType: "ALLOW"},
+			},
+		},
+		{
+			ResourceType:        "TOPIC",
+			ResourceName:        "orders-secret",
+			ResourcePatternType: "LITERAL",
+			ACLs: []*ACLRule{
+				{Principal: "User:*", Host: "*", Operation: "ALL", PermissionType: "DENY"},
+			},
+		},
+		{
+			ResourceType:        "GROUP",
+			ResourceName:        "*",
+			ResourcePatternType: "LITERAL",
+			ACLs: []*ACLRule{
+				{Principal: "User:bob", Host: "*", Operation: "READ", PermissionType: "ALLOW"},
+			},
+		},
+	}
+
+	t.Run("prefixed allow implies describe", func(t *testing.T) {
+		decision := authorizeACL(resources, "User:alice", "", "TOPIC", "orders-eu", kmsg.ACLOperationDescribe, false)
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, ACLDecisionReasonAllowRuleMatched, decision.Reason)
+		require.Len(t, decision.MatchingRules, 1)
+		assert.Equal(t, "READ", decision.MatchingRules[0].Operation)
+	})
+
+	t.Run("host specific rule requires host", func(t *testing.T) {
+		decision := authorizeACL(resources, "User:alice", "", "TOPIC", "orders-eu", kmsg.ACLOperationWrite, false)
+		assert.False(t, decision.Allowed)
+		assert.Equal(t, ACLDecisionReasonNoMatchingRule, decision.Reason)
+
+		decision = authorizeACL(resources, "User:alice", "10.0.0.1", "TOPIC", "orders-eu", kmsg.ACLOperationWrite, false)
+		assert.True(t, decision.Allowed)
+	})
+
+	t.Run("deny takes precedence", func(t *testing.T) {
+		decision := authorizeACL(resources, "User:alice", "", "TOPIC", "orders-secret", kmsg.ACLOperationRead, false)
+		assert.False(t, decision.Allowed)
+		assert.Equal(t, ACLDecisionReasonDenyRuleMatched, decision.Reason)
+		require.Len(t, decision.MatchingRules, 1)
+		assert.Equal(t, "User:*", decision.MatchingRules[0].Principal)
+	})
+
+	t.Run("wildcard resource", func(t *testing.T) {
+		decision := authorizeACL(resources, "User:bob", "", "GROUP", "any-group", kmsg.ACLOperationRead, false)
+		assert.True(t, decision.Allowed)
+	})
+
+	t.Run("no acls found", func(t *testing.T) {
+		decision := authorizeACL(resources, "User:alice", "", "TRANSACTIONAL_ID", "txn", kmsg.ACLOperationWrite, true)
+		assert.True(t, decision.Allowed)
+		assert.Equal(t, ACLDecisionReasonNoACLsFound, decision.Reason)
+	})
+}
+
+func TestEffectiveACLPermissions(t *testing.T) {
+	resources := []*ACLResource{
+		{
+			ResourceType:        "GROUP",
+			ResourceName:        "consumers",
+			ResourcePatternType: "LITERAL",
+			ACLs:                []*ACLRule{{Principal: "User:alice", Host: "*", Operation: "READ", PermissionType: "ALLOW"}},
+		},
+		{
+			ResourceType:        "TOPIC",
+			ResourceName:        "other",
+			ResourcePatternType: "LITERAL",
+			ACLs:                []*ACLRule{{Principal: "User:bob", Host: "*", Operation: "READ", PermissionType: "ALLOW"}},
+		},
+	}
+
+	effective := effectiveACLPermissions(resources, "User:alice", "")
+
+	require.Len(t, effective, 1)
+	assert.Equal(t, "consumers", effective[0].ResourceName)
+	allowed := make(map[string]bool)
+	for _, p := range effective[0].Permissions {
+		allowed[p.Operation] = p.Allowed
+	}
+	assert.Equal(t, map[string]bool{"READ": true, "DESCRIBE": true, "DELETE": false}, allowed)
+}
@@ -0,0 +1,124 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// ACLTemplate describes the ACLs a client application needs, similar to the --producer and --consumer
+// convenience options of kafka-acls.sh.
+type ACLTemplate struct {
+	Principal string `json:"principal"`
+	// Host the client connects from, defaults to '*'.
+	Host string `json:"host"`
+
+	// Producer grants WRITE, DESCRIBE and CREATE on the topic.
+	Producer bool `json:"producer"`
+	// Consumer grants READ and DESCRIBE on the topic and READ on the group.
+	Consumer bool `json:"consumer"`
+
+	TopicName        string                      `json:"topicName"`
+	TopicPatternType kmsg.ACLResourcePatternType `json:"topicPatternType"`
+
+	// GroupID is required for consumers.
+	GroupID          string                      `json:"groupId"`
+	GroupPatternType kmsg.ACLResourcePatternType `json:"groupPatternType"`
+
+	// TransactionalID grants WRITE and DESCRIBE on the transactional ID to producers.
+	TransactionalID            string                      `json:"transactionalId"`
+	TransactionalIDPatternType kmsg.ACLResourcePatternType `json:"transactionalIdPatternType"`
+
+	// Idempotent grants IDEMPOTENT_WRITE on the cluster to producers.
+	Idempotent bool `json:"idempotent"`
+}
+
+// Validate checks whether the template can be expanded.
+func (t *ACLTemplate) Validate() error {
+	if !strings.Contains(t.Principal, ":") {
+		return fmt.Errorf("principal must be set in the format <type>:<name>, but found: %q", t.Principal)
+	}
+	if !t.Producer && !t.Consumer {
+		return fmt.Errorf("template must be for a producer, a consumer or both")
+	}
+	if t.TopicName == "" {
+		return fmt.Errorf("topic name must be set")
+	}
+	if t.Consumer && t.GroupID == "" {
+		return fmt.Errorf("group id must be set for consumers")
+	}
+	if !t.Producer && (t.TransactionalID != "" || t.Idempotent) {
+		return fmt.Errorf("transactional id and idempotent can only be set for producers")
+	}
+	for _, patternType := range []kmsg.ACLResourcePatternType{t.TopicPatternType, t.GroupPatternType, t.TransactionalIDPatternType} {
+		switch patternType {
+		case kmsg.ACLResourcePatternTypeUnknown, kmsg.ACLResourcePatternTypeLiteral, kmsg.ACLResourcePatternTypePrefixed:
+		default:
+			return fmt.Errorf("pattern types must be either LITERAL or PREFIXED, but found: %q", patternType)
+		}
+	}
+	return nil
+}
+
+// Expand returns the ALLOW bindings that the template stands for. The template must be valid.
+func (t *ACLTemplate) Expand() []ACLBinding {
+	host := t.Host
+	if host == "" {
+		host = aclWildcardHost
+	}
+	patternTypeOrLiteral := func(patternType kmsg.ACLResourcePatternType) kmsg.ACLResourcePatternType {
+		if patternType == kmsg.ACLResourcePatternTypeUnknown {
+			return kmsg.ACLResourcePatternTypeLiteral
+		}
+		return patternType
+	}
+
+	bindings := make([]ACLBinding, 0)
+	seen := make(map[ACLBinding]struct{})
+	add := func(resourceType kmsg.ACLResourceType, name string, patternType kmsg.ACLResourcePatternType, ops ...kmsg.ACLOperation) {
+		for _, op := range ops {
+			binding := ACLBinding{
+				ResourceType:        resourceType,
+				ResourceName:        name,
+				ResourcePatternType: patternTypeOrLiteral(patternType),
+				Principal:           t.Principal,
+				Host:                host,
+				Operation:           op,
+				PermissionType:      kmsg.ACLPermissionTypeAllow,
+			}
+			if _, exists := seen[binding]; exists {
+				continue
+			}
+			seen[binding] = struct{}{}
+			bindings = append(bindings, binding)
+		}
+	}
+
+	if t.Producer {
+		add(kmsg.ACLResourceTypeTopic, t.TopicName, t.TopicPatternType,
+			kmsg.ACLOperationWrite, kmsg.ACLOperationDescribe, kmsg.ACLOperationCreate)
+		if t.TransactionalID != "" {
+			add(kmsg.ACLResourceTypeTransactionalId, t.TransactionalID, t.TransactionalIDPatternType,
+				kmsg.ACLOperationWrite, kmsg.ACLOperationDescribe)
+		}
+		if t.Idempotent {
+			add(kmsg.ACLResourceTypeCluster, "kafka-cluster", kmsg.ACLResourcePatternTypeLiteral, kmsg.ACLOperationIdempotentWrite)
+		}
+	}
+	if t.Consumer {
+		add(kmsg.ACLResourceTypeTopic, t.TopicName, t.TopicPatternType, kmsg.ACLOperationRead, kmsg.ACLOperationDescribe)
+		add(kmsg.ACLResourceTypeGroup, t.GroupID, t.GroupPatternType, kmsg.ACLOperationRead)
+	}
+
+	return bindings
+}
@@ -0,0 +1,198 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Quota entity types as used by Kafka.
+const (
+	QuotaEntityTypeUser     = "user"
+	QuotaEntityTypeClientID = "client-id"
+	QuotaEntityTypeIP       = "ip"
+)
+
+// Quota keys as used by Kafka.
+const (
+	QuotaKeyProducerByteRate       = "producer_byte_rate"
+	QuotaKeyConsumerByteRate       = "consumer_byte_rate"
+	QuotaKeyRequestPercentage      = "request_percentage"
+	QuotaKeyControllerMutationRate = "controller_mutation_rate"
+	QuotaKeyConnectionCreationRate = "connection_creation_rate"
+)
+
+// QuotaEntityComponent is a single component of a quota entity, e.g. the user of a
+// user + client-id entity. A nil name refers to the default entity of the type.
+type QuotaEntityComponent struct {
+	Type string  `json:"type"`
+	Name *string `json:"name"`
+}
+
+// AlterQuotasRequest sets and removes quota values of a single entity.
+type AlterQuotasRequest struct {
+	Entity []QuotaEntityComponent
+
+	// Set contains quota values that shall be created or updated.
+	Set []QuotaResponseSetting
+	// Remove contains quota keys whose values shall be removed.
+	Remove []string
+
+	// ValidateOnly lets the brokers validate the request without altering any quotas.
+	ValidateOnly bool
+}
+
+// AlterQuotasResponse is the response after altering the quotas of an entity.
+type AlterQuotasResponse struct {
+	Entity       []QuotaEntityComponent `json:"entity"`
+	ValidateOnly bool                   `json:"validateOnly"`
+
+	// Set and Removed contain the applied (or validated) operations.
+	Set     []QuotaResponseSetting `json:"set"`
+	Removed []string               `json:"removed"`
+}
+
+// AlterQuotas creates, updates or removes the quota values of a single entity.
+func (s *Service) AlterQuotas(ctx context.Context, req AlterQuotasRequest) (*AlterQuotasResponse, *rest.Error) {
+	ops := make([]kmsg.AlterClientQuotasRequestEntryOp, 0, len(req.Set)+len(req.Remove))
+	for _, setting := range req.Set {
+		op := kmsg.NewAlterClientQuotasRequestEntryOp()
+		op.Key = setting.Key
+		op.Value = setting.Value
+		ops = append(ops, op)
+	}
+	for _, key := range req.Remove {
+		op := kmsg.NewAlterClientQuotasRequestEntryOp()
+		op.Key = key
+		op.Remove = true
+		ops = append(ops, op)
+	}
+
+	if restErr := s.alterQuotas(ctx, req.Entity, ops, req.ValidateOnly); restErr != nil {
+		return nil, restErr
+	}
+
+	removed := req.Remove
+	if removed == nil {
+		removed = make([]string, 0)
+	}
+	set := req.Set
+	if set == nil {
+		set = make([]QuotaResponseSetting, 0)
+	}
+	return &AlterQuotasResponse{
+		Entity:       req.Entity,
+		ValidateOnly: req.ValidateOnly,
+		Set:          set,
+		Removed:      removed,
+	}, nil
+}
+
+// DeleteQuotas removes all quota values that are configured for exactly the given entity.
+func (s *Service) DeleteQuotas(ctx context.Context, entity []QuotaEntityComponent, validateOnly bool) (*AlterQuotasResponse, *rest.Error) {
+	// 1. Describe the entity's quota values, because Kafka only removes quotas by key
+	components := make([]kmsg.DescribeClientQuotasRequestComponent, len(entity))
+	for i, e := range entity {
+		component := kmsg.NewDescribeClientQuotasRequestComponent()
+		component.EntityType = e.Type
+		component.MatchType = kmsg.QuotasMatchTypeDefault
+		if e.Name != nil {
+			component.MatchType = kmsg.QuotasMatchTypeExact
+			component.Match = e.Name
+		}
+		components[i] = component
+	}
+	described, err := s.kafkaSvc.DescribeQuotasForEntity(ctx, components)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:     fmt.Errorf("failed to describe quotas: %w", err),
+			Status:  http.StatusServiceUnavailable,
+			Message: fmt.Sprintf("Failed to describe quotas: %v", err.Error()),
+		}
+	}
+	if err := kerr.ErrorForCode(described.ErrorCode); err != nil {
+		return nil, &rest.Error{
+			Err:     fmt.Errorf("failed to describe quotas, inner kafka error: %w", err),
+			Status:  http.StatusServiceUnavailable,
+			Message: fmt.Sprintf("Failed to describe quotas, kafka responded with the following error: %v", err.Error()),
+		}
+	}
+
+	keys := make([]string, 0)
+	for _, entry := range described.Entries {
+		for _, value := range entry.Values {
+			keys = append(keys, value.Key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, &rest.Error{
+			Err:     fmt.Errorf("no quotas configured for the given entity"),
+			Status:  http.StatusNotFound,
+			Message: "There are no quotas configured for the given entity",
+		}
+	}
+
+	// 2. Remove all described values
+	return s.AlterQuotas(ctx, AlterQuotasRequest{
+		Entity:       entity,
+		Remove:       keys,
+		ValidateOnly: validateOnly,
+	})
+}
+
+func (s *Service) alterQuotas(ctx context.Context, entity []QuotaEntityComponent, ops []kmsg.AlterClientQuotasRequestEntryOp, validateOnly bool) *rest.Error {
+	entry := kmsg.NewAlterClientQuotasRequestEntry()
+	entry.Ops = ops
+	for _, e := range entity {
+		component := kmsg.NewAlterClientQuotasRequestEntryEntity()
+		component.Type = e.Type
+		component.Name = e.Name
+		entry.Entity = append(entry.Entity, component)
+	}
+
+	res, err := s.kafkaSvc.AlterQuotas(ctx, []kmsg.AlterClientQuotasRequestEntry{entry}, validateOnly)
+	if err != nil {
+		return &rest.Error{
+			Err:     fmt.Errorf("failed to alter quotas: %w", err),
+			Status:  http.StatusServiceUnavailable,
+			Message: fmt.Sprintf("Failed to alter quotas: %v", err.Error()),
+		}
+	}
+	for _, resEntry := range res.Entries {
+		err := kerr.ErrorForCode(resEntry.ErrorCode)
+		if err == nil {
+			continue
+		}
+		message := err.Error()
+		if resEntry.ErrorMessage != nil {
+			message = fmt.Sprintf("%v: %v", message, *resEntry.ErrorMessage)
+		}
+		status := http.StatusServiceUnavailable
+		switch resEntry.ErrorCode {
+		case kerr.InvalidRequest.Code, kerr.InvalidConfig.Code, kerr.UnsupportedVersion.Code:
+			status = http.StatusBadRequest
+		case kerr.ClusterAuthorizationFailed.Code:
+			status = http.StatusForbidden
+		}
+		return &rest.Error{
+			Err:     fmt.Errorf("failed to alter quotas, inner kafka error: %w", err),
+			Status:  status,
+			Message: fmt.Sprintf("Failed to alter quotas, kafka responded with the following error: %v", message),
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,446 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Source categories of a broker config value.
+const (
+	BrokerConfigSourceStatic  = "STATIC"
+	BrokerConfigSourceDynamic = "DYNAMIC"
+	BrokerConfigSourceDefault = "DEFAULT"
+	BrokerConfigSourceUnknown = "UNKNOWN"
+)
+
+// DynamicBrokerConfig contains the configs of a single broker or the cluster-wide default
+// configs along with everything that is needed to edit them.
+type DynamicBrokerConfig struct {
+	// BrokerID is nil for the cluster-wide default configs.
+	BrokerID *int32                     `json:"brokerId"`
+	Configs  []DynamicBrokerConfigEntry `json:"configs"`
+}
+
+// DynamicBrokerConfigEntry is a broker config entry merged with the config extension metadata.
+type DynamicBrokerConfigEntry struct {
+	Name  string  `json:"name"`
+	Value *string `json:"value"` // If value is sensitive this will be nil
+	// Source is the Kafka config source, SourceCategory groups it into STATIC, DYNAMIC or DEFAULT.
+	Source         string `json:"source"`
+	SourceCategory string `json:"sourceCategory"`
+	// DefaultValue is the value the broker uses if the config is not set at all.
+	DefaultValue   *string `json:"defaultValue"`
+	IsDefaultValue bool    `json:"isDefaultValue"`
+	IsReadOnly     bool    `json:"isReadOnly"`
+	IsSensitive    bool    `json:"isSensitive"`
+	Type           string  `json:"type"`
+	Documentation  *string `json:"documentation"`
+
+	Category       string         `json:"category,omitempty"`
+	FrontendFormat FrontendFormat `json:"frontendFormat,omitempty"`
+	EnumValues     []string       `json:"enumValues,omitempty"`
+
+	Synonyms []BrokerConfigSynonym `json:"synonyms"`
+
+	configType kmsg.ConfigType
+	synonyms   []kmsg.DescribeConfigsResponseResourceConfigConfigSynonym
+}
+
+// BrokerConfigChange is a change to a single dynamic broker config.
+type BrokerConfigChange struct {
+	Name string `json:"name"`
+	// Op is either SET or DELETE. Deleting a config restores the value inherited from the next lower level.
+	Op    kmsg.IncrementalAlterConfigOp `json:"op"`
+	Value *string                       `json:"value"`
+}
+
+// BrokerConfigDiffEntry shows how a single config changes.
+type BrokerConfigDiffEntry struct {
+	Name         string  `json:"name"`
+	Op           string  `json:"op"`
+	OldValue     *string `json:"oldValue"`
+	OldSource    string  `json:"oldSource"`
+	NewValue     *string `json:"newValue"`
+	DefaultValue *string `json:"defaultValue"`
+}
+
+// AlterDynamicBrokerConfigResponse is the result of altering dynamic broker configs.
+type AlterDynamicBrokerConfigResponse struct {
+	BrokerID     *int32                  `json:"brokerId"`
+	ValidateOnly bool                    `json:"validateOnly"`
+	Changes      []BrokerConfigDiffEntry `json:"changes"`
+}
+
+// GetDynamicBrokerConfig returns the configs of the given broker, or the cluster-wide default configs if brokerID
+// is nil. The cluster-wide defaults are resolved from the synonyms of an arbitrary broker, ignoring its
+// per-broker and static configs.
+func (s *Service) GetDynamicBrokerConfig(ctx context.Context, brokerID *int32) (*DynamicBrokerConfig, *rest.Error) {
+	describeBrokerID := int32(0)
+	if brokerID != nil {
+		describeBrokerID = *brokerID
+	} else {
+		metadata, err := s.kafkaSvc.GetMetadata(ctx, nil)
+		if err != nil {
+			return nil, &rest.Error{
+				Err:      fmt.Errorf("failed to get broker ids: %w", err),
+				Status:   http.StatusServiceUnavailable,
+				Message:  fmt.Sprintf("Failed to get broker ids: %v", err.Error()),
+				IsSilent: false,
+			}
+		}
+		if len(metadata.Brokers) == 0 {
+			return nil, &rest.Error{
+				Err:      fmt.Errorf("metadata response did not contain any brokers"),
+				Status:   http.StatusServiceUnavailable,
+				Message:  "Could not find any broker to describe the cluster's default configs",
+				IsSilent: false,
+			}
+		}
+		describeBrokerID = metadata.Brokers[0].NodeID
+	}
+
+	res, err := s.kafkaSvc.DescribeBrokerConfigResource(ctx, strconv.Itoa(int(describeBrokerID)), nil)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("failed to request broker config: %w", err),
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to request broker's config: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+	if len(res.Resources) != 1 {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("unexpected number of resources in describe config response: %d", len(res.Resources)),
+			Status:   http.StatusInternalServerError,
+			Message:  "Broker config response did not contain the requested broker",
+			IsSilent: false,
+		}
+	}
+	resource := res.Resources[0]
+	if err := kerr.ErrorForCode(resource.ErrorCode); err != nil {
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to describe broker config resource: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	return &DynamicBrokerConfig{
+		BrokerID: brokerID,
+		Configs:  newDynamicBrokerConfigEntries(resource.Configs, s.configExtensionsByName, brokerID == nil),
+	}, nil
+}
+
+// AlterDynamicBrokerConfig validates the changes against the described configs and applies them to the given broker,
+// or to the cluster-wide defaults if brokerID is nil. If validateOnly is true, the brokers only validate the changes.
+func (s *Service) AlterDynamicBrokerConfig(ctx context.Context, brokerID *int32, changes []BrokerConfigChange, validateOnly bool) (*AlterDynamicBrokerConfigResponse, *rest.Error) {
+	// 1. Validate the changes against the current configs
+	current, restErr := s.GetDynamicBrokerConfig(ctx, brokerID)
+	if restErr != nil {
+		return nil, restErr
+	}
+	diff, err := diffDynamicBrokerConfig(current.Configs, changes, brokerID == nil)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusBadRequest,
+			Message:  fmt.Sprintf("Invalid broker config changes: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	// 2. Alter configs, the brokers validate the changes once more
+	resourceName := ""
+	if brokerID != nil {
+		resourceName = strconv.Itoa(int(*brokerID))
+	}
+	resource := kmsg.NewIncrementalAlterConfigsRequestResource()
+	resource.ResourceType = kmsg.ConfigResourceTypeBroker
+	resource.ResourceName = resourceName
+	for _, change := range changes {
+		config := kmsg.NewIncrementalAlterConfigsRequestResourceConfig()
+		config.Name = change.Name
+		config.Op = change.Op
+		config.Value = change.Value
+		resource.Configs = append(resource.Configs, config)
+	}
+
+	alterRes, err := s.kafkaSvc.IncrementalAlterConfigsWithValidateOnly(ctx, []kmsg.IncrementalAlterConfigsRequestResource{resource}, validateOnly)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Incremental Alter Config request has failed: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+	for _, res := range alterRes.Resources {
+		if err := kerr.ErrorForCode(res.ErrorCode); err != nil {
+			msg := err.Error()
+			if res.ErrorMessage != nil {
+				msg = fmt.Sprintf("%v: %v", msg, *res.ErrorMessage)
+			}
+			return nil, &rest.Error{
+				Err:      fmt.Errorf("failed to alter broker config: %v", msg),
+				Status:   http.StatusBadRequest,
+				Message:  fmt.Sprintf("Failed to alter broker config: %v", msg),
+				IsSilent: false,
+			}
+		}
+	}
+
+	return &AlterDynamicBrokerConfigResponse{
+		BrokerID:     brokerID,
+		ValidateOnly: validateOnly,
+		Changes:      diff,
+	}, nil
+}
+
+// newDynamicBrokerConfigEntries converts the described configs of a broker. If clusterDefault is true,
+// the entries are resolved from the cluster-wide dynamic default and the default synonyms only.
+func newDynamicBrokerConfigEntries(configs []kmsg.DescribeConfigsResponseResourceConfig, extensions map[string]ConfigEntryExtension, clusterDefault bool) []DynamicBrokerConfigEntry {
+	entries := make([]DynamicBrokerConfigEntry, 0, len(configs))
+	for _, cfg := range configs {
+		extension := extensions[cfg.Name]
+
+		synonyms := make([]kmsg.DescribeConfigsResponseResourceConfigConfigSynonym, 0, len(cfg.ConfigSynonyms))
+		for _, synonym := range cfg.ConfigSynonyms {
+			if clusterDefault && synonym.Source != kmsg.ConfigSourceDynamicDefaultBrokerConfig && synonym.Source != kmsg.ConfigSourceDefaultConfig {
+				continue
+			}
+			synonyms = append(synonyms, synonym)
+		}
+
+		value, source := cfg.Value, cfg.Source
+		if clusterDefault {
+			// Synonyms are ordered by precedence, so the first one is the effective value
+			value, source = nil, kmsg.ConfigSourceUnknown
+			if len(synonyms) > 0 {
+				value, source = synonyms[0].Value, synonyms[0].Source
+			}
+		}
+
+		var defaultValue *string
+		convertedSynonyms := make([]BrokerConfigSynonym, len(synonyms))
+		for i, synonym := range synonyms {
+			convertedSynonyms[i] = BrokerConfigSynonym{
+				Name:   synonym.Name,
+				Value:  synonym.Value,
+				Source: synonym.Source.String(),
+			}
+			if synonym.Source == kmsg.ConfigSourceDefaultConfig {
+				defaultValue = synonym.Value
+			}
+		}
+
+		configType := cfg.ConfigType
+		if configType == kmsg.ConfigTypeUnknown {
+			configType = extension.Type
+		}
+		documentation := cfg.Documentation
+		if documentation == nil {
+			documentation = extension.Documentation
+		}
+
+		entries = append(entries, DynamicBrokerConfigEntry{
+			Name:           cfg.Name,
+			Value:          value,
+			Source:         source.String(),
+			SourceCategory: brokerConfigSourceCategory(source),
+			DefaultValue:   defaultValue,
+			IsDefaultValue: defaultValue != nil && derefString(value) == derefString(defaultValue),
+			IsReadOnly:     cfg.ReadOnly,
+			IsSensitive:    cfg.IsSensitive,
+			Type:           configType.String(),
+			Documentation:  documentation,
+			Category:       extension.ConfigCategory,
+			FrontendFormat: extension.FrontendFormat,
+			EnumValues:     extension.EnumValues,
+			Synonyms:       convertedSynonyms,
+			configType:     configType,
+			synonyms:       synonyms,
+		})
+	}
+	return entries
+}
+
+func brokerConfigSourceCategory(source kmsg.ConfigSource) string {
+	switch source {
+	case kmsg.ConfigSourceStaticBrokerConfig:
+		return BrokerConfigSourceStatic
+	case kmsg.ConfigSourceDynamicBrokerConfig, kmsg.ConfigSourceDynamicDefaultBrokerConfig, kmsg.ConfigSourceDynamicBrokerLoggerConfig:
+		return BrokerConfigSourceDynamic
+	case kmsg.ConfigSourceDefaultConfig:
+		return BrokerConfigSourceDefault
+	default:
+		return BrokerConfigSourceUnknown
+	}
+}
+
+// diffDynamicBrokerConfig validates the changes and returns how each config changes. All validation errors
+// are returned at once. Configs that are not described by the broker (e.g. listener prefixed configs) can't
+// be validated here and are left to the broker.
+func diffDynamicBrokerConfig(entries []DynamicBrokerConfigEntry, changes []BrokerConfigChange, clusterDefault bool) ([]BrokerConfigDiffEntry, error) {
+	entriesByName := make(map[string]DynamicBrokerConfigEntry, len(entries))
+	for _, entry := range entries {
+		entriesByName[entry.Name] = entry
+	}
+	scopeSource := kmsg.ConfigSourceDynamicBrokerConfig
+	if clusterDefault {
+		scopeSource = kmsg.ConfigSourceDynamicDefaultBrokerConfig
+	}
+
+	var errs []string
+	diff := make([]BrokerConfigDiffEntry, 0, len(changes))
+	seen := make(map[string]struct{}, len(changes))
+	for _, change := range changes {
+		if _, exists := seen[change.Name]; exists {
+			errs = append(errs, fmt.Sprintf("%v: must only be changed once", change.Name))
+			continue
+		}
+		seen[change.Name] = struct{}{}
+
+		entry, exists := entriesByName[change.Name]
+		d := BrokerConfigDiffEntry{Name: change.Name, Op: change.Op.String()}
+		if exists {
+			if entry.IsReadOnly {
+				errs = append(errs, fmt.Sprintf("%v: is read-only and can't be changed dynamically", change.Name))
+				continue
+			}
+			if entry.IsSensitive {
+				errs = append(errs, fmt.Sprintf("%v: is sensitive and can't be changed here", change.Name))
+				continue
+			}
+			d.OldValue = entry.Value
+			d.OldSource = entry.Source
+			d.DefaultValue = entry.DefaultValue
+		}
+
+		switch change.Op {
+		case kmsg.IncrementalAlterConfigOpSet:
+			if change.Value == nil {
+				errs = append(errs, fmt.Sprintf("%v: value must be set", change.Name))
+				continue
+			}
+			if exists {
+				if err := validateBrokerConfigValue(entry, *change.Value); err != nil {
+					errs = append(errs, fmt.Sprintf("%v: %v", change.Name, err.Error()))
+					continue
+				}
+			}
+			d.NewValue = change.Value
+		case kmsg.IncrementalAlterConfigOpDelete:
+			if exists {
+				if entry.Source != scopeSource.String() {
+					errs = append(errs, fmt.Sprintf("%v: is not set dynamically on this level", change.Name))
+					continue
+				}
+				// The value inherited from the next lower level becomes effective
+				for _, synonym := range entry.synonyms {
+					if synonym.Source != scopeSource {
+						d.NewValue = synonym.Value
+						break
+					}
+				}
+			}
+		default:
+			errs = append(errs, fmt.Sprintf("%v: op must be either SET or DELETE", change.Name))
+			continue
+		}
+		diff = append(diff, d)
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%v", strings.Join(errs, "; "))
+	}
+	return diff, nil
+}
+
+// validateBrokerConfigValue checks the value against the frontend format of the config extension or,
+// if there is none, against the config type reported by Kafka.
+func validateBrokerConfigValue(entry DynamicBrokerConfigEntry, value string) error {
+	isEnumValue := func(v string) bool {
+		for _, enumValue := range entry.EnumValues {
+			if enumValue == v {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch entry.FrontendFormat {
+	case FrontendFormatBoolean:
+		return validateBooleanConfigValue(value)
+	case FrontendFormatInteger, FrontendFormatByteSize, FrontendFormatDuration:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf("value %q must be an integer", value)
+		}
+		return nil
+	case FrontendFormatDecimal, FrontendFormatRatio:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("value %q must be a decimal number", value)
+		}
+		return nil
+	case FrontendFormatSelect:
+		if len(entry.EnumValues) > 0 && !isEnumValue(value) {
+			return fmt.Errorf("value %q must be one of %v", value, strings.Join(entry.EnumValues, ", "))
+		}
+		return nil
+	case FrontendFormatMultiSelect:
+		if len(entry.EnumValues) == 0 || value == "" {
+			return nil
+		}
+		for _, v := range strings.Split(value, ",") {
+			if !isEnumValue(strings.TrimSpace(v)) {
+				return fmt.Errorf("value %q must be one of %v", strings.TrimSpace(v), strings.Join(entry.EnumValues, ", "))
+			}
+		}
+		return nil
+	}
+
+	switch entry.configType {
+	case kmsg.ConfigTypeBoolean:
+		return validateBooleanConfigValue(value)
+	case kmsg.ConfigTypeShort:
+		if _, err := strconv.ParseInt(value, 10, 16); err != nil {
+			return fmt.Errorf("value %q must be a short", value)
+		}
+	case kmsg.ConfigTypeInt:
+		if _, err := strconv.ParseInt(value, 10, 32); err != nil {
+			return fmt.Errorf("value %q must be an int", value)
+		}
+	case kmsg.ConfigTypeLong:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf("value %q must be a long", value)
+		}
+	case kmsg.ConfigTypeDouble:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("value %q must be a double", value)
+		}
+	}
+	return nil
+}
+
+func validateBooleanConfigValue(value string) error {
+	if !strings.EqualFold(value, "true") && !strings.EqualFold(value, "false") {
+		return fmt.Errorf("value %q must be either true or false", value)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func testDescribedBrokerConfigs() []kmsg.DescribeConfigsResponseResourceConfig {
+	synonym := func(name string, value string, source kmsg.ConfigSource) kmsg.DescribeConfigsResponseResourceConfigConfigSynonym {
+		return kmsg.DescribeConfigsResponseResourceConfigConfigSynonym{Name: name, Value: &value, Source: source}
+	}
+	str := func(s string) *string { return &s }
+
+	return []kmsg.DescribeConfigsResponseResourceConfig{
+		{
+			Name:   "log.retention.ms",
+			Value:  str("3600000"),
+			Source: kmsg.ConfigSourceDynamicBrokerConfig,
+			ConfigSynonyms: []kmsg.DescribeConfigsResponseResourceConfigConfigSynonym{
+				synonym("log.retention.ms", "3600000", kmsg.ConfigSourceDynamicBrokerConfig),
+				synonym("log.retention.ms", "7200000", kmsg.ConfigSourceDynamicDefaultBrokerConfig),
+				synonym("log.retention.hours", "168", kmsg.ConfigSourceDefaultConfig),
+			},
+			ConfigType: kmsg.ConfigTypeLong,
+		},
+		{
+			Name:   "broker.id",
+			Value:  str("1"),
+			Source: kmsg.ConfigSourceStaticBrokerConfig,
+			ConfigSynonyms: []kmsg.DescribeConfigsResponseResourceConfigConfigSynonym{
+				synonym("broker.id", "1", kmsg.ConfigSourceStaticBrokerConfig),
+			},
+			ReadOnly:   true,
+			ConfigType: kmsg.ConfigTypeInt,
+		},
+		{
+			Name:        "ssl.keystore.password",
+			Source:      kmsg.ConfigSourceDynamicBrokerConfig,
+			IsSensitive: true,
+			ConfigType:  kmsg.ConfigTypePassword,
+		},
+		{
+			Name:   "compression.type",
+			Value:  str("producer"),
+			Source: kmsg.ConfigSourceDefaultConfig,
+			ConfigSynonyms: []kmsg.DescribeConfigsResponseResourceConfigConfigSynonym{
+				synonym("compression.type", "producer", kmsg.ConfigSourceDefaultConfig),
+			},
+			ConfigType: kmsg.ConfigTypeString,
+		},
+	}
+}
+
+func TestNewDynamicBrokerConfigEntries(t *testing.T) {
+	extensions := map[string]ConfigEntryExtension{
+		"compression.type": {FrontendFormat: FrontendFormatSelect, EnumValues: []string{"producer", "gzip", "zstd"}},
+	}
+
+	t.Run("broker", func(t *testing.T) {
+		entries := newDynamicBrokerConfigEntries(testDescribedBrokerConfigs(), extensions, false)
+		require.Len(t, entries, 4)
+		assert.Equal(t, "3600000", *entries[0].Value)
+		assert.Equal(t, BrokerConfigSourceDynamic, entries[0].SourceCategory)
+		assert.Equal(t, "168", *entries[0].DefaultValue)
+		assert.False(t, entries[0].IsDefaultValue)
+		assert.Equal(t, BrokerConfigSourceStatic, entries[1].SourceCategory)
+		assert.True(t, entries[3].IsDefaultValue)
+		assert.Equal(t, FrontendFormatSelect, entries[3].FrontendFormat)
+	})
+
+	t.Run("cluster default ignores per broker configs", func(t *testing.T) {
+		entries := newDynamicBrokerConfigEntries(testDescribedBrokerConfigs(), extensions, true)
+		require.Len(t, entries, 4)
+		assert.Equal(t, "7200000", *entries[0].Value)
+		assert.Equal(t, kmsg.ConfigSourceDynamicDefaultBrokerConfig.String(), entries[0].Source)
+		assert.Len(t, entries[0].Synonyms, 2)
+		assert.Nil(t, entries[1].Value)
+		assert.Equal(t, BrokerConfigSourceUnknown, entries[1].SourceCategory)
+	})
+}
+
+func TestDiffDynamicBrokerConfig(t *testing.T) {
+	extensions := map[string]ConfigEntryExtension{
+		"compression.type": {FrontendFormat: FrontendFormatSelect, EnumValues: []string{"producer", "gzip", "zstd"}},
+	}
+	entries := newDynamicBrokerConfigEntries(testDescribedBrokerConfigs(), extensions, false)
+	str := func(s string) *string { return &s }
+
+	t.Run("valid changes", func(t *testing.T) {
+		diff, err := diffDynamicBrokerConfig(entries, []BrokerConfigChange{
+			{Name: "log.retention.ms", Op: kmsg.IncrementalAlterConfigOpDelete},
+			{Name: "compression.type", Op: kmsg.IncrementalAlterConfigOpSet, Value: str("zstd")},
+			{Name: "listener.name.internal.ssl.endpoint.identification.algorithm", Op: kmsg.IncrementalAlterConfigOpSet, Value: str("")},
+		}, false)
+		require.NoError(t, err)
+		require.Len(t, diff, 3)
+		assert.Equal(t, "3600000", *diff[0].OldValue)
+		assert.Equal(t, "7200000", *diff[0].NewValue)
+		assert.Equal(t, "producer", *diff[1].OldValue)
+		assert.Equal(t, "zstd", *diff[1].NewValue)
+		assert.Nil(t, diff[2].OldValue)
+	})
+
+	t.Run("rejects invalid changes", func(t *testing.T) {
+		_, err := diffDynamicBrokerConfig(entries, []BrokerConfigChange{
+			{Name: "broker.id", Op: kmsg.IncrementalAlterConfigOpSet, Value: str("2")},
+			{Name: "ssl.keystore.password", Op: kmsg.IncrementalAlterConfigOpSet, Value: str("secret")},
+			{Name: "log.retention.ms", Op: kmsg.IncrementalAlterConfigOpSet, Value: str("one hour")},
+			{Name: "compression.type", Op: kmsg.IncrementalAlterConfigOpSet, Value: str("brotli")},
+		}, false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "broker.id: is read-only")
+		assert.Contains(t, err.Error(), "ssl.keystore.password: is sensitive")
+		assert.Contains(t, err.Error(), "log.retention.ms: value \"one hour\" must be a long")
+		assert.Contains(t, err.Error(), "compression.type: value \"brotli\" must be one of")
+	})
+
+	t.Run("delete requires config on the same level", func(t *testing.T) {
+		_, err := diffDynamicBrokerConfig(entries, []BrokerConfigChange{
+			{Name: "compression.type", Op: kmsg.IncrementalAlterConfigOpDelete},
+		}, false)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,402 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Operations that can be run against many topics at once.
+const (
+	BulkTopicOperationDelete        = "DELETE"
+	BulkTopicOperationEditConfigs   = "EDIT_CONFIGS"
+	BulkTopicOperationDeleteRecords = "DELETE_RECORDS"
+)
+
+// Outcomes of a bulk topic operation for a single topic.
+const (
+	BulkTopicStatusDryRun    = "DRY_RUN"
+	BulkTopicStatusForbidden = "FORBIDDEN"
+	BulkTopicStatusSkipped   = "SKIPPED"
+	BulkTopicStatusSucceeded = "SUCCEEDED"
+	BulkTopicStatusFailed    = "FAILED"
+)
+
+// TopicSelector selects the topics of a bulk operation. Exactly one of the fields must be set.
+// The regex must match the whole topic name. Internal topics are never selected.
+type TopicSelector struct {
+	Regex      string   `json:"regex,omitempty"`
+	Prefix     string   `json:"prefix,omitempty"`
+	TopicNames []string `json:"topicNames,omitempty"`
+}
+
+// TopicSelection contains the selected topics. Explicitly listed topics that do not exist or are
+// internal topics are returned as unmatched.
+type TopicSelection struct {
+	TopicNames          []string `json:"topicNames"`
+	UnmatchedTopicNames []string `json:"unmatchedTopicNames"`
+}
+
+// BulkTopicResult is the outcome of a bulk operation for a single topic.
+type BulkTopicResult struct {
+	TopicName string `json:"topicName"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+
+	// Partitions contains the records to delete in each partition for record deletions.
+	Partitions []BulkTopicPartitionResult `json:"partitions,omitempty"`
+}
+
+// BulkTopicPartitionResult describes the records that are deleted from a single partition.
+type BulkTopicPartitionResult struct {
+	PartitionID   int32 `json:"partitionId"`
+	LowWaterMark  int64 `json:"lowWaterMark"`
+	HighWaterMark int64 `json:"highWaterMark"`
+	// DeleteBefore is the new low water mark, all records before this offset are deleted.
+	DeleteBefore int64  `json:"deleteBefore"`
+	Error        string `json:"error,omitempty"`
+}
+
+// RecordsDeletionTarget defines up to which point records are deleted. Exactly one of the
+// fields must be set.
+type RecordsDeletionTarget struct {
+	// Offset deletes all records before this offset in each partition. Use -1 to delete all records,
+	// that is all records up to the high water marks of the dry run. Records produced afterwards
+	// are never deleted, instead the dry run must be repeated.
+	Offset *int64 `json:"offset,omitempty"`
+	// Timestamp deletes all records before the first record with a timestamp (unix millis) at or
+	// after the given timestamp.
+	Timestamp *int64 `json:"timestamp,omitempty"`
+}
+
+// Validate checks that exactly one selection criterion is set.
+func (t *TopicSelector) Validate() error {
+	set := 0
+	if t.Regex != "" {
+		set++
+		if _, err := regexp.Compile(t.Regex); err != nil {
+			return fmt.Errorf("invalid regex: %w", err)
+		}
+	}
+	if t.Prefix != "" {
+		set++
+	}
+	if len(t.TopicNames) > 0 {
+		set++
+	}
+	if set != 1 {
+		return fmt.Errorf("exactly one of regex, prefix or topicNames must be set")
+	}
+	return nil
+}
+
+// Validate checks that exactly one of offset and timestamp is set.
+func (r *RecordsDeletionTarget) Validate() error {
+	if (r.Offset == nil) == (r.Timestamp == nil) {
+		return fmt.Errorf("exactly one of offset or timestamp must be set")
+	}
+	if r.Offset != nil && *r.Offset < -1 {
+		return fmt.Errorf("offset must be greater than or equal to -1")
+	}
+	if r.Timestamp != nil && *r.Timestamp < 0 {
+		return fmt.Errorf("timestamp must not be negative")
+	}
+	return nil
+}
+
+// selectTopics returns the matching topics. The selector must be valid.
+func selectTopics(selector TopicSelector, topicNames []string, isInternal func(string) bool) TopicSelection {
+	selection := TopicSelection{TopicNames: make([]string, 0), UnmatchedTopicNames: make([]string, 0)}
+
+	if len(selector.TopicNames) > 0 {
+		existing := make(map[string]struct{}, len(topicNames))
+		for _, topicName := range topicNames {
+			existing[topicName] = struct{}{}
+		}
+		seen := make(map[string]struct{}, len(selector.TopicNames))
+		for _, topicName := range selector.TopicNames {
+			if _, duplicate := seen[topicName]; duplicate {
+				continue
+			}
+			seen[topicName] = struct{}{}
+			if _, exists := existing[topicName]; !exists || isInternal(topicName) {
+				selection.UnmatchedTopicNames = append(selection.UnmatchedTopicNames, topicName)
+				continue
+			}
+			selection.TopicNames = append(selection.TopicNames, topicName)
+		}
+	} else {
+		var regex *regexp.Regexp
+		if selector.Regex != "" {
+			// Anchored so that e.g. "orders" does not select "orders-archive" as well
+			regex = regexp.MustCompile(`^(?:` + selector.Regex + `)$`)
+		}
+		for _, topicName := range topicNames {
+			if isInternal(topicName) {
+				continue
+			}
+			if regex != nil && regex.MatchString(topicName) || selector.Prefix != "" && strings.HasPrefix(topicName, selector.Prefix) {
+				selection.TopicNames = append(selection.TopicNames, topicName)
+			}
+		}
+	}
+
+	sort.Strings(selection.TopicNames)
+	sort.Strings(selection.UnmatchedTopicNames)
+	return selection
+}
+
+// SelectTopics returns all topics that match the selector.
+func (s *Service) SelectTopics(ctx context.Context, selector TopicSelector) (*TopicSelection, *rest.Error) {
+	metadata, err := s.kafkaSvc.KafkaAdmClient.Metadata(ctx)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("failed to request metadata: %w", err),
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to request metadata: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	selection := selectTopics(selector, metadata.Topics.Names(), func(topicName string) bool {
+		return metadata.Topics[topicName].IsInternal || strings.HasPrefix(topicName, "__")
+	})
+	return &selection, nil
+}
+
+// BulkTopicOperationToken signs the operation, its parameters and the selected topics. A bulk
+// operation is only run if the token of its dry run is passed, so that the affected topics
+// can't change between the dry run and the actual run. The token is an HMAC with a random key
+// of this process, hence clients can't compute it without running the dry run.
+func (s *Service) BulkTopicOperationToken(operation string, topicNames []string, params interface{}) string {
+	// Marshalling can't fail, all parameters are serializable
+	payload, _ := json.Marshal(struct {
+		Operation  string      `json:"operation"`
+		TopicNames []string    `json:"topicNames"`
+		Params     interface{} `json:"params"`
+	}{operation, topicNames, params})
+	mac := hmac.New(sha256.New, s.bulkTopicTokenKey)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))[:32]
+}
+
+// DeleteTopics deletes all given topics.
+func (s *Service) DeleteTopics(ctx context.Context, topicNames []string) []BulkTopicResult {
+	responses, err := s.kafkaSvc.KafkaAdmClient.DeleteTopics(ctx, topicNames...)
+	results := make([]BulkTopicResult, len(topicNames))
+	for i, topicName := range topicNames {
+		results[i] = bulkTopicResult(topicName, err, func() error {
+			res, exists := responses[topicName]
+			if !exists {
+				return fmt.Errorf("no response for topic")
+			}
+			return res.Err
+		})
+	}
+	return results
+}
+
+// EditTopicsConfigs incrementally alters the configs of all given topics. With validateOnly the
+// changes are only validated by the brokers.
+func (s *Service) EditTopicsConfigs(ctx context.Context, topicNames []string, configs []kmsg.IncrementalAlterConfigsRequestResourceConfig, validateOnly bool) []BulkTopicResult {
+	alterConfigs := make([]kadm.AlterConfig, len(configs))
+	for i, cfg := range configs {
+		alterConfigs[i] = kadm.AlterConfig{Op: kadm.IncrementalOp(cfg.Op), Name: cfg.Name, Value: cfg.Value}
+	}
+
+	alter := s.kafkaSvc.KafkaAdmClient.AlterTopicConfigs
+	if validateOnly {
+		alter = s.kafkaSvc.KafkaAdmClient.ValidateAlterTopicConfigs
+	}
+	responses, err := alter(ctx, alterConfigs, topicNames...)
+
+	errByTopic := make(map[string]error, len(responses))
+	for _, res := range responses {
+		errByTopic[res.Name] = res.Err
+	}
+	results := make([]BulkTopicResult, len(topicNames))
+	for i, topicName := range topicNames {
+		results[i] = bulkTopicResult(topicName, err, func() error {
+			topicErr, exists := errByTopic[topicName]
+			if !exists {
+				return fmt.Errorf("no response for topic")
+			}
+			return topicErr
+		})
+	}
+	return results
+}
+
+// PlanTopicsRecordsDeletion resolves the deletion target to an offset for each partition of
+// the given topics. Partitions without records before the target are omitted.
+func (s *Service) PlanTopicsRecordsDeletion(ctx context.Context, topicNames []string, target RecordsDeletionTarget) ([]BulkTopicResult, *rest.Error) {
+	admCl := s.kafkaSvc.KafkaAdmClient
+	startOffsets, err := admCl.ListStartOffsets(ctx, topicNames...)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("failed to list start offsets: %w", err),
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to list start offsets: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+	endOffsets, err := admCl.ListEndOffsets(ctx, topicNames...)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("failed to list end offsets: %w", err),
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to list end offsets: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+	var timestampOffsets kadm.ListedOffsets
+	if target.Timestamp != nil {
+		timestampOffsets, err = admCl.ListOffsetsAfterMilli(ctx, *target.Timestamp, topicNames...)
+		if err != nil {
+			return nil, &rest.Error{
+				Err:      fmt.Errorf("failed to list offsets by timestamp: %w", err),
+				Status:   http.StatusServiceUnavailable,
+				Message:  fmt.Sprintf("Failed to list offsets by timestamp: %v", err.Error()),
+				IsSilent: false,
+			}
+		}
+	}
+
+	results := make([]BulkTopicResult, len(topicNames))
+	for i, topicName := range topicNames {
+		result := BulkTopicResult{TopicName: topicName, Partitions: make([]BulkTopicPartitionResult, 0)}
+		for _, end := range endOffsets[topicName] {
+			start, _ := startOffsets.Lookup(topicName, end.Partition)
+			partition := BulkTopicPartitionResult{
+				PartitionID:   end.Partition,
+				LowWaterMark:  start.Offset,
+				HighWaterMark: end.Offset,
+			}
+			if end.Err != nil || start.Err != nil {
+				partition.Error = fmt.Sprintf("failed to list watermarks: %v", firstError(end.Err, start.Err))
+				result.Partitions = append(result.Partitions, partition)
+				continue
+			}
+
+			partition.DeleteBefore = end.Offset
+			if target.Offset != nil && *target.Offset >= 0 && *target.Offset < end.Offset {
+				partition.DeleteBefore = *target.Offset
+			}
+			if target.Timestamp != nil {
+				listed, exists := timestampOffsets.Lookup(topicName, end.Partition)
+				if !exists || listed.Err != nil {
+					partition.Error = "failed to list offset by timestamp"
+					result.Partitions = append(result.Partitions, partition)
+					continue
+				}
+				if listed.Offset >= 0 && listed.Offset < end.Offset {
+					partition.DeleteBefore = listed.Offset
+				}
+			}
+			if partition.DeleteBefore <= partition.LowWaterMark {
+				continue
+			}
+			result.Partitions = append(result.Partitions, partition)
+		}
+		sort.Slice(result.Partitions, func(a, b int) bool {
+			return result.Partitions[a].PartitionID < result.Partitions[b].PartitionID
+		})
+		results[i] = result
+	}
+	return results, nil
+}
+
+// DeleteTopicsRecords deletes the records as planned by PlanTopicsRecordsDeletion. The status
+// of each result is set, partitions with planning errors are not touched. Topics without
+// records to delete are skipped.
+func (s *Service) DeleteTopicsRecords(ctx context.Context, plan []BulkTopicResult) []BulkTopicResult {
+	var offsets kadm.Offsets
+	for _, topic := range plan {
+		for _, partition := range topic.Partitions {
+			if partition.Error == "" {
+				offsets.Add(kadm.Offset{Topic: topic.TopicName, Partition: partition.PartitionID, At: partition.DeleteBefore, LeaderEpoch: -1})
+			}
+		}
+	}
+
+	var responses kadm.DeleteRecordsResponses
+	var err error
+	if len(offsets) > 0 {
+		responses, err = s.kafkaSvc.KafkaAdmClient.DeleteRecords(ctx, offsets)
+	}
+
+	results := make([]BulkTopicResult, len(plan))
+	for i, topic := range plan {
+		result := topic
+		result.Status = BulkTopicStatusSucceeded
+		if len(topic.Partitions) == 0 {
+			result.Status = BulkTopicStatusSkipped
+		}
+		result.Partitions = make([]BulkTopicPartitionResult, len(topic.Partitions))
+		for j, partition := range topic.Partitions {
+			switch {
+			case partition.Error != "":
+			case err != nil:
+				partition.Error = err.Error()
+			default:
+				res, exists := responses[topic.TopicName][partition.PartitionID]
+				if !exists {
+					partition.Error = "no response for partition"
+				} else if res.Err != nil {
+					partition.Error = res.Err.Error()
+				} else {
+					partition.LowWaterMark = res.LowWatermark
+				}
+			}
+			if partition.Error != "" {
+				result.Status = BulkTopicStatusFailed
+				result.Error = "failed to delete records of one or more partitions"
+			}
+			result.Partitions[j] = partition
+		}
+		results[i] = result
+	}
+	return results
+}
+
+// bulkTopicResult returns a succeeded or failed result. requestErr is the error of the whole
+// request, topicErr returns the error of the topic if the request succeeded.
+func bulkTopicResult(topicName string, requestErr error, topicErr func() error) BulkTopicResult {
+	err := requestErr
+	if err == nil {
+		err = topicErr()
+	}
+	if err != nil {
+		return BulkTopicResult{TopicName: topicName, Status: BulkTopicStatusFailed, Error: err.Error()}
+	}
+	return BulkTopicResult{TopicName: topicName, Status: BulkTopicStatusSucceeded}
+}
+
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectTopics(t *testing.T) {
+	topicNames := []string{"test-b", "test-a", "orders", "__consumer_offsets", "test-internal"}
+	isInternal := func(topicName string) bool {
+		return strings.HasPrefix(topicName, "__") || topicName == "test-internal"
+	}
+
+	tests := map[string]struct {
+		selector TopicSelector
+		want     TopicSelection
+	}{
+		"prefix": {
+			selector: TopicSelector{Prefix: "test-"},
+			want:     TopicSelection{TopicNames: []string{"test-a", "test-b"}, UnmatchedTopicNames: []string{}},
+		},
+		"regex": {
+			selector: TopicSelector{Regex: "^(orders|.*-a)$"},
+			want:     TopicSelection{TopicNames: []string{"orders", "test-a"}, UnmatchedTopicNames: []string{}},
+		},
+		"regex matches whole topic name": {
+			selector: TopicSelector{Regex: "test|orders"},
+			want:     TopicSelection{TopicNames: []string{"orders"}, UnmatchedTopicNames: []string{}},
+		},
+		"explicit list": {
+			selector: TopicSelector{TopicNames: []string{"orders", "missing", "orders", "__consumer_offsets"}},
+			want:     TopicSelection{TopicNames: []string{"orders"}, UnmatchedTopicNames: []string{"__consumer_offsets", "missing"}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.NoError(t, test.selector.Validate())
+			assert.Equal(t, test.want, selectTopics(test.selector, topicNames, isInternal))
+		})
+	}
+}
+
+func TestTopicSelectorValidate(t *testing.T) {
+	assert.Error(t, (&TopicSelector{}).Validate())
+	assert.Error(t, (&TopicSelector{Prefix: "test-", Regex: "test-.*"}).Validate())
+	assert.Error(t, (&TopicSelector{Regex: "test-("}).Validate())
+}
+
+func TestRecordsDeletionTargetValidate(t *testing.T) {
+	offset, timestamp, invalid := int64(-1), int64(1685577600000), int64(-2)
+
+	assert.NoError(t, (&RecordsDeletionTarget{Offset: &offset}).Validate())
+	assert.NoError(t, (&RecordsDeletionTarget{Timestamp: &timestamp}).Validate())
+	assert.Error(t, (&RecordsDeletionTarget{}).Validate())
+	assert.Error(t, (&RecordsDeletionTarget{Offset: &offset, Timestamp: &timestamp}).Validate())
+	assert.Error(t, (&RecordsDeletionTarget{Offset: &invalid}).Validate())
+}
+
+func TestBulkTopicOperationToken(t *testing.T) {
+	s := &Service{bulkTopicTokenKey: []byte("key")}
+	token := s.BulkTopicOperationToken(BulkTopicOperationDelete, []string{"test-a", "test-b"}, nil)
+
+	assert.Len(t, token, 32)
+	assert.Equal(t, token, s.BulkTopicOperationToken(BulkTopicOperationDelete, []string{"test-a", "test-b"}, nil))
+	assert.NotEqual(t, token, s.BulkTopicOperationToken(BulkTopicOperationDelete, []string{"test-a", "test-b", "test-c"}, nil))
+	assert.NotEqual(t, token, s.BulkTopicOperationToken(BulkTopicOperationDeleteRecords, []string{"test-a", "test-b"}, nil))
+
+	// Tokens can't be computed without the key of the process
+	other := &Service{bulkTopicTokenKey: []byte("other-key")}
+	assert.NotEqual(t, token, other.BulkTopicOperationToken(BulkTopicOperationDelete, []string{"test-a", "test-b"}, nil))
+}
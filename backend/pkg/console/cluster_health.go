@@ -0,0 +1,699 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"go.uber.org/zap"
+
+	"github.com/redpanda-data/console/backend/pkg/config"
+)
+
+// HealthSeverity describes how urgent a health finding is.
+type HealthSeverity string
+
+const (
+	// HealthSeverityInfo is for findings that are worth knowing, but require no action.
+	HealthSeverityInfo HealthSeverity = "INFO"
+	// HealthSeverityWarning is for findings that put availability or durability at risk.
+	HealthSeverityWarning HealthSeverity = "WARNING"
+	// HealthSeverityCritical is for findings that already affect clients.
+	HealthSeverityCritical HealthSeverity = "CRITICAL"
+)
+
+// IDs of all health rules. These are used for disabling rules via the config.
+const (
+	HealthRuleOfflinePartitions           = "OFFLINE_PARTITIONS"
+	HealthRuleUnderReplicatedPartitions   = "UNDER_REPLICATED_PARTITIONS"
+	HealthRuleUnderMinISRPartitions       = "UNDER_MIN_ISR_PARTITIONS"
+	HealthRuleLeaderSkew                  = "LEADER_SKEW"
+	HealthRulePartitionSkew               = "PARTITION_SKEW"
+	HealthRuleMinISRNotBelowRF            = "MIN_ISR_NOT_BELOW_REPLICATION_FACTOR"
+	HealthRuleSingleReplicaTopic          = "SINGLE_REPLICA_TOPIC"
+	HealthRuleBrokerDiskUsage             = "BROKER_DISK_USAGE"
+	HealthRuleEmptyGroupWithRecentCommits = "EMPTY_GROUP_WITH_RECENT_COMMITS"
+)
+
+// Resource types that may be affected by a health finding.
+const (
+	HealthResourceTypeBroker        = "BROKER"
+	HealthResourceTypeTopic         = "TOPIC"
+	HealthResourceTypePartition     = "PARTITION"
+	HealthResourceTypeConsumerGroup = "CONSUMER_GROUP"
+)
+
+// ClusterHealth is the result of evaluating all enabled health rules.
+type ClusterHealth struct {
+	// Status is derived from the most severe finding. Critical findings make the
+	// cluster unhealthy, warnings make it degraded.
+	Status   StatusType      `json:"status"`
+	Findings []HealthFinding `json:"findings"`
+
+	// EvaluatedRules contains the IDs of all rules that have been evaluated. Rules
+	// that are disabled or whose input could not be gathered are missing.
+	EvaluatedRules []string `json:"evaluatedRules"`
+
+	// Errors describes inputs that could not be gathered.
+	Errors []string `json:"errors"`
+}
+
+// HealthFinding is a problem that has been detected by a health rule.
+type HealthFinding struct {
+	RuleID    string                  `json:"ruleId"`
+	Severity  HealthSeverity          `json:"severity"`
+	Message   string                  `json:"message"`
+	Resources []HealthFindingResource `json:"resources"`
+
+	// formatMessage formats the message for the given number of affected resources, so that
+	// the message can be rebuilt after resources have been filtered.
+	formatMessage func(resourceCount int) string
+}
+
+func newHealthFinding(ruleID string, severity HealthSeverity, resources []HealthFindingResource, formatMessage func(resourceCount int) string) HealthFinding {
+	return HealthFinding{
+		RuleID:        ruleID,
+		Severity:      severity,
+		Message:       formatMessage(len(resources)),
+		Resources:     resources,
+		formatMessage: formatMessage,
+	}
+}
+
+// HealthFindingResource is a resource that is affected by a health finding.
+type HealthFindingResource struct {
+	Type string `json:"type"`
+
+	// Name is the topic name, group id or broker id.
+	Name        string `json:"name"`
+	PartitionID *int32 `json:"partitionId,omitempty"`
+
+	// Details describe why this resource is affected, e.g. "leads 42 partitions".
+	Details string `json:"details,omitempty"`
+}
+
+// clusterHealthInput contains everything the health rules are evaluated on.
+// Inputs that could not be gathered are nil.
+type clusterHealthInput struct {
+	Metadata kadm.Metadata
+
+	// MinISRByTopic contains the min.insync.replicas config of each topic.
+	MinISRByTopic map[string]int
+
+	// LogDirsByBroker contains the log dirs of each broker. Only dirs that report
+	// their volume capacity (Kafka 3.3+) are relevant.
+	LogDirsByBroker map[int32][]LogDirUsage
+
+	// LastCommitByEmptyGroup contains a lower bound for the last offset commit of
+	// each consumer group without members.
+	LastCommitByEmptyGroup map[string]time.Time
+}
+
+// healthRule evaluates a single aspect of the cluster health.
+type healthRule struct {
+	ID string
+
+	// IsEvaluable returns false if the input the rule depends on is missing.
+	IsEvaluable func(in *clusterHealthInput) bool
+	Evaluate    func(cfg config.ConsoleHealth, in *clusterHealthInput, now time.Time) []HealthFinding
+}
+
+func hasMetadata(*clusterHealthInput) bool { return true }
+
+var healthRules = []healthRule{
+	{ID: HealthRuleOfflinePartitions, IsEvaluable: hasMetadata, Evaluate: evaluateOfflinePartitions},
+	{ID: HealthRuleUnderReplicatedPartitions, IsEvaluable: hasMetadata, Evaluate: evaluateUnderReplicatedPartitions},
+	{
+		ID:          HealthRuleUnderMinISRPartitions,
+		IsEvaluable: func(in *clusterHealthInput) bool { return in.MinISRByTopic != nil },
+		Evaluate:    evaluateUnderMinISRPartitions,
+	},
+	{ID: HealthRuleLeaderSkew, IsEvaluable: hasMetadata, Evaluate: evaluateLeaderSkew},
+	{ID: HealthRulePartitionSkew, IsEvaluable: hasMetadata, Evaluate: evaluatePartitionSkew},
+	{
+		ID:          HealthRuleMinISRNotBelowRF,
+		IsEvaluable: func(in *clusterHealthInput) bool { return in.MinISRByTopic != nil },
+		Evaluate:    evaluateMinISRNotBelowRF,
+	},
+	{ID: HealthRuleSingleReplicaTopic, IsEvaluable: hasMetadata, Evaluate: evaluateSingleReplicaTopics},
+	{
+		ID:          HealthRuleBrokerDiskUsage,
+		IsEvaluable: func(in *clusterHealthInput) bool { return in.LogDirsByBroker != nil },
+		Evaluate:    evaluateBrokerDiskUsage,
+	},
+	{
+		ID:          HealthRuleEmptyGroupWithRecentCommits,
+		IsEvaluable: func(in *clusterHealthInput) bool { return in.LastCommitByEmptyGroup != nil },
+		Evaluate:    evaluateEmptyGroupsWithRecentCommits,
+	},
+}
+
+// ValidateHealthRuleIDs returns an error if one of the given rule ids is unknown.
+func ValidateHealthRuleIDs(ruleIDs []string) error {
+	for _, id := range ruleIDs {
+		known := false
+		for _, rule := range healthRules {
+			if rule.ID == id {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown health rule '%v'", id)
+		}
+	}
+	return nil
+}
+
+// GetClusterHealth gathers metadata, topic configs, log dirs and consumer group offsets
+// and evaluates all enabled health rules on them. Inputs other than the metadata are
+// optional, rules that depend on a missing input are skipped and reported as error.
+func (s *Service) GetClusterHealth(ctx context.Context) (*ClusterHealth, *rest.Error) {
+	metadata, err := s.kafkaSvc.KafkaAdmClient.Metadata(ctx)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("failed to request metadata: %w", err),
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to request metadata: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	in := &clusterHealthInput{Metadata: metadata}
+	health := &ClusterHealth{Errors: make([]string, 0)}
+
+	if s.isHealthRuleEnabled(HealthRuleUnderMinISRPartitions) || s.isHealthRuleEnabled(HealthRuleMinISRNotBelowRF) {
+		in.MinISRByTopic, err = s.minISRByTopic(ctx, metadata.Topics.Names())
+		if err != nil {
+			health.Errors = append(health.Errors, fmt.Sprintf("failed to describe topic configs: %v", err.Error()))
+		}
+	}
+	if s.isHealthRuleEnabled(HealthRuleBrokerDiskUsage) {
+		in.LogDirsByBroker, err = s.logDirUsageByBroker(ctx)
+		if err != nil {
+			health.Errors = append(health.Errors, fmt.Sprintf("failed to describe log dirs: %v", err.Error()))
+		}
+	}
+	if s.isHealthRuleEnabled(HealthRuleEmptyGroupWithRecentCommits) {
+		in.LastCommitByEmptyGroup, err = s.lastCommitByEmptyGroup(ctx)
+		if err != nil {
+			health.Errors = append(health.Errors, fmt.Sprintf("failed to describe consumer groups: %v", err.Error()))
+		}
+	}
+
+	health.Findings, health.EvaluatedRules = evaluateClusterHealth(s.healthCfg, in, time.Now())
+	health.Status = clusterHealthStatus(health.Findings)
+
+	return health, nil
+}
+
+// FilterResources removes all affected resources for which isVisible returns false. Findings
+// without any remaining resources are omitted. Messages and the status are recomputed from the
+// remaining findings, so that they do not reveal anything about the removed resources.
+func (h *ClusterHealth) FilterResources(isVisible func(resource HealthFindingResource) (bool, *rest.Error)) *rest.Error {
+	visibleFindings := make([]HealthFinding, 0, len(h.Findings))
+	for _, finding := range h.Findings {
+		visibleResources := make([]HealthFindingResource, 0, len(finding.Resources))
+		for _, resource := range finding.Resources {
+			canSee, restErr := isVisible(resource)
+			if restErr != nil {
+				return restErr
+			}
+			if canSee {
+				visibleResources = append(visibleResources, resource)
+			}
+		}
+		if len(visibleResources) == 0 {
+			continue
+		}
+		if len(visibleResources) != len(finding.Resources) && finding.formatMessage != nil {
+			finding.Message = finding.formatMessage(len(visibleResources))
+		}
+		finding.Resources = visibleResources
+		visibleFindings = append(visibleFindings, finding)
+	}
+	h.Findings = visibleFindings
+	h.Status = clusterHealthStatus(visibleFindings)
+
+	return nil
+}
+
+func (s *Service) isHealthRuleEnabled(ruleID string) bool {
+	for _, id := range s.healthCfg.DisabledRules {
+		if id == ruleID {
+			return false
+		}
+	}
+	return true
+}
+
+// evaluateClusterHealth evaluates all enabled rules whose input is available and
+// returns the findings along with the IDs of the evaluated rules.
+func evaluateClusterHealth(cfg config.ConsoleHealth, in *clusterHealthInput, now time.Time) ([]HealthFinding, []string) {
+	disabled := make(map[string]struct{}, len(cfg.DisabledRules))
+	for _, id := range cfg.DisabledRules {
+		disabled[id] = struct{}{}
+	}
+
+	findings := make([]HealthFinding, 0)
+	evaluated := make([]string, 0, len(healthRules))
+	for _, rule := range healthRules {
+		if _, isDisabled := disabled[rule.ID]; isDisabled || !rule.IsEvaluable(in) {
+			continue
+		}
+		evaluated = append(evaluated, rule.ID)
+		findings = append(findings, rule.Evaluate(cfg, in, now)...)
+	}
+
+	return findings, evaluated
+}
+
+func clusterHealthStatus(findings []HealthFinding) StatusType {
+	status := StatusType(StatusTypeHealthy)
+	for _, finding := range findings {
+		switch finding.Severity {
+		case HealthSeverityCritical:
+			return StatusTypeUnhealthy
+		case HealthSeverityWarning:
+			status = StatusTypeDegraded
+		case HealthSeverityInfo:
+		}
+	}
+	return status
+}
+
+func isPartitionOffline(p kadm.PartitionDetail) bool {
+	return p.Leader < 0 || errors.Is(p.Err, kerr.LeaderNotAvailable)
+}
+
+// sortedPartitions returns all partitions of all topics sorted by topic name and partition id.
+func sortedPartitions(topics kadm.TopicDetails) []kadm.PartitionDetail {
+	partitions := make([]kadm.PartitionDetail, 0)
+	for _, topicName := range topics.Names() {
+		partitions = append(partitions, topics[topicName].Partitions.Sorted()...)
+	}
+	return partitions
+}
+
+// replicationFactor returns the highest replica count of all partitions of a topic.
+func replicationFactor(topic kadm.TopicDetail) int {
+	rf := 0
+	for _, p := range topic.Partitions {
+		if len(p.Replicas) > rf {
+			rf = len(p.Replicas)
+		}
+	}
+	return rf
+}
+
+func partitionResource(p kadm.PartitionDetail, details string) HealthFindingResource {
+	partitionID := p.Partition
+	return HealthFindingResource{
+		Type:        HealthResourceTypePartition,
+		Name:        p.Topic,
+		PartitionID: &partitionID,
+		Details:     details,
+	}
+}
+
+func evaluateOfflinePartitions(_ config.ConsoleHealth, in *clusterHealthInput, _ time.Time) []HealthFinding {
+	resources := make([]HealthFindingResource, 0)
+	for _, p := range sortedPartitions(in.Metadata.Topics) {
+		if isPartitionOffline(p) {
+			resources = append(resources, partitionResource(p, fmt.Sprintf("offline replicas: %v", p.OfflineReplicas)))
+		}
+	}
+	if len(resources) == 0 {
+		return nil
+	}
+
+	return []HealthFinding{newHealthFinding(HealthRuleOfflinePartitions, HealthSeverityCritical, resources, func(count int) string {
+		return fmt.Sprintf("%d partitions have no leader and can neither be produced to nor consumed from", count)
+	})}
+}
+
+func evaluateUnderReplicatedPartitions(_ config.ConsoleHealth, in *clusterHealthInput, _ time.Time) []HealthFinding {
+	resources := make([]HealthFindingResource, 0)
+	for _, p := range sortedPartitions(in.Metadata.Topics) {
+		if isPartitionOffline(p) || len(p.ISR) >= len(p.Replicas) {
+			continue
+		}
+		resources = append(resources, partitionResource(p, fmt.Sprintf("%d of %d replicas in sync", len(p.ISR), len(p.Replicas))))
+	}
+	if len(resources) == 0 {
+		return nil
+	}
+
+	return []HealthFinding{newHealthFinding(HealthRuleUnderReplicatedPartitions, HealthSeverityWarning, resources, func(count int) string {
+		return fmt.Sprintf("%d partitions have replicas that are not in sync with the leader", count)
+	})}
+}
+
+func evaluateUnderMinISRPartitions(_ config.ConsoleHealth, in *clusterHealthInput, _ time.Time) []HealthFinding {
+	resources := make([]HealthFindingResource, 0)
+	for _, p := range sortedPartitions(in.Metadata.Topics) {
+		minISR, exists := in.MinISRByTopic[p.Topic]
+		if !exists || isPartitionOffline(p) || len(p.ISR) >= minISR {
+			continue
+		}
+		resources = append(resources, partitionResource(p, fmt.Sprintf("%d in-sync replicas, but min.insync.replicas is %d", len(p.ISR), minISR)))
+	}
+	if len(resources) == 0 {
+		return nil
+	}
+
+	return []HealthFinding{newHealthFinding(HealthRuleUnderMinISRPartitions, HealthSeverityCritical, resources, func(count int) string {
+		return fmt.Sprintf("%d partitions have fewer in-sync replicas than min.insync.replicas and reject produce requests with acks=all", count)
+	})}
+}
+
+// skewedBrokers returns a resource for each broker whose count deviates more than the
+// threshold from the average count of all given brokers.
+func skewedBrokers(countByBroker map[int32]int, thresholdPercent float64, unit string) ([]HealthFindingResource, float64) {
+	if len(countByBroker) < 2 {
+		return nil, 0
+	}
+	total := 0
+	brokerIDs := make([]int32, 0, len(countByBroker))
+	for brokerID, count := range countByBroker {
+		total += count
+		brokerIDs = append(brokerIDs, brokerID)
+	}
+	sort.Slice(brokerIDs, func(i, j int) bool { return brokerIDs[i] < brokerIDs[j] })
+
+	avg := float64(total) / float64(len(countByBroker))
+	if avg == 0 {
+		return nil, 0
+	}
+
+	resources := make([]HealthFindingResource, 0)
+	for _, brokerID := range brokerIDs {
+		count := countByBroker[brokerID]
+		deviation := math.Abs(float64(count)-avg) / avg * 100
+		if deviation <= thresholdPercent {
+			continue
+		}
+		resources = append(resources, HealthFindingResource{
+			Type:    HealthResourceTypeBroker,
+			Name:    strconv.Itoa(int(brokerID)),
+			Details: fmt.Sprintf("%d %s (%.0f%% off the average)", count, unit, deviation),
+		})
+	}
+	return resources, avg
+}
+
+// countByOnlineBroker initializes a count for each online broker, so that brokers without
+// any partitions are considered as well.
+func countByOnlineBroker(metadata kadm.Metadata) map[int32]int {
+	counts := make(map[int32]int, len(metadata.Brokers))
+	for _, b := range metadata.Brokers {
+		counts[b.NodeID] = 0
+	}
+	return counts
+}
+
+func evaluateLeaderSkew(cfg config.ConsoleHealth, in *clusterHealthInput, _ time.Time) []HealthFinding {
+	leaders := countByOnlineBroker(in.Metadata)
+	in.Metadata.Topics.EachPartition(func(p kadm.PartitionDetail) {
+		if _, isOnline := leaders[p.Leader]; isOnline {
+			leaders[p.Leader]++
+		}
+	})
+
+	resources, avg := skewedBrokers(leaders, cfg.SkewThresholdPercent, "partition leaders")
+	if len(resources) == 0 {
+		return nil
+	}
+
+	return []HealthFinding{newHealthFinding(HealthRuleLeaderSkew, HealthSeverityWarning, resources, func(count int) string {
+		return fmt.Sprintf("%d brokers lead a number of partitions that deviates more than %.0f%% from the average of %.1f. A preferred leader election may rebalance the load",
+			count, cfg.SkewThresholdPercent, avg)
+	})}
+}
+
+func evaluatePartitionSkew(cfg config.ConsoleHealth, in *clusterHealthInput, _ time.Time) []HealthFinding {
+	replicas := countByOnlineBroker(in.Metadata)
+	in.Metadata.Topics.EachPartition(func(p kadm.PartitionDetail) {
+		for _, brokerID := range p.Replicas {
+			if _, isOnline := replicas[brokerID]; isOnline {
+				replicas[brokerID]++
+			}
+		}
+	})
+
+	resources, avg := skewedBrokers(replicas, cfg.SkewThresholdPercent, "replicas")
+	if len(resources) == 0 {
+		return nil
+	}
+
+	return []HealthFinding{newHealthFinding(HealthRulePartitionSkew, HealthSeverityWarning, resources, func(count int) string {
+		return fmt.Sprintf("%d brokers host a number of replicas that deviates more than %.0f%% from the average of %.1f. A partition reassignment may rebalance the load",
+			count, cfg.SkewThresholdPercent, avg)
+	})}
+}
+
+func evaluateMinISRNotBelowRF(_ config.ConsoleHealth, in *clusterHealthInput, _ time.Time) []HealthFinding {
+	resources := make([]HealthFindingResource, 0)
+	for _, topicName := range in.Metadata.Topics.Names() {
+		minISR, exists := in.MinISRByTopic[topicName]
+		rf := replicationFactor(in.Metadata.Topics[topicName])
+		// Topics with a single replica are reported by the single replica rule
+		if !exists || rf <= 1 || minISR < rf {
+			continue
+		}
+		resources = append(resources, HealthFindingResource{
+			Type:    HealthResourceTypeTopic,
+			Name:    topicName,
+			Details: fmt.Sprintf("min.insync.replicas is %d, replication factor is %d", minISR, rf),
+		})
+	}
+	if len(resources) == 0 {
+		return nil
+	}
+
+	return []HealthFinding{newHealthFinding(HealthRuleMinISRNotBelowRF, HealthSeverityWarning, resources, func(count int) string {
+		return fmt.Sprintf("%d topics reject produce requests with acks=all as soon as a single replica is out of sync, because min.insync.replicas is not lower than the replication factor", count)
+	})}
+}
+
+func evaluateSingleReplicaTopics(_ config.ConsoleHealth, in *clusterHealthInput, _ time.Time) []HealthFinding {
+	resources := make([]HealthFindingResource, 0)
+	for _, topicName := range in.Metadata.Topics.Names() {
+		topic := in.Metadata.Topics[topicName]
+		if topic.Err != nil || replicationFactor(topic) != 1 {
+			continue
+		}
+		resources = append(resources, HealthFindingResource{Type: HealthResourceTypeTopic, Name: topicName})
+	}
+	if len(resources) == 0 {
+		return nil
+	}
+
+	return []HealthFinding{newHealthFinding(HealthRuleSingleReplicaTopic, HealthSeverityWarning, resources, func(count int) string {
+		return fmt.Sprintf("%d topics have a replication factor of 1 and become unavailable or lose data if a single broker fails", count)
+	})}
+}
+
+func evaluateBrokerDiskUsage(cfg config.ConsoleHealth, in *clusterHealthInput, _ time.Time) []HealthFinding {
+	brokerIDs := make([]int32, 0, len(in.LogDirsByBroker))
+	for brokerID := range in.LogDirsByBroker {
+		brokerIDs = append(brokerIDs, brokerID)
+	}
+	sort.Slice(brokerIDs, func(i, j int) bool { return brokerIDs[i] < brokerIDs[j] })
+
+	warning := make([]HealthFindingResource, 0)
+	critical := make([]HealthFindingResource, 0)
+	for _, brokerID := range brokerIDs {
+		// Only the fullest log dir of each broker is reported
+		var fullestDir string
+		fullestPercent := -1.0
+		for _, dir := range in.LogDirsByBroker[brokerID] {
+			if dir.TotalBytes <= 0 || dir.UsableBytes < 0 {
+				continue
+			}
+			usedPercent := float64(dir.TotalBytes-dir.UsableBytes) / float64(dir.TotalBytes) * 100
+			if usedPercent > fullestPercent {
+				fullestDir, fullestPercent = dir.LogDir, usedPercent
+			}
+		}
+
+		resource := HealthFindingResource{
+			Type:    HealthResourceTypeBroker,
+			Name:    strconv.Itoa(int(brokerID)),
+			Details: fmt.Sprintf("log dir '%v' is %.1f%% full", fullestDir, fullestPercent),
+		}
+		switch {
+		case fullestPercent >= cfg.DiskUsageCriticalPercent:
+			critical = append(critical, resource)
+		case fullestPercent >= cfg.DiskUsageWarningPercent:
+			warning = append(warning, resource)
+		}
+	}
+
+	findings := make([]HealthFinding, 0)
+	if len(critical) > 0 {
+		findings = append(findings, newHealthFinding(HealthRuleBrokerDiskUsage, HealthSeverityCritical, critical, func(count int) string {
+			return fmt.Sprintf("%d brokers have used at least %.0f%% of their disk capacity", count, cfg.DiskUsageCriticalPercent)
+		}))
+	}
+	if len(warning) > 0 {
+		findings = append(findings, newHealthFinding(HealthRuleBrokerDiskUsage, HealthSeverityWarning, warning, func(count int) string {
+			return fmt.Sprintf("%d brokers have used at least %.0f%% of their disk capacity", count, cfg.DiskUsageWarningPercent)
+		}))
+	}
+	return findings
+}
+
+func evaluateEmptyGroupsWithRecentCommits(cfg config.ConsoleHealth, in *clusterHealthInput, now time.Time) []HealthFinding {
+	groupIDs := make([]string, 0, len(in.LastCommitByEmptyGroup))
+	for groupID := range in.LastCommitByEmptyGroup {
+		groupIDs = append(groupIDs, groupID)
+	}
+	sort.Strings(groupIDs)
+
+	resources := make([]HealthFindingResource, 0)
+	for _, groupID := range groupIDs {
+		age := now.Sub(in.LastCommitByEmptyGroup[groupID])
+		if age > cfg.RecentCommitWindow {
+			continue
+		}
+		resources = append(resources, HealthFindingResource{
+			Type:    HealthResourceTypeConsumerGroup,
+			Name:    groupID,
+			Details: fmt.Sprintf("committed offsets within the last %v", age.Truncate(time.Second)),
+		})
+	}
+	if len(resources) == 0 {
+		return nil
+	}
+
+	return []HealthFinding{newHealthFinding(HealthRuleEmptyGroupWithRecentCommits, HealthSeverityWarning, resources, func(count int) string {
+		return fmt.Sprintf("%d consumer groups have no members but committed offsets within the last %v. Their consumers may have crashed", count, cfg.RecentCommitWindow)
+	})}
+}
+
+// minISRByTopic returns the min.insync.replicas config of each given topic.
+func (s *Service) minISRByTopic(ctx context.Context, topicNames []string) (map[string]int, error) {
+	configs, err := s.GetTopicsConfigs(ctx, topicNames, []string{"min.insync.replicas"})
+	if err != nil {
+		return nil, err
+	}
+
+	minISRByTopic := make(map[string]int, len(configs))
+	for topicName, cfg := range configs {
+		if cfg.Error != nil {
+			continue
+		}
+		for _, entry := range cfg.ConfigEntries {
+			if entry.Name != "min.insync.replicas" || entry.Value == nil {
+				continue
+			}
+			minISR, err := strconv.Atoi(*entry.Value)
+			if err != nil {
+				continue
+			}
+			minISRByTopic[topicName] = minISR
+		}
+	}
+	return minISRByTopic, nil
+}
+
+// logDirUsageByBroker describes the log dirs of all brokers without any partitions, so that
+// only the volume capacity is returned. Brokers that fail to respond are logged and skipped.
+func (s *Service) logDirUsageByBroker(ctx context.Context) (map[int32][]LogDirUsage, error) {
+	responses := s.kafkaSvc.DescribeLogDirs(ctx, []kmsg.DescribeLogDirsRequestTopic{})
+
+	usageByBroker := make(map[int32][]LogDirUsage, len(responses))
+	var lastErr error
+	for _, res := range responses {
+		if res.Error != nil {
+			lastErr = res.Error
+			s.logger.Warn("failed to describe log dirs for health analysis",
+				zap.Int32("broker_id", res.BrokerMetadata.NodeID), zap.Error(res.Error))
+			continue
+		}
+		usages := make([]LogDirUsage, 0, len(res.LogDirs.Dirs))
+		for _, dir := range res.LogDirs.Dirs {
+			if kerr.ErrorForCode(dir.ErrorCode) != nil {
+				continue
+			}
+			usages = append(usages, LogDirUsage{LogDir: dir.Dir, TotalBytes: dir.TotalBytes, UsableBytes: dir.UsableBytes})
+		}
+		usageByBroker[res.BrokerMetadata.NodeID] = usages
+	}
+	if len(usageByBroker) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return usageByBroker, nil
+}
+
+// lastCommitByEmptyGroup returns a lower bound for the last offset commit of each consumer
+// group without members. Kafka does not expose commit timestamps, hence the timestamp of the
+// last consumed record (the record before the committed offset) is used: the offset can only
+// have been committed after that record had been produced.
+func (s *Service) lastCommitByEmptyGroup(ctx context.Context) (map[string]time.Time, error) {
+	groups, restErr := s.GetConsumerGroupsOverview(ctx, nil, ConsumerGroupsOverviewOptions{})
+	if restErr != nil {
+		return nil, restErr.Err
+	}
+	return s.lastCommitOfEmptyGroups(ctx, groups), nil
+}
+
+// lastCommitOfEmptyGroups is like lastCommitByEmptyGroup, but for already described groups.
+// Groups whose record timestamps could not be fetched are missing in the returned map.
+func (s *Service) lastCommitOfEmptyGroups(ctx context.Context, groups []ConsumerGroupOverview) map[string]time.Time {
+	offsetsByGroup := make(map[string][]GroupTopicOffsets)
+	required := make(map[recordOffset]struct{})
+	for _, group := range groups {
+		if group.State != "Empty" {
+			continue
+		}
+		offsetsByGroup[group.GroupID] = group.TopicOffsets
+		for _, topic := range group.TopicOffsets {
+			for _, p := range topic.PartitionOffsets {
+				if p.Error != "" || p.GroupOffset <= 0 || p.GroupOffset > p.HighWaterMark {
+					continue
+				}
+				required[recordOffset{Topic: topic.Topic, Partition: p.PartitionID, Offset: p.GroupOffset - 1}] = struct{}{}
+			}
+		}
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, timeLagEstimationTimeout)
+	defer cancel()
+	timestamps := s.fetchRecordTimestamps(fetchCtx, required)
+
+	lastCommitByGroup := make(map[string]time.Time, len(offsetsByGroup))
+	for groupID, topics := range offsetsByGroup {
+		for _, topic := range topics {
+			for _, p := range topic.PartitionOffsets {
+				ts, exists := timestamps[recordOffset{Topic: topic.Topic, Partition: p.PartitionID, Offset: p.GroupOffset - 1}]
+				if !exists {
+					continue
+				}
+				if commitTime := time.UnixMilli(ts.Timestamp); commitTime.After(lastCommitByGroup[groupID]) {
+					lastCommitByGroup[groupID] = commitTime
+				}
+			}
+		}
+	}
+	return lastCommitByGroup
+}
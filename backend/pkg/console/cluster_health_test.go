@@ -0,0 +1,219 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/redpanda-data/console/backend/pkg/config"
+)
+
+func newHealthTestMetadata(brokerIDs []int32, partitions ...kadm.PartitionDetail) kadm.Metadata {
+	metadata := kadm.Metadata{Topics: make(kadm.TopicDetails)}
+	for _, id := range brokerIDs {
+		metadata.Brokers = append(metadata.Brokers, kgo.BrokerMetadata{NodeID: id})
+	}
+	for _, p := range partitions {
+		topic, exists := metadata.Topics[p.Topic]
+		if !exists {
+			topic = kadm.TopicDetail{Topic: p.Topic, Partitions: make(kadm.PartitionDetails)}
+		}
+		topic.Partitions[p.Partition] = p
+		metadata.Topics[p.Topic] = topic
+	}
+	return metadata
+}
+
+func newHealthTestConfig() config.ConsoleHealth {
+	cfg := config.ConsoleHealth{}
+	cfg.SetDefaults()
+	return cfg
+}
+
+func findingsByRule(findings []HealthFinding) map[string][]HealthFinding {
+	byRule := make(map[string][]HealthFinding)
+	for _, f := range findings {
+		byRule[f.RuleID] = append(byRule[f.RuleID], f)
+	}
+	return byRule
+}
+
+func TestEvaluateClusterHealth(t *testing.T) {
+	metadata := newHealthTestMetadata([]int32{1, 2, 3},
+		kadm.PartitionDetail{Topic: "orders", Partition: 0, Leader: 1, Replicas: []int32{1, 2, 3}, ISR: []int32{1, 2, 3}},
+		kadm.PartitionDetail{Topic: "orders", Partition: 1, Leader: 2, Replicas: []int32{2, 3, 1}, ISR: []int32{2}},
+		kadm.PartitionDetail{Topic: "orders", Partition: 2, Leader: -1, Replicas: []int32{3, 1, 2}, OfflineReplicas: []int32{3}},
+		kadm.PartitionDetail{Topic: "strict", Partition: 0, Leader: 3, Replicas: []int32{3, 1}, ISR: []int32{3, 1}},
+		kadm.PartitionDetail{Topic: "dev", Partition: 0, Leader: 1, Replicas: []int32{1}, ISR: []int32{1}},
+	)
+	in := &clusterHealthInput{
+		Metadata:      metadata,
+		MinISRByTopic: map[string]int{"orders": 2, "strict": 2, "dev": 1},
+	}
+
+	findings, evaluated := evaluateClusterHealth(newHealthTestConfig(), in, time.Now())
+	byRule := findingsByRule(findings)
+
+	assert.NotContains(t, evaluated, HealthRuleBrokerDiskUsage)
+	assert.NotContains(t, evaluated, HealthRuleEmptyGroupWithRecentCommits)
+	assert.Contains(t, evaluated, HealthRuleUnderMinISRPartitions)
+
+	require.Len(t, byRule[HealthRuleOfflinePartitions], 1)
+	offline := byRule[HealthRuleOfflinePartitions][0]
+	assert.Equal(t, HealthSeverityCritical, offline.Severity)
+	require.Len(t, offline.Resources, 1)
+	assert.Equal(t, "orders", offline.Resources[0].Name)
+	assert.Equal(t, int32(2), *offline.Resources[0].PartitionID)
+
+	// Offline partitions are not reported as under-replicated as well
+	require.Len(t, byRule[HealthRuleUnderReplicatedPartitions], 1)
+	require.Len(t, byRule[HealthRuleUnderReplicatedPartitions][0].Resources, 1)
+	assert.Equal(t, int32(1), *byRule[HealthRuleUnderReplicatedPartitions][0].Resources[0].PartitionID)
+
+	require.Len(t, byRule[HealthRuleUnderMinISRPartitions], 1)
+	assert.Equal(t, int32(1), *byRule[HealthRuleUnderMinISRPartitions][0].Resources[0].PartitionID)
+
+	require.Len(t, byRule[HealthRuleMinISRNotBelowRF], 1)
+	require.Len(t, byRule[HealthRuleMinISRNotBelowRF][0].Resources, 1)
+	assert.Equal(t, "strict", byRule[HealthRuleMinISRNotBelowRF][0].Resources[0].Name)
+
+	require.Len(t, byRule[HealthRuleSingleReplicaTopic], 1)
+	assert.Equal(t, []HealthFindingResource{{Type: HealthResourceTypeTopic, Name: "dev"}}, byRule[HealthRuleSingleReplicaTopic][0].Resources)
+
+	assert.Equal(t, StatusType(StatusTypeUnhealthy), clusterHealthStatus(findings))
+}
+
+func TestEvaluateClusterHealthDisabledRules(t *testing.T) {
+	in := &clusterHealthInput{
+		Metadata: newHealthTestMetadata([]int32{1},
+			kadm.PartitionDetail{Topic: "dev", Partition: 0, Leader: 1, Replicas: []int32{1}, ISR: []int32{1}},
+		),
+	}
+	cfg := newHealthTestConfig()
+	cfg.DisabledRules = []string{HealthRuleSingleReplicaTopic}
+
+	findings, evaluated := evaluateClusterHealth(cfg, in, time.Now())
+
+	assert.Empty(t, findings)
+	assert.NotContains(t, evaluated, HealthRuleSingleReplicaTopic)
+	assert.Equal(t, StatusType(StatusTypeHealthy), clusterHealthStatus(findings))
+	assert.NoError(t, ValidateHealthRuleIDs(cfg.DisabledRules))
+	assert.Error(t, ValidateHealthRuleIDs([]string{"UNKNOWN"}))
+}
+
+func TestEvaluateSkew(t *testing.T) {
+	// Broker 1 leads all partitions, broker 3 hosts no replicas at all
+	metadata := newHealthTestMetadata([]int32{1, 2, 3},
+		kadm.PartitionDetail{Topic: "a", Partition: 0, Leader: 1, Replicas: []int32{1, 2}, ISR: []int32{1, 2}},
+		kadm.PartitionDetail{Topic: "a", Partition: 1, Leader: 1, Replicas: []int32{1, 2}, ISR: []int32{1, 2}},
+		kadm.PartitionDetail{Topic: "a", Partition: 2, Leader: 1, Replicas: []int32{1, 2}, ISR: []int32{1, 2}},
+	)
+	in := &clusterHealthInput{Metadata: metadata}
+	cfg := newHealthTestConfig()
+
+	leaderSkew := evaluateLeaderSkew(cfg, in, time.Now())
+	require.Len(t, leaderSkew, 1)
+	names := make([]string, 0)
+	for _, r := range leaderSkew[0].Resources {
+		names = append(names, r.Name)
+	}
+	assert.Equal(t, []string{"1", "2", "3"}, names)
+
+	partitionSkew := evaluatePartitionSkew(cfg, in, time.Now())
+	require.Len(t, partitionSkew, 1)
+	names = names[:0]
+	for _, r := range partitionSkew[0].Resources {
+		names = append(names, r.Name)
+	}
+	// Brokers 1 and 2 host 3 replicas each (avg 2), broker 3 hosts none
+	assert.Equal(t, []string{"1", "2", "3"}, names)
+
+	cfg.SkewThresholdPercent = 100
+	assert.Empty(t, evaluatePartitionSkew(cfg, in, time.Now()))
+}
+
+func TestEvaluateBrokerDiskUsage(t *testing.T) {
+	in := &clusterHealthInput{
+		LogDirsByBroker: map[int32][]LogDirUsage{
+			1: {{LogDir: "/data", TotalBytes: 100, UsableBytes: 50}},
+			2: {{LogDir: "/data1", TotalBytes: 100, UsableBytes: 50}, {LogDir: "/data2", TotalBytes: 100, UsableBytes: 15}},
+			3: {{LogDir: "/data", TotalBytes: 100, UsableBytes: 5}},
+			4: {{LogDir: "/data", TotalBytes: -1, UsableBytes: -1}},
+		},
+	}
+
+	findings := evaluateBrokerDiskUsage(newHealthTestConfig(), in, time.Now())
+
+	require.Len(t, findings, 2)
+	assert.Equal(t, HealthSeverityCritical, findings[0].Severity)
+	require.Len(t, findings[0].Resources, 1)
+	assert.Equal(t, "3", findings[0].Resources[0].Name)
+	assert.Equal(t, HealthSeverityWarning, findings[1].Severity)
+	require.Len(t, findings[1].Resources, 1)
+	assert.Equal(t, "2", findings[1].Resources[0].Name)
+	assert.Contains(t, findings[1].Resources[0].Details, "/data2")
+}
+
+func TestEvaluateEmptyGroupsWithRecentCommits(t *testing.T) {
+	now := time.Now()
+	in := &clusterHealthInput{
+		LastCommitByEmptyGroup: map[string]time.Time{
+			"crashed":   now.Add(-5 * time.Minute),
+			"abandoned": now.Add(-48 * time.Hour),
+		},
+	}
+
+	findings := evaluateEmptyGroupsWithRecentCommits(newHealthTestConfig(), in, now)
+
+	require.Len(t, findings, 1)
+	require.Len(t, findings[0].Resources, 1)
+	assert.Equal(t, HealthFindingResource{
+		Type:    HealthResourceTypeConsumerGroup,
+		Name:    "crashed",
+		Details: "committed offsets within the last 5m0s",
+	}, findings[0].Resources[0])
+}
+
+func TestClusterHealth_FilterResources(t *testing.T) {
+	now := time.Now()
+	in := &clusterHealthInput{
+		LastCommitByEmptyGroup: map[string]time.Time{
+			"visible-1": now.Add(-5 * time.Minute),
+			"visible-2": now.Add(-5 * time.Minute),
+			"hidden":    now.Add(-5 * time.Minute),
+		},
+	}
+	findings := evaluateEmptyGroupsWithRecentCommits(newHealthTestConfig(), in, now)
+	health := &ClusterHealth{Findings: findings, Status: clusterHealthStatus(findings)}
+	require.Len(t, health.Findings, 1)
+	require.Contains(t, health.Findings[0].Message, "3 consumer groups")
+
+	restErr := health.FilterResources(func(resource HealthFindingResource) (bool, *rest.Error) {
+		return resource.Name != "hidden", nil
+	})
+	require.Nil(t, restErr)
+	require.Len(t, health.Findings, 1)
+	assert.Len(t, health.Findings[0].Resources, 2)
+	assert.Contains(t, health.Findings[0].Message, "2 consumer groups")
+	assert.Equal(t, StatusType(StatusTypeDegraded), health.Status)
+
+	// Findings without visible resources are omitted and don't affect the status
+	restErr = health.FilterResources(func(HealthFindingResource) (bool, *rest.Error) { return false, nil })
+	require.Nil(t, restErr)
+	assert.Empty(t, health.Findings)
+	assert.Equal(t, StatusType(StatusTypeHealthy), health.Status)
+}
@@ -0,0 +1,627 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"go.uber.org/zap"
+
+	"github.com/redpanda-data/console/backend/pkg/connector/model"
+	"github.com/redpanda-data/console/backend/pkg/schema"
+)
+
+// ClusterSnapshotVersion is the version of the cluster snapshot format.
+const ClusterSnapshotVersion = 1
+
+// Scopes of a cluster snapshot. Each scope can be exported and imported on its own.
+const (
+	SnapshotScopeTopics               = "topics"
+	SnapshotScopeACLs                 = "acls"
+	SnapshotScopeQuotas               = "quotas"
+	SnapshotScopeConsumerGroupOffsets = "consumerGroupOffsets"
+	SnapshotScopeConnectors           = "connectors"
+	SnapshotScopeSchemaRegistry       = "schemaRegistry"
+)
+
+// clusterSnapshotScopes lists all scopes in the order they are imported. Topics are created
+// first, so that consumer group offsets can be committed for them.
+var clusterSnapshotScopes = []string{
+	SnapshotScopeTopics,
+	SnapshotScopeACLs,
+	SnapshotScopeQuotas,
+	SnapshotScopeConsumerGroupOffsets,
+	SnapshotScopeConnectors,
+	SnapshotScopeSchemaRegistry,
+}
+
+// Actions that are reported for each resource of an imported snapshot.
+const (
+	SnapshotImportActionCreate    = "CREATE"
+	SnapshotImportActionSkip      = "SKIP"
+	SnapshotImportActionConflict  = "CONFLICT"
+	SnapshotImportActionForbidden = "FORBIDDEN"
+)
+
+// RedactedConfigValue replaces secret connector config values in exported snapshots.
+const RedactedConfigValue = "[REDACTED]"
+
+// ClusterSnapshot is a versioned document that contains the configuration of a cluster, so
+// that it can be migrated to another cluster. Secret connector config values are redacted
+// unless the snapshot has been exported with secrets.
+type ClusterSnapshot struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Scopes lists all scopes that have been exported.
+	Scopes []string `json:"scopes"`
+
+	Topics               []SnapshotTopic        `json:"topics,omitempty"`
+	ACLs                 []ACLBinding           `json:"acls,omitempty"`
+	Quotas               []SnapshotQuota        `json:"quotas,omitempty"`
+	ConsumerGroupOffsets []SnapshotGroupOffsets `json:"consumerGroupOffsets,omitempty"`
+	Connectors           []SnapshotConnector    `json:"connectors,omitempty"`
+	SchemaRegistry       *schema.Backup         `json:"schemaRegistry,omitempty"`
+
+	// Errors contains the requested scopes that could not be exported, e.g. because Kafka
+	// connect is not configured or the requester is not allowed to list them.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// ClusterSnapshotExportOptions controls which parts of the cluster are exported.
+type ClusterSnapshotExportOptions struct {
+	// Scopes that shall be exported.
+	Scopes []string
+
+	// IncludeSecrets exports secret connector config values instead of redacting them. This
+	// requires the permission to edit the connect cluster.
+	IncludeSecrets bool
+
+	// Authorizer decides which resources the requester may see. Resources that must not be seen
+	// are left out. All resources are exported if it is nil.
+	Authorizer ClusterSnapshotExportAuthorizer
+}
+
+// ClusterSnapshotExportAuthorizer checks the permissions for each exported resource, the same
+// way as if the resource was listed on its own.
+type ClusterSnapshotExportAuthorizer interface {
+	CanSeeTopic(ctx context.Context, topicName string) (bool, *rest.Error)
+	CanListACLs(ctx context.Context) (bool, *rest.Error)
+	CanListQuotas(ctx context.Context) (bool, *rest.Error)
+	CanSeeConsumerGroup(ctx context.Context, groupName string) (bool, *rest.Error)
+	CanViewConnectCluster(ctx context.Context, clusterName string) (bool, *rest.Error)
+	CanEditConnectCluster(ctx context.Context, clusterName string) (bool, *rest.Error)
+}
+
+// SnapshotTopic is a topic along with all configs that are explicitly set.
+type SnapshotTopic struct {
+	Name              string            `json:"name"`
+	Partitions        int32             `json:"partitions"`
+	ReplicationFactor int16             `json:"replicationFactor"`
+	Configs           map[string]string `json:"configs,omitempty"`
+}
+
+// SnapshotQuota contains all quota values of a single quota entity.
+type SnapshotQuota struct {
+	Entity []QuotaEntityComponent `json:"entity"`
+	Values []QuotaResponseSetting `json:"values"`
+}
+
+// SnapshotGroupOffsets contains the committed offsets of a consumer group.
+type SnapshotGroupOffsets struct {
+	GroupID string                    `json:"groupId"`
+	Offsets []SnapshotPartitionOffset `json:"offsets"`
+}
+
+// SnapshotPartitionOffset is the committed offset of a group for a single partition.
+type SnapshotPartitionOffset struct {
+	Topic     string `json:"topic"`
+	Partition int32  `json:"partition"`
+	Offset    int64  `json:"offset"`
+}
+
+// SnapshotConnector is the config of a connector in one of the configured connect clusters.
+type SnapshotConnector struct {
+	ClusterName string            `json:"clusterName"`
+	Name        string            `json:"name"`
+	Config      map[string]string `json:"config"`
+}
+
+// ClusterSnapshotImportOptions controls which parts of a snapshot are imported.
+type ClusterSnapshotImportOptions struct {
+	// Scopes that shall be imported. All scopes are imported if empty.
+	Scopes []string
+
+	// DryRun reports the actions and conflicts without changing the cluster.
+	DryRun bool
+
+	// PreserveSchemaIDs registers schemas with their original IDs, see schema.ImportOptions.
+	PreserveSchemaIDs bool
+
+	// IsProtectedPrincipal reports principals whose ACLs must not be created.
+	IsProtectedPrincipal func(principal string) bool
+
+	// Authorizer decides whether the requester may create each resource. Resources that must
+	// not be created are reported as forbidden. All resources may be created if it is nil.
+	Authorizer ClusterSnapshotImportAuthorizer
+}
+
+// ClusterSnapshotImportAuthorizer checks the permissions for each resource of an imported
+// snapshot, the same way as if the resource was created on its own.
+type ClusterSnapshotImportAuthorizer interface {
+	CanCreateTopic(ctx context.Context, topicName string) (bool, *rest.Error)
+	CanCreateACL(ctx context.Context) (bool, *rest.Error)
+	CanAlterQuotas(ctx context.Context, entity []QuotaEntityComponent) (bool, *rest.Error)
+	CanEditConsumerGroup(ctx context.Context, groupName string) (bool, *rest.Error)
+	CanEditConnectCluster(ctx context.Context, clusterName string) (bool, *rest.Error)
+	CanImportSchemaRegistry(ctx context.Context) (bool, *rest.Error)
+}
+
+// ClusterSnapshotImportReport contains the action for each resource of the imported snapshot.
+// Existing resources are never overwritten, but reported as conflict if they differ.
+type ClusterSnapshotImportReport struct {
+	DryRun bool                        `json:"dryRun"`
+	Scopes []string                    `json:"scopes"`
+	Items  []ClusterSnapshotImportItem `json:"items"`
+
+	SchemaRegistry *schema.ImportReport `json:"schemaRegistry,omitempty"`
+
+	// Errors contains scopes that could not be imported at all.
+	Errors []string `json:"errors"`
+}
+
+// ClusterSnapshotImportItem is the planned action for a single resource and the outcome
+// of applying it.
+type ClusterSnapshotImportItem struct {
+	Scope    string `json:"scope"`
+	Resource string `json:"resource"`
+	Action   string `json:"action"`
+	Reason   string `json:"reason,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ParseClusterSnapshotScopes validates the given scopes and returns them in import order.
+// All scopes are returned if none are given.
+func ParseClusterSnapshotScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return clusterSnapshotScopes, nil
+	}
+
+	requested := make(map[string]struct{}, len(scopes))
+	for _, scope := range scopes {
+		found := false
+		for _, known := range clusterSnapshotScopes {
+			if scope == known {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown scope %q, valid scopes are: %v", scope, strings.Join(clusterSnapshotScopes, ", "))
+		}
+		requested[scope] = struct{}{}
+	}
+
+	ordered := make([]string, 0, len(requested))
+	for _, scope := range clusterSnapshotScopes {
+		if _, ok := requested[scope]; ok {
+			ordered = append(ordered, scope)
+		}
+	}
+	return ordered, nil
+}
+
+// Validate checks the snapshot version and all resources that are imported by Console itself.
+func (c *ClusterSnapshot) Validate() error {
+	if c.Version != ClusterSnapshotVersion {
+		return fmt.Errorf("unsupported cluster snapshot version %d, expected %d", c.Version, ClusterSnapshotVersion)
+	}
+	for _, topic := range c.Topics {
+		if topic.Name == "" {
+			return fmt.Errorf("topic name must be set")
+		}
+		if topic.Partitions <= 0 || topic.ReplicationFactor <= 0 {
+			return fmt.Errorf("topic %q must have a positive partition count and replication factor", topic.Name)
+		}
+	}
+	for i := range c.ACLs {
+		if err := c.ACLs[i].Validate(); err != nil {
+			return fmt.Errorf("acl %d (%v) is invalid: %w", i, c.ACLs[i].String(), err)
+		}
+	}
+	for _, group := range c.ConsumerGroupOffsets {
+		if group.GroupID == "" {
+			return fmt.Errorf("consumer group id must be set")
+		}
+	}
+	for _, connector := range c.Connectors {
+		if connector.ClusterName == "" || connector.Name == "" {
+			return fmt.Errorf("connector cluster name and name must be set")
+		}
+		if connector.Config["connector.class"] == "" {
+			return fmt.Errorf("connector %q must have a connector.class", connector.Name)
+		}
+	}
+	if c.SchemaRegistry != nil {
+		if err := c.SchemaRegistry.Validate(); err != nil {
+			return fmt.Errorf("schema registry backup is invalid: %w", err)
+		}
+	}
+	return nil
+}
+
+// ExportClusterSnapshot exports the given scopes of the cluster into a single snapshot. Kafka
+// connect and the schema registry are optional, if they are not configured the scope is
+// reported as error in the snapshot. Resources the requester can't see are left out, scopes
+// the requester can't list are reported as error.
+func (s *Service) ExportClusterSnapshot(ctx context.Context, opts ClusterSnapshotExportOptions) (*ClusterSnapshot, *rest.Error) {
+	snapshot := &ClusterSnapshot{
+		Version:   ClusterSnapshotVersion,
+		CreatedAt: time.Now(),
+		Scopes:    make([]string, 0, len(opts.Scopes)),
+		Errors:    make([]string, 0),
+	}
+
+	auth := opts.Authorizer
+	if auth == nil {
+		auth = allowAllSnapshotExports{}
+	}
+
+	for _, scope := range opts.Scopes {
+		var restErr *rest.Error
+		switch scope {
+		case SnapshotScopeTopics:
+			snapshot.Topics, restErr = s.exportSnapshotTopics(ctx, auth)
+		case SnapshotScopeACLs:
+			var isAllowed bool
+			isAllowed, restErr = auth.CanListACLs(ctx)
+			if restErr != nil {
+				break
+			}
+			if !isAllowed {
+				snapshot.Errors = append(snapshot.Errors, fmt.Sprintf("%v: you don't have permissions to list ACLs", scope))
+				continue
+			}
+			snapshot.ACLs, restErr = s.listACLBindings(ctx)
+		case SnapshotScopeQuotas:
+			var isAllowed bool
+			isAllowed, restErr = auth.CanListQuotas(ctx)
+			if restErr != nil {
+				break
+			}
+			if !isAllowed {
+				snapshot.Errors = append(snapshot.Errors, fmt.Sprintf("%v: you don't have permissions to list quotas", scope))
+				continue
+			}
+			snapshot.Quotas, restErr = s.listSnapshotQuotas(ctx)
+		case SnapshotScopeConsumerGroupOffsets:
+			snapshot.ConsumerGroupOffsets, restErr = s.exportSnapshotGroupOffsets(ctx, auth)
+		case SnapshotScopeConnectors:
+			live, err := s.liveSnapshotConnectors(ctx)
+			if err != nil {
+				snapshot.Errors = append(snapshot.Errors, fmt.Sprintf("%v: %v", scope, err.Error()))
+				continue
+			}
+			snapshot.Connectors, restErr = s.exportSnapshotConnectors(ctx, live, auth, opts.IncludeSecrets)
+		case SnapshotScopeSchemaRegistry:
+			backup, err := s.ExportSchemaRegistry(ctx)
+			if err != nil {
+				snapshot.Errors = append(snapshot.Errors, fmt.Sprintf("%v: %v", scope, err.Error()))
+				continue
+			}
+			snapshot.SchemaRegistry = backup
+		}
+		if restErr != nil {
+			return nil, restErr
+		}
+		snapshot.Scopes = append(snapshot.Scopes, scope)
+	}
+
+	return snapshot, nil
+}
+
+func (s *Service) exportSnapshotTopics(ctx context.Context, auth ClusterSnapshotExportAuthorizer) ([]SnapshotTopic, *rest.Error) {
+	live, restErr := s.liveTopicStates(ctx)
+	if restErr != nil {
+		return nil, restErr
+	}
+
+	topics := make([]SnapshotTopic, 0, len(live))
+	for _, topicName := range sortedKeys(live) {
+		// Internal topics are managed by the cluster itself
+		if strings.HasPrefix(topicName, "__") {
+			continue
+		}
+		canSee, restErr := auth.CanSeeTopic(ctx, topicName)
+		if restErr != nil {
+			return nil, restErr
+		}
+		if !canSee {
+			continue
+		}
+		state := live[topicName]
+		topics = append(topics, SnapshotTopic{
+			Name:              topicName,
+			Partitions:        state.Partitions,
+			ReplicationFactor: state.ReplicationFactor,
+			Configs:           state.Configs,
+		})
+	}
+	return topics, nil
+}
+
+func (s *Service) listSnapshotQuotas(ctx context.Context) ([]SnapshotQuota, *rest.Error) {
+	res, err := s.kafkaSvc.DescribeQuotas(ctx)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("failed to describe quotas: %w", err),
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to describe quotas: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+	if err := kerr.ErrorForCode(res.ErrorCode); err != nil {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("failed to describe quotas, inner kafka error: %w", err),
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to describe quotas, kafka responded with the following error: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	quotas := make([]SnapshotQuota, 0, len(res.Entries))
+	for _, entry := range res.Entries {
+		quota := SnapshotQuota{
+			Entity: make([]QuotaEntityComponent, len(entry.Entity)),
+			Values: make([]QuotaResponseSetting, len(entry.Values)),
+		}
+		for i, entity := range entry.Entity {
+			quota.Entity[i] = QuotaEntityComponent{Type: entity.Type, Name: entity.Name}
+		}
+		for i, value := range entry.Values {
+			quota.Values[i] = QuotaResponseSetting{Key: value.Key, Value: value.Value}
+		}
+		quotas = append(quotas, quota)
+	}
+	sort.Slice(quotas, func(i, j int) bool {
+		return quotaEntityKey(quotas[i].Entity) < quotaEntityKey(quotas[j].Entity)
+	})
+	return quotas, nil
+}
+
+func (s *Service) exportSnapshotGroupOffsets(ctx context.Context, auth ClusterSnapshotExportAuthorizer) ([]SnapshotGroupOffsets, *rest.Error) {
+	groupsRes, err := s.kafkaSvc.ListConsumerGroups(ctx)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("failed to list consumer groups: %w", err),
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to list consumer groups: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+	groupIDs := make([]string, 0)
+	for _, groupID := range groupsRes.GetGroupIDs() {
+		canSee, restErr := auth.CanSeeConsumerGroup(ctx, groupID)
+		if restErr != nil {
+			return nil, restErr
+		}
+		if canSee {
+			groupIDs = append(groupIDs, groupID)
+		}
+	}
+	sort.Strings(groupIDs)
+	if len(groupIDs) == 0 {
+		return make([]SnapshotGroupOffsets, 0), nil
+	}
+
+	fetched := s.kafkaSvc.KafkaAdmClient.FetchManyOffsets(ctx, groupIDs...)
+	groups := make([]SnapshotGroupOffsets, 0, len(groupIDs))
+	visibleTopics := make(map[string]bool)
+	for _, groupID := range groupIDs {
+		res, exists := fetched[groupID]
+		if !exists || res.Err != nil {
+			errMsg := "no response"
+			if exists {
+				errMsg = res.Err.Error()
+			}
+			return nil, &rest.Error{
+				Err:      fmt.Errorf("failed to fetch offsets of group %q: %v", groupID, errMsg),
+				Status:   http.StatusServiceUnavailable,
+				Message:  fmt.Sprintf("Failed to fetch offsets of consumer group %q: %v", groupID, errMsg),
+				IsSilent: false,
+			}
+		}
+
+		group := SnapshotGroupOffsets{GroupID: groupID, Offsets: make([]SnapshotPartitionOffset, 0)}
+		for _, offset := range res.Fetched.Sorted() {
+			if offset.Err != nil || offset.At < 0 {
+				continue
+			}
+			canSee, checked := visibleTopics[offset.Topic]
+			if !checked {
+				var restErr *rest.Error
+				canSee, restErr = auth.CanSeeTopic(ctx, offset.Topic)
+				if restErr != nil {
+					return nil, restErr
+				}
+				visibleTopics[offset.Topic] = canSee
+			}
+			if !canSee {
+				continue
+			}
+			group.Offsets = append(group.Offsets, SnapshotPartitionOffset{
+				Topic:     offset.Topic,
+				Partition: offset.Partition,
+				Offset:    offset.At,
+			})
+		}
+		if len(group.Offsets) > 0 {
+			groups = append(groups, group)
+		}
+	}
+	return groups, nil
+}
+
+// exportSnapshotConnectors returns the connectors of all connect clusters the requester can view.
+// Secret config values are redacted, unless includeSecrets is set and the requester is allowed
+// to edit the connect cluster.
+func (s *Service) exportSnapshotConnectors(ctx context.Context, live map[string]map[string]map[string]string, auth ClusterSnapshotExportAuthorizer, includeSecrets bool) ([]SnapshotConnector, *rest.Error) {
+	connectors := make([]SnapshotConnector, 0)
+	for _, clusterName := range sortedKeys(live) {
+		canView, restErr := auth.CanViewConnectCluster(ctx, clusterName)
+		if restErr != nil {
+			return nil, restErr
+		}
+		if !canView {
+			continue
+		}
+		if includeSecrets {
+			canEdit, restErr := auth.CanEditConnectCluster(ctx, clusterName)
+			if restErr != nil {
+				return nil, restErr
+			}
+			if !canEdit {
+				return nil, &rest.Error{
+					Err:      fmt.Errorf("requester has no permissions to export secrets of connect cluster %q", clusterName),
+					Status:   http.StatusForbidden,
+					Message:  fmt.Sprintf("You don't have permissions to export the secrets of connect cluster %q", clusterName),
+					IsSilent: false,
+				}
+			}
+		}
+
+		for _, name := range sortedKeys(live[clusterName]) {
+			config := live[clusterName][name]
+			if !includeSecrets {
+				config = redactConnectorConfig(config, s.connectorPasswordConfigs(ctx, clusterName, config))
+			}
+			connectors = append(connectors, SnapshotConnector{
+				ClusterName: clusterName,
+				Name:        name,
+				Config:      config,
+			})
+		}
+	}
+	return connectors, nil
+}
+
+// connectorPasswordConfigs returns the names of all configs that the connector plugin declares as
+// password. Secrets are still redacted by their name if the plugin can't be asked.
+func (s *Service) connectorPasswordConfigs(ctx context.Context, clusterName string, config map[string]string) map[string]struct{} {
+	configs := make(map[string]any, len(config))
+	for key, value := range config {
+		configs[key] = value
+	}
+	res, restErr := s.connectSvc.ValidateConnectorConfig(ctx, clusterName, config["connector.class"], configs)
+	if restErr != nil {
+		s.logger.Debug("failed to get config definitions of connector plugin",
+			zap.String("cluster_name", clusterName),
+			zap.String("connector_class", config["connector.class"]),
+			zap.Error(restErr.Err))
+		return nil
+	}
+
+	passwordConfigs := make(map[string]struct{})
+	for _, cfg := range res.Configs {
+		if cfg.Definition.Type == model.ConfigDefinitionTypePassword {
+			passwordConfigs[cfg.Definition.Name] = struct{}{}
+		}
+	}
+	return passwordConfigs
+}
+
+// redactConnectorConfig returns a copy of the connector config with all secret values replaced.
+// Configs are secret if they are declared as password or if their name suggests a secret.
+func redactConnectorConfig(config map[string]string, passwordConfigs map[string]struct{}) map[string]string {
+	redacted := make(map[string]string, len(config))
+	for key, value := range config {
+		_, isPassword := passwordConfigs[key]
+		if isPassword || isSecretConfigName(key) {
+			value = RedactedConfigValue
+		}
+		redacted[key] = value
+	}
+	return redacted
+}
+
+func isSecretConfigName(key string) bool {
+	key = strings.ToLower(key)
+	return strings.Contains(key, "password") || strings.Contains(key, "secret") || strings.HasSuffix(key, ".key")
+}
+
+// hasRedactedConfig returns true if any config value has been redacted during the export.
+func hasRedactedConfig(config map[string]string) bool {
+	for _, value := range config {
+		if value == RedactedConfigValue {
+			return true
+		}
+	}
+	return false
+}
+
+// allowAllSnapshotExports is used if no authorizer is passed to ExportClusterSnapshot.
+type allowAllSnapshotExports struct{}
+
+func (allowAllSnapshotExports) CanSeeTopic(context.Context, string) (bool, *rest.Error) {
+	return true, nil
+}
+
+func (allowAllSnapshotExports) CanListACLs(context.Context) (bool, *rest.Error) {
+	return true, nil
+}
+
+func (allowAllSnapshotExports) CanListQuotas(context.Context) (bool, *rest.Error) {
+	return true, nil
+}
+
+func (allowAllSnapshotExports) CanSeeConsumerGroup(context.Context, string) (bool, *rest.Error) {
+	return true, nil
+}
+
+func (allowAllSnapshotExports) CanViewConnectCluster(context.Context, string) (bool, *rest.Error) {
+	return true, nil
+}
+
+func (allowAllSnapshotExports) CanEditConnectCluster(context.Context, string) (bool, *rest.Error) {
+	return true, nil
+}
+
+// liveSnapshotConnectors returns the connector configs by connector name and cluster name.
+func (s *Service) liveSnapshotConnectors(ctx context.Context) (map[string]map[string]map[string]string, error) {
+	if s.connectSvc == nil || !s.connectSvc.Cfg.Enabled {
+		return nil, errors.New("kafka connect is not configured")
+	}
+
+	clusters, err := s.connectSvc.GetAllClusterConnectors(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	live := make(map[string]map[string]map[string]string, len(clusters))
+	for _, cluster := range clusters {
+		if cluster.Error != "" {
+			return nil, fmt.Errorf("failed to list connectors of connect cluster %q: %v", cluster.ClusterName, cluster.Error)
+		}
+		connectors := make(map[string]map[string]string, len(cluster.Connectors))
+		for _, connector := range cluster.Connectors {
+			connectors[connector.Name] = connector.Config
+		}
+		live[cluster.ClusterName] = connectors
+	}
+	return live, nil
+}
@@ -0,0 +1,530 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/cloudhut/common/rest"
+	con "github.com/cloudhut/connect-client"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kmsg"
+
+	"github.com/redpanda-data/console/backend/pkg/schema"
+)
+
+// ImportClusterSnapshot imports the selected scopes of a snapshot into the cluster. Resources
+// that do not exist yet are created, existing resources are never modified. Existing resources
+// that differ from the snapshot are reported as conflict.
+func (s *Service) ImportClusterSnapshot(ctx context.Context, snapshot *ClusterSnapshot, opts ClusterSnapshotImportOptions) (*ClusterSnapshotImportReport, *rest.Error) {
+	scopes, err := ParseClusterSnapshotScopes(opts.Scopes)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusBadRequest,
+			Message:  err.Error(),
+			IsSilent: false,
+		}
+	}
+
+	report := &ClusterSnapshotImportReport{
+		DryRun: opts.DryRun,
+		Scopes: scopes,
+		Items:  make([]ClusterSnapshotImportItem, 0),
+		Errors: make([]string, 0),
+	}
+
+	auth := opts.Authorizer
+	if auth == nil {
+		auth = allowAllSnapshotImports{}
+	}
+
+	// Topics that will exist after the topics scope has been imported. Consumer group offsets
+	// can only be committed for these.
+	var knownTopics map[string]struct{}
+
+	for _, scope := range scopes {
+		var restErr *rest.Error
+		switch scope {
+		case SnapshotScopeTopics:
+			knownTopics, restErr = s.importSnapshotTopics(ctx, snapshot.Topics, auth, report)
+		case SnapshotScopeACLs:
+			restErr = s.importSnapshotACLs(ctx, snapshot.ACLs, opts.IsProtectedPrincipal, auth, report)
+		case SnapshotScopeQuotas:
+			restErr = s.importSnapshotQuotas(ctx, snapshot.Quotas, auth, report)
+		case SnapshotScopeConsumerGroupOffsets:
+			if knownTopics == nil {
+				var live map[string]liveTopicState
+				live, restErr = s.liveTopicStates(ctx)
+				if restErr != nil {
+					break
+				}
+				knownTopics = make(map[string]struct{}, len(live))
+				for topicName := range live {
+					knownTopics[topicName] = struct{}{}
+				}
+			}
+			restErr = s.importSnapshotGroupOffsets(ctx, snapshot.ConsumerGroupOffsets, knownTopics, auth, report)
+		case SnapshotScopeConnectors:
+			if len(snapshot.Connectors) == 0 {
+				continue
+			}
+			if err := s.importSnapshotConnectors(ctx, snapshot.Connectors, auth, report); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("%v: %v", scope, err.Error()))
+			}
+		case SnapshotScopeSchemaRegistry:
+			if snapshot.SchemaRegistry == nil {
+				continue
+			}
+			var isAllowed bool
+			isAllowed, restErr = auth.CanImportSchemaRegistry(ctx)
+			if restErr != nil {
+				break
+			}
+			if !isAllowed {
+				report.Items = append(report.Items, ClusterSnapshotImportItem{
+					Scope:    scope,
+					Resource: "schema registry",
+					Action:   SnapshotImportActionForbidden,
+					Reason:   "you don't have permissions to import the schema registry",
+				})
+				continue
+			}
+			schemaReport, err := s.ImportSchemaRegistry(ctx, snapshot.SchemaRegistry, schema.ImportOptions{
+				DryRun:      opts.DryRun,
+				PreserveIDs: opts.PreserveSchemaIDs,
+			})
+			if err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("%v: %v", scope, err.Error()))
+				continue
+			}
+			report.SchemaRegistry = schemaReport
+		}
+		if restErr != nil {
+			return nil, restErr
+		}
+	}
+
+	return report, nil
+}
+
+func (s *Service) importSnapshotTopics(ctx context.Context, topics []SnapshotTopic, auth ClusterSnapshotImportAuthorizer, report *ClusterSnapshotImportReport) (map[string]struct{}, *rest.Error) {
+	live, restErr := s.liveTopicStates(ctx)
+	if restErr != nil {
+		return nil, restErr
+	}
+
+	knownTopics := make(map[string]struct{}, len(live)+len(topics))
+	for topicName := range live {
+		knownTopics[topicName] = struct{}{}
+	}
+
+	items := planSnapshotTopics(topics, live)
+	restErr = authorizeSnapshotItems(items, func(i int) (bool, *rest.Error) {
+		return auth.CanCreateTopic(ctx, topics[i].Name)
+	})
+	if restErr != nil {
+		return nil, restErr
+	}
+	for i, topic := range topics {
+		if items[i].Action != SnapshotImportActionCreate {
+			continue
+		}
+		knownTopics[topic.Name] = struct{}{}
+		if report.DryRun {
+			continue
+		}
+
+		req := kmsg.NewCreateTopicsRequestTopic()
+		req.Topic = topic.Name
+		req.NumPartitions = topic.Partitions
+		req.ReplicationFactor = topic.ReplicationFactor
+		for _, name := range sortedKeys(topic.Configs) {
+			cfg := kmsg.NewCreateTopicsRequestTopicConfig()
+			cfg.Name = name
+			cfg.Value = kmsg.StringPtr(topic.Configs[name])
+			req.Configs = append(req.Configs, cfg)
+		}
+		if _, restErr := s.CreateTopic(ctx, req); restErr != nil {
+			items[i].Error = restErr.Message
+			delete(knownTopics, topic.Name)
+		}
+	}
+
+	report.Items = append(report.Items, items...)
+	return knownTopics, nil
+}
+
+func (s *Service) importSnapshotACLs(ctx context.Context, bindings []ACLBinding, isProtected func(string) bool, auth ClusterSnapshotImportAuthorizer, report *ClusterSnapshotImportReport) *rest.Error {
+	current, restErr := s.listACLBindings(ctx)
+	if restErr != nil {
+		return restErr
+	}
+
+	items := planSnapshotACLs(bindings, current, isProtected)
+	restErr = authorizeSnapshotItems(items, func(int) (bool, *rest.Error) {
+		return auth.CanCreateACL(ctx)
+	})
+	if restErr != nil {
+		return restErr
+	}
+	if !report.DryRun {
+		res := &ACLImportResult{Creations: make([]ACLBinding, 0), Errors: make([]ACLBindingError, 0)}
+		for i, binding := range bindings {
+			if items[i].Action == SnapshotImportActionCreate {
+				res.Creations = append(res.Creations, binding)
+			}
+		}
+		if restErr := s.createACLBindings(ctx, res); restErr != nil {
+			return restErr
+		}
+
+		errByBinding := make(map[ACLBinding]string, len(res.Errors))
+		for _, bindingErr := range res.Errors {
+			errByBinding[bindingErr.ACL] = bindingErr.Error
+		}
+		for i, binding := range bindings {
+			if errMsg, exists := errByBinding[binding]; exists && items[i].Action == SnapshotImportActionCreate {
+				items[i].Error = errMsg
+			}
+		}
+	}
+
+	report.Items = append(report.Items, items...)
+	return nil
+}
+
+func (s *Service) importSnapshotQuotas(ctx context.Context, quotas []SnapshotQuota, auth ClusterSnapshotImportAuthorizer, report *ClusterSnapshotImportReport) *rest.Error {
+	current, restErr := s.listSnapshotQuotas(ctx)
+	if restErr != nil {
+		return restErr
+	}
+
+	items := planSnapshotQuotas(quotas, current)
+	restErr = authorizeSnapshotItems(items, func(i int) (bool, *rest.Error) {
+		return auth.CanAlterQuotas(ctx, quotas[i].Entity)
+	})
+	if restErr != nil {
+		return restErr
+	}
+	for i, quota := range quotas {
+		if items[i].Action != SnapshotImportActionCreate || report.DryRun {
+			continue
+		}
+		if _, restErr := s.AlterQuotas(ctx, AlterQuotasRequest{Entity: quota.Entity, Set: quota.Values}); restErr != nil {
+			items[i].Error = restErr.Message
+		}
+	}
+
+	report.Items = append(report.Items, items...)
+	return nil
+}
+
+func (s *Service) importSnapshotGroupOffsets(ctx context.Context, groups []SnapshotGroupOffsets, knownTopics map[string]struct{}, auth ClusterSnapshotImportAuthorizer, report *ClusterSnapshotImportReport) *rest.Error {
+	groupsRes, err := s.kafkaSvc.ListConsumerGroups(ctx)
+	if err != nil {
+		return &rest.Error{
+			Err:      fmt.Errorf("failed to list consumer groups: %w", err),
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to list consumer groups: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+	existingGroups := make(map[string]struct{})
+	for _, groupID := range groupsRes.GetGroupIDs() {
+		existingGroups[groupID] = struct{}{}
+	}
+
+	items := planSnapshotGroupOffsets(groups, existingGroups, knownTopics)
+	restErr := authorizeSnapshotItems(items, func(i int) (bool, *rest.Error) {
+		return auth.CanEditConsumerGroup(ctx, groups[i].GroupID)
+	})
+	if restErr != nil {
+		return restErr
+	}
+	for i, group := range groups {
+		if items[i].Action != SnapshotImportActionCreate || report.DryRun {
+			continue
+		}
+
+		// The group does not exist yet, hence the offsets can be committed without a generation
+		var offsets kadm.Offsets
+		for _, offset := range group.Offsets {
+			offsets.Add(kadm.Offset{
+				Topic:       offset.Topic,
+				Partition:   offset.Partition,
+				At:          offset.Offset,
+				LeaderEpoch: -1,
+			})
+		}
+		if err := s.kafkaSvc.KafkaAdmClient.CommitAllOffsets(ctx, group.GroupID, offsets); err != nil {
+			items[i].Error = err.Error()
+		}
+	}
+
+	report.Items = append(report.Items, items...)
+	return nil
+}
+
+func (s *Service) importSnapshotConnectors(ctx context.Context, connectors []SnapshotConnector, auth ClusterSnapshotImportAuthorizer, report *ClusterSnapshotImportReport) error {
+	live, err := s.liveSnapshotConnectors(ctx)
+	if err != nil {
+		return err
+	}
+
+	items := planSnapshotConnectors(connectors, live)
+	restErr := authorizeSnapshotItems(items, func(i int) (bool, *rest.Error) {
+		return auth.CanEditConnectCluster(ctx, connectors[i].ClusterName)
+	})
+	if restErr != nil {
+		return restErr.Err
+	}
+	for i, connector := range connectors {
+		if items[i].Action != SnapshotImportActionCreate || report.DryRun {
+			continue
+		}
+
+		cfg := make(map[string]interface{}, len(connector.Config))
+		for key, value := range connector.Config {
+			cfg[key] = value
+		}
+		_, restErr := s.connectSvc.PutConnectorConfig(ctx, connector.ClusterName, connector.Name, con.PutConnectorConfigOptions{Config: cfg})
+		if restErr != nil {
+			items[i].Error = restErr.Message
+		}
+	}
+
+	report.Items = append(report.Items, items...)
+	return nil
+}
+
+// authorizeSnapshotItems checks the permission for each item that would be created and reports
+// the items that are not allowed as forbidden.
+func authorizeSnapshotItems(items []ClusterSnapshotImportItem, isAllowed func(i int) (bool, *rest.Error)) *rest.Error {
+	for i := range items {
+		if items[i].Action != SnapshotImportActionCreate {
+			continue
+		}
+		allowed, restErr := isAllowed(i)
+		if restErr != nil {
+			return restErr
+		}
+		if !allowed {
+			items[i].Action = SnapshotImportActionForbidden
+			items[i].Reason = "you don't have permissions to create this resource"
+		}
+	}
+	return nil
+}
+
+// allowAllSnapshotImports is used if no authorizer is passed to ImportClusterSnapshot.
+type allowAllSnapshotImports struct{}
+
+func (allowAllSnapshotImports) CanCreateTopic(context.Context, string) (bool, *rest.Error) {
+	return true, nil
+}
+
+func (allowAllSnapshotImports) CanCreateACL(context.Context) (bool, *rest.Error) {
+	return true, nil
+}
+
+func (allowAllSnapshotImports) CanAlterQuotas(context.Context, []QuotaEntityComponent) (bool, *rest.Error) {
+	return true, nil
+}
+
+func (allowAllSnapshotImports) CanEditConsumerGroup(context.Context, string) (bool, *rest.Error) {
+	return true, nil
+}
+
+func (allowAllSnapshotImports) CanEditConnectCluster(context.Context, string) (bool, *rest.Error) {
+	return true, nil
+}
+
+func (allowAllSnapshotImports) CanImportSchemaRegistry(context.Context) (bool, *rest.Error) {
+	return true, nil
+}
+
+// planSnapshotTopics returns one import item per snapshot topic. Topics that already exist
+// are a conflict if their partition count, replication factor or explicitly set configs differ.
+func planSnapshotTopics(topics []SnapshotTopic, live map[string]liveTopicState) []ClusterSnapshotImportItem {
+	items := make([]ClusterSnapshotImportItem, len(topics))
+	for i, topic := range topics {
+		item := ClusterSnapshotImportItem{Scope: SnapshotScopeTopics, Resource: topic.Name, Action: SnapshotImportActionCreate}
+		current, exists := live[topic.Name]
+		if exists {
+			differences := make([]string, 0)
+			if current.Partitions != topic.Partitions {
+				differences = append(differences, fmt.Sprintf("partitions %d != %d", current.Partitions, topic.Partitions))
+			}
+			if current.ReplicationFactor != topic.ReplicationFactor {
+				differences = append(differences, fmt.Sprintf("replication factor %d != %d", current.ReplicationFactor, topic.ReplicationFactor))
+			}
+			desired := topic.Configs
+			if desired == nil {
+				desired = make(map[string]string)
+			}
+			for _, change := range diffTopicStateConfigs(current.Configs, desired) {
+				differences = append(differences, fmt.Sprintf("config %v", change.Name))
+			}
+
+			item.Action = SnapshotImportActionSkip
+			item.Reason = "topic already exists"
+			if len(differences) > 0 {
+				item.Action = SnapshotImportActionConflict
+				item.Reason = fmt.Sprintf("topic already exists with a different %v", strings.Join(differences, ", "))
+			}
+		}
+		items[i] = item
+	}
+	return items
+}
+
+// planSnapshotACLs returns one import item per snapshot ACL. Existing ACLs are never deleted.
+func planSnapshotACLs(bindings []ACLBinding, current []ACLBinding, isProtected func(string) bool) []ClusterSnapshotImportItem {
+	existing := make(map[ACLBinding]struct{}, len(current))
+	for _, binding := range current {
+		existing[binding] = struct{}{}
+	}
+
+	items := make([]ClusterSnapshotImportItem, len(bindings))
+	for i, binding := range bindings {
+		item := ClusterSnapshotImportItem{Scope: SnapshotScopeACLs, Resource: binding.String(), Action: SnapshotImportActionCreate}
+		if _, exists := existing[binding]; exists {
+			item.Action = SnapshotImportActionSkip
+			item.Reason = "acl already exists"
+		} else if isProtected != nil && isProtected(binding.Principal) {
+			item.Action = SnapshotImportActionConflict
+			item.Reason = "acls of this principal are protected"
+		}
+		items[i] = item
+	}
+	return items
+}
+
+// planSnapshotQuotas returns one import item per snapshot quota entity. Entities that already
+// have quotas are a conflict if any value differs.
+func planSnapshotQuotas(quotas []SnapshotQuota, current []SnapshotQuota) []ClusterSnapshotImportItem {
+	existing := make(map[string]map[string]float64, len(current))
+	for _, quota := range current {
+		values := make(map[string]float64, len(quota.Values))
+		for _, value := range quota.Values {
+			values[value.Key] = value.Value
+		}
+		existing[quotaEntityKey(quota.Entity)] = values
+	}
+
+	items := make([]ClusterSnapshotImportItem, len(quotas))
+	for i, quota := range quotas {
+		key := quotaEntityKey(quota.Entity)
+		item := ClusterSnapshotImportItem{Scope: SnapshotScopeQuotas, Resource: key, Action: SnapshotImportActionCreate}
+		if values, exists := existing[key]; exists {
+			item.Action = SnapshotImportActionSkip
+			item.Reason = "quotas already exist"
+			equal := len(values) == len(quota.Values)
+			for _, value := range quota.Values {
+				if currentValue, ok := values[value.Key]; !ok || currentValue != value.Value {
+					equal = false
+				}
+			}
+			if !equal {
+				item.Action = SnapshotImportActionConflict
+				item.Reason = "quotas already exist with different values"
+			}
+		}
+		items[i] = item
+	}
+	return items
+}
+
+// planSnapshotGroupOffsets returns one import item per snapshot consumer group. Offsets are
+// only committed for groups that do not exist yet, and only if all topics exist.
+func planSnapshotGroupOffsets(groups []SnapshotGroupOffsets, existingGroups map[string]struct{}, knownTopics map[string]struct{}) []ClusterSnapshotImportItem {
+	items := make([]ClusterSnapshotImportItem, len(groups))
+	for i, group := range groups {
+		item := ClusterSnapshotImportItem{Scope: SnapshotScopeConsumerGroupOffsets, Resource: group.GroupID, Action: SnapshotImportActionCreate}
+		missingTopics := make(map[string]struct{})
+		for _, offset := range group.Offsets {
+			if _, exists := knownTopics[offset.Topic]; !exists {
+				missingTopics[offset.Topic] = struct{}{}
+			}
+		}
+
+		if _, exists := existingGroups[group.GroupID]; exists {
+			item.Action = SnapshotImportActionConflict
+			item.Reason = "consumer group already exists, its offsets are not overwritten"
+		} else if len(missingTopics) > 0 {
+			item.Action = SnapshotImportActionConflict
+			item.Reason = fmt.Sprintf("topics do not exist: %v", strings.Join(sortedKeys(missingTopics), ", "))
+		}
+		items[i] = item
+	}
+	return items
+}
+
+// planSnapshotConnectors returns one import item per snapshot connector. Connectors can only be
+// created in connect clusters with the same name.
+func planSnapshotConnectors(connectors []SnapshotConnector, live map[string]map[string]map[string]string) []ClusterSnapshotImportItem {
+	items := make([]ClusterSnapshotImportItem, len(connectors))
+	for i, connector := range connectors {
+		item := ClusterSnapshotImportItem{
+			Scope:    SnapshotScopeConnectors,
+			Resource: fmt.Sprintf("%v/%v", connector.ClusterName, connector.Name),
+			Action:   SnapshotImportActionCreate,
+		}
+		clusterConnectors, clusterExists := live[connector.ClusterName]
+		current, connectorExists := clusterConnectors[connector.Name]
+		switch {
+		case !clusterExists:
+			item.Action = SnapshotImportActionConflict
+			item.Reason = "connect cluster is not configured"
+		case connectorExists && !equalStringMaps(current, connector.Config):
+			item.Action = SnapshotImportActionConflict
+			item.Reason = "connector already exists with a different config"
+		case connectorExists:
+			item.Action = SnapshotImportActionSkip
+			item.Reason = "connector already exists"
+		case hasRedactedConfig(connector.Config):
+			item.Action = SnapshotImportActionConflict
+			item.Reason = "connector config contains redacted secrets, export the snapshot with secrets to create it"
+		}
+		items[i] = item
+	}
+	return items
+}
+
+// quotaEntityKey returns a stable string representation of a quota entity.
+func quotaEntityKey(entity []QuotaEntityComponent) string {
+	parts := make([]string, len(entity))
+	for i, component := range entity {
+		name := "<default>"
+		if component.Name != nil {
+			name = *component.Name
+		}
+		parts[i] = fmt.Sprintf("%v=%v", component.Type, name)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+func equalStringMaps(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		if other, exists := b[key]; !exists || other != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,405 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/redpanda-data/console/backend/pkg/kafka"
+)
+
+// OffsetResetStrategy determines how the new group offsets are computed.
+type OffsetResetStrategy string
+
+const (
+	// OffsetResetStrategyEarliest resets the group offsets to the low watermarks.
+	OffsetResetStrategyEarliest OffsetResetStrategy = "earliest"
+	// OffsetResetStrategyLatest resets the group offsets to the high watermarks.
+	OffsetResetStrategyLatest OffsetResetStrategy = "latest"
+	// OffsetResetStrategyTimestamp resets the group offsets to the first offset whose
+	// timestamp is equal or greater than the given timestamp.
+	OffsetResetStrategyTimestamp OffsetResetStrategy = "timestamp"
+	// OffsetResetStrategyDuration resets the group offsets to the first offset whose
+	// timestamp is equal or greater than now minus the given duration.
+	OffsetResetStrategyDuration OffsetResetStrategy = "duration"
+	// OffsetResetStrategyShiftBy shifts the committed group offsets by N, which may be negative.
+	OffsetResetStrategyShiftBy OffsetResetStrategy = "shiftBy"
+	// OffsetResetStrategyCopyFromGroup copies the committed offsets of another group.
+	OffsetResetStrategyCopyFromGroup OffsetResetStrategy = "copyFromGroup"
+)
+
+// IsValid returns true if the strategy is known.
+func (s OffsetResetStrategy) IsValid() bool {
+	switch s {
+	case OffsetResetStrategyEarliest, OffsetResetStrategyLatest, OffsetResetStrategyTimestamp,
+		OffsetResetStrategyDuration, OffsetResetStrategyShiftBy, OffsetResetStrategyCopyFromGroup:
+		return true
+	default:
+		return false
+	}
+}
+
+// ResetConsumerGroupOffsetsRequest describes a strategy based reset of a group's offsets,
+// similar to kafka-consumer-groups --reset-offsets.
+type ResetConsumerGroupOffsetsRequest struct {
+	GroupID  string
+	Strategy OffsetResetStrategy
+
+	// Topics whose offsets shall be reset. If empty, all topics the group has committed
+	// offsets for are reset. For the copyFromGroup strategy the source group's topics are used.
+	Topics []string
+
+	// Timestamp in unix milliseconds, used by the timestamp strategy.
+	Timestamp int64
+	// Duration is used by the duration strategy.
+	Duration time.Duration
+	// ShiftBy is used by the shiftBy strategy.
+	ShiftBy int64
+	// SourceGroupID is used by the copyFromGroup strategy.
+	SourceGroupID string
+
+	// DryRun computes the new offsets without committing them.
+	DryRun bool
+	// Force commits the offsets even if the group has active members. Kafka may still
+	// reject the commit for a group that is not empty, which is reported per partition.
+	Force bool
+}
+
+// ResetConsumerGroupOffsetsResponse contains the before/after table of a group offset reset.
+type ResetConsumerGroupOffsetsResponse struct {
+	GroupID     string                               `json:"groupId"`
+	Strategy    OffsetResetStrategy                  `json:"strategy"`
+	GroupState  string                               `json:"groupState"`
+	MemberCount int                                  `json:"memberCount"`
+	DryRun      bool                                 `json:"dryRun"`
+	Partitions  []ResetConsumerGroupOffsetsPartition `json:"partitions"`
+}
+
+// ResetConsumerGroupOffsetsPartition is the computed offset reset for a single partition.
+type ResetConsumerGroupOffsetsPartition struct {
+	TopicName   string `json:"topicName"`
+	PartitionID int32  `json:"partitionId"`
+
+	// CurrentOffset is the committed group offset or -1 if the group has no committed offset.
+	CurrentOffset int64 `json:"currentOffset"`
+	NewOffset     int64 `json:"newOffset"`
+	LowWaterMark  int64 `json:"lowWaterMark"`
+	HighWaterMark int64 `json:"highWaterMark"`
+
+	// Error is set if the new offset could not be computed or committed.
+	Error string `json:"error,omitempty"`
+}
+
+// offsetResetInput contains everything that is known about a partition to compute its new offset.
+type offsetResetInput struct {
+	CurrentOffset    int64
+	HasCurrentOffset bool
+	Low              int64
+	High             int64
+
+	// TimestampOffset is the offset for the requested timestamp, -1 if there is no such record.
+	TimestampOffset int64
+	// SourceOffset is the committed offset of the group offsets are copied from.
+	SourceOffset    int64
+	HasSourceOffset bool
+}
+
+// computeResetOffset returns the new group offset for a partition. The returned offset is
+// always within the partition's watermarks.
+func computeResetOffset(strategy OffsetResetStrategy, shiftBy int64, in offsetResetInput) (int64, error) {
+	var offset int64
+	switch strategy {
+	case OffsetResetStrategyEarliest:
+		offset = in.Low
+	case OffsetResetStrategyLatest:
+		offset = in.High
+	case OffsetResetStrategyTimestamp, OffsetResetStrategyDuration:
+		offset = in.TimestampOffset
+		if offset < 0 {
+			// There is no record at or after the timestamp
+			offset = in.High
+		}
+	case OffsetResetStrategyShiftBy:
+		if !in.HasCurrentOffset {
+			return 0, fmt.Errorf("group has no committed offset that could be shifted")
+		}
+		offset = in.CurrentOffset + shiftBy
+	case OffsetResetStrategyCopyFromGroup:
+		if !in.HasSourceOffset {
+			return 0, fmt.Errorf("source group has no committed offset for this partition")
+		}
+		offset = in.SourceOffset
+	default:
+		return 0, fmt.Errorf("unknown offset reset strategy %q", strategy)
+	}
+
+	if offset < in.Low {
+		offset = in.Low
+	}
+	if offset > in.High {
+		offset = in.High
+	}
+	return offset, nil
+}
+
+// ResetConsumerGroupOffsets computes new group offsets for the given strategy and commits them
+// unless it's a dry run. Offsets are only committed for groups without active members, unless
+// force is set.
+//
+//nolint:gocognit,cyclop // Computing the offsets requires several lookups that depend on the strategy
+func (s *Service) ResetConsumerGroupOffsets(ctx context.Context, req ResetConsumerGroupOffsetsRequest) (*ResetConsumerGroupOffsetsResponse, *rest.Error) {
+	internalLogs := []zapcore.Field{
+		zap.String("group_id", req.GroupID),
+		zap.String("strategy", string(req.Strategy)),
+		zap.Bool("dry_run", req.DryRun),
+	}
+
+	// 1. Check group state
+	describedGroup, err := s.kafkaSvc.DescribeConsumerGroup(ctx, req.GroupID)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:          fmt.Errorf("failed to check group state: %w", err),
+			Status:       http.StatusServiceUnavailable,
+			Message:      fmt.Sprintf("Failed to check consumer group state before proceeding: %v", err.Error()),
+			InternalLogs: internalLogs,
+		}
+	}
+	isInactive := strings.EqualFold(describedGroup.State, "empty") || strings.EqualFold(describedGroup.State, "dead")
+	if !req.DryRun && !req.Force && !isInactive {
+		return nil, &rest.Error{
+			Err:          fmt.Errorf("group is in state %q and has %d members", describedGroup.State, len(describedGroup.Members)),
+			Status:       http.StatusConflict,
+			Message:      fmt.Sprintf("Consumer group still has %d active members (state: %v). Stop all consumers or force the reset.", len(describedGroup.Members), describedGroup.State),
+			InternalLogs: internalLogs,
+		}
+	}
+
+	// 2. Fetch the committed offsets of the group and, if needed, of the source group
+	groups := []string{req.GroupID}
+	if req.Strategy == OffsetResetStrategyCopyFromGroup {
+		groups = append(groups, req.SourceGroupID)
+	}
+	fetchedOffsets := s.kafkaSvc.ListConsumerGroupOffsetsBulk(ctx, groups)
+	for _, group := range groups {
+		if fetched, exists := fetchedOffsets[group]; !exists || fetched.Err != nil {
+			fetchErr := errors.New("no response")
+			if exists {
+				fetchErr = fetched.Err
+			}
+			return nil, &rest.Error{
+				Err:          fmt.Errorf("failed to fetch offsets of group %q: %w", group, fetchErr),
+				Status:       http.StatusServiceUnavailable,
+				Message:      fmt.Sprintf("Failed to fetch committed offsets of group '%v': %v", group, fetchErr.Error()),
+				InternalLogs: internalLogs,
+			}
+		}
+	}
+	currentOffsets := fetchedOffsets[req.GroupID].Fetched
+	sourceOffsets := fetchedOffsets[req.SourceGroupID].Fetched
+
+	topics := req.Topics
+	if len(topics) == 0 {
+		if req.Strategy == OffsetResetStrategyCopyFromGroup {
+			topics = fetchedOffsets[req.SourceGroupID].CommittedPartitions().Topics()
+		} else {
+			topics = fetchedOffsets[req.GroupID].CommittedPartitions().Topics()
+		}
+	}
+	if len(topics) == 0 {
+		return nil, &rest.Error{
+			Err:          fmt.Errorf("no topics to reset offsets for"),
+			Status:       http.StatusBadRequest,
+			Message:      "The group has no committed offsets, please specify the topics whose offsets shall be reset",
+			InternalLogs: internalLogs,
+		}
+	}
+
+	// 3. Fetch partitions and watermarks of all topics
+	metadata, err := s.kafkaSvc.KafkaAdmClient.Metadata(ctx, topics...)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:          fmt.Errorf("failed to get topic metadata: %w", err),
+			Status:       http.StatusServiceUnavailable,
+			Message:      fmt.Sprintf("Failed to get topic metadata: %v", err.Error()),
+			InternalLogs: internalLogs,
+		}
+	}
+	topicPartitions := make(map[string][]int32, len(topics))
+	for _, topic := range topics {
+		td, exists := metadata.Topics[topic]
+		if !exists || td.Err != nil {
+			var topicErr error = kerr.UnknownTopicOrPartition
+			if exists {
+				topicErr = td.Err
+			}
+			return nil, &rest.Error{
+				Err:          fmt.Errorf("failed to get metadata for topic %q: %w", topic, topicErr),
+				Status:       http.StatusBadRequest,
+				Message:      fmt.Sprintf("Failed to get metadata for topic '%v': %v", topic, topicErr.Error()),
+				InternalLogs: internalLogs,
+			}
+		}
+		topicPartitions[topic] = td.Partitions.Numbers()
+	}
+
+	waterMarks, err := s.kafkaSvc.GetPartitionMarksBulk(ctx, topicPartitions)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:          err,
+			Status:       http.StatusServiceUnavailable,
+			Message:      fmt.Sprintf("Failed to fetch partition watermarks: %v", err.Error()),
+			InternalLogs: internalLogs,
+		}
+	}
+
+	var timestampOffsets map[string]map[int32]kafka.ListOffsetsResponseTopicPartition
+	switch req.Strategy {
+	case OffsetResetStrategyTimestamp:
+		timestampOffsets = s.kafkaSvc.ListOffsets(ctx, topicPartitions, req.Timestamp)
+	case OffsetResetStrategyDuration:
+		timestampOffsets = s.kafkaSvc.ListOffsets(ctx, topicPartitions, time.Now().Add(-req.Duration).UnixMilli())
+	}
+
+	// 4. Compute new offsets
+	partitions := make([]ResetConsumerGroupOffsetsPartition, 0)
+	for topic, partitionIDs := range topicPartitions {
+		for _, pID := range partitionIDs {
+			p := ResetConsumerGroupOffsetsPartition{
+				TopicName:     topic,
+				PartitionID:   pID,
+				CurrentOffset: -1,
+				NewOffset:     -1,
+			}
+			in := offsetResetInput{}
+			if current, exists := currentOffsets.Lookup(topic, pID); exists && current.Err == nil {
+				p.CurrentOffset = current.At
+				in.CurrentOffset = current.At
+				in.HasCurrentOffset = true
+			}
+			if source, exists := sourceOffsets.Lookup(topic, pID); exists && source.Err == nil {
+				in.SourceOffset = source.At
+				in.HasSourceOffset = true
+			}
+
+			marks := waterMarks[topic][pID]
+			if marks == nil || marks.Error != nil {
+				p.Error = "failed to fetch partition watermarks"
+				if marks != nil {
+					p.Error = fmt.Sprintf("failed to fetch partition watermarks: %v", marks.Error.Error())
+				}
+				partitions = append(partitions, p)
+				continue
+			}
+			p.LowWaterMark = marks.Low
+			p.HighWaterMark = marks.High
+			in.Low = marks.Low
+			in.High = marks.High
+
+			if timestampOffsets != nil {
+				timestampOffset, exists := timestampOffsets[topic][pID]
+				if !exists || timestampOffset.Err != nil {
+					p.Error = "failed to list offsets for timestamp"
+					if exists {
+						p.Error = fmt.Sprintf("failed to list offsets for timestamp: %v", timestampOffset.Err.Error())
+					}
+					partitions = append(partitions, p)
+					continue
+				}
+				in.TimestampOffset = timestampOffset.Offset
+			}
+
+			newOffset, err := computeResetOffset(req.Strategy, req.ShiftBy, in)
+			if err != nil {
+				p.Error = err.Error()
+			} else {
+				p.NewOffset = newOffset
+			}
+			partitions = append(partitions, p)
+		}
+	}
+	sort.Slice(partitions, func(i, j int) bool {
+		if partitions[i].TopicName != partitions[j].TopicName {
+			return partitions[i].TopicName < partitions[j].TopicName
+		}
+		return partitions[i].PartitionID < partitions[j].PartitionID
+	})
+
+	res := &ResetConsumerGroupOffsetsResponse{
+		GroupID:     req.GroupID,
+		Strategy:    req.Strategy,
+		GroupState:  describedGroup.State,
+		MemberCount: len(describedGroup.Members),
+		DryRun:      req.DryRun,
+		Partitions:  partitions,
+	}
+	if req.DryRun {
+		return res, nil
+	}
+
+	// 5. Commit all successfully computed offsets
+	commitTopics := make([]kmsg.OffsetCommitRequestTopic, 0)
+	partitionIndex := make(map[string]map[int32]int)
+	for i, p := range partitions {
+		if p.Error != "" {
+			continue
+		}
+		if _, exists := partitionIndex[p.TopicName]; !exists {
+			partitionIndex[p.TopicName] = make(map[int32]int)
+			topicReq := kmsg.NewOffsetCommitRequestTopic()
+			topicReq.Topic = p.TopicName
+			commitTopics = append(commitTopics, topicReq)
+		}
+		partitionIndex[p.TopicName][p.PartitionID] = i
+
+		partitionReq := kmsg.NewOffsetCommitRequestTopicPartition()
+		partitionReq.Partition = p.PartitionID
+		partitionReq.Offset = p.NewOffset
+		topicReq := &commitTopics[len(commitTopics)-1]
+		topicReq.Partitions = append(topicReq.Partitions, partitionReq)
+	}
+	if len(commitTopics) == 0 {
+		return res, nil
+	}
+
+	commitRes, err := s.kafkaSvc.EditConsumerGroupOffsets(ctx, req.GroupID, commitTopics)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:          err,
+			Status:       http.StatusServiceUnavailable,
+			Message:      fmt.Sprintf("Reset consumer group offsets failed: %v", err.Error()),
+			InternalLogs: internalLogs,
+		}
+	}
+	for _, topic := range commitRes.Topics {
+		for _, partition := range topic.Partitions {
+			err := kerr.ErrorForCode(partition.ErrorCode)
+			if err == nil {
+				continue
+			}
+			if i, exists := partitionIndex[topic.Topic][partition.Partition]; exists {
+				res.Partitions[i].Error = err.Error()
+			}
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeResetOffset(t *testing.T) {
+	in := offsetResetInput{
+		CurrentOffset:    50,
+		HasCurrentOffset: true,
+		Low:              10,
+		High:             100,
+		TimestampOffset:  42,
+		SourceOffset:     70,
+		HasSourceOffset:  true,
+	}
+
+	tests := []struct {
+		name     string
+		strategy OffsetResetStrategy
+		shiftBy  int64
+		input    offsetResetInput
+		expected int64
+	}{
+		{name: "earliest", strategy: OffsetResetStrategyEarliest, input: in, expected: 10},
+		{name: "latest", strategy: OffsetResetStrategyLatest, input: in, expected: 100},
+		{name: "timestamp", strategy: OffsetResetStrategyTimestamp, input: in, expected: 42},
+		{name: "duration", strategy: OffsetResetStrategyDuration, input: in, expected: 42},
+		{name: "shift forward", strategy: OffsetResetStrategyShiftBy, shiftBy: 20, input: in, expected: 70},
+		{name: "shift backward", strategy: OffsetResetStrategyShiftBy, shiftBy: -20, input: in, expected: 30},
+		{name: "shift below low watermark", strategy: OffsetResetStrategyShiftBy, shiftBy: -100, input: in, expected: 10},
+		{name: "shift above high watermark", strategy: OffsetResetStrategyShiftBy, shiftBy: 100, input: in, expected: 100},
+		{name: "copy from group", strategy: OffsetResetStrategyCopyFromGroup, input: in, expected: 70},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offset, err := computeResetOffset(tt.strategy, tt.shiftBy, tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, offset)
+		})
+	}
+
+	t.Run("timestamp after last record", func(t *testing.T) {
+		noRecord := in
+		noRecord.TimestampOffset = -1
+		offset, err := computeResetOffset(OffsetResetStrategyTimestamp, 0, noRecord)
+		require.NoError(t, err)
+		assert.Equal(t, int64(100), offset)
+	})
+
+	t.Run("shift without committed offset", func(t *testing.T) {
+		noCurrent := in
+		noCurrent.HasCurrentOffset = false
+		_, err := computeResetOffset(OffsetResetStrategyShiftBy, 5, noCurrent)
+		assert.Error(t, err)
+	})
+
+	t.Run("copy without source offset", func(t *testing.T) {
+		noSource := in
+		noSource.HasSourceOffset = false
+		_, err := computeResetOffset(OffsetResetStrategyCopyFromGroup, 0, noSource)
+		assert.Error(t, err)
+	})
+}
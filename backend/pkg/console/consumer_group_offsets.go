@@ -28,6 +28,9 @@ type GroupTopicOffsets struct {
 	PartitionCount       int                `json:"partitionCount"`
 	PartitionsWithOffset int                `json:"partitionsWithOffset"` // Number of partitions which have an active group offset
 	PartitionOffsets     []PartitionOffsets `json:"partitionOffsets"`
+
+	// MaxTimeLagSeconds is the highest estimated time lag of all partitions, nil if unknown.
+	MaxTimeLagSeconds *float64 `json:"maxTimeLagSeconds"`
 }
 
 // PartitionOffsets describes the kafka lag for a partition for a single consumer group
@@ -38,6 +41,11 @@ type PartitionOffsets struct {
 	GroupOffset   int64  `json:"groupOffset"`
 	HighWaterMark int64  `json:"highWaterMark"`
 	Lag           int64  `json:"lag"`
+
+	// TimeLagSeconds is the estimated time lag, which is the difference between the timestamp
+	// of the newest record and the timestamp of the record at the group offset. It's nil if
+	// the timestamps could not be fetched.
+	TimeLagSeconds *float64 `json:"timeLagSeconds"`
 }
 
 // getConsumerGroupOffsets returns a nested map where the group id is the key
@@ -33,6 +33,10 @@ type ConsumerGroupOverview struct {
 	CoordinatorID int32                    `json:"coordinatorId"`
 	TopicOffsets  []GroupTopicOffsets      `json:"topicOffsets"`
 
+	// MaxTimeLagSeconds is the highest estimated time lag across all consumed partitions,
+	// nil if no time lag could be estimated.
+	MaxTimeLagSeconds *float64 `json:"maxTimeLagSeconds"`
+
 	// AllowedActions define the Kowl Business permissions on this specific group
 	AllowedActions []string `json:"allowedActions"`
 }
@@ -51,9 +55,24 @@ type GroupMemberAssignment struct {
 	PartitionIDs []int32 `json:"partitionIds"`
 }
 
+// ConsumerGroupsOverviewOptions control which optional details are added to the overview.
+type ConsumerGroupsOverviewOptions struct {
+	// EstimateTimeLag fetches record timestamps from Kafka to estimate the time lag of each
+	// consumed partition. Without it all time lags are nil.
+	EstimateTimeLag bool
+
+	// GroupFilter restricts the overview to the groups it returns true for. It's only used if
+	// no group IDs are passed, so that callers don't have to list the groups themselves.
+	GroupFilter func(groupID string) bool
+
+	// TopicFilter restricts the topic offsets of each group, and thereby the time lag
+	// estimation, to the topics it returns true for.
+	TopicFilter func(topicName string) bool
+}
+
 // GetConsumerGroupsOverview returns a ConsumerGroupOverview for all available consumer groups
 // Pass nil for groupIDs if you want to fetch all available groups.
-func (s *Service) GetConsumerGroupsOverview(ctx context.Context, groupIDs []string) ([]ConsumerGroupOverview, *rest.Error) {
+func (s *Service) GetConsumerGroupsOverview(ctx context.Context, groupIDs []string, opts ConsumerGroupsOverviewOptions) ([]ConsumerGroupOverview, *rest.Error) {
 	groups, err := s.kafkaSvc.ListConsumerGroups(ctx)
 	if err != nil {
 		return nil, &rest.Error{
@@ -66,6 +85,15 @@ func (s *Service) GetConsumerGroupsOverview(ctx context.Context, groupIDs []stri
 
 	if groupIDs == nil {
 		groupIDs = groups.GetGroupIDs()
+		if opts.GroupFilter != nil {
+			filtered := make([]string, 0, len(groupIDs))
+			for _, id := range groupIDs {
+				if opts.GroupFilter(id) {
+					filtered = append(filtered, id)
+				}
+			}
+			groupIDs = filtered
+		}
 	} else {
 		// Not existent consumer groups will be reported as "dead" by Kafka. We would like to report them as 404 instead.
 		// Hence we'll check if the passed group IDs exist in the response
@@ -101,6 +129,20 @@ func (s *Service) GetConsumerGroupsOverview(ctx context.Context, groupIDs []stri
 			IsSilent: false,
 		}
 	}
+	if opts.TopicFilter != nil {
+		for groupID, topics := range groupLags {
+			filtered := make([]GroupTopicOffsets, 0, len(topics))
+			for _, topic := range topics {
+				if opts.TopicFilter(topic.Topic) {
+					filtered = append(filtered, topic)
+				}
+			}
+			groupLags[groupID] = filtered
+		}
+	}
+	if opts.EstimateTimeLag {
+		s.estimateTimeLags(ctx, groupLags)
+	}
 
 	res := s.convertKgoGroupDescriptions(describedGroupsSharded, groupLags)
 	sort.Slice(res, func(i, j int) bool { return res[i].GroupID < res[j].GroupID })
@@ -138,6 +180,8 @@ func (s *Service) convertKgoGroupDescriptions(describedGroups *kafka.DescribeCon
 				Members:       s.convertGroupMembers(d.Members),
 				CoordinatorID: coordinatorID,
 				TopicOffsets:  offsets[d.Group],
+
+				MaxTimeLagSeconds: maxTimeLag(offsets[d.Group]),
 			})
 		}
 	}
@@ -0,0 +1,274 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/redpanda-data/console/backend/pkg/kafka"
+)
+
+const (
+	// timeLagEstimationTimeout bounds the time that is spent on fetching record timestamps
+	// for a single request. Partitions whose timestamps could not be fetched in time have
+	// no time lag estimate.
+	timeLagEstimationTimeout = 3 * time.Second
+
+	// maxTimeLagLookups is the maximum number of record timestamps that are fetched from
+	// Kafka for a single request. Cached timestamps do not count towards this limit.
+	maxTimeLagLookups = 1000
+
+	// timeLagFetchConcurrency is the number of Kafka clients that fetch timestamps concurrently.
+	timeLagFetchConcurrency = 4
+
+	// maxControlRecordSkips is the number of control records that are skipped at most when
+	// looking for the consumed and newest data record of a partition.
+	maxControlRecordSkips = 3
+
+	// recordTimestampMaxAge is how long the timestamp of a record at an offset is cached. The
+	// timestamp of a record never changes, but the record may be deleted by retention.
+	recordTimestampMaxAge = 10 * time.Minute
+)
+
+// recordOffset identifies a single record.
+type recordOffset struct {
+	Topic     string
+	Partition int32
+	Offset    int64
+}
+
+// estimateTimeLags estimates the time lag of each consumed partition by comparing the
+// timestamp of the record at the committed group offset with the timestamp of the newest
+// record in that partition. The estimates are set on the given group offsets.
+func (s *Service) estimateTimeLags(ctx context.Context, offsetsByGroup map[string][]GroupTopicOffsets) {
+	ctx, cancel := context.WithTimeout(ctx, timeLagEstimationTimeout)
+	defer cancel()
+
+	// 1. Collect all partitions whose record timestamps are required
+	lookups := make(map[timeLagPartition]*timeLagLookup)
+	for _, topics := range offsetsByGroup {
+		for _, topic := range topics {
+			for _, p := range topic.PartitionOffsets {
+				if p.Error != "" || p.Lag <= 0 {
+					continue
+				}
+				key := timeLagPartition{Topic: topic.Topic, Partition: p.PartitionID, GroupOffset: p.GroupOffset, HighWaterMark: p.HighWaterMark}
+				lookups[key] = newTimeLagLookup(key)
+			}
+		}
+	}
+
+	// 2. Fetch all timestamps, each round skips the control records that have been found
+	timestamps := make(map[recordOffset]kafka.RecordTimestamp)
+	for i := 0; i <= maxControlRecordSkips; i++ {
+		required := make(map[recordOffset]struct{})
+		for _, lookup := range lookups {
+			for _, offset := range lookup.pending(timestamps) {
+				required[offset] = struct{}{}
+			}
+		}
+		if len(required) == 0 || ctx.Err() != nil {
+			break
+		}
+		for offset, ts := range s.fetchRecordTimestamps(ctx, required) {
+			timestamps[offset] = ts
+		}
+		for _, lookup := range lookups {
+			lookup.skipControlRecords(timestamps)
+		}
+	}
+
+	// 3. Compute time lags
+	for _, topics := range offsetsByGroup {
+		for i := range topics {
+			topic := &topics[i]
+			for j := range topic.PartitionOffsets {
+				p := &topic.PartitionOffsets[j]
+				if p.Error != "" {
+					continue
+				}
+				if p.Lag <= 0 {
+					p.TimeLagSeconds = float64Ptr(0)
+				} else {
+					key := timeLagPartition{Topic: topic.Topic, Partition: p.PartitionID, GroupOffset: p.GroupOffset, HighWaterMark: p.HighWaterMark}
+					p.TimeLagSeconds = lookups[key].timeLagSeconds(timestamps)
+					if p.TimeLagSeconds == nil {
+						continue
+					}
+				}
+
+				if topic.MaxTimeLagSeconds == nil || *p.TimeLagSeconds > *topic.MaxTimeLagSeconds {
+					topic.MaxTimeLagSeconds = p.TimeLagSeconds
+				}
+			}
+		}
+	}
+}
+
+// timeLagPartition identifies a partition consumed by a group with lag.
+type timeLagPartition struct {
+	Topic         string
+	Partition     int32
+	GroupOffset   int64
+	HighWaterMark int64
+}
+
+// timeLagLookup tracks which records are used to estimate the time lag of a partition.
+// Control records (e.g. transaction markers) have no meaningful record timestamp, hence the
+// consumed record moves forward and the newest record moves backward past them.
+type timeLagLookup struct {
+	consumed recordOffset
+	newest   recordOffset
+}
+
+func newTimeLagLookup(p timeLagPartition) *timeLagLookup {
+	return &timeLagLookup{
+		consumed: recordOffset{Topic: p.Topic, Partition: p.Partition, Offset: p.GroupOffset},
+		newest:   recordOffset{Topic: p.Topic, Partition: p.Partition, Offset: p.HighWaterMark - 1},
+	}
+}
+
+// skipControlRecords moves the consumed and newest records past all known control records.
+func (l *timeLagLookup) skipControlRecords(timestamps map[recordOffset]kafka.RecordTimestamp) {
+	for ts, exists := timestamps[l.consumed]; exists && ts.IsControl && !l.caughtUp(); ts, exists = timestamps[l.consumed] {
+		l.consumed.Offset = ts.Offset + 1
+	}
+	for ts, exists := timestamps[l.newest]; exists && ts.IsControl && !l.caughtUp(); ts, exists = timestamps[l.newest] {
+		l.newest.Offset--
+	}
+}
+
+// caughtUp returns true if there are only control records between the consumed and the
+// newest record, hence the group has consumed all data records.
+func (l *timeLagLookup) caughtUp() bool {
+	return l.consumed.Offset > l.newest.Offset
+}
+
+// pending returns the records whose timestamps are still unknown.
+func (l *timeLagLookup) pending(timestamps map[recordOffset]kafka.RecordTimestamp) []recordOffset {
+	if l.caughtUp() {
+		return nil
+	}
+	pending := make([]recordOffset, 0, 2)
+	for _, offset := range []recordOffset{l.consumed, l.newest} {
+		if _, exists := timestamps[offset]; !exists {
+			pending = append(pending, offset)
+		}
+	}
+	return pending
+}
+
+// timeLagSeconds returns the estimated time lag or nil if the timestamps of the consumed
+// or newest data record are unknown.
+func (l *timeLagLookup) timeLagSeconds(timestamps map[recordOffset]kafka.RecordTimestamp) *float64 {
+	if l.caughtUp() {
+		return float64Ptr(0)
+	}
+	consumed, hasConsumed := timestamps[l.consumed]
+	newest, hasNewest := timestamps[l.newest]
+	if !hasConsumed || !hasNewest || consumed.IsControl || newest.IsControl {
+		return nil
+	}
+	lagMs := newest.Timestamp - consumed.Timestamp
+	if lagMs < 0 {
+		// Record timestamps may be set by producers and are not necessarily monotonic
+		lagMs = 0
+	}
+	return float64Ptr(float64(lagMs) / 1000)
+}
+
+// fetchRecordTimestamps returns the timestamps of the given records. Cached timestamps are
+// reused, all others are fetched from Kafka. A consumer can only consume a partition at a
+// single offset, hence offsets of the same partition are split into separate batches.
+// Records whose timestamps could not be fetched are missing in the returned map.
+func (s *Service) fetchRecordTimestamps(ctx context.Context, required map[recordOffset]struct{}) map[recordOffset]kafka.RecordTimestamp {
+	timestamps := make(map[recordOffset]kafka.RecordTimestamp, len(required))
+	batches := make([]map[string]map[int32]int64, 0)
+	lookups := 0
+	for offset := range required {
+		if ts, err, state := s.recordTimestampByOffset.TryGet(offset); state.IsHit() && err == nil {
+			timestamps[offset] = ts
+			continue
+		}
+		if lookups >= maxTimeLagLookups {
+			continue
+		}
+		lookups++
+
+		added := false
+		for _, batch := range batches {
+			if _, exists := batch[offset.Topic][offset.Partition]; exists {
+				continue
+			}
+			if _, exists := batch[offset.Topic]; !exists {
+				batch[offset.Topic] = make(map[int32]int64)
+			}
+			batch[offset.Topic][offset.Partition] = offset.Offset
+			added = true
+			break
+		}
+		if !added {
+			batches = append(batches, map[string]map[int32]int64{offset.Topic: {offset.Partition: offset.Offset}})
+		}
+	}
+
+	fetched := make([]map[string]map[int32]kafka.RecordTimestamp, len(batches))
+	grp, grpCtx := errgroup.WithContext(ctx)
+	grp.SetLimit(timeLagFetchConcurrency)
+	for i, batch := range batches {
+		i, batch := i, batch
+		grp.Go(func() error {
+			res, err := s.kafkaSvc.FetchRecordTimestamps(grpCtx, batch)
+			if err != nil {
+				s.logger.Warn("failed to fetch record timestamps for time lag estimation", zap.Error(err))
+				return nil
+			}
+			fetched[i] = res
+			return nil
+		})
+	}
+	_ = grp.Wait()
+
+	for i, res := range fetched {
+		for topic, partitions := range res {
+			for partitionID, ts := range partitions {
+				// The fetched record may have a higher offset than requested, e.g. due to compaction,
+				// which is fine for an estimate.
+				offset := recordOffset{Topic: topic, Partition: partitionID, Offset: batches[i][topic][partitionID]}
+				timestamps[offset] = ts
+				s.recordTimestampByOffset.Set(offset, ts)
+			}
+		}
+	}
+
+	return timestamps
+}
+
+// maxTimeLag returns the highest time lag of all topics or nil if no time lag is known.
+func maxTimeLag(topics []GroupTopicOffsets) *float64 {
+	var maxLag *float64
+	for _, topic := range topics {
+		if topic.MaxTimeLagSeconds == nil {
+			continue
+		}
+		if maxLag == nil || *topic.MaxTimeLagSeconds > *maxLag {
+			maxLag = topic.MaxTimeLagSeconds
+		}
+	}
+	return maxLag
+}
+
+func float64Ptr(v float64) *float64 {
+	return &v
+}
@@ -0,0 +1,135 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/redpanda-data/console/backend/pkg/kafka"
+)
+
+// fetchFromLog returns the first record at or after each offset like a Kafka fetch would.
+func fetchFromLog(log []kafka.RecordTimestamp, offsets []recordOffset) map[recordOffset]kafka.RecordTimestamp {
+	res := make(map[recordOffset]kafka.RecordTimestamp)
+	for _, offset := range offsets {
+		for _, record := range log {
+			if record.Offset >= offset.Offset {
+				res[offset] = record
+				break
+			}
+		}
+	}
+	return res
+}
+
+func TestTimeLagLookup(t *testing.T) {
+	data := func(offset, ts int64) kafka.RecordTimestamp {
+		return kafka.RecordTimestamp{Offset: offset, Timestamp: ts}
+	}
+	control := func(offset, ts int64) kafka.RecordTimestamp {
+		return kafka.RecordTimestamp{Offset: offset, Timestamp: ts, IsControl: true}
+	}
+
+	tests := map[string]struct {
+		log           []kafka.RecordTimestamp
+		groupOffset   int64
+		highWaterMark int64
+		want          *float64
+	}{
+		"data records": {
+			log:           []kafka.RecordTimestamp{data(0, 1000), data(1, 3000), data(2, 6500)},
+			groupOffset:   1,
+			highWaterMark: 3,
+			want:          float64Ptr(3.5),
+		},
+		"non monotonic timestamps": {
+			log:           []kafka.RecordTimestamp{data(0, 5000), data(1, 1000)},
+			groupOffset:   0,
+			highWaterMark: 2,
+			want:          float64Ptr(0),
+		},
+		"compacted consumed record": {
+			log:           []kafka.RecordTimestamp{data(0, 1000), data(3, 2000), data(4, 4000)},
+			groupOffset:   1,
+			highWaterMark: 5,
+			want:          float64Ptr(2),
+		},
+		"transaction marker at high water mark": {
+			log:           []kafka.RecordTimestamp{data(0, 1000), data(1, 2000), control(2, 9000)},
+			groupOffset:   0,
+			highWaterMark: 3,
+			want:          float64Ptr(1),
+		},
+		"transaction marker at group offset": {
+			log:           []kafka.RecordTimestamp{data(0, 1000), control(1, 1500), data(2, 2000), data(3, 5000)},
+			groupOffset:   1,
+			highWaterMark: 4,
+			want:          float64Ptr(3),
+		},
+		"only transaction markers left": {
+			log:           []kafka.RecordTimestamp{data(0, 1000), control(1, 1500), control(2, 2000)},
+			groupOffset:   1,
+			highWaterMark: 3,
+			want:          float64Ptr(0),
+		},
+		"too many transaction markers": {
+			log: []kafka.RecordTimestamp{
+				data(0, 1000), control(1, 1100), control(2, 1200), control(3, 1300), control(4, 1400),
+				control(5, 1500), control(6, 1600), control(7, 1700), control(8, 1800), control(9, 1900),
+				data(10, 2000), control(11, 2100), control(12, 2200), control(13, 2300), control(14, 2400),
+			},
+			groupOffset:   1,
+			highWaterMark: 15,
+			want:          nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			lookup := newTimeLagLookup(timeLagPartition{Topic: "test", Partition: 0, GroupOffset: test.groupOffset, HighWaterMark: test.highWaterMark})
+			timestamps := make(map[recordOffset]kafka.RecordTimestamp)
+			for i := 0; i <= maxControlRecordSkips; i++ {
+				for offset, ts := range fetchFromLog(test.log, lookup.pending(timestamps)) {
+					timestamps[offset] = ts
+				}
+				lookup.skipControlRecords(timestamps)
+			}
+
+			got := lookup.timeLagSeconds(timestamps)
+			if test.want == nil {
+				assert.Nil(t, got)
+				return
+			}
+			require.NotNil(t, got)
+			assert.Equal(t, *test.want, *got)
+		})
+	}
+}
+
+func TestTimeLagLookup_MissingTimestamps(t *testing.T) {
+	lookup := newTimeLagLookup(timeLagPartition{Topic: "test", Partition: 0, GroupOffset: 2, HighWaterMark: 10})
+	assert.ElementsMatch(t, []recordOffset{
+		{Topic: "test", Partition: 0, Offset: 2},
+		{Topic: "test", Partition: 0, Offset: 9},
+	}, lookup.pending(nil))
+	assert.Nil(t, lookup.timeLagSeconds(nil))
+}
+
+func TestMaxTimeLag(t *testing.T) {
+	assert.Nil(t, maxTimeLag([]GroupTopicOffsets{{Topic: "a"}}))
+	assert.Equal(t, 7.5, *maxTimeLag([]GroupTopicOffsets{
+		{Topic: "a", MaxTimeLagSeconds: float64Ptr(2)},
+		{Topic: "b"},
+		{Topic: "c", MaxTimeLagSeconds: float64Ptr(7.5)},
+	}))
+}
@@ -0,0 +1,184 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/redpanda-data/console/backend/pkg/kafka"
+)
+
+// keyedRecordsSampleSize is the number of most recent records that are checked for keys
+// before adding partitions to a topic.
+const keyedRecordsSampleSize = 100
+
+// CreateTopicPartitionsRequest describes the new partition count of a topic.
+type CreateTopicPartitionsRequest struct {
+	TopicName      string
+	PartitionCount int32
+
+	// Assignments optionally contains the replica broker ids for each new partition.
+	// If set, the number of assignments must match the number of new partitions.
+	Assignments [][]int32
+
+	// ValidateOnly lets the brokers validate the request without creating any partitions.
+	ValidateOnly bool
+}
+
+// CreateTopicPartitionsResponse is the response that is sent after increasing the partition
+// count of a topic.
+type CreateTopicPartitionsResponse struct {
+	TopicName              string `json:"topicName"`
+	PreviousPartitionCount int32  `json:"previousPartitionCount"`
+	PartitionCount         int32  `json:"partitionCount"`
+	ValidateOnly           bool   `json:"validateOnly"`
+
+	// HasKeyedRecords is true if at least one of the sampled records has a key. Adding
+	// partitions changes the key to partition mapping of the default partitioner.
+	HasKeyedRecords bool     `json:"hasKeyedRecords"`
+	Warnings        []string `json:"warnings"`
+}
+
+// keyedRecordsProgress counts the sampled records that have a key.
+type keyedRecordsProgress struct {
+	sampledRecords int
+	keyedRecords   int
+}
+
+func (*keyedRecordsProgress) OnPhase(string) {}
+
+func (p *keyedRecordsProgress) OnMessage(message *kafka.TopicMessage) {
+	p.sampledRecords++
+	if message.Key != nil && !message.Key.IsPayloadNull {
+		p.keyedRecords++
+	}
+}
+
+func (*keyedRecordsProgress) OnMessageConsumed(int64) {}
+
+func (*keyedRecordsProgress) OnComplete(int64, bool) {}
+
+func (*keyedRecordsProgress) OnError(string) {}
+
+// CreateTopicPartitions increases the partition count of an existing topic. The response
+// contains a warning if the topic contains keyed records, because records with the same key
+// may be produced to a different partition after adding partitions.
+func (s *Service) CreateTopicPartitions(ctx context.Context, req CreateTopicPartitionsRequest) (*CreateTopicPartitionsResponse, *rest.Error) {
+	internalLogs := []zapcore.Field{
+		zap.String("topic_name", req.TopicName),
+		zap.Int32("partition_count", req.PartitionCount),
+		zap.Bool("validate_only", req.ValidateOnly),
+	}
+
+	metadata, restErr := s.kafkaSvc.GetSingleMetadata(ctx, req.TopicName)
+	if restErr != nil {
+		return nil, restErr
+	}
+	currentPartitionCount := int32(len(metadata.Partitions))
+	if req.PartitionCount <= currentPartitionCount {
+		return nil, &rest.Error{
+			Err:          fmt.Errorf("new partition count %d is not greater than current partition count %d", req.PartitionCount, currentPartitionCount),
+			Status:       http.StatusBadRequest,
+			Message:      fmt.Sprintf("The partition count can only be increased. The topic currently has %d partitions.", currentPartitionCount),
+			InternalLogs: internalLogs,
+			IsSilent:     false,
+		}
+	}
+	newPartitions := int(req.PartitionCount - currentPartitionCount)
+	if len(req.Assignments) > 0 && len(req.Assignments) != newPartitions {
+		return nil, &rest.Error{
+			Err:          fmt.Errorf("got %d replica assignments for %d new partitions", len(req.Assignments), newPartitions),
+			Status:       http.StatusBadRequest,
+			Message:      fmt.Sprintf("Exactly one replica assignment per new partition is required, but got %d assignments for %d new partitions", len(req.Assignments), newPartitions),
+			InternalLogs: internalLogs,
+			IsSilent:     false,
+		}
+	}
+
+	// Check whether ordering guarantees of keyed records would be affected
+	progress := &keyedRecordsProgress{}
+	err := s.ListMessages(ctx, ListMessageRequest{
+		TopicName:    req.TopicName,
+		PartitionID:  partitionsAll,
+		StartOffset:  StartOffsetRecent,
+		MessageCount: keyedRecordsSampleSize,
+	}, progress)
+	if err != nil {
+		s.logger.Warn("failed to sample records for keys before adding partitions", append(internalLogs, zap.Error(err))...)
+	}
+
+	topicReq := kmsg.NewCreatePartitionsRequestTopic()
+	topicReq.Topic = req.TopicName
+	topicReq.Count = req.PartitionCount
+	if len(req.Assignments) > 0 {
+		topicReq.Assignment = make([]kmsg.CreatePartitionsRequestTopicAssignment, len(req.Assignments))
+		for i, replicas := range req.Assignments {
+			assignment := kmsg.NewCreatePartitionsRequestTopicAssignment()
+			assignment.Replicas = replicas
+			topicReq.Assignment[i] = assignment
+		}
+	}
+
+	topicRes, err := s.kafkaSvc.CreatePartitions(ctx, topicReq, req.ValidateOnly)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:          fmt.Errorf("failed to create partitions: %w", err),
+			Status:       http.StatusServiceUnavailable,
+			Message:      fmt.Sprintf("Failed to create partitions: %v", err.Error()),
+			InternalLogs: internalLogs,
+			IsSilent:     false,
+		}
+	}
+
+	err = kerr.ErrorForCode(topicRes.ErrorCode)
+	if err != nil {
+		status := http.StatusServiceUnavailable
+		if errors.Is(err, kerr.InvalidPartitions) || errors.Is(err, kerr.InvalidReplicaAssignment) ||
+			errors.Is(err, kerr.InvalidReplicationFactor) {
+			status = http.StatusBadRequest
+		}
+		message := err.Error()
+		if topicRes.ErrorMessage != nil {
+			message = fmt.Sprintf("%v: %v", message, *topicRes.ErrorMessage)
+		}
+		return nil, &rest.Error{
+			Err:          fmt.Errorf("failed to create partitions, inner kafka error: %w", err),
+			Status:       status,
+			Message:      fmt.Sprintf("Failed to create partitions, kafka responded with the following error: %v", message),
+			InternalLogs: internalLogs,
+			IsSilent:     false,
+		}
+	}
+
+	warnings := make([]string, 0)
+	if progress.keyedRecords > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d of %d sampled records have a key. Records with the same key may be "+
+			"produced to a different partition after adding partitions, so that ordering by key is no longer guaranteed.",
+			progress.keyedRecords, progress.sampledRecords))
+	}
+
+	return &CreateTopicPartitionsResponse{
+		TopicName:              req.TopicName,
+		PreviousPartitionCount: currentPartitionCount,
+		PartitionCount:         req.PartitionCount,
+		ValidateOnly:           req.ValidateOnly,
+		HasKeyedRecords:        progress.keyedRecords > 0,
+		Warnings:               warnings,
+	}, nil
+}
@@ -0,0 +1,247 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// ElectionType is the type of leader election that shall be triggered.
+type ElectionType string
+
+const (
+	// ElectionTypePreferred elects the preferred replica (first replica in the replica list) as leader.
+	ElectionTypePreferred ElectionType = "PREFERRED"
+	// ElectionTypeUnclean elects the first live replica as leader if there are no in-sync replicas.
+	// This may result in data loss.
+	ElectionTypeUnclean ElectionType = "UNCLEAN"
+)
+
+// Int8 returns the election type as used in the Kafka protocol.
+func (e ElectionType) Int8() (int8, error) {
+	switch e {
+	case ElectionTypePreferred:
+		return 0, nil
+	case ElectionTypeUnclean:
+		return 1, nil
+	default:
+		return 0, fmt.Errorf("unknown election type %q", e)
+	}
+}
+
+// Leader election results for a single partition.
+const (
+	LeaderElectionStatusElected   = "ELECTED"
+	LeaderElectionStatusNotNeeded = "NOT_NEEDED"
+	LeaderElectionStatusFailed    = "FAILED"
+)
+
+// ElectLeadersRequest describes the partitions for which leader elections shall be triggered.
+type ElectLeadersRequest struct {
+	ElectionType ElectionType
+
+	// Topics is the list of topics whose leaders shall be elected. If nil, leaders will
+	// be elected for all partitions in the cluster.
+	Topics []ElectLeadersRequestTopic
+}
+
+// ElectLeadersRequestTopic is a topic whose partition leaders shall be elected.
+type ElectLeadersRequestTopic struct {
+	TopicName string
+
+	// Partitions whose leaders shall be elected. If empty, leaders will be elected
+	// for all partitions of the topic.
+	Partitions []int32
+}
+
+// ElectLeadersResponse contains the leader election results of all requested partitions.
+type ElectLeadersResponse struct {
+	ElectionType ElectionType                `json:"electionType"`
+	Topics       []ElectLeadersResponseTopic `json:"topics"`
+}
+
+// ElectLeadersResponseTopic contains the leader election results for a topic's partitions.
+type ElectLeadersResponseTopic struct {
+	TopicName  string                          `json:"topicName"`
+	Partitions []ElectLeadersResponsePartition `json:"partitions"`
+}
+
+// ElectLeadersResponsePartition is the leader election result for a single partition.
+type ElectLeadersResponsePartition struct {
+	PartitionID int32  `json:"partitionId"`
+	Status      string `json:"status"`
+	Error       string `json:"error,omitempty"`
+}
+
+// ElectLeaders triggers preferred or unclean leader elections for the requested partitions.
+func (s *Service) ElectLeaders(ctx context.Context, req ElectLeadersRequest) (*ElectLeadersResponse, *rest.Error) {
+	electionType, err := req.ElectionType.Int8()
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusBadRequest,
+			Message:  err.Error(),
+			IsSilent: false,
+		}
+	}
+
+	// 1. Kafka expects all partitions to be listed, hence we have to resolve topics without
+	// explicitly requested partitions via the metadata.
+	var topics []kmsg.ElectLeadersRequestTopic
+	if req.Topics != nil {
+		restErr := s.resolveElectLeadersTopics(ctx, req.Topics)
+		if restErr != nil {
+			return nil, restErr
+		}
+		topics = make([]kmsg.ElectLeadersRequestTopic, len(req.Topics))
+		for i, topic := range req.Topics {
+			t := kmsg.NewElectLeadersRequestTopic()
+			t.Topic = topic.TopicName
+			t.Partitions = topic.Partitions
+			topics[i] = t
+		}
+	}
+
+	// 2. Trigger elections
+	res, err := s.kafkaSvc.ElectLeaders(ctx, electionType, topics)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("failed to elect leaders: %w", err),
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to elect leaders: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+	if err := kerr.ErrorForCode(res.ErrorCode); err != nil {
+		status := http.StatusServiceUnavailable
+		if errors.Is(err, kerr.ClusterAuthorizationFailed) {
+			status = http.StatusForbidden
+		}
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("failed to elect leaders, inner kafka error: %w", err),
+			Status:   status,
+			Message:  fmt.Sprintf("Failed to elect leaders, kafka responded with the following error: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	// 3. Map per-partition results
+	resTopics := make([]ElectLeadersResponseTopic, len(res.Topics))
+	for i, topic := range res.Topics {
+		partitions := make([]ElectLeadersResponsePartition, len(topic.Partitions))
+		for j, partition := range topic.Partitions {
+			partitions[j] = electLeadersPartitionResult(partition)
+		}
+		sort.Slice(partitions, func(a, b int) bool {
+			return partitions[a].PartitionID < partitions[b].PartitionID
+		})
+		resTopics[i] = ElectLeadersResponseTopic{
+			TopicName:  topic.Topic,
+			Partitions: partitions,
+		}
+	}
+	sort.Slice(resTopics, func(a, b int) bool {
+		return resTopics[a].TopicName < resTopics[b].TopicName
+	})
+
+	return &ElectLeadersResponse{
+		ElectionType: req.ElectionType,
+		Topics:       resTopics,
+	}, nil
+}
+
+// resolveElectLeadersTopics fills the partition ids of all topics that have no explicitly requested partitions.
+func (s *Service) resolveElectLeadersTopics(ctx context.Context, topics []ElectLeadersRequestTopic) *rest.Error {
+	topicNames := make([]string, 0)
+	for _, topic := range topics {
+		if len(topic.Partitions) == 0 {
+			topicNames = append(topicNames, topic.TopicName)
+		}
+	}
+	if len(topicNames) == 0 {
+		return nil
+	}
+
+	metadata, err := s.kafkaSvc.GetMetadata(ctx, topicNames)
+	if err != nil {
+		return &rest.Error{
+			Err:      err,
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to get topic metadata from cluster: '%v'", err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	partitionsByTopic := make(map[string][]int32, len(metadata.Topics))
+	for _, topic := range metadata.Topics {
+		topicName := *topic.Topic
+		if err := kerr.ErrorForCode(topic.ErrorCode); err != nil {
+			status := http.StatusServiceUnavailable
+			if errors.Is(err, kerr.UnknownTopicOrPartition) {
+				status = http.StatusNotFound
+			}
+			return &rest.Error{
+				Err:      fmt.Errorf("failed to get metadata for topic %q: %w", topicName, err),
+				Status:   status,
+				Message:  fmt.Sprintf("Failed to get metadata for topic %q: %v", topicName, err.Error()),
+				IsSilent: false,
+			}
+		}
+		partitionIDs := make([]int32, len(topic.Partitions))
+		for i, partition := range topic.Partitions {
+			partitionIDs[i] = partition.Partition
+		}
+		partitionsByTopic[topicName] = partitionIDs
+	}
+
+	for i, topic := range topics {
+		if len(topic.Partitions) == 0 {
+			topics[i].Partitions = partitionsByTopic[topic.TopicName]
+		}
+	}
+
+	return nil
+}
+
+func electLeadersPartitionResult(partition kmsg.ElectLeadersResponseTopicPartition) ElectLeadersResponsePartition {
+	result := ElectLeadersResponsePartition{
+		PartitionID: partition.Partition,
+		Status:      LeaderElectionStatusElected,
+	}
+
+	err := kerr.ErrorForCode(partition.ErrorCode)
+	switch {
+	case err == nil:
+	case errors.Is(err, kerr.ElectionNotNeeded):
+		result.Status = LeaderElectionStatusNotNeeded
+	default:
+		result.Status = LeaderElectionStatusFailed
+		result.Error = err.Error()
+		if partition.ErrorMessage != nil {
+			result.Error = fmt.Sprintf("%v: %v", err.Error(), *partition.ErrorMessage)
+		}
+	}
+
+	return result
+}
+
+// isPreferredLeader returns true if the current leader is the preferred replica, which is
+// the first replica in the replica list.
+func isPreferredLeader(replicas []int32, leader int32) bool {
+	return len(replicas) > 0 && replicas[0] == leader
+}
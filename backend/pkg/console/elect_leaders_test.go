@@ -0,0 +1,51 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestIsPreferredLeader(t *testing.T) {
+	assert.True(t, isPreferredLeader([]int32{1, 2, 3}, 1))
+	assert.False(t, isPreferredLeader([]int32{1, 2, 3}, 2))
+	assert.False(t, isPreferredLeader([]int32{1, 2, 3}, -1))
+	assert.False(t, isPreferredLeader(nil, 1))
+}
+
+func TestElectLeadersPartitionResult(t *testing.T) {
+	msg := "preferred replica is not in sync"
+	tests := map[string]struct {
+		input      kmsg.ElectLeadersResponseTopicPartition
+		wantStatus string
+		wantError  bool
+	}{
+		"elected":    {input: kmsg.ElectLeadersResponseTopicPartition{Partition: 0}, wantStatus: LeaderElectionStatusElected},
+		"not needed": {input: kmsg.ElectLeadersResponseTopicPartition{Partition: 1, ErrorCode: kerr.ElectionNotNeeded.Code}, wantStatus: LeaderElectionStatusNotNeeded},
+		"failed": {
+			input:      kmsg.ElectLeadersResponseTopicPartition{Partition: 2, ErrorCode: kerr.PreferredLeaderNotAvailable.Code, ErrorMessage: &msg},
+			wantStatus: LeaderElectionStatusFailed,
+			wantError:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			res := electLeadersPartitionResult(test.input)
+			assert.Equal(t, test.input.Partition, res.PartitionID)
+			assert.Equal(t, test.wantStatus, res.Status)
+			assert.Equal(t, test.wantError, res.Error != "")
+		})
+	}
+}
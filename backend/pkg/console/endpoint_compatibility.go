@@ -32,6 +32,16 @@ type EndpointCompatibilityEndpoint struct {
 	IsSupported bool   `json:"isSupported"`
 }
 
+// IsSupported returns whether the endpoint with the given URL and method is supported.
+func (e EndpointCompatibility) IsSupported(url, method string) bool {
+	for _, endpoint := range e.Endpoints {
+		if endpoint.Endpoint == url && endpoint.Method == method {
+			return endpoint.IsSupported
+		}
+	}
+	return false
+}
+
 // GetEndpointCompatibility requests API versions from brokers in order to figure out what Console endpoints
 // can be offered to the frontend. If the broker does not support certain features which are required for a
 // Console endpoint we can let the frontend know in advance, so that these features will be rendered as
@@ -57,6 +67,16 @@ func (s *Service) GetEndpointCompatibility(ctx context.Context) (EndpointCompati
 			Method:   "GET",
 			Requests: []kmsg.Request{&kmsg.DescribeConfigsRequest{}},
 		},
+		{
+			URL:      "/api/cluster/dynamic-config",
+			Method:   "PATCH",
+			Requests: []kmsg.Request{&kmsg.DescribeConfigsRequest{}, &kmsg.IncrementalAlterConfigsRequest{}},
+		},
+		{
+			URL:      "/api/brokers/{brokerID}/dynamic-config",
+			Method:   "PATCH",
+			Requests: []kmsg.Request{&kmsg.DescribeConfigsRequest{}, &kmsg.IncrementalAlterConfigsRequest{}},
+		},
 		{
 			URL:      "/api/consumer-groups",
 			Method:   "GET",
@@ -67,11 +87,21 @@ func (s *Service) GetEndpointCompatibility(ctx context.Context) (EndpointCompati
 			Method:   "DELETE",
 			Requests: []kmsg.Request{&kmsg.DeleteRecordsRequest{}},
 		},
+		{
+			URL:      "/api/topics/{topicName}/partitions",
+			Method:   "PATCH",
+			Requests: []kmsg.Request{&kmsg.CreatePartitionsRequest{}},
+		},
 		{
 			URL:      "/api/consumer-groups/{groupId}",
 			Method:   "PATCH",
 			Requests: []kmsg.Request{&kmsg.OffsetCommitRequest{}},
 		},
+		{
+			URL:      "/api/consumer-groups/{groupId}/reset-offsets",
+			Method:   "POST",
+			Requests: []kmsg.Request{&kmsg.OffsetCommitRequest{}, &kmsg.ListOffsetsRequest{}},
+		},
 		{
 			URL:      "/api/consumer-groups/{groupId}",
 			Method:   "DELETE",
@@ -92,11 +122,86 @@ func (s *Service) GetEndpointCompatibility(ctx context.Context) (EndpointCompati
 			Method:   "PATCH",
 			Requests: []kmsg.Request{&kmsg.IncrementalAlterConfigsRequest{}, &kmsg.AlterPartitionAssignmentsRequest{}},
 		},
+		{
+			URL:      "/api/operations/reassign-partitions",
+			Method:   "DELETE",
+			Requests: []kmsg.Request{&kmsg.ListPartitionReassignmentsRequest{}, &kmsg.AlterPartitionAssignmentsRequest{}},
+		},
+		{
+			URL:      "/api/operations/reassign-partitions/plan",
+			Method:   "POST",
+			Requests: []kmsg.Request{&kmsg.DescribeLogDirsRequest{}},
+		},
+		{
+			URL:      "/api/operations/reassign-partitions/progress",
+			Method:   "GET",
+			Requests: []kmsg.Request{&kmsg.ListPartitionReassignmentsRequest{}, &kmsg.DescribeLogDirsRequest{}},
+		},
+		{
+			URL:      "/api/operations/reassign-partitions/throttle",
+			Method:   "PUT",
+			Requests: []kmsg.Request{&kmsg.IncrementalAlterConfigsRequest{}},
+		},
+		{
+			URL:      "/api/operations/reassign-partitions/throttle",
+			Method:   "DELETE",
+			Requests: []kmsg.Request{&kmsg.IncrementalAlterConfigsRequest{}},
+		},
+		{
+			URL:      "/api/brokers/{brokerID}/log-dirs/balance-plan",
+			Method:   "GET",
+			Requests: []kmsg.Request{&kmsg.DescribeLogDirsRequest{}},
+		},
+		{
+			URL:      "/api/brokers/{brokerID}/log-dirs/moves",
+			Method:   "GET",
+			Requests: []kmsg.Request{&kmsg.DescribeLogDirsRequest{}},
+		},
+		{
+			URL:      "/api/brokers/{brokerID}/log-dirs/moves",
+			Method:   "POST",
+			Requests: []kmsg.Request{&kmsg.DescribeLogDirsRequest{}, &kmsg.AlterReplicaLogDirsRequest{}},
+		},
+		{
+			URL:      "/api/transactions",
+			Method:   "GET",
+			Requests: []kmsg.Request{&kmsg.ListTransactionsRequest{}, &kmsg.DescribeTransactionsRequest{}},
+		},
+		{
+			URL:      "/api/transactions/hanging",
+			Method:   "GET",
+			Requests: []kmsg.Request{&kmsg.DescribeProducersRequest{}, &kmsg.ListTransactionsRequest{}, &kmsg.DescribeTransactionsRequest{}},
+		},
+		{
+			URL:      "/api/transactions/abort",
+			Method:   "POST",
+			Requests: []kmsg.Request{&kmsg.DescribeProducersRequest{}, &kmsg.WriteTxnMarkersRequest{}},
+		},
+		{
+			URL:      "/api/topics/{topicName}/producers",
+			Method:   "GET",
+			Requests: []kmsg.Request{&kmsg.DescribeProducersRequest{}},
+		},
+		{
+			URL:      "/api/operations/elect-leaders",
+			Method:   "POST",
+			Requests: []kmsg.Request{&kmsg.ElectLeadersRequest{}},
+		},
 		{
 			URL:      "/api/quotas",
 			Method:   "GET",
 			Requests: []kmsg.Request{&kmsg.DescribeClientQuotasRequest{}},
 		},
+		{
+			URL:      "/api/quotas",
+			Method:   "PUT",
+			Requests: []kmsg.Request{&kmsg.AlterClientQuotasRequest{}},
+		},
+		{
+			URL:      "/api/quotas",
+			Method:   "DELETE",
+			Requests: []kmsg.Request{&kmsg.DescribeClientQuotasRequest{}, &kmsg.AlterClientQuotasRequest{}},
+		},
 		{
 			URL:            "/api/users",
 			Method:         "GET",
@@ -109,6 +214,12 @@ func (s *Service) GetEndpointCompatibility(ctx context.Context) (EndpointCompati
 			Requests:       []kmsg.Request{&kmsg.AlterUserSCRAMCredentialsRequest{}},
 			HasRedpandaAPI: true,
 		},
+		{
+			URL:            "/api/users",
+			Method:         "PUT",
+			Requests:       []kmsg.Request{&kmsg.DescribeUserSCRAMCredentialsRequest{}, &kmsg.AlterUserSCRAMCredentialsRequest{}},
+			HasRedpandaAPI: true,
+		},
 		{
 			URL:            "/api/users",
 			Method:         "DELETE",
@@ -14,3 +14,7 @@ import "errors"
 // ErrSchemaRegistryNotConfigured is an error that declares the schema registry has not
 // been configured in Redpanda Console and thus the request could not be processed.
 var ErrSchemaRegistryNotConfigured = errors.New("no schema registry configured")
+
+// ErrHistoryNotEnabled is an error that declares the background history collector has not
+// been enabled in Redpanda Console and thus no time series are available.
+var ErrHistoryNotEnabled = errors.New("history collector is not enabled")
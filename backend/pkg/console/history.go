@@ -0,0 +1,39 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"time"
+
+	"github.com/redpanda-data/console/backend/pkg/history"
+)
+
+// GetTopicHistory returns the produce rate of a topic for all samples that have been
+// collected since the given time.
+func (s *Service) GetTopicHistory(topicName string, since time.Time) (*history.TopicSeries, error) {
+	if s.historySvc == nil {
+		return nil, ErrHistoryNotEnabled
+	}
+
+	series := s.historySvc.TopicSeries(topicName, since)
+	return &series, nil
+}
+
+// GetConsumerGroupHistory returns the consume rate, lag and lag trend of a consumer group for
+// all samples that have been collected since the given time. If topicName is not empty, only
+// this topic is considered.
+func (s *Service) GetConsumerGroupHistory(groupID string, topicName string, since time.Time) (*history.GroupSeries, error) {
+	if s.historySvc == nil {
+		return nil, ErrHistoryNotEnabled
+	}
+
+	series := s.historySvc.GroupSeries(groupID, topicName, since)
+	return &series, nil
+}
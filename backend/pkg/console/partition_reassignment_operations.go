@@ -0,0 +1,560 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// PlanPartitionReassignmentsRequest describes the goal of a partition reassignment plan.
+type PlanPartitionReassignmentsRequest struct {
+	Goal ReassignmentGoal
+
+	// TopicNames limits the plan to the given topics. All topics are considered if empty.
+	TopicNames []string
+
+	// BrokerIDs are the brokers that shall be drained (ReassignmentGoalDrainBrokers).
+	BrokerIDs []int32
+
+	// ReplicationFactor is the desired replication factor (ReassignmentGoalChangeReplicationFactor).
+	ReplicationFactor int
+}
+
+// PartitionReassignmentPlan is a computed partition reassignment. The topics can be submitted
+// as is to alter the partition assignments.
+type PartitionReassignmentPlan struct {
+	Goal    ReassignmentGoal                  `json:"goal"`
+	Topics  []PartitionReassignmentPlanTopic  `json:"topics"`
+	Brokers []PartitionReassignmentPlanBroker `json:"brokers"`
+
+	// MovedReplicas is the number of replicas that have to be created on a new broker.
+	MovedReplicas int `json:"movedReplicas"`
+	// MovedBytes is the estimated number of bytes that have to be copied between brokers.
+	MovedBytes int64    `json:"movedBytes"`
+	Warnings   []string `json:"warnings"`
+}
+
+// PartitionReassignmentPlanTopic contains all partitions of a topic whose replicas change.
+type PartitionReassignmentPlanTopic struct {
+	TopicName  string                               `json:"topicName"`
+	Partitions []PartitionReassignmentPlanPartition `json:"partitions"`
+}
+
+// PartitionReassignmentPlanPartition is the planned assignment for a single partition.
+type PartitionReassignmentPlanPartition struct {
+	PartitionID     int32   `json:"partitionId"`
+	CurrentReplicas []int32 `json:"currentReplicas"`
+	Replicas        []int32 `json:"replicas"`
+	// Size is the partition's size in bytes. -1 if it could not be determined.
+	Size int64 `json:"size"`
+}
+
+// PartitionReassignmentPlanBroker shows the replica count of a broker before and after the reassignment.
+type PartitionReassignmentPlanBroker struct {
+	BrokerID       int32   `json:"brokerId"`
+	Rack           *string `json:"rack"`
+	ReplicasBefore int     `json:"replicasBefore"`
+	ReplicasAfter  int     `json:"replicasAfter"`
+}
+
+// PlanPartitionReassignments computes a partition reassignment for the given goal. The plan is
+// not applied.
+//
+//nolint:gocognit,cyclop // Validation of the goal and mapping the results is straightforward but long
+func (s *Service) PlanPartitionReassignments(ctx context.Context, req PlanPartitionReassignmentsRequest) (*PartitionReassignmentPlan, *rest.Error) {
+	// 1. Get brokers and partitions
+	metadata, err := s.kafkaSvc.GetMetadata(ctx, req.TopicNames)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to get metadata from cluster: '%v'", err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	brokers := make([]plannerBroker, len(metadata.Brokers))
+	racksByBrokerID := make(map[int32]*string, len(metadata.Brokers))
+	for i, broker := range metadata.Brokers {
+		brokers[i] = plannerBroker{ID: broker.NodeID}
+		if broker.Rack != nil {
+			brokers[i].Rack = *broker.Rack
+		}
+		racksByBrokerID[broker.NodeID] = broker.Rack
+	}
+
+	topicDetails := make(map[string]TopicDetails, len(metadata.Topics))
+	partitions := make([]*plannerPartition, 0)
+	for _, topic := range metadata.Topics {
+		topicName := *topic.Topic
+		if err := kerr.ErrorForCode(topic.ErrorCode); err != nil {
+			status := http.StatusServiceUnavailable
+			if errors.Is(err, kerr.UnknownTopicOrPartition) {
+				status = http.StatusNotFound
+			}
+			return nil, &rest.Error{
+				Err:      fmt.Errorf("failed to get metadata for topic %q: %w", topicName, err),
+				Status:   status,
+				Message:  fmt.Sprintf("Failed to get metadata for topic %q: %v", topicName, err.Error()),
+				IsSilent: false,
+			}
+		}
+
+		details := TopicDetails{TopicName: topicName, Partitions: make([]TopicPartitionDetails, len(topic.Partitions))}
+		for i, partition := range topic.Partitions {
+			details.Partitions[i] = TopicPartitionDetails{
+				TopicPartitionMetadata: &TopicPartitionMetadata{ID: partition.Partition, Replicas: partition.Replicas},
+			}
+			partitions = append(partitions, &plannerPartition{
+				Topic:   topicName,
+				ID:      partition.Partition,
+				Current: partition.Replicas,
+			})
+		}
+		topicDetails[topicName] = details
+	}
+
+	// 2. Validate goal against the cluster
+	brokerExists := make(map[int32]struct{}, len(brokers))
+	for _, b := range brokers {
+		brokerExists[b.ID] = struct{}{}
+	}
+	var excluded []int32
+	switch req.Goal {
+	case ReassignmentGoalBalanceReplicas:
+	case ReassignmentGoalDrainBrokers:
+		for _, id := range req.BrokerIDs {
+			if _, exists := brokerExists[id]; !exists {
+				return nil, &rest.Error{
+					Err:      fmt.Errorf("broker %d does not exist", id),
+					Status:   http.StatusBadRequest,
+					Message:  fmt.Sprintf("Broker %d does not exist", id),
+					IsSilent: false,
+				}
+			}
+		}
+		excluded = req.BrokerIDs
+		if len(excluded) >= len(brokers) {
+			return nil, &rest.Error{
+				Err:      fmt.Errorf("can not drain all brokers"),
+				Status:   http.StatusBadRequest,
+				Message:  "At least one broker must remain after draining",
+				IsSilent: false,
+			}
+		}
+	case ReassignmentGoalChangeReplicationFactor:
+		if req.ReplicationFactor < 1 || req.ReplicationFactor > len(brokers) {
+			return nil, &rest.Error{
+				Err:      fmt.Errorf("replication factor %d is out of range", req.ReplicationFactor),
+				Status:   http.StatusBadRequest,
+				Message:  fmt.Sprintf("Replication factor must be between 1 and the number of brokers (%d)", len(brokers)),
+				IsSilent: false,
+			}
+		}
+	default:
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("unknown reassignment goal %q", req.Goal),
+			Status:   http.StatusBadRequest,
+			Message:  fmt.Sprintf("Unknown reassignment goal %q", req.Goal),
+			IsSilent: false,
+		}
+	}
+
+	// 3. Determine partition sizes so that smaller partitions can be moved first
+	logDirs := s.describePartitionLogDirs(ctx, topicDetails)
+	for _, partition := range partitions {
+		partition.Size = -1
+		for _, logDir := range logDirs[partition.Topic][partition.ID] {
+			if logDir.Error == "" && logDir.Size > partition.Size {
+				partition.Size = logDir.Size
+			}
+		}
+	}
+
+	// 4. Compute plan
+	planner := newReassignmentPlanner(brokers, partitions, excluded)
+	loadBefore := make(map[int32]int, len(planner.load))
+	for id, load := range planner.load {
+		loadBefore[id] = load
+	}
+	switch req.Goal {
+	case ReassignmentGoalBalanceReplicas:
+		planner.balance()
+	case ReassignmentGoalDrainBrokers:
+		planner.drain()
+	case ReassignmentGoalChangeReplicationFactor:
+		planner.changeReplicationFactor(req.ReplicationFactor)
+	}
+
+	// 5. Map results
+	plan := &PartitionReassignmentPlan{
+		Goal:     req.Goal,
+		Topics:   make([]PartitionReassignmentPlanTopic, 0),
+		Brokers:  make([]PartitionReassignmentPlanBroker, 0, len(planner.brokerIDs)),
+		Warnings: planner.warnings,
+	}
+	if plan.Warnings == nil {
+		plan.Warnings = make([]string, 0)
+	}
+	topicIdx := make(map[string]int)
+	for _, partition := range partitions {
+		if equalInt32s(partition.Current, partition.Target) {
+			continue
+		}
+		for _, replica := range partition.Target {
+			if !containsInt32(partition.Current, replica) {
+				plan.MovedReplicas++
+				if partition.Size > 0 {
+					plan.MovedBytes += partition.Size
+				}
+			}
+		}
+
+		idx, exists := topicIdx[partition.Topic]
+		if !exists {
+			idx = len(plan.Topics)
+			topicIdx[partition.Topic] = idx
+			plan.Topics = append(plan.Topics, PartitionReassignmentPlanTopic{TopicName: partition.Topic})
+		}
+		plan.Topics[idx].Partitions = append(plan.Topics[idx].Partitions, PartitionReassignmentPlanPartition{
+			PartitionID:     partition.ID,
+			CurrentReplicas: partition.Current,
+			Replicas:        partition.Target,
+			Size:            partition.Size,
+		})
+	}
+	sort.Slice(plan.Topics, func(i, j int) bool { return plan.Topics[i].TopicName < plan.Topics[j].TopicName })
+	for _, topic := range plan.Topics {
+		sort.Slice(topic.Partitions, func(i, j int) bool { return topic.Partitions[i].PartitionID < topic.Partitions[j].PartitionID })
+	}
+	for _, id := range planner.brokerIDs {
+		plan.Brokers = append(plan.Brokers, PartitionReassignmentPlanBroker{
+			BrokerID:       id,
+			Rack:           racksByBrokerID[id],
+			ReplicasBefore: loadBefore[id],
+			ReplicasAfter:  planner.load[id],
+		})
+	}
+
+	return plan, nil
+}
+
+// Replication throttle config keys, see KIP-73.
+const (
+	configLeaderReplicationThrottledRate       = "leader.replication.throttled.rate"
+	configFollowerReplicationThrottledRate     = "follower.replication.throttled.rate"
+	configLeaderReplicationThrottledReplicas   = "leader.replication.throttled.replicas"
+	configFollowerReplicationThrottledReplicas = "follower.replication.throttled.replicas"
+)
+
+// SetReplicationThrottlesRequest throttles the replication traffic of a partition reassignment.
+type SetReplicationThrottlesRequest struct {
+	// RateBytesPerSecond is applied as leader and follower throttle rate on all involved brokers.
+	RateBytesPerSecond int64
+
+	// Topics is the partition reassignment that shall be throttled, usually the topics of a plan.
+	Topics []PartitionReassignmentPlanTopic
+}
+
+// replicationThrottledReplicas returns the values for the throttled replicas topic configs of the given
+// partitions. As in Kafka's reassignment tool, all current replicas are throttled as leaders, because they
+// may serve the data and all new replicas are throttled as followers.
+func replicationThrottledReplicas(partitions []PartitionReassignmentPlanPartition) (leader, follower string) {
+	leaders := make([]string, 0)
+	followers := make([]string, 0)
+	for _, partition := range partitions {
+		for _, replica := range partition.CurrentReplicas {
+			leaders = append(leaders, fmt.Sprintf("%d:%d", partition.PartitionID, replica))
+		}
+		for _, replica := range partition.Replicas {
+			if !containsInt32(partition.CurrentReplicas, replica) {
+				followers = append(followers, fmt.Sprintf("%d:%d", partition.PartitionID, replica))
+			}
+		}
+	}
+	return strings.Join(leaders, ","), strings.Join(followers, ",")
+}
+
+// SetReplicationThrottles sets the throttled replicas configs on all given topics and the throttle rates
+// on all brokers that are involved in the reassignment.
+func (s *Service) SetReplicationThrottles(ctx context.Context, req SetReplicationThrottlesRequest) ([]IncrementalAlterConfigsResourceResponse, *rest.Error) {
+	resources := make([]kmsg.IncrementalAlterConfigsRequestResource, 0)
+	involvedBrokers := make(map[int32]struct{})
+	for _, topic := range req.Topics {
+		leader, follower := replicationThrottledReplicas(topic.Partitions)
+		for _, partition := range topic.Partitions {
+			for _, replica := range append(append([]int32(nil), partition.CurrentReplicas...), partition.Replicas...) {
+				involvedBrokers[replica] = struct{}{}
+			}
+		}
+		resources = append(resources, newAlterConfigsResource(kmsg.ConfigResourceTypeTopic, topic.TopicName, kmsg.IncrementalAlterConfigOpSet, map[string]string{
+			configLeaderReplicationThrottledReplicas:   leader,
+			configFollowerReplicationThrottledReplicas: follower,
+		}))
+	}
+
+	rate := strconv.FormatInt(req.RateBytesPerSecond, 10)
+	brokerIDs := make([]int32, 0, len(involvedBrokers))
+	for id := range involvedBrokers {
+		brokerIDs = append(brokerIDs, id)
+	}
+	sort.Slice(brokerIDs, func(i, j int) bool { return brokerIDs[i] < brokerIDs[j] })
+	for _, id := range brokerIDs {
+		resources = append(resources, newAlterConfigsResource(kmsg.ConfigResourceTypeBroker, strconv.Itoa(int(id)), kmsg.IncrementalAlterConfigOpSet, map[string]string{
+			configLeaderReplicationThrottledRate:   rate,
+			configFollowerReplicationThrottledRate: rate,
+		}))
+	}
+
+	return s.IncrementalAlterConfigs(ctx, resources)
+}
+
+// ClearReplicationThrottles removes the throttled replicas configs from the given topics and the throttle
+// rates from all brokers. If no topic names are given, the configs are removed from all topics.
+func (s *Service) ClearReplicationThrottles(ctx context.Context, topicNames []string) ([]IncrementalAlterConfigsResourceResponse, *rest.Error) {
+	metadata, err := s.kafkaSvc.GetMetadata(ctx, topicNames)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to get metadata from cluster: '%v'", err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	resources := make([]kmsg.IncrementalAlterConfigsRequestResource, 0, len(metadata.Topics)+len(metadata.Brokers))
+	for _, topic := range metadata.Topics {
+		if kerr.ErrorForCode(topic.ErrorCode) != nil {
+			continue
+		}
+		resources = append(resources, newAlterConfigsResource(kmsg.ConfigResourceTypeTopic, *topic.Topic, kmsg.IncrementalAlterConfigOpDelete, map[string]string{
+			configLeaderReplicationThrottledReplicas:   "",
+			configFollowerReplicationThrottledReplicas: "",
+		}))
+	}
+	for _, broker := range metadata.Brokers {
+		resources = append(resources, newAlterConfigsResource(kmsg.ConfigResourceTypeBroker, strconv.Itoa(int(broker.NodeID)), kmsg.IncrementalAlterConfigOpDelete, map[string]string{
+			configLeaderReplicationThrottledRate:   "",
+			configFollowerReplicationThrottledRate: "",
+		}))
+	}
+
+	return s.IncrementalAlterConfigs(ctx, resources)
+}
+
+func newAlterConfigsResource(resourceType kmsg.ConfigResourceType, name string, op kmsg.IncrementalAlterConfigOp, configs map[string]string) kmsg.IncrementalAlterConfigsRequestResource {
+	resource := kmsg.NewIncrementalAlterConfigsRequestResource()
+	resource.ResourceType = resourceType
+	resource.ResourceName = name
+
+	keys := make([]string, 0, len(configs))
+	for key := range configs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		config := kmsg.NewIncrementalAlterConfigsRequestResourceConfig()
+		config.Name = key
+		config.Op = op
+		if op != kmsg.IncrementalAlterConfigOpDelete {
+			value := configs[key]
+			config.Value = &value
+		}
+		resource.Configs = append(resource.Configs, config)
+	}
+	return resource
+}
+
+// PartitionReassignmentsProgress shows how much data still has to be copied for all in-flight reassignments.
+type PartitionReassignmentsProgress struct {
+	TotalBytes     int64                                 `json:"totalBytes"`
+	RemainingBytes int64                                 `json:"remainingBytes"`
+	Topics         []PartitionReassignmentsProgressTopic `json:"topics"`
+}
+
+// PartitionReassignmentsProgressTopic is the progress of a topic's in-flight reassignments.
+type PartitionReassignmentsProgressTopic struct {
+	TopicName  string                                    `json:"topicName"`
+	Partitions []PartitionReassignmentsProgressPartition `json:"partitions"`
+}
+
+// PartitionReassignmentsProgressPartition is the progress of a single partition reassignment. TotalBytes
+// is the leader's partition size for each adding replica, RemainingBytes is what the adding replicas
+// have not copied yet.
+type PartitionReassignmentsProgressPartition struct {
+	PartitionID      int32   `json:"partitionId"`
+	Replicas         []int32 `json:"replicas"`
+	AddingReplicas   []int32 `json:"addingReplicas"`
+	RemovingReplicas []int32 `json:"removingReplicas"`
+	TotalBytes       int64   `json:"totalBytes"`
+	RemainingBytes   int64   `json:"remainingBytes"`
+	Error            string  `json:"error,omitempty"`
+}
+
+// reassignmentBytesRemaining estimates the total and remaining bytes to copy for the adding replicas
+// based on the log dir sizes of the leader and the adding replicas.
+func reassignmentBytesRemaining(leader int32, logDirs []TopicPartitionLogDirs, adding []int32) (total, remaining int64, err error) {
+	leaderSize := int64(-1)
+	sizeByBrokerID := make(map[int32]int64, len(logDirs))
+	for _, logDir := range logDirs {
+		if logDir.Error != "" {
+			continue
+		}
+		sizeByBrokerID[logDir.BrokerID] = logDir.Size
+		if logDir.BrokerID == leader {
+			leaderSize = logDir.Size
+		}
+	}
+	if leaderSize < 0 {
+		return 0, 0, fmt.Errorf("partition size of leader %d is unknown", leader)
+	}
+
+	for _, replica := range adding {
+		total += leaderSize
+		copied := sizeByBrokerID[replica]
+		if copied < leaderSize {
+			remaining += leaderSize - copied
+		}
+	}
+	return total, remaining, nil
+}
+
+// GetPartitionReassignmentsProgress returns the progress of all in-flight partition reassignments.
+func (s *Service) GetPartitionReassignmentsProgress(ctx context.Context) (*PartitionReassignmentsProgress, *rest.Error) {
+	reassignments, err := s.ListPartitionReassignments(ctx)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Could not list active partition reassignments: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	progress := &PartitionReassignmentsProgress{Topics: make([]PartitionReassignmentsProgressTopic, 0, len(reassignments))}
+	if len(reassignments) == 0 {
+		return progress, nil
+	}
+
+	topicNames := make([]string, len(reassignments))
+	for i, topic := range reassignments {
+		topicNames[i] = topic.TopicName
+	}
+	details, restErr := s.GetTopicDetails(ctx, topicNames)
+	if restErr != nil {
+		return nil, restErr
+	}
+	partitionsByTopic := make(map[string]map[int32]TopicPartitionDetails, len(details))
+	for _, topic := range details {
+		partitions := make(map[int32]TopicPartitionDetails, len(topic.Partitions))
+		for _, partition := range topic.Partitions {
+			partitions[partition.ID] = partition
+		}
+		partitionsByTopic[topic.TopicName] = partitions
+	}
+
+	for _, topic := range reassignments {
+		topicProgress := PartitionReassignmentsProgressTopic{
+			TopicName:  topic.TopicName,
+			Partitions: make([]PartitionReassignmentsProgressPartition, len(topic.Partitions)),
+		}
+		for i, partition := range topic.Partitions {
+			partitionProgress := PartitionReassignmentsProgressPartition{
+				PartitionID:      partition.PartitionID,
+				Replicas:         partition.Replicas,
+				AddingReplicas:   partition.AddingReplicas,
+				RemovingReplicas: partition.RemovingReplicas,
+			}
+			partitionDetails, exists := partitionsByTopic[topic.TopicName][partition.PartitionID]
+			if !exists {
+				partitionProgress.Error = "partition details are not available"
+			} else {
+				total, remaining, err := reassignmentBytesRemaining(partitionDetails.Leader, partitionDetails.PartitionLogDirs, partition.AddingReplicas)
+				partitionProgress.TotalBytes = total
+				partitionProgress.RemainingBytes = remaining
+				partitionProgress.Error = errToString(err)
+			}
+			progress.TotalBytes += partitionProgress.TotalBytes
+			progress.RemainingBytes += partitionProgress.RemainingBytes
+			topicProgress.Partitions[i] = partitionProgress
+		}
+		progress.Topics = append(progress.Topics, topicProgress)
+	}
+
+	return progress, nil
+}
+
+// CancelPartitionReassignments cancels the given in-flight partition reassignments. If topics is nil,
+// all in-flight reassignments are cancelled.
+func (s *Service) CancelPartitionReassignments(ctx context.Context, topics []PartitionReassignments) ([]AlterPartitionReassignmentsResponse, *rest.Error) {
+	if topics == nil {
+		var err error
+		topics, err = s.ListPartitionReassignments(ctx)
+		if err != nil {
+			return nil, &rest.Error{
+				Err:      err,
+				Status:   http.StatusServiceUnavailable,
+				Message:  fmt.Sprintf("Could not list active partition reassignments: %v", err.Error()),
+				IsSilent: false,
+			}
+		}
+	}
+	if len(topics) == 0 {
+		return make([]AlterPartitionReassignmentsResponse, 0), nil
+	}
+
+	// Reassignments are cancelled by submitting null replicas
+	kmsgReq := make([]kmsg.AlterPartitionAssignmentsRequestTopic, len(topics))
+	for i, topic := range topics {
+		topicReq := kmsg.NewAlterPartitionAssignmentsRequestTopic()
+		topicReq.Topic = topic.TopicName
+		for _, partition := range topic.Partitions {
+			partitionReq := kmsg.NewAlterPartitionAssignmentsRequestTopicPartition()
+			partitionReq.Partition = partition.PartitionID
+			partitionReq.Replicas = nil
+			topicReq.Partitions = append(topicReq.Partitions, partitionReq)
+		}
+		kmsgReq[i] = topicReq
+	}
+
+	res, err := s.AlterPartitionAssignments(ctx, kmsgReq)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Cancel partition reassignments request has failed: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	return res, nil
+}
+
+func equalInt32s(a, b []int32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
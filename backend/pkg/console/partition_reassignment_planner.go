@@ -0,0 +1,304 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ReassignmentGoal is the objective the partition reassignment planner shall achieve.
+type ReassignmentGoal string
+
+const (
+	// ReassignmentGoalBalanceReplicas distributes the replicas evenly across all brokers.
+	ReassignmentGoalBalanceReplicas ReassignmentGoal = "BALANCE_REPLICAS"
+	// ReassignmentGoalDrainBrokers moves all replicas away from the given brokers.
+	ReassignmentGoalDrainBrokers ReassignmentGoal = "DRAIN_BROKERS"
+	// ReassignmentGoalChangeReplicationFactor adds or removes replicas until each partition
+	// has the requested number of replicas.
+	ReassignmentGoalChangeReplicationFactor ReassignmentGoal = "CHANGE_REPLICATION_FACTOR"
+)
+
+// plannerBroker is a broker that may hold partition replicas.
+type plannerBroker struct {
+	ID   int32
+	Rack string
+}
+
+// plannerPartition is a partition whose replicas may be moved by the planner.
+type plannerPartition struct {
+	Topic string
+	ID    int32
+	// Size is the partition's size in bytes, which is the amount of data that has to
+	// be copied for each new replica.
+	Size int64
+
+	Current []int32
+	Target  []int32
+}
+
+// reassignmentPlanner computes new replica assignments with as few replica movements as
+// possible. If all brokers have a rack configured, replicas of a partition will be spread
+// across as many racks as possible.
+type reassignmentPlanner struct {
+	brokers   map[int32]plannerBroker
+	brokerIDs []int32
+	// excluded brokers must not be picked as target for any replica.
+	excluded   map[int32]struct{}
+	partitions []*plannerPartition
+	// load is the number of target replicas per broker.
+	load      map[int32]int
+	rackAware bool
+	warnings  []string
+}
+
+func newReassignmentPlanner(brokers []plannerBroker, partitions []*plannerPartition, excluded []int32) *reassignmentPlanner {
+	p := &reassignmentPlanner{
+		brokers:    make(map[int32]plannerBroker, len(brokers)),
+		brokerIDs:  make([]int32, 0, len(brokers)),
+		excluded:   make(map[int32]struct{}, len(excluded)),
+		partitions: partitions,
+		load:       make(map[int32]int, len(brokers)),
+		rackAware:  len(brokers) > 0,
+	}
+
+	racks := 0
+	for _, b := range brokers {
+		p.brokers[b.ID] = b
+		p.brokerIDs = append(p.brokerIDs, b.ID)
+		p.load[b.ID] = 0
+		if b.Rack == "" {
+			p.rackAware = false
+		} else {
+			racks++
+		}
+	}
+	sort.Slice(p.brokerIDs, func(i, j int) bool { return p.brokerIDs[i] < p.brokerIDs[j] })
+	if !p.rackAware && racks > 0 {
+		p.warnings = append(p.warnings, "Rack awareness is disabled, because not all brokers have a rack configured")
+	}
+
+	for _, id := range excluded {
+		p.excluded[id] = struct{}{}
+	}
+	for _, partition := range partitions {
+		partition.Target = append([]int32(nil), partition.Current...)
+		for _, replica := range partition.Target {
+			p.load[replica]++
+		}
+	}
+
+	return p
+}
+
+// isEligible returns true if the broker may be picked as target for a replica.
+func (p *reassignmentPlanner) isEligible(brokerID int32) bool {
+	if _, exists := p.brokers[brokerID]; !exists {
+		return false
+	}
+	_, isExcluded := p.excluded[brokerID]
+	return !isExcluded
+}
+
+// rackOverlap returns how many of the given replicas are placed in the same rack as the broker.
+func (p *reassignmentPlanner) rackOverlap(brokerID int32, replicas []int32) int {
+	if !p.rackAware {
+		return 0
+	}
+	overlap := 0
+	for _, replica := range replicas {
+		if p.brokers[replica].Rack == p.brokers[brokerID].Rack {
+			overlap++
+		}
+	}
+	return overlap
+}
+
+// pickBroker returns the best broker for a new replica next to the given replicas. Brokers in racks
+// that are not yet used by the other replicas are preferred, then brokers with fewer replicas.
+func (p *reassignmentPlanner) pickBroker(others []int32) (int32, bool) {
+	best := int32(-1)
+	bestOverlap, bestLoad := 0, 0
+	for _, id := range p.brokerIDs {
+		if !p.isEligible(id) || containsInt32(others, id) {
+			continue
+		}
+		overlap := p.rackOverlap(id, others)
+		load := p.load[id]
+		if best == -1 || overlap < bestOverlap || (overlap == bestOverlap && load < bestLoad) {
+			best, bestOverlap, bestLoad = id, overlap, load
+		}
+	}
+	return best, best != -1
+}
+
+// drain replaces all replicas on excluded brokers. The replaced replica keeps its position, so
+// that the preferred leader only changes if it was placed on a drained broker.
+func (p *reassignmentPlanner) drain() {
+	unplaced := 0
+	for _, partition := range p.partitions {
+		for i, replica := range partition.Target {
+			if _, isExcluded := p.excluded[replica]; !isExcluded {
+				continue
+			}
+			others := withoutIndex(partition.Target, i)
+			target, ok := p.pickBroker(others)
+			if !ok {
+				unplaced++
+				continue
+			}
+			partition.Target[i] = target
+			p.load[replica]--
+			p.load[target]++
+		}
+	}
+	if unplaced > 0 {
+		p.warnings = append(p.warnings, fmt.Sprintf("%d replicas could not be moved, because there are not enough remaining brokers", unplaced))
+	}
+	p.rebalance(true)
+}
+
+// changeReplicationFactor adds or removes replicas until each partition has the given number of replicas.
+// The first replica (preferred leader) is never removed.
+func (p *reassignmentPlanner) changeReplicationFactor(replicationFactor int) {
+	unplaced := 0
+	for _, partition := range p.partitions {
+		for len(partition.Target) < replicationFactor {
+			target, ok := p.pickBroker(partition.Target)
+			if !ok {
+				unplaced++
+				break
+			}
+			partition.Target = append(partition.Target, target)
+			p.load[target]++
+		}
+
+		for len(partition.Target) > replicationFactor && len(partition.Target) > 1 {
+			// Prefer removing replicas that share a rack with another replica, then the ones on the busiest broker
+			removeIdx := -1
+			bestOverlap, bestLoad := 0, 0
+			for i := 1; i < len(partition.Target); i++ {
+				replica := partition.Target[i]
+				overlap := p.rackOverlap(replica, withoutIndex(partition.Target, i))
+				load := p.load[replica]
+				if removeIdx == -1 || overlap > bestOverlap || (overlap == bestOverlap && load > bestLoad) {
+					removeIdx, bestOverlap, bestLoad = i, overlap, load
+				}
+			}
+			p.load[partition.Target[removeIdx]]--
+			partition.Target = withoutIndex(partition.Target, removeIdx)
+		}
+	}
+	if unplaced > 0 {
+		p.warnings = append(p.warnings, fmt.Sprintf("%d partitions could not reach the requested replication factor, because there are not enough brokers", unplaced))
+	}
+	p.rebalance(true)
+}
+
+// balance moves single replicas from the most to the least loaded brokers until the replica counts
+// of all eligible brokers differ by at most one. Smaller partitions are moved first.
+func (p *reassignmentPlanner) balance() {
+	p.rebalance(false)
+}
+
+// rebalance moves replicas until the brokers are balanced or no replica can be moved anymore. If onlyNew
+// is true, only replicas that are not part of the current assignment are moved. This evens out the
+// placement of new replicas without causing additional data movement.
+func (p *reassignmentPlanner) rebalance(onlyNew bool) {
+	maxMoves := 0
+	for _, partition := range p.partitions {
+		maxMoves += len(partition.Target)
+	}
+
+	for moves := 0; moves < maxMoves; moves++ {
+		if !p.moveOneReplica(onlyNew) {
+			break
+		}
+	}
+}
+
+// moveOneReplica moves a single replica from the most loaded broker to a less loaded broker. It
+// returns false if the brokers are balanced or no replica can be moved.
+func (p *reassignmentPlanner) moveOneReplica(onlyNew bool) bool {
+	eligible := make([]int32, 0, len(p.brokerIDs))
+	for _, id := range p.brokerIDs {
+		if p.isEligible(id) {
+			eligible = append(eligible, id)
+		}
+	}
+	if len(eligible) < 2 {
+		return false
+	}
+	sort.SliceStable(eligible, func(i, j int) bool { return p.load[eligible[i]] < p.load[eligible[j]] })
+
+	for srcIdx := len(eligible) - 1; srcIdx > 0; srcIdx-- {
+		src := eligible[srcIdx]
+		for _, dst := range eligible[:srcIdx] {
+			if p.load[src]-p.load[dst] <= 1 {
+				break
+			}
+			partition, replicaIdx := p.bestReplicaToMove(src, dst, onlyNew)
+			if partition == nil {
+				continue
+			}
+			partition.Target[replicaIdx] = dst
+			p.load[src]--
+			p.load[dst]++
+			return true
+		}
+	}
+
+	return false
+}
+
+// bestReplicaToMove returns the smallest partition with a replica on src that can be moved to dst
+// without reducing the partition's rack diversity. Replicas that are not the preferred leader are preferred.
+func (p *reassignmentPlanner) bestReplicaToMove(src, dst int32, onlyNew bool) (*plannerPartition, int) {
+	var best *plannerPartition
+	bestIdx := -1
+	for _, partition := range p.partitions {
+		if containsInt32(partition.Target, dst) {
+			continue
+		}
+		idx := indexOfInt32(partition.Target, src)
+		if idx == -1 || (onlyNew && containsInt32(partition.Current, src)) {
+			continue
+		}
+		others := withoutIndex(partition.Target, idx)
+		if p.rackOverlap(dst, others) > p.rackOverlap(src, others) {
+			continue
+		}
+		if best == nil || partition.Size < best.Size || (partition.Size == best.Size && bestIdx == 0 && idx != 0) {
+			best, bestIdx = partition, idx
+		}
+	}
+	return best, bestIdx
+}
+
+func containsInt32(values []int32, value int32) bool {
+	return indexOfInt32(values, value) != -1
+}
+
+func indexOfInt32(values []int32, value int32) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// withoutIndex returns a copy of values without the element at index i.
+func withoutIndex(values []int32, i int) []int32 {
+	res := make([]int32, 0, len(values)-1)
+	res = append(res, values[:i]...)
+	return append(res, values[i+1:]...)
+}
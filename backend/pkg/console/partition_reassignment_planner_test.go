@@ -0,0 +1,185 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func plannerTestPartitions(replicas ...[]int32) []*plannerPartition {
+	partitions := make([]*plannerPartition, len(replicas))
+	for i, r := range replicas {
+		partitions[i] = &plannerPartition{Topic: "orders", ID: int32(i), Current: r, Size: int64(100 * (i + 1))}
+	}
+	return partitions
+}
+
+func TestReassignmentPlannerBalance(t *testing.T) {
+	brokers := []plannerBroker{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}}
+	// Broker 4 has been added to the cluster and holds no replicas yet
+	partitions := plannerTestPartitions(
+		[]int32{1, 2, 3}, []int32{2, 3, 1}, []int32{3, 1, 2}, []int32{1, 3, 2},
+	)
+
+	planner := newReassignmentPlanner(brokers, partitions, nil)
+	planner.balance()
+
+	assert.Equal(t, map[int32]int{1: 3, 2: 3, 3: 3, 4: 3}, planner.load)
+	moved := 0
+	for _, p := range partitions {
+		assert.Len(t, p.Target, 3)
+		for _, replica := range p.Target {
+			if !containsInt32(p.Current, replica) {
+				moved++
+			}
+		}
+	}
+	assert.Equal(t, 3, moved, "only as many replicas as necessary should be moved")
+	assert.Empty(t, planner.warnings)
+}
+
+func TestReassignmentPlannerBalanceKeepsBalancedClusterUntouched(t *testing.T) {
+	brokers := []plannerBroker{{ID: 1}, {ID: 2}, {ID: 3}}
+	partitions := plannerTestPartitions([]int32{1, 2}, []int32{2, 3}, []int32{3, 1})
+
+	planner := newReassignmentPlanner(brokers, partitions, nil)
+	planner.balance()
+
+	for _, p := range partitions {
+		assert.Equal(t, p.Current, p.Target)
+	}
+}
+
+func TestReassignmentPlannerDrain(t *testing.T) {
+	brokers := []plannerBroker{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}}
+	partitions := plannerTestPartitions([]int32{1, 2}, []int32{2, 3}, []int32{3, 1}, []int32{1, 4})
+
+	planner := newReassignmentPlanner(brokers, partitions, []int32{1})
+	planner.drain()
+
+	assert.Equal(t, 0, planner.load[1])
+	for _, p := range partitions {
+		assert.NotContains(t, p.Target, int32(1))
+		assert.Len(t, p.Target, 2)
+	}
+	// Replicas that were not placed on the drained broker keep their position
+	assert.Equal(t, []int32{2, 3}, partitions[1].Target)
+	assert.Equal(t, int32(3), partitions[2].Target[0])
+}
+
+func TestReassignmentPlannerDrainNotEnoughBrokers(t *testing.T) {
+	brokers := []plannerBroker{{ID: 1}, {ID: 2}}
+	partitions := plannerTestPartitions([]int32{1, 2})
+
+	planner := newReassignmentPlanner(brokers, partitions, []int32{1})
+	planner.drain()
+
+	assert.Equal(t, []int32{1, 2}, partitions[0].Target)
+	require.Len(t, planner.warnings, 1)
+}
+
+func TestReassignmentPlannerChangeReplicationFactor(t *testing.T) {
+	brokers := []plannerBroker{{ID: 1}, {ID: 2}, {ID: 3}}
+
+	t.Run("increase", func(t *testing.T) {
+		partitions := plannerTestPartitions([]int32{1}, []int32{2}, []int32{3})
+		planner := newReassignmentPlanner(brokers, partitions, nil)
+		planner.changeReplicationFactor(2)
+
+		for _, p := range partitions {
+			require.Len(t, p.Target, 2)
+			assert.Equal(t, p.Current[0], p.Target[0])
+		}
+		assert.Equal(t, map[int32]int{1: 2, 2: 2, 3: 2}, planner.load)
+	})
+
+	t.Run("decrease keeps preferred leader", func(t *testing.T) {
+		partitions := plannerTestPartitions([]int32{1, 2, 3}, []int32{2, 3, 1})
+		planner := newReassignmentPlanner(brokers, partitions, nil)
+		planner.changeReplicationFactor(1)
+
+		assert.Equal(t, []int32{1}, partitions[0].Target)
+		assert.Equal(t, []int32{2}, partitions[1].Target)
+	})
+}
+
+func TestReassignmentPlannerRackAwareness(t *testing.T) {
+	brokers := []plannerBroker{
+		{ID: 1, Rack: "a"}, {ID: 2, Rack: "a"},
+		{ID: 3, Rack: "b"}, {ID: 4, Rack: "b"},
+	}
+
+	t.Run("new replicas are placed in unused racks", func(t *testing.T) {
+		partitions := plannerTestPartitions([]int32{1})
+		planner := newReassignmentPlanner(brokers, partitions, nil)
+		planner.changeReplicationFactor(2)
+
+		require.Len(t, partitions[0].Target, 2)
+		assert.Contains(t, []int32{3, 4}, partitions[0].Target[1])
+	})
+
+	t.Run("drained replicas stay in their rack if the other rack is used", func(t *testing.T) {
+		partitions := plannerTestPartitions([]int32{1, 3})
+		planner := newReassignmentPlanner(brokers, partitions, []int32{1})
+		planner.drain()
+
+		assert.Equal(t, []int32{2, 3}, partitions[0].Target)
+	})
+
+	t.Run("balancing does not reduce rack diversity", func(t *testing.T) {
+		partitions := plannerTestPartitions([]int32{1, 3}, []int32{1, 3}, []int32{1, 3}, []int32{1, 3})
+		planner := newReassignmentPlanner(brokers, partitions, nil)
+		planner.balance()
+
+		for _, p := range partitions {
+			racks := map[string]struct{}{}
+			for _, replica := range p.Target {
+				racks[planner.brokers[replica].Rack] = struct{}{}
+			}
+			assert.Len(t, racks, 2)
+		}
+		assert.Equal(t, map[int32]int{1: 2, 2: 2, 3: 2, 4: 2}, planner.load)
+	})
+
+	t.Run("partially configured racks disable rack awareness", func(t *testing.T) {
+		planner := newReassignmentPlanner([]plannerBroker{{ID: 1, Rack: "a"}, {ID: 2}}, nil, nil)
+		assert.False(t, planner.rackAware)
+		assert.Len(t, planner.warnings, 1)
+	})
+}
+
+func TestReplicationThrottledReplicas(t *testing.T) {
+	leader, follower := replicationThrottledReplicas([]PartitionReassignmentPlanPartition{
+		{PartitionID: 0, CurrentReplicas: []int32{1, 2}, Replicas: []int32{1, 3}},
+		{PartitionID: 1, CurrentReplicas: []int32{2, 3}, Replicas: []int32{4, 1}},
+	})
+
+	assert.Equal(t, "0:1,0:2,1:2,1:3", leader)
+	assert.Equal(t, "0:3,1:4,1:1", follower)
+}
+
+func TestReassignmentBytesRemaining(t *testing.T) {
+	logDirs := []TopicPartitionLogDirs{
+		{BrokerID: 1, Size: 1000},
+		{BrokerID: 2, Size: 1000},
+		{BrokerID: 3, Size: 400},
+	}
+
+	total, remaining, err := reassignmentBytesRemaining(1, logDirs, []int32{3, 4})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2000), total)
+	assert.Equal(t, int64(1600), remaining)
+
+	_, _, err = reassignmentBytesRemaining(5, logDirs, []int32{3})
+	assert.Error(t, err)
+}
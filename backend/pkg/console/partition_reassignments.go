@@ -51,7 +51,7 @@ func (s *Service) ListPartitionReassignments(ctx context.Context) ([]PartitionRe
 				PartitionID:      partition.Partition,
 				AddingReplicas:   partition.AddingReplicas,
 				RemovingReplicas: partition.RemovingReplicas,
-				Replicas:         partition.RemovingReplicas,
+				Replicas:         partition.Replicas,
 			})
 		}
 
@@ -0,0 +1,419 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// ReplicaLogDirMove moves a single partition replica to another log dir on the same broker.
+type ReplicaLogDirMove struct {
+	TopicName    string `json:"topicName"`
+	PartitionID  int32  `json:"partitionId"`
+	SourceLogDir string `json:"sourceLogDir,omitempty"`
+	TargetLogDir string `json:"targetLogDir"`
+	SizeBytes    int64  `json:"sizeBytes"`
+}
+
+// ReplicaLogDirMoveResult is the broker's response to a single replica move.
+type ReplicaLogDirMoveResult struct {
+	TopicName    string `json:"topicName"`
+	PartitionID  int32  `json:"partitionId"`
+	TargetLogDir string `json:"targetLogDir"`
+	Error        string `json:"error,omitempty"`
+}
+
+// MoveReplicaLogDirs moves the given partition replicas to other log dirs on the given broker. The
+// data is copied in the background by the broker, use GetReplicaLogDirMovesProgress to track it.
+func (s *Service) MoveReplicaLogDirs(ctx context.Context, brokerID int32, moves []ReplicaLogDirMove) ([]ReplicaLogDirMoveResult, *rest.Error) {
+	// 1. Check that all target log dirs exist on the broker, so that we can return a descriptive error
+	logDirs, restErr := s.describeBrokerLogDirs(ctx, brokerID)
+	if restErr != nil {
+		return nil, restErr
+	}
+	dirExists := make(map[string]struct{}, len(logDirs.Dirs))
+	for _, dir := range logDirs.Dirs {
+		dirExists[dir.Dir] = struct{}{}
+	}
+
+	partitionsByDirTopic := make(map[string]map[string][]int32)
+	for _, move := range moves {
+		if _, exists := dirExists[move.TargetLogDir]; !exists {
+			return nil, &rest.Error{
+				Err:      fmt.Errorf("log dir %q does not exist on broker %d", move.TargetLogDir, brokerID),
+				Status:   http.StatusBadRequest,
+				Message:  fmt.Sprintf("Log dir %q does not exist on broker %d", move.TargetLogDir, brokerID),
+				IsSilent: false,
+			}
+		}
+		if _, exists := partitionsByDirTopic[move.TargetLogDir]; !exists {
+			partitionsByDirTopic[move.TargetLogDir] = make(map[string][]int32)
+		}
+		partitionsByDirTopic[move.TargetLogDir][move.TopicName] = append(partitionsByDirTopic[move.TargetLogDir][move.TopicName], move.PartitionID)
+	}
+
+	// 2. Submit moves
+	dirs := make([]kmsg.AlterReplicaLogDirsRequestDir, 0, len(partitionsByDirTopic))
+	targetDirByTopicPartition := make(map[string]map[int32]string)
+	for dirName, topics := range partitionsByDirTopic {
+		dir := kmsg.NewAlterReplicaLogDirsRequestDir()
+		dir.Dir = dirName
+		for topicName, partitionIDs := range topics {
+			topic := kmsg.NewAlterReplicaLogDirsRequestDirTopic()
+			topic.Topic = topicName
+			topic.Partitions = partitionIDs
+			dir.Topics = append(dir.Topics, topic)
+
+			if _, exists := targetDirByTopicPartition[topicName]; !exists {
+				targetDirByTopicPartition[topicName] = make(map[int32]string)
+			}
+			for _, partitionID := range partitionIDs {
+				targetDirByTopicPartition[topicName][partitionID] = dirName
+			}
+		}
+		dirs = append(dirs, dir)
+	}
+
+	res, err := s.kafkaSvc.AlterReplicaLogDirs(ctx, brokerID, dirs)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("failed to alter replica log dirs: %w", err),
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to move replicas between log dirs: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	// 3. Map per-partition results
+	results := make([]ReplicaLogDirMoveResult, 0, len(moves))
+	for _, topic := range res.Topics {
+		for _, partition := range topic.Partitions {
+			results = append(results, ReplicaLogDirMoveResult{
+				TopicName:    topic.Topic,
+				PartitionID:  partition.Partition,
+				TargetLogDir: targetDirByTopicPartition[topic.Topic][partition.Partition],
+				Error:        errToString(kerr.ErrorForCode(partition.ErrorCode)),
+			})
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].TopicName != results[j].TopicName {
+			return results[i].TopicName < results[j].TopicName
+		}
+		return results[i].PartitionID < results[j].PartitionID
+	})
+
+	return results, nil
+}
+
+// LogDirUsage is the disk usage of a single log dir.
+type LogDirUsage struct {
+	LogDir string `json:"logDir"`
+	// SizeBytes is the summed size of all partition replicas in this log dir.
+	SizeBytes int64 `json:"sizeBytes"`
+	// TotalBytes and UsableBytes describe the volume of the log dir. Both are -1 if not
+	// reported by the broker (Kafka < 3.3).
+	TotalBytes  int64 `json:"totalBytes"`
+	UsableBytes int64 `json:"usableBytes"`
+	// PlannedSizeBytes is the summed size of all partition replicas after all planned moves are done.
+	PlannedSizeBytes int64 `json:"plannedSizeBytes"`
+}
+
+// LogDirBalancePlan contains replica moves that even out the disk usage across a broker's log dirs.
+type LogDirBalancePlan struct {
+	BrokerID   int32               `json:"brokerId"`
+	LogDirs    []LogDirUsage       `json:"logDirs"`
+	Moves      []ReplicaLogDirMove `json:"moves"`
+	MovedBytes int64               `json:"movedBytes"`
+}
+
+// logDirBalanceReplica is a partition replica that the log dir balance planner may move.
+type logDirBalanceReplica struct {
+	TopicName   string
+	PartitionID int32
+	SizeBytes   int64
+}
+
+// logDirBalanceDir is a log dir as seen by the log dir balance planner.
+type logDirBalanceDir struct {
+	Usage    LogDirUsage
+	Replicas []logDirBalanceReplica
+	// used is the number of used bytes after planned moves. If the volume's capacity is
+	// known this includes data that is not managed by Kafka.
+	used int64
+}
+
+// fill returns the fill level of the log dir. If the capacity of all volumes is known, it's the
+// used fraction of the volume, otherwise the number of bytes used by Kafka.
+func (d *logDirBalanceDir) fill(capacityKnown bool) float64 {
+	if capacityKnown {
+		return float64(d.used) / float64(d.Usage.TotalBytes)
+	}
+	return float64(d.used)
+}
+
+// planLogDirBalance repeatedly moves a replica from the fullest to the emptiest log dir. It picks the replica
+// that minimizes the fill level difference between both dirs, preferring smaller replicas if several
+// are equally good, and stops once no move reduces the difference anymore.
+func planLogDirBalance(dirs []*logDirBalanceDir) []ReplicaLogDirMove {
+	moves := make([]ReplicaLogDirMove, 0)
+	if len(dirs) < 2 {
+		return moves
+	}
+
+	capacityKnown := true
+	replicaCount := 0
+	for _, dir := range dirs {
+		if dir.Usage.TotalBytes > 0 && dir.Usage.UsableBytes >= 0 {
+			dir.used = dir.Usage.TotalBytes - dir.Usage.UsableBytes
+		} else {
+			capacityKnown = false
+		}
+		replicaCount += len(dir.Replicas)
+	}
+	if !capacityKnown {
+		for _, dir := range dirs {
+			dir.used = dir.Usage.SizeBytes
+		}
+	}
+
+	for i := 0; i < replicaCount; i++ {
+		sort.SliceStable(dirs, func(a, b int) bool { return dirs[a].fill(capacityKnown) < dirs[b].fill(capacityKnown) })
+		src, dst := dirs[len(dirs)-1], dirs[0]
+		bestGap := src.fill(capacityKnown) - dst.fill(capacityKnown)
+
+		bestIdx := -1
+		for idx, replica := range src.Replicas {
+			if replica.SizeBytes <= 0 {
+				continue
+			}
+			src.used -= replica.SizeBytes
+			dst.used += replica.SizeBytes
+			gap := math.Abs(src.fill(capacityKnown) - dst.fill(capacityKnown))
+			src.used += replica.SizeBytes
+			dst.used -= replica.SizeBytes
+			if gap < bestGap || (gap == bestGap && bestIdx != -1 && replica.SizeBytes < src.Replicas[bestIdx].SizeBytes) {
+				bestIdx, bestGap = idx, gap
+			}
+		}
+		if bestIdx == -1 {
+			break
+		}
+
+		replica := src.Replicas[bestIdx]
+		src.Replicas = append(src.Replicas[:bestIdx], src.Replicas[bestIdx+1:]...)
+		dst.Replicas = append(dst.Replicas, replica)
+		src.used -= replica.SizeBytes
+		dst.used += replica.SizeBytes
+		src.Usage.PlannedSizeBytes -= replica.SizeBytes
+		dst.Usage.PlannedSizeBytes += replica.SizeBytes
+		moves = append(moves, ReplicaLogDirMove{
+			TopicName:    replica.TopicName,
+			PartitionID:  replica.PartitionID,
+			SourceLogDir: src.Usage.LogDir,
+			TargetLogDir: dst.Usage.LogDir,
+			SizeBytes:    replica.SizeBytes,
+		})
+	}
+
+	return moves
+}
+
+// PlanLogDirBalance computes replica moves that even out the disk usage across the log dirs of the
+// given broker. Log dirs with errors and replicas that are already being moved are not considered.
+func (s *Service) PlanLogDirBalance(ctx context.Context, brokerID int32) (*LogDirBalancePlan, *rest.Error) {
+	logDirs, restErr := s.describeBrokerLogDirs(ctx, brokerID)
+	if restErr != nil {
+		return nil, restErr
+	}
+
+	// Replicas that are currently moved have a future replica in another log dir
+	moving := make(map[string]map[int32]struct{})
+	for _, dir := range logDirs.Dirs {
+		for _, topic := range dir.Topics {
+			for _, partition := range topic.Partitions {
+				if partition.IsFuture {
+					if _, exists := moving[topic.Topic]; !exists {
+						moving[topic.Topic] = make(map[int32]struct{})
+					}
+					moving[topic.Topic][partition.Partition] = struct{}{}
+				}
+			}
+		}
+	}
+
+	dirs := make([]*logDirBalanceDir, 0, len(logDirs.Dirs))
+	for _, dir := range logDirs.Dirs {
+		if kerr.ErrorForCode(dir.ErrorCode) != nil {
+			continue
+		}
+		balanceDir := &logDirBalanceDir{
+			Usage: LogDirUsage{
+				LogDir:      dir.Dir,
+				TotalBytes:  dir.TotalBytes,
+				UsableBytes: dir.UsableBytes,
+			},
+			Replicas: make([]logDirBalanceReplica, 0),
+		}
+		for _, topic := range dir.Topics {
+			for _, partition := range topic.Partitions {
+				balanceDir.Usage.SizeBytes += partition.Size
+				if _, isMoving := moving[topic.Topic][partition.Partition]; isMoving {
+					continue
+				}
+				balanceDir.Replicas = append(balanceDir.Replicas, logDirBalanceReplica{
+					TopicName:   topic.Topic,
+					PartitionID: partition.Partition,
+					SizeBytes:   partition.Size,
+				})
+			}
+		}
+		balanceDir.Usage.PlannedSizeBytes = balanceDir.Usage.SizeBytes
+		dirs = append(dirs, balanceDir)
+	}
+
+	moves := planLogDirBalance(dirs)
+
+	plan := &LogDirBalancePlan{
+		BrokerID: brokerID,
+		LogDirs:  make([]LogDirUsage, len(dirs)),
+		Moves:    moves,
+	}
+	for i, dir := range dirs {
+		plan.LogDirs[i] = dir.Usage
+	}
+	sort.Slice(plan.LogDirs, func(i, j int) bool { return plan.LogDirs[i].LogDir < plan.LogDirs[j].LogDir })
+	for _, move := range moves {
+		plan.MovedBytes += move.SizeBytes
+	}
+
+	return plan, nil
+}
+
+// ReplicaLogDirMovesProgress shows the progress of all replica moves between log dirs of a broker.
+type ReplicaLogDirMovesProgress struct {
+	BrokerID       int32                       `json:"brokerId"`
+	TotalBytes     int64                       `json:"totalBytes"`
+	RemainingBytes int64                       `json:"remainingBytes"`
+	Moves          []ReplicaLogDirMoveProgress `json:"moves"`
+}
+
+// ReplicaLogDirMoveProgress is the progress of a single replica move. The future replica is the copy in the
+// target log dir that replaces the current replica once it has caught up.
+type ReplicaLogDirMoveProgress struct {
+	TopicName      string `json:"topicName"`
+	PartitionID    int32  `json:"partitionId"`
+	SourceLogDir   string `json:"sourceLogDir"`
+	TargetLogDir   string `json:"targetLogDir"`
+	SizeBytes      int64  `json:"sizeBytes"`
+	CopiedBytes    int64  `json:"copiedBytes"`
+	RemainingBytes int64  `json:"remainingBytes"`
+	// OffsetLag is the future replica's lag behind the current replica.
+	OffsetLag int64 `json:"offsetLag"`
+}
+
+// replicaLogDirMovesProgress extracts the in-progress replica moves from a broker's log dir response.
+func replicaLogDirMovesProgress(brokerID int32, res *kmsg.DescribeLogDirsResponse) *ReplicaLogDirMovesProgress {
+	type replicaInfo struct {
+		dir       string
+		size      int64
+		offsetLag int64
+	}
+	current := make(map[string]map[int32]replicaInfo)
+	future := make(map[string]map[int32]replicaInfo)
+	for _, dir := range res.Dirs {
+		if kerr.ErrorForCode(dir.ErrorCode) != nil {
+			continue
+		}
+		for _, topic := range dir.Topics {
+			for _, partition := range topic.Partitions {
+				target := current
+				if partition.IsFuture {
+					target = future
+				}
+				if _, exists := target[topic.Topic]; !exists {
+					target[topic.Topic] = make(map[int32]replicaInfo)
+				}
+				target[topic.Topic][partition.Partition] = replicaInfo{dir: dir.Dir, size: partition.Size, offsetLag: partition.OffsetLag}
+			}
+		}
+	}
+
+	progress := &ReplicaLogDirMovesProgress{BrokerID: brokerID, Moves: make([]ReplicaLogDirMoveProgress, 0)}
+	for topicName, partitions := range future {
+		for partitionID, futureReplica := range partitions {
+			move := ReplicaLogDirMoveProgress{
+				TopicName:    topicName,
+				PartitionID:  partitionID,
+				TargetLogDir: futureReplica.dir,
+				CopiedBytes:  futureReplica.size,
+				OffsetLag:    futureReplica.offsetLag,
+			}
+			if currentReplica, exists := current[topicName][partitionID]; exists {
+				move.SourceLogDir = currentReplica.dir
+				move.SizeBytes = currentReplica.size
+			}
+			if move.SizeBytes > move.CopiedBytes {
+				move.RemainingBytes = move.SizeBytes - move.CopiedBytes
+			}
+			progress.TotalBytes += move.SizeBytes
+			progress.RemainingBytes += move.RemainingBytes
+			progress.Moves = append(progress.Moves, move)
+		}
+	}
+	sort.Slice(progress.Moves, func(i, j int) bool {
+		if progress.Moves[i].TopicName != progress.Moves[j].TopicName {
+			return progress.Moves[i].TopicName < progress.Moves[j].TopicName
+		}
+		return progress.Moves[i].PartitionID < progress.Moves[j].PartitionID
+	})
+
+	return progress
+}
+
+// GetReplicaLogDirMovesProgress returns the progress of all replica moves between the log dirs of the given broker.
+func (s *Service) GetReplicaLogDirMovesProgress(ctx context.Context, brokerID int32) (*ReplicaLogDirMovesProgress, *rest.Error) {
+	logDirs, restErr := s.describeBrokerLogDirs(ctx, brokerID)
+	if restErr != nil {
+		return nil, restErr
+	}
+
+	return replicaLogDirMovesProgress(brokerID, logDirs), nil
+}
+
+func (s *Service) describeBrokerLogDirs(ctx context.Context, brokerID int32) (*kmsg.DescribeLogDirsResponse, *rest.Error) {
+	res, err := s.kafkaSvc.DescribeBrokerLogDirs(ctx, brokerID, nil)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("failed to describe log dirs of broker %d: %w", brokerID, err),
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to describe log dirs of broker %d: %v", brokerID, err.Error()),
+			IsSilent: false,
+		}
+	}
+	if err := kerr.ErrorForCode(res.ErrorCode); err != nil {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("failed to describe log dirs of broker %d, inner kafka error: %w", brokerID, err),
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to describe log dirs of broker %d, kafka responded with the following error: %v", brokerID, err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	return res, nil
+}
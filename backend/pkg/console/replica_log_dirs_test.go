@@ -0,0 +1,104 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestPlanLogDirBalance(t *testing.T) {
+	t.Run("moves replicas from the full to the empty dir", func(t *testing.T) {
+		full := &logDirBalanceDir{
+			Usage: LogDirUsage{LogDir: "/data1", SizeBytes: 900, PlannedSizeBytes: 900, TotalBytes: -1, UsableBytes: -1},
+			Replicas: []logDirBalanceReplica{
+				{TopicName: "a", PartitionID: 0, SizeBytes: 500},
+				{TopicName: "a", PartitionID: 1, SizeBytes: 300},
+				{TopicName: "b", PartitionID: 0, SizeBytes: 100},
+			},
+		}
+		empty := &logDirBalanceDir{
+			Usage: LogDirUsage{LogDir: "/data2", SizeBytes: 100, PlannedSizeBytes: 100, TotalBytes: -1, UsableBytes: -1},
+			Replicas: []logDirBalanceReplica{
+				{TopicName: "c", PartitionID: 0, SizeBytes: 100},
+			},
+		}
+
+		moves := planLogDirBalance([]*logDirBalanceDir{full, empty})
+
+		require.Len(t, moves, 2)
+		assert.Equal(t, ReplicaLogDirMove{TopicName: "a", PartitionID: 1, SourceLogDir: "/data1", TargetLogDir: "/data2", SizeBytes: 300}, moves[0])
+		assert.Equal(t, ReplicaLogDirMove{TopicName: "b", PartitionID: 0, SourceLogDir: "/data1", TargetLogDir: "/data2", SizeBytes: 100}, moves[1])
+		assert.Equal(t, int64(500), full.Usage.PlannedSizeBytes)
+		assert.Equal(t, int64(500), empty.Usage.PlannedSizeBytes)
+	})
+
+	t.Run("uses volume capacity if known", func(t *testing.T) {
+		// Both dirs hold the same amount of Kafka data, but the first volume is much smaller
+		small := &logDirBalanceDir{
+			Usage:    LogDirUsage{LogDir: "/small", SizeBytes: 400, TotalBytes: 500, UsableBytes: 100},
+			Replicas: []logDirBalanceReplica{{TopicName: "a", PartitionID: 0, SizeBytes: 200}, {TopicName: "a", PartitionID: 1, SizeBytes: 200}},
+		}
+		large := &logDirBalanceDir{
+			Usage:    LogDirUsage{LogDir: "/large", SizeBytes: 400, TotalBytes: 2000, UsableBytes: 1600},
+			Replicas: []logDirBalanceReplica{{TopicName: "b", PartitionID: 0, SizeBytes: 400}},
+		}
+
+		moves := planLogDirBalance([]*logDirBalanceDir{small, large})
+
+		require.Len(t, moves, 1)
+		assert.Equal(t, "/large", moves[0].TargetLogDir)
+	})
+
+	t.Run("balanced dirs are untouched", func(t *testing.T) {
+		a := &logDirBalanceDir{Usage: LogDirUsage{LogDir: "/a", SizeBytes: 100}, Replicas: []logDirBalanceReplica{{TopicName: "a", SizeBytes: 100}}}
+		b := &logDirBalanceDir{Usage: LogDirUsage{LogDir: "/b", SizeBytes: 100}, Replicas: []logDirBalanceReplica{{TopicName: "b", SizeBytes: 100}}}
+
+		assert.Empty(t, planLogDirBalance([]*logDirBalanceDir{a, b}))
+	})
+}
+
+func TestReplicaLogDirMovesProgress(t *testing.T) {
+	res := &kmsg.DescribeLogDirsResponse{
+		Dirs: []kmsg.DescribeLogDirsResponseDir{
+			{
+				Dir: "/data1",
+				Topics: []kmsg.DescribeLogDirsResponseDirTopic{
+					{Topic: "orders", Partitions: []kmsg.DescribeLogDirsResponseDirTopicPartition{{Partition: 0, Size: 1000}, {Partition: 1, Size: 50}}},
+				},
+			},
+			{
+				Dir: "/data2",
+				Topics: []kmsg.DescribeLogDirsResponseDirTopic{
+					{Topic: "orders", Partitions: []kmsg.DescribeLogDirsResponseDirTopicPartition{{Partition: 0, Size: 250, OffsetLag: 42, IsFuture: true}}},
+				},
+			},
+		},
+	}
+
+	progress := replicaLogDirMovesProgress(1, res)
+
+	assert.Equal(t, int64(1000), progress.TotalBytes)
+	assert.Equal(t, int64(750), progress.RemainingBytes)
+	require.Len(t, progress.Moves, 1)
+	assert.Equal(t, ReplicaLogDirMoveProgress{
+		TopicName:      "orders",
+		PartitionID:    0,
+		SourceLogDir:   "/data1",
+		TargetLogDir:   "/data2",
+		SizeBytes:      1000,
+		CopiedBytes:    250,
+		RemainingBytes: 750,
+		OffsetLag:      42,
+	}, progress.Moves[0])
+}
@@ -0,0 +1,47 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redpanda-data/console/backend/pkg/schema"
+)
+
+// ExportSchemaRegistry exports all subjects, schema versions and settings of the configured
+// schema registry into a single backup document.
+func (s *Service) ExportSchemaRegistry(_ context.Context) (*schema.Backup, error) {
+	if s.kafkaSvc.SchemaService == nil {
+		return nil, ErrSchemaRegistryNotConfigured
+	}
+
+	backup, err := s.kafkaSvc.SchemaService.ExportBackup()
+	if err != nil {
+		return nil, fmt.Errorf("failed to export schema registry: %w", err)
+	}
+
+	return backup, nil
+}
+
+// ImportSchemaRegistry replays a backup into the configured schema registry. With the dry run
+// option only the import report including all conflicts is returned.
+func (s *Service) ImportSchemaRegistry(_ context.Context, backup *schema.Backup, opts schema.ImportOptions) (*schema.ImportReport, error) {
+	if s.kafkaSvc.SchemaService == nil {
+		return nil, ErrSchemaRegistryNotConfigured
+	}
+
+	report, err := s.kafkaSvc.SchemaService.ImportBackup(backup, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import schema registry backup: %w", err)
+	}
+
+	return report, nil
+}
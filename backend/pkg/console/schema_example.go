@@ -0,0 +1,287 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/jhump/protoreflect/desc"
+
+	"github.com/redpanda-data/console/backend/pkg/example"
+	"github.com/redpanda-data/console/backend/pkg/schema"
+)
+
+// ExampleOptions configure how example payloads are generated.
+type ExampleOptions struct {
+	// Seed for generating the payload. If nil, a random seed will be used.
+	Seed *int64
+
+	// MessageType is the fully qualified name of the Protobuf message type that shall
+	// be generated. If empty, the first message type of the schema will be used.
+	MessageType string
+
+	OptionalFields example.OptionalFields
+}
+
+// ExamplePayload is a generated example payload that conforms to a schema or proto type.
+type ExamplePayload struct {
+	// Seed that has been used to generate the payload. Passing the same seed again will
+	// generate the same payload.
+	Seed int64 `json:"seed"`
+
+	SchemaType  string `json:"schemaType"`
+	SchemaID    int    `json:"schemaId,omitempty"`
+	MessageType string `json:"messageType,omitempty"`
+
+	// JSON is the JSON representation of the generated payload.
+	JSON json.RawMessage `json:"json"`
+
+	// Encoded is the binary encoded payload. Payloads for schemas that are stored in the schema
+	// registry are encoded using the schema registry wire format (magic byte and schema id).
+	Encoded []byte `json:"encoded"`
+}
+
+// GetSchemaExample generates an example payload for the given subject version.
+func (s *Service) GetSchemaExample(_ context.Context, subject string, version string, opts ExampleOptions) (*ExamplePayload, *rest.Error) {
+	if s.kafkaSvc.SchemaService == nil {
+		return nil, &rest.Error{
+			Err:      ErrSchemaRegistryNotConfigured,
+			Status:   http.StatusNotFound,
+			Message:  "Schema registry is not configured",
+			IsSilent: false,
+		}
+	}
+
+	schemaRes, err := s.kafkaSvc.SchemaService.GetSchemaBySubjectAndVersion(subject, version)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to retrieve schema from the schema registry: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	seed := exampleSeed(opts)
+	generator := example.NewGenerator(seed, opts.OptionalFields)
+	payload := &ExamplePayload{
+		Seed:     seed,
+		SchemaID: schemaRes.SchemaID,
+	}
+
+	var encoded []byte
+	switch schemaRes.Type {
+	case "", "AVRO":
+		payload.SchemaType = "AVRO"
+		avroSchema, err := s.kafkaSvc.SchemaService.ParseAvroSchemaWithReferences(&schema.SchemaResponse{
+			Schema:     schemaRes.Schema,
+			SchemaType: schemaRes.Type,
+			References: schemaRes.References,
+		})
+		if err != nil {
+			return nil, exampleSchemaError(err)
+		}
+		var jsonValue any
+		encoded, jsonValue, err = generator.Avro(avroSchema)
+		if err != nil {
+			return nil, exampleGenerationError(err)
+		}
+		payload.JSON, err = json.Marshal(jsonValue)
+		if err != nil {
+			return nil, exampleGenerationError(err)
+		}
+		encoded = appendSchemaRegistryHeader(schemaRes.SchemaID, nil, encoded)
+
+	case "PROTOBUF":
+		payload.SchemaType = "PROTOBUF"
+		fd, err := s.kafkaSvc.SchemaService.CompileProtoSchemaWithReferences(schemaRes)
+		if err != nil {
+			return nil, exampleSchemaError(err)
+		}
+		md, restErr := findExampleMessageType(fd, opts.MessageType)
+		if restErr != nil {
+			return nil, restErr
+		}
+		payload.MessageType = md.GetFullyQualifiedName()
+		encoded, payload.JSON, err = generator.Protobuf(md)
+		if err != nil {
+			return nil, exampleGenerationError(err)
+		}
+		encoded = appendSchemaRegistryHeader(schemaRes.SchemaID, messageIndexes(md), encoded)
+
+	case "JSON":
+		payload.SchemaType = "JSON"
+		payload.JSON, err = generator.JSONSchema([]byte(schemaRes.Schema))
+		if err != nil {
+			return nil, exampleGenerationError(err)
+		}
+		encoded = appendSchemaRegistryHeader(schemaRes.SchemaID, nil, payload.JSON)
+
+	default:
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("unsupported schema type %q", schemaRes.Type),
+			Status:   http.StatusBadRequest,
+			Message:  fmt.Sprintf("Generating examples for schema type %q is not supported", schemaRes.Type),
+			IsSilent: false,
+		}
+	}
+	payload.Encoded = encoded
+
+	return payload, nil
+}
+
+// GetProtoTypeExample generates an example payload for the given proto type from the local
+// proto registry. The encoded payload is plain Protobuf without any schema registry framing.
+func (s *Service) GetProtoTypeExample(_ context.Context, protoType string, opts ExampleOptions) (*ExamplePayload, *rest.Error) {
+	if s.kafkaSvc.ProtoService == nil {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("protobuf is not configured"),
+			Status:   http.StatusNotFound,
+			Message:  "Protobuf deserialization is not configured",
+			IsSilent: false,
+		}
+	}
+
+	md, err := s.kafkaSvc.ProtoService.GetMessageDescriptorByType(protoType)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusNotFound,
+			Message:  fmt.Sprintf("Could not find proto type: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	seed := exampleSeed(opts)
+	encoded, jsonBytes, err := example.NewGenerator(seed, opts.OptionalFields).Protobuf(md)
+	if err != nil {
+		return nil, exampleGenerationError(err)
+	}
+
+	return &ExamplePayload{
+		Seed:        seed,
+		SchemaType:  "PROTOBUF",
+		MessageType: md.GetFullyQualifiedName(),
+		JSON:        jsonBytes,
+		Encoded:     encoded,
+	}, nil
+}
+
+func exampleSeed(opts ExampleOptions) int64 {
+	if opts.Seed != nil {
+		return *opts.Seed
+	}
+	// The seed only needs to differ between requests, it is not used for anything security related
+	//nolint:gosec // See above
+	return rand.New(rand.NewSource(time.Now().UnixNano())).Int63()
+}
+
+func exampleSchemaError(err error) *rest.Error {
+	return &rest.Error{
+		Err:      err,
+		Status:   http.StatusUnprocessableEntity,
+		Message:  fmt.Sprintf("Failed to parse schema: %v", err.Error()),
+		IsSilent: false,
+	}
+}
+
+func exampleGenerationError(err error) *rest.Error {
+	return &rest.Error{
+		Err:      err,
+		Status:   http.StatusUnprocessableEntity,
+		Message:  fmt.Sprintf("Failed to generate example payload: %v", err.Error()),
+		IsSilent: false,
+	}
+}
+
+// findExampleMessageType returns the message type with the given name. The name may either be
+// fully qualified or relative to the file's package. If no name is given the first message type
+// in the file is returned.
+func findExampleMessageType(fd *desc.FileDescriptor, messageType string) (*desc.MessageDescriptor, *rest.Error) {
+	if messageType == "" {
+		if len(fd.GetMessageTypes()) == 0 {
+			return nil, &rest.Error{
+				Err:      fmt.Errorf("schema does not contain any message types"),
+				Status:   http.StatusUnprocessableEntity,
+				Message:  "The schema does not contain any message types",
+				IsSilent: false,
+			}
+		}
+		return fd.GetMessageTypes()[0], nil
+	}
+
+	md := fd.FindMessage(messageType)
+	if md == nil && fd.GetPackage() != "" && !strings.HasPrefix(messageType, fd.GetPackage()+".") {
+		md = fd.FindMessage(fd.GetPackage() + "." + messageType)
+	}
+	if md == nil {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("message type %q not found in schema", messageType),
+			Status:   http.StatusNotFound,
+			Message:  fmt.Sprintf("Message type %q does not exist in the schema", messageType),
+			IsSilent: false,
+		}
+	}
+	return md, nil
+}
+
+// messageIndexes returns the path of indexes that identifies the message type within its file.
+func messageIndexes(md *desc.MessageDescriptor) []int64 {
+	var indexes []int64
+	current := md
+	for current != nil {
+		var siblings []*desc.MessageDescriptor
+		var parent *desc.MessageDescriptor
+		switch p := current.GetParent().(type) {
+		case *desc.MessageDescriptor:
+			siblings = p.GetNestedMessageTypes()
+			parent = p
+		case *desc.FileDescriptor:
+			siblings = p.GetMessageTypes()
+		}
+		for i, sibling := range siblings {
+			if sibling == current {
+				indexes = append([]int64{int64(i)}, indexes...)
+				break
+			}
+		}
+		current = parent
+	}
+	return indexes
+}
+
+// appendSchemaRegistryHeader prefixes the payload with the schema registry wire format header.
+// The header consists of the magic byte, the schema id and - for Protobuf - the message indexes.
+func appendSchemaRegistryHeader(schemaID int, indexes []int64, payload []byte) []byte {
+	buf := make([]byte, 5, 5+len(payload)+len(indexes)+1)
+	binary.BigEndian.PutUint32(buf[1:5], uint32(schemaID))
+
+	if indexes != nil {
+		// The common case of the first message type in the file is encoded as a single 0 byte
+		if len(indexes) == 1 && indexes[0] == 0 {
+			buf = append(buf, 0)
+		} else {
+			buf = binary.AppendVarint(buf, int64(len(indexes)))
+			for _, idx := range indexes {
+				buf = binary.AppendVarint(buf, idx)
+			}
+		}
+	}
+
+	return append(buf, payload...)
+}
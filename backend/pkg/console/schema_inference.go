@@ -0,0 +1,180 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/cloudhut/common/rest"
+
+	"github.com/redpanda-data/console/backend/pkg/inference"
+	"github.com/redpanda-data/console/backend/pkg/kafka"
+)
+
+// InferSchemaRequest describes which records shall be sampled and what kind of schema
+// shall be inferred from them.
+type InferSchemaRequest struct {
+	TopicName string
+
+	// SampleSize is the maximum number of most recent records that will be sampled.
+	SampleSize int
+
+	// UseKeys infers the schema from the record keys instead of the record values.
+	UseKeys bool
+
+	// SchemaType is either "JSON" or "AVRO".
+	SchemaType string
+
+	// RecordName and Namespace of the top-level Avro record.
+	RecordName string
+	Namespace  string
+}
+
+// InferSchemaResponse is the schema that has been inferred from the sampled records.
+type InferSchemaResponse struct {
+	TopicName  string `json:"topicName"`
+	SchemaType string `json:"schemaType"`
+	Schema     string `json:"schema"`
+
+	// SuggestedSubject is the subject name as chosen by the default TopicNameStrategy.
+	SuggestedSubject string `json:"suggestedSubject"`
+
+	// SampledRecords is the number of consumed records, ObservedDocuments is the number of
+	// records whose payload could be deserialized and has been used for inferring the schema.
+	SampledRecords    int `json:"sampledRecords"`
+	ObservedDocuments int `json:"observedDocuments"`
+
+	// Encodings counts the detected encodings of the sampled payloads.
+	Encodings map[string]int `json:"encodings"`
+
+	Coverage []inference.FieldCoverage `json:"coverage"`
+	Warnings []string                  `json:"warnings"`
+}
+
+// inferSchemaProgress collects the consumed records for the schema inference.
+type inferSchemaProgress struct {
+	useKeys  bool
+	inferrer *inference.Inferrer
+
+	sampledRecords int
+	encodings      map[string]int
+	errors         []string
+}
+
+func (p *inferSchemaProgress) OnPhase(string) {}
+
+func (p *inferSchemaProgress) OnMessage(message *kafka.TopicMessage) {
+	p.sampledRecords++
+
+	payload := message.Value
+	if p.useKeys {
+		payload = message.Key
+	}
+	if payload == nil || payload.IsPayloadNull {
+		p.encodings["null"]++
+		return
+	}
+	p.encodings[string(payload.RecognizedEncoding)]++
+
+	// Only structured payloads are used for inferring the schema. Plain text or binary payloads
+	// would otherwise add a string type to the top-level.
+	switch payload.Object.(type) {
+	case map[string]any, []any:
+		p.inferrer.Observe(payload.Object)
+	}
+}
+
+func (*inferSchemaProgress) OnMessageConsumed(int64) {}
+
+func (*inferSchemaProgress) OnComplete(int64, bool) {}
+
+func (p *inferSchemaProgress) OnError(msg string) {
+	p.errors = append(p.errors, msg)
+}
+
+// InferSchema samples the most recent records of a topic and infers a JSON schema or an
+// Avro schema from the deserialized payloads.
+func (s *Service) InferSchema(ctx context.Context, req InferSchemaRequest) (*InferSchemaResponse, *rest.Error) {
+	progress := &inferSchemaProgress{
+		useKeys:   req.UseKeys,
+		inferrer:  inference.NewInferrer(),
+		encodings: make(map[string]int),
+		errors:    make([]string, 0),
+	}
+
+	err := s.ListMessages(ctx, ListMessageRequest{
+		TopicName:    req.TopicName,
+		PartitionID:  partitionsAll,
+		StartOffset:  StartOffsetRecent,
+		MessageCount: req.SampleSize,
+	}, progress)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      err,
+			Status:   http.StatusInternalServerError,
+			Message:  fmt.Sprintf("Failed to sample records: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	if progress.inferrer.Documents() == 0 {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("no structured payloads found in %d sampled records", progress.sampledRecords),
+			Status:   http.StatusUnprocessableEntity,
+			Message:  fmt.Sprintf("None of the %d sampled records contained a structured payload a schema could be inferred from", progress.sampledRecords),
+			IsSilent: false,
+		}
+	}
+
+	suggestedSubject := req.TopicName + "-value"
+	if req.UseKeys {
+		suggestedSubject = req.TopicName + "-key"
+	}
+	res := &InferSchemaResponse{
+		TopicName:         req.TopicName,
+		SchemaType:        req.SchemaType,
+		SuggestedSubject:  suggestedSubject,
+		SampledRecords:    progress.sampledRecords,
+		ObservedDocuments: progress.inferrer.Documents(),
+		Encodings:         progress.encodings,
+		Coverage:          progress.inferrer.Coverage(),
+		Warnings:          progress.errors,
+	}
+
+	switch req.SchemaType {
+	case "AVRO":
+		schemaBytes, warnings, err := progress.inferrer.AvroSchema(req.RecordName, req.Namespace)
+		if err != nil {
+			return nil, &rest.Error{
+				Err:      err,
+				Status:   http.StatusUnprocessableEntity,
+				Message:  fmt.Sprintf("Failed to infer avro schema: %v", err.Error()),
+				IsSilent: false,
+			}
+		}
+		res.Schema = string(schemaBytes)
+		res.Warnings = append(res.Warnings, warnings...)
+	default:
+		schemaBytes, err := progress.inferrer.JSONSchema()
+		if err != nil {
+			return nil, &rest.Error{
+				Err:      err,
+				Status:   http.StatusInternalServerError,
+				Message:  fmt.Sprintf("Failed to infer JSON schema: %v", err.Error()),
+				IsSilent: false,
+			}
+		}
+		res.Schema = string(schemaBytes)
+	}
+
+	return res, nil
+}
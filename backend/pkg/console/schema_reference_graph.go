@@ -0,0 +1,33 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redpanda-data/console/backend/pkg/schema"
+)
+
+// GetSchemaReferenceGraph returns all schema versions that are referenced by the given subject
+// version as well as all schema versions that depend on it. Reference cycles and references
+// that can not be resolved are reported as part of the graph.
+func (s *Service) GetSchemaReferenceGraph(_ context.Context, subject string, version string) (*schema.ReferenceGraph, error) {
+	if s.kafkaSvc.SchemaService == nil {
+		return nil, ErrSchemaRegistryNotConfigured
+	}
+
+	graph, err := s.kafkaSvc.SchemaService.GetReferenceGraph(subject, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build reference graph: %w", err)
+	}
+
+	return graph, nil
+}
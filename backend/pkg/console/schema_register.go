@@ -0,0 +1,31 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redpanda-data/console/backend/pkg/schema"
+)
+
+// RegisterSchema registers a new schema version under the given subject.
+func (s *Service) RegisterSchema(_ context.Context, subject string, req schema.RegisterSchemaRequest) (*schema.RegisterSchemaResponse, error) {
+	if s.kafkaSvc.SchemaService == nil {
+		return nil, ErrSchemaRegistryNotConfigured
+	}
+
+	res, err := s.kafkaSvc.SchemaService.RegisterSchema(subject, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register schema: %w", err)
+	}
+
+	return res, nil
+}
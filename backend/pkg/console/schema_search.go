@@ -0,0 +1,32 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redpanda-data/console/backend/pkg/schema"
+)
+
+// SearchSchemas searches the content and structure of all schema versions in the schema
+// registry. If refresh is true, the search index will be rebuilt before searching.
+func (s *Service) SearchSchemas(_ context.Context, query schema.SearchQuery, refresh bool) (*schema.SearchResult, error) {
+	if s.kafkaSvc.SchemaService == nil {
+		return nil, ErrSchemaRegistryNotConfigured
+	}
+
+	result, err := s.kafkaSvc.SchemaService.SearchSchemas(query, refresh)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search schemas: %w", err)
+	}
+
+	return result, nil
+}
@@ -11,13 +11,16 @@ package console
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
 
+	"github.com/twmb/go-cache/cache"
 	"go.uber.org/zap"
 
 	"github.com/redpanda-data/console/backend/pkg/config"
 	"github.com/redpanda-data/console/backend/pkg/connect"
 	"github.com/redpanda-data/console/backend/pkg/git"
+	"github.com/redpanda-data/console/backend/pkg/history"
 	"github.com/redpanda-data/console/backend/pkg/kafka"
 	"github.com/redpanda-data/console/backend/pkg/redpanda"
 )
@@ -29,12 +32,24 @@ type Service struct {
 	redpandaSvc *redpanda.Service
 	gitSvc      *git.Service // Git service can be nil if not configured
 	connectSvc  *connect.Service
+	historySvc  *history.Service   // History service is nil if not enabled
+	topicState  *topicStateManager // Topic state is nil if not enabled
 	logger      *zap.Logger
+	healthCfg   config.ConsoleHealth
+
+	// topicLintRules are the rules topic configurations are linted against.
+	topicLintRules []TopicLintRule
 
 	// configExtensionsByName contains additional metadata about Topic or BrokerWithLogDirs configs.
 	// The additional information is used by the frontend to provide a good UX when
 	// editing configs or creating new topics.
 	configExtensionsByName map[string]ConfigEntryExtension
+
+	// bulkTopicTokenKey signs the confirmation tokens of bulk topic operations.
+	bulkTopicTokenKey []byte
+
+	// recordTimestampByOffset caches record timestamps for estimating consumer group time lags.
+	recordTimestampByOffset *cache.Cache[recordOffset, kafka.RecordTimestamp]
 }
 
 // NewService for the Console package
@@ -59,25 +74,77 @@ func NewService(
 		return nil, fmt.Errorf("failed to load config extensions: %w", err)
 	}
 
+	if err := ValidateHealthRuleIDs(cfg.Console.Health.DisabledRules); err != nil {
+		return nil, fmt.Errorf("failed to validate disabled health rules: %w", err)
+	}
+
+	topicLintRules, err := loadTopicLintRules(cfg.Console.TopicLint.RulesFilepath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load topic lint rules: %w", err)
+	}
+
 	kafkaSvc, err := kafka.NewService(cfg, logger, cfg.MetricsNamespace)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kafka svc: %w", err)
 	}
 
+	var topicState *topicStateManager
+	if cfg.Console.TopicState.Enabled {
+		topicStateGitSvc, err := git.NewService(cfg.Console.TopicState.Git, logger, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create git service for topic state: %w", err)
+		}
+		topicState, err = newTopicStateManager(cfg.Console.TopicState, topicStateGitSvc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create topic state: %w", err)
+		}
+	}
+
+	bulkTopicTokenKey := make([]byte, 32)
+	if _, err := rand.Read(bulkTopicTokenKey); err != nil {
+		return nil, fmt.Errorf("failed to generate bulk topic operation token key: %w", err)
+	}
+
+	var historySvc *history.Service
+	if cfg.Console.History.Enabled {
+		historySvc = history.NewService(cfg.Console.History, logger, kafkaSvc)
+	}
+
 	return &Service{
 		kafkaSvc:    kafkaSvc,
 		redpandaSvc: redpandaSvc,
 		gitSvc:      gitSvc,
 		connectSvc:  connectSvc,
+		historySvc:  historySvc,
+		topicState:  topicState,
 		logger:      logger,
+		healthCfg:   cfg.Console.Health,
 
+		topicLintRules:         topicLintRules,
 		configExtensionsByName: configExtensionsByName,
+		bulkTopicTokenKey:      bulkTopicTokenKey,
+		recordTimestampByOffset: cache.New[recordOffset, kafka.RecordTimestamp](
+			cache.MaxAge(recordTimestampMaxAge),
+			cache.AutoCleanInterval(recordTimestampMaxAge),
+		),
 	}, nil
 }
 
 // Start starts all the (background) tasks which are required for this service to work properly. If any of these
 // tasks can not be setup an error will be returned which will cause the application to exit.
 func (s *Service) Start() error {
+	if s.historySvc != nil {
+		if err := s.historySvc.Start(); err != nil {
+			return fmt.Errorf("failed to start history service: %w", err)
+		}
+	}
+
+	if s.topicState != nil {
+		if err := s.topicState.gitSvc.Start(); err != nil {
+			return fmt.Errorf("failed to start topic state git service: %w", err)
+		}
+	}
+
 	if s.gitSvc == nil {
 		return nil
 	}
@@ -91,6 +158,12 @@ func (s *Service) Start() error {
 
 // Stop stops running go routines and releases allocated resources.
 func (s *Service) Stop() {
+	if s.historySvc != nil {
+		s.historySvc.Stop()
+	}
+	if s.topicState != nil {
+		s.topicState.gitSvc.Stop()
+	}
 	s.kafkaSvc.KafkaClient.Close()
 }
 
@@ -2,12 +2,15 @@ package console
 
 import (
 	"context"
+	"time"
 
 	"github.com/cloudhut/common/rest"
 	"github.com/twmb/franz-go/pkg/kgo"
 	"github.com/twmb/franz-go/pkg/kmsg"
 
+	"github.com/redpanda-data/console/backend/pkg/history"
 	"github.com/redpanda-data/console/backend/pkg/kafka"
+	"github.com/redpanda-data/console/backend/pkg/schema"
 )
 
 // Servicer is an interface for the Console package that offers all methods to serve the responses for the API layer.
@@ -15,32 +18,86 @@ type Servicer interface {
 	GetAPIVersions(ctx context.Context) ([]APIVersion, error)
 	GetAllBrokerConfigs(ctx context.Context) (map[int32]BrokerConfig, error)
 	GetBrokerConfig(ctx context.Context, brokerID int32) ([]BrokerConfigEntry, *rest.Error)
+	GetDynamicBrokerConfig(ctx context.Context, brokerID *int32) (*DynamicBrokerConfig, *rest.Error)
+	AlterDynamicBrokerConfig(ctx context.Context, brokerID *int32, changes []BrokerConfigChange, validateOnly bool) (*AlterDynamicBrokerConfigResponse, *rest.Error)
 	GetBrokersWithLogDirs(ctx context.Context) ([]BrokerWithLogDirs, error)
+	MoveReplicaLogDirs(ctx context.Context, brokerID int32, moves []ReplicaLogDirMove) ([]ReplicaLogDirMoveResult, *rest.Error)
+	PlanLogDirBalance(ctx context.Context, brokerID int32) (*LogDirBalancePlan, *rest.Error)
+	GetReplicaLogDirMovesProgress(ctx context.Context, brokerID int32) (*ReplicaLogDirMovesProgress, *rest.Error)
 	GetClusterInfo(ctx context.Context) (*ClusterInfo, error)
+	ListTransactions(ctx context.Context, states []string) (*TransactionsOverview, *rest.Error)
+	GetTopicProducers(ctx context.Context, topicName string) (*TopicProducers, *rest.Error)
+	FindHangingTransactions(ctx context.Context, topicNames []string, maxTransactionTimeout time.Duration) (*HangingTransactions, *rest.Error)
+	AbortTransaction(ctx context.Context, topicName string, partitionID int32, startOffset int64, maxTransactionTimeout time.Duration, force bool) (*AbortTransactionResponse, *rest.Error)
 	DeleteConsumerGroup(ctx context.Context, groupID string) error
-	GetConsumerGroupsOverview(ctx context.Context, groupIDs []string) ([]ConsumerGroupOverview, *rest.Error)
+	GetConsumerGroupsOverview(ctx context.Context, groupIDs []string, opts ConsumerGroupsOverviewOptions) ([]ConsumerGroupOverview, *rest.Error)
 	CreateACL(ctx context.Context, createReq kmsg.CreateACLsRequestCreation) *rest.Error
 	CreateKafkaClient(_ context.Context, additionalOpts ...kgo.Opt) (*kgo.Client, error)
 	CreateTopic(ctx context.Context, createTopicReq kmsg.CreateTopicsRequestTopic) (CreateTopicResponse, *rest.Error)
+	CreateTopicPartitions(ctx context.Context, req CreateTopicPartitionsRequest) (*CreateTopicPartitionsResponse, *rest.Error)
 	DeleteACLs(ctx context.Context, filter kmsg.DeleteACLsRequestFilter) (DeleteACLsResponse, *rest.Error)
 	DeleteConsumerGroupOffsets(ctx context.Context, groupID string, topics []kmsg.OffsetDeleteRequestTopic) ([]DeleteConsumerGroupOffsetsResponseTopic, error)
 	DeleteTopic(ctx context.Context, topicName string) *rest.Error
 	DeleteTopicRecords(ctx context.Context, deleteReq kmsg.DeleteRecordsRequestTopic) (DeleteTopicRecordsResponse, *rest.Error)
+	SelectTopics(ctx context.Context, selector TopicSelector) (*TopicSelection, *rest.Error)
+	BulkTopicOperationToken(operation string, topicNames []string, params interface{}) string
+	DeleteTopics(ctx context.Context, topicNames []string) []BulkTopicResult
+	EditTopicsConfigs(ctx context.Context, topicNames []string, configs []kmsg.IncrementalAlterConfigsRequestResourceConfig, validateOnly bool) []BulkTopicResult
+	PlanTopicsRecordsDeletion(ctx context.Context, topicNames []string, target RecordsDeletionTarget) ([]BulkTopicResult, *rest.Error)
+	DeleteTopicsRecords(ctx context.Context, plan []BulkTopicResult) []BulkTopicResult
 	DescribeQuotas(ctx context.Context) QuotaResponse
+	AlterQuotas(ctx context.Context, req AlterQuotasRequest) (*AlterQuotasResponse, *rest.Error)
+	DeleteQuotas(ctx context.Context, entity []QuotaEntityComponent, validateOnly bool) (*AlterQuotasResponse, *rest.Error)
 	EditConsumerGroupOffsets(ctx context.Context, groupID string, topics []kmsg.OffsetCommitRequestTopic) (*EditConsumerGroupOffsetsResponse, *rest.Error)
+	ResetConsumerGroupOffsets(ctx context.Context, req ResetConsumerGroupOffsetsRequest) (*ResetConsumerGroupOffsetsResponse, *rest.Error)
 	EditTopicConfig(ctx context.Context, topicName string, configs []kmsg.IncrementalAlterConfigsRequestResourceConfig) error
 	GetEndpointCompatibility(ctx context.Context) (EndpointCompatibility, error)
+	ListSCRAMUsers(ctx context.Context) ([]SCRAMUser, *rest.Error)
+	CreateSCRAMUser(ctx context.Context, username, password, mechanism string, iterations int32) *rest.Error
+	UpdateSCRAMUserPassword(ctx context.Context, username, password, mechanism string, iterations int32) *rest.Error
+	DeleteSCRAMUser(ctx context.Context, username string) *rest.Error
 	IncrementalAlterConfigs(ctx context.Context, alterConfigs []kmsg.IncrementalAlterConfigsRequestResource) ([]IncrementalAlterConfigsResourceResponse, *rest.Error)
 	ListAllACLs(ctx context.Context, req kmsg.DescribeACLsRequest) (*ACLOverview, error)
+	GetEffectiveACLPermissions(ctx context.Context, principal string, host string) (*EffectiveACLPermissions, *rest.Error)
+	ExplainACL(ctx context.Context, req ExplainACLRequest) (*ExplainACLResponse, *rest.Error)
+	ExportACLs(ctx context.Context) (*ACLExport, *rest.Error)
+	ImportACLs(ctx context.Context, export *ACLExport, opts ACLImportOptions) (*ACLImportResult, *rest.Error)
+	CreateACLBindings(ctx context.Context, bindings []ACLBinding, validateOnly bool) (*ACLImportResult, *rest.Error)
 	ListMessages(ctx context.Context, listReq ListMessageRequest, progress kafka.IListMessagesProgress) error
 	ListOffsets(ctx context.Context, topicNames []string, timestamp int64) ([]TopicOffset, error)
 	GetOverview(ctx context.Context) Overview
+	GetTopicHistory(topicName string, since time.Time) (*history.TopicSeries, error)
+	GetConsumerGroupHistory(groupID string, topicName string, since time.Time) (*history.GroupSeries, error)
 	GetKafkaVersion(ctx context.Context) (string, error)
+	GetClusterHealth(ctx context.Context) (*ClusterHealth, *rest.Error)
+	ExportClusterSnapshot(ctx context.Context, opts ClusterSnapshotExportOptions) (*ClusterSnapshot, *rest.Error)
+	ImportClusterSnapshot(ctx context.Context, snapshot *ClusterSnapshot, opts ClusterSnapshotImportOptions) (*ClusterSnapshotImportReport, *rest.Error)
+	GetUnusedResources(ctx context.Context, opts UnusedResourcesOptions) (*UnusedResourcesReport, *rest.Error)
+	DeleteUnusedResources(ctx context.Context, topicNames []string, groupIDs []string, opts UnusedResourcesOptions, dryRun bool) ([]UnusedResourceDeletion, *rest.Error)
+	LintTopicConfig(cfg *TopicConfig) []TopicLintFinding
+	LintTopicsConfigs(ctx context.Context, topicNames []string) ([]TopicLintReport, *rest.Error)
+	PlanTopicState(ctx context.Context, overrideDeletionLimit bool) (*TopicStatePlan, *rest.Error)
+	ApplyTopicState(ctx context.Context, req ApplyTopicStateRequest) (*TopicStateAuditLogEntry, *rest.Error)
+	GetTopicStateAuditLog() ([]TopicStateAuditLogEntry, *rest.Error)
 	ListPartitionReassignments(ctx context.Context) ([]PartitionReassignments, error)
+	ElectLeaders(ctx context.Context, req ElectLeadersRequest) (*ElectLeadersResponse, *rest.Error)
 	AlterPartitionAssignments(ctx context.Context, topics []kmsg.AlterPartitionAssignmentsRequestTopic) ([]AlterPartitionReassignmentsResponse, error)
+	PlanPartitionReassignments(ctx context.Context, req PlanPartitionReassignmentsRequest) (*PartitionReassignmentPlan, *rest.Error)
+	GetPartitionReassignmentsProgress(ctx context.Context) (*PartitionReassignmentsProgress, *rest.Error)
+	CancelPartitionReassignments(ctx context.Context, topics []PartitionReassignments) ([]AlterPartitionReassignmentsResponse, *rest.Error)
+	SetReplicationThrottles(ctx context.Context, req SetReplicationThrottlesRequest) ([]IncrementalAlterConfigsResourceResponse, *rest.Error)
+	ClearReplicationThrottles(ctx context.Context, topicNames []string) ([]IncrementalAlterConfigsResourceResponse, *rest.Error)
 	ProduceRecords(ctx context.Context, records []*kgo.Record, useTransactions bool, compressionType int8) ProduceRecordsResponse
 	GetSchemaDetails(_ context.Context, subject string, version string) (*SchemaDetails, error)
 	GetSchemaOverview(ctx context.Context) (*SchemaOverview, error)
+	GetSchemaReferenceGraph(_ context.Context, subject string, version string) (*schema.ReferenceGraph, error)
+	ExportSchemaRegistry(_ context.Context) (*schema.Backup, error)
+	ImportSchemaRegistry(_ context.Context, backup *schema.Backup, opts schema.ImportOptions) (*schema.ImportReport, error)
+	GetSchemaExample(_ context.Context, subject string, version string, opts ExampleOptions) (*ExamplePayload, *rest.Error)
+	GetProtoTypeExample(_ context.Context, protoType string, opts ExampleOptions) (*ExamplePayload, *rest.Error)
+	SearchSchemas(_ context.Context, query schema.SearchQuery, refresh bool) (*schema.SearchResult, error)
+	InferSchema(ctx context.Context, req InferSchemaRequest) (*InferSchemaResponse, *rest.Error)
+	RegisterSchema(_ context.Context, subject string, req schema.RegisterSchemaRequest) (*schema.RegisterSchemaResponse, error)
 	Start() error
 	Stop()
 	IsHealthy(ctx context.Context) error
@@ -0,0 +1,257 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cloudhut/common/rest"
+	"gopkg.in/yaml.v3"
+
+	"github.com/redpanda-data/console/backend/pkg/embed"
+)
+
+// Operators that can be used in topic lint conditions.
+const (
+	TopicLintOperatorEq          = "eq"
+	TopicLintOperatorNeq         = "neq"
+	TopicLintOperatorLt          = "lt"
+	TopicLintOperatorLte         = "lte"
+	TopicLintOperatorGt          = "gt"
+	TopicLintOperatorGte         = "gte"
+	TopicLintOperatorContains    = "contains"
+	TopicLintOperatorNotContains = "notContains"
+)
+
+// TopicLintRuleFile is the YAML file topic lint rules are loaded from.
+type TopicLintRuleFile struct {
+	Rules []TopicLintRule `yaml:"rules"`
+}
+
+// TopicLintRule describes a risky topic configuration. It matches a topic if all
+// of its conditions match.
+type TopicLintRule struct {
+	ID          string               `yaml:"id" json:"id"`
+	Severity    HealthSeverity       `yaml:"severity" json:"severity"`
+	Description string               `yaml:"description" json:"description"`
+	Remediation string               `yaml:"remediation" json:"remediation"`
+	Conditions  []TopicLintCondition `yaml:"conditions" json:"conditions"`
+}
+
+// TopicLintCondition compares a topic config's value with the given value.
+type TopicLintCondition struct {
+	Config   string `yaml:"config" json:"config"`
+	Operator string `yaml:"operator" json:"operator"`
+	Value    string `yaml:"value" json:"value"`
+}
+
+// TopicLintFinding is a lint rule that matched a topic's configuration.
+type TopicLintFinding struct {
+	RuleID      string         `json:"ruleId"`
+	Severity    HealthSeverity `json:"severity"`
+	Description string         `json:"description"`
+	Remediation string         `json:"remediation"`
+
+	// Configs contains the current values of all configs the rule's conditions refer to.
+	Configs map[string]string `json:"configs"`
+}
+
+// TopicLintReport contains the lint findings of a single topic.
+type TopicLintReport struct {
+	TopicName string             `json:"topicName"`
+	Findings  []TopicLintFinding `json:"findings"`
+	Error     *KafkaError        `json:"error,omitempty"`
+}
+
+// Validate the rule and all its conditions.
+func (r *TopicLintRule) Validate() error {
+	if r.ID == "" {
+		return fmt.Errorf("rule id must be set")
+	}
+	switch r.Severity {
+	case HealthSeverityInfo, HealthSeverityWarning, HealthSeverityCritical:
+	default:
+		return fmt.Errorf("rule '%v' has invalid severity '%v'", r.ID, r.Severity)
+	}
+	if len(r.Conditions) == 0 {
+		return fmt.Errorf("rule '%v' must have at least one condition", r.ID)
+	}
+	for i, c := range r.Conditions {
+		if err := c.Validate(); err != nil {
+			return fmt.Errorf("rule '%v' has an invalid condition at index %d: %w", r.ID, i, err)
+		}
+	}
+	return nil
+}
+
+// Validate the condition's operator and value.
+func (c *TopicLintCondition) Validate() error {
+	if c.Config == "" {
+		return fmt.Errorf("config must be set")
+	}
+	switch c.Operator {
+	case TopicLintOperatorEq, TopicLintOperatorNeq, TopicLintOperatorContains, TopicLintOperatorNotContains:
+	case TopicLintOperatorLt, TopicLintOperatorLte, TopicLintOperatorGt, TopicLintOperatorGte:
+		if _, err := strconv.ParseFloat(c.Value, 64); err != nil {
+			return fmt.Errorf("operator '%v' requires a numeric value, but got '%v'", c.Operator, c.Value)
+		}
+	default:
+		return fmt.Errorf("unknown operator '%v'", c.Operator)
+	}
+	return nil
+}
+
+// matches returns true if the given config value satisfies the condition. Numeric
+// comparisons on non-numeric values never match.
+func (c *TopicLintCondition) matches(value string) bool {
+	switch c.Operator {
+	case TopicLintOperatorEq:
+		return value == c.Value
+	case TopicLintOperatorNeq:
+		return value != c.Value
+	case TopicLintOperatorContains:
+		return containsListValue(value, c.Value)
+	case TopicLintOperatorNotContains:
+		return !containsListValue(value, c.Value)
+	}
+
+	actual, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return false
+	}
+	expected, _ := strconv.ParseFloat(c.Value, 64)
+	switch c.Operator {
+	case TopicLintOperatorLt:
+		return actual < expected
+	case TopicLintOperatorLte:
+		return actual <= expected
+	case TopicLintOperatorGt:
+		return actual > expected
+	case TopicLintOperatorGte:
+		return actual >= expected
+	default:
+		return false
+	}
+}
+
+// containsListValue checks whether a comma separated config value such as
+// cleanup.policy=compact,delete contains the given element.
+func containsListValue(value, element string) bool {
+	for _, v := range strings.Split(value, ",") {
+		if strings.TrimSpace(v) == element {
+			return true
+		}
+	}
+	return false
+}
+
+// loadTopicLintRules parses and validates the rule file at the given path, or the
+// embedded default rules if no path is given.
+func loadTopicLintRules(filepath string) ([]TopicLintRule, error) {
+	content := embed.TopicLintRules
+	if filepath != "" {
+		var err error
+		content, err = os.ReadFile(filepath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read topic lint rule file: %w", err)
+		}
+	}
+
+	var file TopicLintRuleFile
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse topic lint rule file: %w", err)
+	}
+
+	ids := make(map[string]struct{}, len(file.Rules))
+	for _, rule := range file.Rules {
+		if err := rule.Validate(); err != nil {
+			return nil, err
+		}
+		if _, exists := ids[rule.ID]; exists {
+			return nil, fmt.Errorf("rule id '%v' is used more than once", rule.ID)
+		}
+		ids[rule.ID] = struct{}{}
+	}
+	return file.Rules, nil
+}
+
+// lintTopicConfig evaluates all rules against the given topic config entries.
+func lintTopicConfig(rules []TopicLintRule, entries []*TopicConfigEntry) []TopicLintFinding {
+	values := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		if entry.Value != nil {
+			values[entry.Name] = *entry.Value
+		}
+	}
+
+	findings := make([]TopicLintFinding, 0)
+	for _, rule := range rules {
+		configs := make(map[string]string, len(rule.Conditions))
+		matches := true
+		for _, c := range rule.Conditions {
+			value, exists := values[c.Config]
+			if !exists || !c.matches(value) {
+				matches = false
+				break
+			}
+			configs[c.Config] = value
+		}
+		if !matches {
+			continue
+		}
+		findings = append(findings, TopicLintFinding{
+			RuleID:      rule.ID,
+			Severity:    rule.Severity,
+			Description: rule.Description,
+			Remediation: rule.Remediation,
+			Configs:     configs,
+		})
+	}
+	return findings
+}
+
+// LintTopicConfig returns the lint findings for the given topic config.
+func (s *Service) LintTopicConfig(cfg *TopicConfig) []TopicLintFinding {
+	if cfg == nil {
+		return make([]TopicLintFinding, 0)
+	}
+	return lintTopicConfig(s.topicLintRules, cfg.ConfigEntries)
+}
+
+// LintTopicsConfigs returns a lint report for each of the given topics.
+func (s *Service) LintTopicsConfigs(ctx context.Context, topicNames []string) ([]TopicLintReport, *rest.Error) {
+	configs, err := s.GetTopicsConfigs(ctx, topicNames, nil)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("failed to describe topic configs: %w", err),
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to describe topic configs: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	reports := make([]TopicLintReport, 0, len(configs))
+	for topicName, cfg := range configs {
+		report := TopicLintReport{TopicName: topicName, Findings: make([]TopicLintFinding, 0), Error: cfg.Error}
+		if cfg.Error == nil {
+			report.Findings = s.LintTopicConfig(cfg)
+		}
+		reports = append(reports, report)
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].TopicName < reports[j].TopicName })
+
+	return reports, nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLintTestEntries(configs map[string]string) []*TopicConfigEntry {
+	entries := make([]*TopicConfigEntry, 0, len(configs))
+	for name, value := range configs {
+		value := value
+		entries = append(entries, &TopicConfigEntry{Name: name, Value: &value})
+	}
+	return entries
+}
+
+func lintRuleIDs(findings []TopicLintFinding) []string {
+	ids := make([]string, len(findings))
+	for i, f := range findings {
+		ids[i] = f.RuleID
+	}
+	return ids
+}
+
+func TestLoadDefaultTopicLintRules(t *testing.T) {
+	rules, err := loadTopicLintRules("")
+	require.NoError(t, err)
+	assert.NotEmpty(t, rules)
+}
+
+func TestLintTopicConfig(t *testing.T) {
+	rules, err := loadTopicLintRules("")
+	require.NoError(t, err)
+
+	t.Run("risky topic", func(t *testing.T) {
+		entries := newLintTestEntries(map[string]string{
+			"retention.ms":                   "-1",
+			"retention.bytes":                "-1",
+			"cleanup.policy":                 "delete",
+			"unclean.leader.election.enable": "true",
+			"segment.bytes":                  "1048576",
+			"segment.ms":                     "604800000",
+			"max.message.bytes":              "10485760",
+		})
+
+		findings := lintTopicConfig(rules, entries)
+
+		assert.Equal(t, []string{
+			"UNBOUNDED_RETENTION_WITHOUT_COMPACTION",
+			"UNCLEAN_LEADER_ELECTION_ENABLED",
+			"SMALL_SEGMENT_BYTES",
+			"LARGE_MAX_MESSAGE_BYTES",
+		}, lintRuleIDs(findings))
+		assert.Equal(t, map[string]string{"retention.ms": "-1", "retention.bytes": "-1", "cleanup.policy": "delete"}, findings[0].Configs)
+	})
+
+	t.Run("compacted topic with broker defaults", func(t *testing.T) {
+		entries := newLintTestEntries(map[string]string{
+			"retention.ms":                   "-1",
+			"retention.bytes":                "-1",
+			"cleanup.policy":                 "compact,delete",
+			"unclean.leader.election.enable": "false",
+			"segment.bytes":                  "1073741824",
+			"segment.ms":                     "604800000",
+			"max.message.bytes":              "1048588",
+		})
+
+		assert.Empty(t, lintTopicConfig(rules, entries))
+	})
+
+	t.Run("missing and sensitive configs never match", func(t *testing.T) {
+		entries := []*TopicConfigEntry{{Name: "segment.bytes", Value: nil}}
+		assert.Empty(t, lintTopicConfig(rules, entries))
+	})
+}
+
+func TestLoadTopicLintRulesFromFile(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid.yaml")
+	require.NoError(t, os.WriteFile(valid, []byte(`
+rules:
+  - id: LOW_MIN_INSYNC_REPLICAS
+    severity: INFO
+    description: A single replica acknowledges writes
+    remediation: Increase min.insync.replicas
+    conditions:
+      - config: min.insync.replicas
+        operator: lte
+        value: "1"
+`), 0o600))
+	rules, err := loadTopicLintRules(valid)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.Equal(t, HealthSeverityInfo, rules[0].Severity)
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	require.NoError(t, os.WriteFile(invalid, []byte(`
+rules:
+  - id: BROKEN
+    severity: WARNING
+    conditions:
+      - config: segment.bytes
+        operator: lt
+        value: small
+`), 0o600))
+	_, err = loadTopicLintRules(invalid)
+	assert.ErrorContains(t, err, "requires a numeric value")
+
+	unknownSeverity := filepath.Join(dir, "severity.yaml")
+	require.NoError(t, os.WriteFile(unknownSeverity, []byte(`
+rules:
+  - id: BROKEN
+    severity: FATAL
+    conditions:
+      - config: segment.bytes
+        operator: eq
+        value: "1"
+`), 0o600))
+	_, err = loadTopicLintRules(unknownSeverity)
+	assert.ErrorContains(t, err, "invalid severity")
+}
@@ -47,6 +47,9 @@ type TopicSummary struct {
 	Documentation     DocumentationState `json:"documentation"`
 	LogDirSummary     TopicLogDirSummary `json:"logDirSummary"`
 
+	// NonPreferredLeaderCount is the number of partitions whose leader is not the preferred replica.
+	NonPreferredLeaderCount int `json:"nonPreferredLeaderCount"`
+
 	// What actions the logged in user is allowed to run on this topic
 	AllowedActions []string `json:"allowedActions"`
 }
@@ -111,6 +114,13 @@ func (s *Service) GetTopicsOverview(ctx context.Context) ([]*TopicSummary, error
 			}
 		}
 
+		nonPreferredLeaders := 0
+		for _, partition := range topic.Partitions {
+			if partition.ErrorCode == 0 && !isPreferredLeader(partition.Replicas, partition.Leader) {
+				nonPreferredLeaders++
+			}
+		}
+
 		res[i] = &TopicSummary{
 			TopicName:         topicName,
 			IsInternal:        topic.IsInternal,
@@ -119,6 +129,8 @@ func (s *Service) GetTopicsOverview(ctx context.Context) ([]*TopicSummary, error
 			CleanupPolicy:     policy,
 			LogDirSummary:     logDirsByTopic[topicName],
 			Documentation:     docState,
+
+			NonPreferredLeaderCount: nonPreferredLeaders,
 		}
 	}
 
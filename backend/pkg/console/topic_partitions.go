@@ -32,6 +32,10 @@ type TopicDetails struct {
 	// Partitions is an array of all the available partition details. If there's an error on the topic level this
 	// array will be nil.
 	Partitions []TopicPartitionDetails `json:"partitions"`
+
+	// NonPreferredLeaderCount is the number of partitions whose leader is not the preferred replica. A preferred
+	// leader election can be triggered to rebalance the leadership.
+	NonPreferredLeaderCount int `json:"nonPreferredLeaderCount"`
 }
 
 // TopicPartitionDetails consists of some (not all) information about a single partition of a topic.
@@ -68,6 +72,12 @@ type TopicPartitionMetadata struct {
 
 	// Leader is the broker leader for this partition. This will be -1 on leader / listener error.
 	Leader int32 `json:"leader"`
+
+	// PreferredLeader is the first broker ID in the list of replicas. This will be -1 if there are no replicas.
+	PreferredLeader int32 `json:"preferredLeader"`
+
+	// IsPreferredLeader is true if the current leader is the preferred leader.
+	IsPreferredLeader bool `json:"isPreferredLeader"`
 }
 
 // TopicPartitionMarks contains information about the offsets for a partition.
@@ -162,6 +172,7 @@ func (s *Service) GetTopicDetails(ctx context.Context, topicNames []string) ([]T
 		// Construct partition details
 		topicMarks := waterMarks[topic.TopicName]
 		partitionsDetails := make([]TopicPartitionDetails, len(topic.Partitions))
+		nonPreferredLeaders := 0
 		for i, partition := range topic.Partitions {
 			partitionMarks := topicMarks[partition.ID]
 
@@ -172,12 +183,14 @@ func (s *Service) GetTopicDetails(ctx context.Context, topicNames []string) ([]T
 
 			d := TopicPartitionDetails{
 				TopicPartitionMetadata: &TopicPartitionMetadata{
-					ID:              partition.ID,
-					PartitionError:  partition.PartitionError,
-					Replicas:        partition.Replicas,
-					OfflineReplicas: partition.OfflineReplicas,
-					InSyncReplicas:  partition.InSyncReplicas,
-					Leader:          partition.Leader,
+					ID:                partition.ID,
+					PartitionError:    partition.PartitionError,
+					Replicas:          partition.Replicas,
+					OfflineReplicas:   partition.OfflineReplicas,
+					InSyncReplicas:    partition.InSyncReplicas,
+					Leader:            partition.Leader,
+					PreferredLeader:   partition.PreferredLeader,
+					IsPreferredLeader: partition.IsPreferredLeader,
 				},
 				TopicPartitionMarks: &TopicPartitionMarks{
 					PartitionID:     partitionMarks.PartitionID,
@@ -188,8 +201,12 @@ func (s *Service) GetTopicDetails(ctx context.Context, topicNames []string) ([]T
 				PartitionLogDirs: logDirs,
 			}
 			partitionsDetails[i] = d
+			if partition.PartitionError == "" && !partition.IsPreferredLeader {
+				nonPreferredLeaders++
+			}
 		}
 		details.Partitions = partitionsDetails
+		details.NonPreferredLeaderCount = nonPreferredLeaders
 		topicsDetails = append(topicsDetails, details)
 	}
 
@@ -227,7 +244,8 @@ func (s *Service) getTopicPartitionMetadata(ctx context.Context, topicNames []st
 		partitionInfo := make([]TopicPartitionDetails, len(topic.Partitions))
 		for i, partition := range topic.Partitions {
 			metadata := TopicPartitionMetadata{
-				ID: partition.Partition,
+				ID:              partition.Partition,
+				PreferredLeader: -1,
 			}
 			err := kerr.TypedErrorForCode(partition.ErrorCode)
 			if err != nil {
@@ -248,6 +266,10 @@ func (s *Service) getTopicPartitionMetadata(ctx context.Context, topicNames []st
 			metadata.Replicas = partition.Replicas
 			metadata.Leader = partition.Leader
 			metadata.OfflineReplicas = partition.OfflineReplicas
+			if len(partition.Replicas) > 0 {
+				metadata.PreferredLeader = partition.Replicas[0]
+			}
+			metadata.IsPreferredLeader = isPreferredLeader(partition.Replicas, partition.Leader)
 			partitionInfo[i] = TopicPartitionDetails{
 				&metadata,
 				&TopicPartitionMarks{},
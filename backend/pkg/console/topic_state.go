@@ -0,0 +1,593 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"gopkg.in/yaml.v3"
+
+	"github.com/redpanda-data/console/backend/pkg/config"
+	"github.com/redpanda-data/console/backend/pkg/filesystem"
+	"github.com/redpanda-data/console/backend/pkg/git"
+)
+
+// ErrTopicStateNotEnabled is returned if declarative topic management is used, but not enabled.
+var ErrTopicStateNotEnabled = errors.New("topic state is not enabled")
+
+// Types of actions in a topic state plan.
+const (
+	TopicStateActionCreateTopic   = "CREATE_TOPIC"
+	TopicStateActionAddPartitions = "ADD_PARTITIONS"
+	TopicStateActionAlterConfigs  = "ALTER_CONFIGS"
+	TopicStateActionCreateACL     = "CREATE_ACL"
+	TopicStateActionDeleteACL     = "DELETE_ACL"
+	TopicStateActionDeleteTopic   = "DELETE_TOPIC"
+	// TopicStateActionUndeclaredTopic flags topics that exist in the cluster, but are neither
+	// declared nor listed as deleted in the repository. They are never deleted.
+	TopicStateActionUndeclaredTopic = "UNDECLARED_TOPIC"
+	// TopicStateActionUnsupported flags drift that can't be resolved via the Kafka API,
+	// such as fewer partitions or a different replication factor.
+	TopicStateActionUnsupported = "UNSUPPORTED"
+)
+
+// topicStateActionOrder is the order in which actions of a plan are applied.
+var topicStateActionOrder = map[string]int{
+	TopicStateActionCreateTopic:     0,
+	TopicStateActionAddPartitions:   1,
+	TopicStateActionAlterConfigs:    2,
+	TopicStateActionCreateACL:       3,
+	TopicStateActionDeleteACL:       4,
+	TopicStateActionDeleteTopic:     5,
+	TopicStateActionUndeclaredTopic: 6,
+	TopicStateActionUnsupported:     7,
+}
+
+// TopicStateFile is a desired-state YAML file in the topic state repository.
+type TopicStateFile struct {
+	Topics []DesiredTopic `yaml:"topics"`
+
+	// DeletedTopics are tombstones for topics that shall be deleted. Topics are only deleted
+	// if they are listed here, so that an empty or broken checkout never deletes topics.
+	DeletedTopics []string `yaml:"deletedTopics"`
+}
+
+// DesiredTopic is the desired state of a single topic.
+type DesiredTopic struct {
+	Name              string            `yaml:"name" json:"name"`
+	Partitions        int32             `yaml:"partitions" json:"partitions"`
+	ReplicationFactor int16             `yaml:"replicationFactor" json:"replicationFactor"`
+	Configs           map[string]string `yaml:"configs" json:"configs"`
+
+	// ACLs are literal ACLs on this topic. They are only managed if set, an empty
+	// list deletes all literal ACLs on this topic.
+	ACLs []DesiredTopicACL `yaml:"acls" json:"acls"`
+}
+
+// DesiredTopicACL is an ACL on the topic it is declared on.
+type DesiredTopicACL struct {
+	Principal      string                 `yaml:"principal" json:"principal"`
+	Host           string                 `yaml:"host" json:"host"`
+	Operation      kmsg.ACLOperation      `yaml:"operation" json:"operation"`
+	PermissionType kmsg.ACLPermissionType `yaml:"permissionType" json:"permissionType"`
+}
+
+// Validate the desired topic and its ACLs.
+func (t *DesiredTopic) Validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("topic name must be set")
+	}
+	if t.Partitions <= 0 {
+		return fmt.Errorf("topic '%v' must have at least one partition", t.Name)
+	}
+	if t.ReplicationFactor <= 0 {
+		return fmt.Errorf("topic '%v' must have a replication factor of at least 1", t.Name)
+	}
+	for _, binding := range t.aclBindings() {
+		binding := binding
+		if err := binding.Validate(); err != nil {
+			return fmt.Errorf("topic '%v' has an invalid acl (%v): %w", t.Name, binding.String(), err)
+		}
+	}
+	return nil
+}
+
+// aclBindings converts the topic's ACLs into bindings. Host defaults to "*" and the
+// permission type to ALLOW.
+func (t *DesiredTopic) aclBindings() []ACLBinding {
+	bindings := make([]ACLBinding, len(t.ACLs))
+	for i, acl := range t.ACLs {
+		host := acl.Host
+		if host == "" {
+			host = "*"
+		}
+		permissionType := acl.PermissionType
+		if permissionType == kmsg.ACLPermissionTypeUnknown {
+			permissionType = kmsg.ACLPermissionTypeAllow
+		}
+		bindings[i] = ACLBinding{
+			ResourceType:        kmsg.ACLResourceTypeTopic,
+			ResourceName:        t.Name,
+			ResourcePatternType: kmsg.ACLResourcePatternTypeLiteral,
+			Principal:           acl.Principal,
+			Host:                host,
+			Operation:           acl.Operation,
+			PermissionType:      permissionType,
+		}
+	}
+	return bindings
+}
+
+// TopicStatePlan contains all actions that are required so that the cluster matches the
+// desired state in the repository.
+type TopicStatePlan struct {
+	// ID identifies the plan's actions. It must be passed when applying the plan, so that
+	// only the reviewed actions are applied.
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	// InSync is true if the cluster matches the desired state.
+	InSync  bool               `json:"inSync"`
+	Actions []TopicStateAction `json:"actions"`
+
+	// Errors describes invalid desired-state files. Plans with errors can't be applied.
+	Errors []string `json:"errors"`
+}
+
+// TopicStateAction is a single change of a topic state plan.
+type TopicStateAction struct {
+	Type      string `json:"type"`
+	TopicName string `json:"topicName"`
+
+	// WillApply is false for actions that are only flagged, such as deletions when they
+	// are not allowed or unsupported changes.
+	WillApply   bool   `json:"willApply"`
+	Description string `json:"description"`
+
+	Partitions        int32                    `json:"partitions,omitempty"`
+	ReplicationFactor int16                    `json:"replicationFactor,omitempty"`
+	ConfigChanges     []TopicStateConfigChange `json:"configChanges,omitempty"`
+	ACL               *ACLBinding              `json:"acl,omitempty"`
+}
+
+// TopicStateConfigChange is the change of a single topic config.
+type TopicStateConfigChange struct {
+	Name    string                        `json:"name"`
+	Op      kmsg.IncrementalAlterConfigOp `json:"op"`
+	Current *string                       `json:"current"`
+	Desired *string                       `json:"desired"`
+}
+
+// liveTopicState is the state of a topic in the cluster that is compared with the desired state.
+type liveTopicState struct {
+	Partitions        int32
+	ReplicationFactor int16
+
+	// Configs contains all configs that are explicitly set on the topic.
+	Configs map[string]string
+}
+
+type topicStatePlanOptions struct {
+	AllowDeletions bool
+	IsIgnored      func(topicName string) bool
+
+	// MaxDeletions is the number of topics that may be deleted, unless OverrideDeletionLimit is set.
+	MaxDeletions          int
+	OverrideDeletionLimit bool
+}
+
+// topicStateManager holds the git repository with the desired state and the audit log
+// of applied plans.
+type topicStateManager struct {
+	cfg     config.ConsoleTopicState
+	gitSvc  *git.Service
+	ignored []*regexp.Regexp
+
+	auditLogMutex sync.Mutex
+	auditLog      []TopicStateAuditLogEntry
+}
+
+func newTopicStateManager(cfg config.ConsoleTopicState, gitSvc *git.Service) (*topicStateManager, error) {
+	ignored, err := config.CompileRegexes(cfg.IgnoredTopics)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile ignored topics: %w", err)
+	}
+	return &topicStateManager{
+		cfg:      cfg,
+		gitSvc:   gitSvc,
+		ignored:  ignored,
+		auditLog: make([]TopicStateAuditLogEntry, 0),
+	}, nil
+}
+
+func (m *topicStateManager) isIgnored(topicName string) bool {
+	for _, regex := range m.ignored {
+		if regex.MatchString(topicName) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTopicStateFiles parses all desired-state files and returns the declared topics and the
+// names of deleted topics. Invalid files and topics that are declared more than once are
+// reported as errors.
+//
+//nolint:gocognit,cyclop // Every declaration is checked against all previous ones
+func parseTopicStateFiles(files map[string]filesystem.File, isIgnored func(string) bool) ([]DesiredTopic, []string, []string) {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	topics := make([]DesiredTopic, 0)
+	deleted := make([]string, 0)
+	declaredIn := make(map[string]string)
+	deletedIn := make(map[string]string)
+	errs := make([]string, 0)
+	for _, path := range paths {
+		var file TopicStateFile
+		if err := yaml.Unmarshal(files[path].Payload, &file); err != nil {
+			errs = append(errs, fmt.Sprintf("%v: failed to parse file: %v", path, err.Error()))
+			continue
+		}
+		for _, topic := range file.Topics {
+			if err := topic.Validate(); err != nil {
+				errs = append(errs, fmt.Sprintf("%v: %v", path, err.Error()))
+				continue
+			}
+			if isIgnored(topic.Name) {
+				errs = append(errs, fmt.Sprintf("%v: topic '%v' is ignored by the topic state config", path, topic.Name))
+				continue
+			}
+			if otherPath, exists := declaredIn[topic.Name]; exists {
+				errs = append(errs, fmt.Sprintf("%v: topic '%v' is already declared in %v", path, topic.Name, otherPath))
+				continue
+			}
+			declaredIn[topic.Name] = path
+			topics = append(topics, topic)
+		}
+		for _, topicName := range file.DeletedTopics {
+			switch {
+			case topicName == "":
+				errs = append(errs, fmt.Sprintf("%v: deleted topic name must be set", path))
+				continue
+			case isIgnored(topicName):
+				errs = append(errs, fmt.Sprintf("%v: deleted topic '%v' is ignored by the topic state config", path, topicName))
+				continue
+			}
+			if otherPath, exists := deletedIn[topicName]; exists {
+				errs = append(errs, fmt.Sprintf("%v: topic '%v' is already listed as deleted in %v", path, topicName, otherPath))
+				continue
+			}
+			deletedIn[topicName] = path
+			deleted = append(deleted, topicName)
+		}
+	}
+
+	// A topic can't be desired and deleted at the same time, regardless of the file order
+	for _, topicName := range deleted {
+		if declaredPath, exists := declaredIn[topicName]; exists {
+			errs = append(errs, fmt.Sprintf("%v: topic '%v' is listed as deleted, but declared in %v", deletedIn[topicName], topicName, declaredPath))
+		}
+	}
+	return topics, deleted, errs
+}
+
+// planTopicState compares the desired with the live state and returns all actions that are
+// required to resolve the drift. Only topics that are listed as deleted may be deleted.
+//
+//nolint:gocognit,cyclop // Plan is computed in a single pass so that the order is deterministic
+func planTopicState(desired []DesiredTopic, deleted []string, live map[string]liveTopicState, liveACLs []ACLBinding, opts topicStatePlanOptions) []TopicStateAction {
+	actions := make([]TopicStateAction, 0)
+
+	declared := make(map[string]struct{}, len(desired))
+	for _, topic := range desired {
+		declared[topic.Name] = struct{}{}
+		current, exists := live[topic.Name]
+
+		if !exists {
+			configChanges := make([]TopicStateConfigChange, 0, len(topic.Configs))
+			for _, name := range sortedKeys(topic.Configs) {
+				value := topic.Configs[name]
+				configChanges = append(configChanges, TopicStateConfigChange{Name: name, Op: kmsg.IncrementalAlterConfigOpSet, Desired: &value})
+			}
+			actions = append(actions, TopicStateAction{
+				Type:              TopicStateActionCreateTopic,
+				TopicName:         topic.Name,
+				WillApply:         true,
+				Description:       fmt.Sprintf("Create topic with %d partitions and replication factor %d", topic.Partitions, topic.ReplicationFactor),
+				Partitions:        topic.Partitions,
+				ReplicationFactor: topic.ReplicationFactor,
+				ConfigChanges:     configChanges,
+			})
+		} else {
+			switch {
+			case topic.Partitions > current.Partitions:
+				actions = append(actions, TopicStateAction{
+					Type:        TopicStateActionAddPartitions,
+					TopicName:   topic.Name,
+					WillApply:   true,
+					Description: fmt.Sprintf("Increase partition count from %d to %d", current.Partitions, topic.Partitions),
+					Partitions:  topic.Partitions,
+				})
+			case topic.Partitions < current.Partitions:
+				actions = append(actions, TopicStateAction{
+					Type:        TopicStateActionUnsupported,
+					TopicName:   topic.Name,
+					Description: fmt.Sprintf("Topic has %d partitions, but %d are desired. The partition count can't be decreased", current.Partitions, topic.Partitions),
+					Partitions:  topic.Partitions,
+				})
+			}
+			if topic.ReplicationFactor != current.ReplicationFactor {
+				actions = append(actions, TopicStateAction{
+					Type:      TopicStateActionUnsupported,
+					TopicName: topic.Name,
+					Description: fmt.Sprintf("Topic has a replication factor of %d, but %d is desired. Use a partition reassignment to change the replication factor",
+						current.ReplicationFactor, topic.ReplicationFactor),
+					ReplicationFactor: topic.ReplicationFactor,
+				})
+			}
+
+			if changes := diffTopicStateConfigs(current.Configs, topic.Configs); len(changes) > 0 {
+				actions = append(actions, TopicStateAction{
+					Type:          TopicStateActionAlterConfigs,
+					TopicName:     topic.Name,
+					WillApply:     true,
+					Description:   fmt.Sprintf("Change %d configs", len(changes)),
+					ConfigChanges: changes,
+				})
+			}
+		}
+
+		// ACLs are only managed if they are declared for this topic
+		if topic.ACLs == nil {
+			continue
+		}
+		currentACLs := make([]ACLBinding, 0)
+		for _, binding := range liveACLs {
+			if binding.ResourceType == kmsg.ACLResourceTypeTopic && binding.ResourcePatternType == kmsg.ACLResourcePatternTypeLiteral &&
+				binding.ResourceName == topic.Name {
+				currentACLs = append(currentACLs, binding)
+			}
+		}
+		diff := diffACLBindings(currentACLs, topic.aclBindings(), nil)
+		for i := range diff.Creations {
+			actions = append(actions, TopicStateAction{
+				Type:        TopicStateActionCreateACL,
+				TopicName:   topic.Name,
+				WillApply:   true,
+				Description: fmt.Sprintf("Create ACL %v", diff.Creations[i].String()),
+				ACL:         &diff.Creations[i],
+			})
+		}
+		for i := range diff.Deletions {
+			actions = append(actions, TopicStateAction{
+				Type:        TopicStateActionDeleteACL,
+				TopicName:   topic.Name,
+				WillApply:   true,
+				Description: fmt.Sprintf("Delete ACL %v", diff.Deletions[i].String()),
+				ACL:         &diff.Deletions[i],
+			})
+		}
+	}
+
+	actions = append(actions, planTopicStateDeletions(len(desired), deleted, live, opts)...)
+
+	isDeleted := make(map[string]struct{}, len(deleted))
+	for _, topicName := range deleted {
+		isDeleted[topicName] = struct{}{}
+	}
+	for _, topicName := range sortedKeys(live) {
+		_, isDeclared := declared[topicName]
+		_, isTombstoned := isDeleted[topicName]
+		if isDeclared || isTombstoned || opts.IsIgnored(topicName) {
+			continue
+		}
+		actions = append(actions, TopicStateAction{
+			Type:        TopicStateActionUndeclaredTopic,
+			TopicName:   topicName,
+			Description: "Topic is not declared in the repository. List it in deletedTopics to delete it",
+		})
+	}
+
+	sort.SliceStable(actions, func(i, j int) bool {
+		return topicStateActionOrder[actions[i].Type] < topicStateActionOrder[actions[j].Type]
+	})
+	return actions
+}
+
+// planTopicStateDeletions returns a deletion for each deleted topic that still exists. Deletions
+// are only applied if they are allowed, at least one topic is declared and the number of
+// deletions does not exceed the limit.
+func planTopicStateDeletions(declaredCount int, deleted []string, live map[string]liveTopicState, opts topicStatePlanOptions) []TopicStateAction {
+	topicNames := make([]string, 0, len(deleted))
+	for _, topicName := range deleted {
+		if _, exists := live[topicName]; exists && !opts.IsIgnored(topicName) {
+			topicNames = append(topicNames, topicName)
+		}
+	}
+	sort.Strings(topicNames)
+
+	willApply := true
+	description := "Topic is listed as deleted in the repository and will be deleted"
+	switch {
+	case !opts.AllowDeletions:
+		willApply = false
+		description = "Topic is listed as deleted in the repository. Deletions are disabled in the topic state config"
+	case declaredCount == 0:
+		// An empty checkout would otherwise only consist of deletions
+		willApply = false
+		description = "Topic is listed as deleted in the repository. Deletions are refused, because no topics are declared"
+	case len(topicNames) > opts.MaxDeletions && !opts.OverrideDeletionLimit:
+		willApply = false
+		description = fmt.Sprintf("Topic is listed as deleted in the repository. The plan deletes %d topics, which exceeds the limit of %d deletions per plan",
+			len(topicNames), opts.MaxDeletions)
+	}
+
+	actions := make([]TopicStateAction, len(topicNames))
+	for i, topicName := range topicNames {
+		actions[i] = TopicStateAction{
+			Type:        TopicStateActionDeleteTopic,
+			TopicName:   topicName,
+			WillApply:   willApply,
+			Description: description,
+		}
+	}
+	return actions
+}
+
+// diffTopicStateConfigs returns the config changes so that the explicitly set configs match
+// the desired configs. Configs that are not desired are deleted, so that they fall back to
+// the broker default.
+func diffTopicStateConfigs(current map[string]string, desired map[string]string) []TopicStateConfigChange {
+	changes := make([]TopicStateConfigChange, 0)
+	for _, name := range sortedKeys(desired) {
+		desiredValue := desired[name]
+		currentValue, exists := current[name]
+		if exists && currentValue == desiredValue {
+			continue
+		}
+		change := TopicStateConfigChange{Name: name, Op: kmsg.IncrementalAlterConfigOpSet, Desired: &desiredValue}
+		if exists {
+			change.Current = &currentValue
+		}
+		changes = append(changes, change)
+	}
+	for _, name := range sortedKeys(current) {
+		if _, isDesired := desired[name]; isDesired {
+			continue
+		}
+		currentValue := current[name]
+		changes = append(changes, TopicStateConfigChange{Name: name, Op: kmsg.IncrementalAlterConfigOpDelete, Current: &currentValue})
+	}
+	return changes
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// topicStatePlanID hashes the plan's actions, so that a plan can only be applied if the
+// actions didn't change since it has been reviewed.
+func topicStatePlanID(actions []TopicStateAction) string {
+	// Marshalling the actions can't fail, all fields are serializable
+	payload, _ := json.Marshal(actions)
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// PlanTopicState compares the desired topic state from the git repository with the cluster
+// and returns the actions that are required to resolve the drift. Deletions beyond the
+// configured limit are only applied if overrideDeletionLimit is set.
+func (s *Service) PlanTopicState(ctx context.Context, overrideDeletionLimit bool) (*TopicStatePlan, *rest.Error) {
+	if s.topicState == nil {
+		return nil, &rest.Error{
+			Err:      ErrTopicStateNotEnabled,
+			Status:   http.StatusNotFound,
+			Message:  "Declarative topic management is not enabled",
+			IsSilent: false,
+		}
+	}
+
+	desired, deleted, errs := parseTopicStateFiles(s.topicState.gitSvc.GetFilesByFilename(), s.topicState.isIgnored)
+
+	live, restErr := s.liveTopicStates(ctx)
+	if restErr != nil {
+		return nil, restErr
+	}
+	liveACLs, restErr := s.listACLBindings(ctx)
+	if restErr != nil {
+		return nil, restErr
+	}
+
+	actions := planTopicState(desired, deleted, live, liveACLs, topicStatePlanOptions{
+		AllowDeletions:        s.topicState.cfg.AllowDeletions,
+		IsIgnored:             s.topicState.isIgnored,
+		MaxDeletions:          s.topicState.cfg.MaxDeletionsPerPlan,
+		OverrideDeletionLimit: overrideDeletionLimit,
+	})
+	return &TopicStatePlan{
+		ID:        topicStatePlanID(actions),
+		CreatedAt: time.Now(),
+		InSync:    len(actions) == 0,
+		Actions:   actions,
+		Errors:    errs,
+	}, nil
+}
+
+// liveTopicStates returns the partition count, replication factor and explicitly set configs
+// of all topics.
+func (s *Service) liveTopicStates(ctx context.Context) (map[string]liveTopicState, *rest.Error) {
+	metadata, err := s.kafkaSvc.KafkaAdmClient.Metadata(ctx)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("failed to request metadata: %w", err),
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to request metadata: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	topicNames := metadata.Topics.Names()
+	configs, err := s.GetTopicsConfigs(ctx, topicNames, nil)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("failed to describe topic configs: %w", err),
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to describe topic configs: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	live := make(map[string]liveTopicState, len(topicNames))
+	for _, topicName := range topicNames {
+		topic := metadata.Topics[topicName]
+		state := liveTopicState{
+			Partitions:        int32(len(topic.Partitions)),
+			ReplicationFactor: int16(replicationFactor(topic)),
+			Configs:           make(map[string]string),
+		}
+		cfg, exists := configs[topicName]
+		if !exists || cfg.Error != nil {
+			return nil, &rest.Error{
+				Err:      fmt.Errorf("failed to describe config of topic '%v'", topicName),
+				Status:   http.StatusServiceUnavailable,
+				Message:  fmt.Sprintf("Failed to describe config of topic '%v', the topic state can't be compared", topicName),
+				IsSilent: false,
+			}
+		}
+		for _, entry := range cfg.ConfigEntries {
+			if entry.IsExplicitlySet && entry.Value != nil {
+				state.Configs[entry.Name] = *entry.Value
+			}
+		}
+		live[topicName] = state
+	}
+	return live, nil
+}
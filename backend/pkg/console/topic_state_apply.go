@@ -0,0 +1,228 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"go.uber.org/zap"
+)
+
+// topicStateAuditLogMaxEntries is the number of applied plans that are kept in memory.
+const topicStateAuditLogMaxEntries = 1000
+
+// TopicStateAuditLogEntry records an applied topic state plan along with the result of
+// each action.
+type TopicStateAuditLogEntry struct {
+	Time   time.Time `json:"time"`
+	PlanID string    `json:"planId"`
+
+	// Requester is the authenticated user that applied the plan, empty if unknown.
+	Requester             string                   `json:"requester"`
+	Comment               string                   `json:"comment"`
+	OverrideDeletionLimit bool                     `json:"overrideDeletionLimit"`
+	Results               []TopicStateActionResult `json:"results"`
+}
+
+// ApplyTopicStateRequest identifies the reviewed plan that shall be applied.
+type ApplyTopicStateRequest struct {
+	PlanID  string
+	Comment string
+
+	// Requester is recorded in the audit log.
+	Requester string
+
+	// OverrideDeletionLimit must match the value the plan has been reviewed with.
+	OverrideDeletionLimit bool
+}
+
+// TopicStateActionResult is the outcome of applying a single action.
+type TopicStateActionResult struct {
+	Action TopicStateAction `json:"action"`
+	Error  string           `json:"error,omitempty"`
+}
+
+// ApplyTopicState applies the current topic state plan, if its ID matches the given plan ID.
+// This guarantees that only the actions that have been reviewed and approved are applied.
+// Actions that fail don't stop the remaining actions. Each applied plan is recorded in the
+// audit log.
+func (s *Service) ApplyTopicState(ctx context.Context, req ApplyTopicStateRequest) (*TopicStateAuditLogEntry, *rest.Error) {
+	plan, restErr := s.PlanTopicState(ctx, req.OverrideDeletionLimit)
+	if restErr != nil {
+		return nil, restErr
+	}
+	if !s.topicState.cfg.ApplyEnabled {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("applying topic state plans is disabled"),
+			Status:   http.StatusForbidden,
+			Message:  "Applying topic state plans is disabled in the Console config",
+			IsSilent: false,
+		}
+	}
+	if len(plan.Errors) > 0 {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("topic state repository has %d errors", len(plan.Errors)),
+			Status:   http.StatusBadRequest,
+			Message:  fmt.Sprintf("The topic state repository contains invalid files, the plan can't be applied: %v", plan.Errors[0]),
+			IsSilent: false,
+		}
+	}
+	if plan.ID != req.PlanID {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("plan id '%v' does not match current plan id '%v'", req.PlanID, plan.ID),
+			Status:   http.StatusConflict,
+			Message:  "The plan has changed since it has been reviewed. Review the current plan and apply it again",
+			IsSilent: false,
+		}
+	}
+
+	entry := TopicStateAuditLogEntry{
+		Time:                  time.Now(),
+		PlanID:                plan.ID,
+		Requester:             req.Requester,
+		Comment:               req.Comment,
+		OverrideDeletionLimit: req.OverrideDeletionLimit,
+		Results:               make([]TopicStateActionResult, 0, len(plan.Actions)),
+	}
+	for _, action := range plan.Actions {
+		if !action.WillApply {
+			continue
+		}
+		result := TopicStateActionResult{Action: action}
+		if err := s.applyTopicStateAction(ctx, action); err != nil {
+			result.Error = err.Error()
+		}
+		entry.Results = append(entry.Results, result)
+	}
+
+	s.topicState.appendAuditLog(entry, s.logger)
+	return &entry, nil
+}
+
+//nolint:cyclop // One case per action type
+func (s *Service) applyTopicStateAction(ctx context.Context, action TopicStateAction) error {
+	switch action.Type {
+	case TopicStateActionCreateTopic:
+		req := kmsg.NewCreateTopicsRequestTopic()
+		req.Topic = action.TopicName
+		req.NumPartitions = action.Partitions
+		req.ReplicationFactor = action.ReplicationFactor
+		for _, change := range action.ConfigChanges {
+			cfg := kmsg.NewCreateTopicsRequestTopicConfig()
+			cfg.Name = change.Name
+			cfg.Value = change.Desired
+			req.Configs = append(req.Configs, cfg)
+		}
+		if _, restErr := s.CreateTopic(ctx, req); restErr != nil {
+			return restErr.Err
+		}
+	case TopicStateActionAddPartitions:
+		req := CreateTopicPartitionsRequest{TopicName: action.TopicName, PartitionCount: action.Partitions}
+		if _, restErr := s.CreateTopicPartitions(ctx, req); restErr != nil {
+			return restErr.Err
+		}
+	case TopicStateActionAlterConfigs:
+		configs := make([]kmsg.IncrementalAlterConfigsRequestResourceConfig, len(action.ConfigChanges))
+		for i, change := range action.ConfigChanges {
+			cfg := kmsg.NewIncrementalAlterConfigsRequestResourceConfig()
+			cfg.Name = change.Name
+			cfg.Op = change.Op
+			cfg.Value = change.Desired
+			configs[i] = cfg
+		}
+		return s.EditTopicConfig(ctx, action.TopicName, configs)
+	case TopicStateActionCreateACL:
+		res := &ACLImportResult{Creations: []ACLBinding{*action.ACL}}
+		if restErr := s.createACLBindings(ctx, res); restErr != nil {
+			return restErr.Err
+		}
+		if len(res.Errors) > 0 {
+			return fmt.Errorf("%v", res.Errors[0].Error)
+		}
+	case TopicStateActionDeleteACL:
+		res := &ACLImportResult{Deletions: []ACLBinding{*action.ACL}}
+		if restErr := s.deleteACLBindings(ctx, res); restErr != nil {
+			return restErr.Err
+		}
+		if len(res.Errors) > 0 {
+			return fmt.Errorf("%v", res.Errors[0].Error)
+		}
+	case TopicStateActionDeleteTopic:
+		if restErr := s.DeleteTopic(ctx, action.TopicName); restErr != nil {
+			return restErr.Err
+		}
+	default:
+		return fmt.Errorf("action type '%v' can't be applied", action.Type)
+	}
+	return nil
+}
+
+// GetTopicStateAuditLog returns all applied plans that are kept in memory, newest first.
+func (s *Service) GetTopicStateAuditLog() ([]TopicStateAuditLogEntry, *rest.Error) {
+	if s.topicState == nil {
+		return nil, &rest.Error{
+			Err:      ErrTopicStateNotEnabled,
+			Status:   http.StatusNotFound,
+			Message:  "Declarative topic management is not enabled",
+			IsSilent: false,
+		}
+	}
+
+	s.topicState.auditLogMutex.Lock()
+	defer s.topicState.auditLogMutex.Unlock()
+
+	entries := make([]TopicStateAuditLogEntry, len(s.topicState.auditLog))
+	for i, entry := range s.topicState.auditLog {
+		entries[len(entries)-1-i] = entry
+	}
+	return entries, nil
+}
+
+// appendAuditLog keeps the entry in memory and appends it to the audit log file if configured.
+// Failing to write the file is logged, because the plan has been applied already.
+func (m *topicStateManager) appendAuditLog(entry TopicStateAuditLogEntry, logger *zap.Logger) {
+	m.auditLogMutex.Lock()
+	defer m.auditLogMutex.Unlock()
+
+	m.auditLog = append(m.auditLog, entry)
+	if len(m.auditLog) > topicStateAuditLogMaxEntries {
+		m.auditLog = m.auditLog[len(m.auditLog)-topicStateAuditLogMaxEntries:]
+	}
+
+	if m.cfg.AuditLogFilepath == "" {
+		return
+	}
+	if err := appendJSONLine(m.cfg.AuditLogFilepath, entry); err != nil {
+		logger.Error("failed to write topic state audit log", zap.String("plan_id", entry.PlanID), zap.Error(err))
+	}
+}
+
+func appendJSONLine(filepath string, v any) error {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
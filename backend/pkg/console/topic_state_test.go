@@ -0,0 +1,208 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kmsg"
+
+	"github.com/redpanda-data/console/backend/pkg/filesystem"
+)
+
+func isInternalTopic(topicName string) bool {
+	return strings.HasPrefix(topicName, "__")
+}
+
+func TestParseTopicStateFiles(t *testing.T) {
+	files := map[string]filesystem.File{
+		"/teams/orders.yaml": {Payload: []byte(`
+topics:
+  - name: orders
+    partitions: 6
+    replicationFactor: 3
+    configs:
+      retention.ms: "604800000"
+    acls:
+      - principal: User:orders-svc
+        operation: WRITE
+`)},
+		"/teams/payments.yaml": {Payload: []byte(`
+topics:
+  - name: orders
+    partitions: 1
+    replicationFactor: 1
+  - name: payments
+    partitions: 0
+    replicationFactor: 3
+  - name: __consumer_offsets
+    partitions: 50
+    replicationFactor: 3
+deletedTopics:
+  - legacy
+  - orders
+  - __transaction_state
+`)},
+		"/teams/retired.yaml": {Payload: []byte(`
+deletedTopics:
+  - legacy
+  - old-payments
+`)},
+		"/broken.yaml": {Payload: []byte("topics: [")},
+	}
+
+	topics, deleted, errs := parseTopicStateFiles(files, isInternalTopic)
+
+	require.Len(t, topics, 1)
+	assert.Equal(t, "orders", topics[0].Name)
+	assert.Equal(t, []ACLBinding{{
+		ResourceType:        kmsg.ACLResourceTypeTopic,
+		ResourceName:        "orders",
+		ResourcePatternType: kmsg.ACLResourcePatternTypeLiteral,
+		Principal:           "User:orders-svc",
+		Host:                "*",
+		Operation:           kmsg.ACLOperationWrite,
+		PermissionType:      kmsg.ACLPermissionTypeAllow,
+	}}, topics[0].aclBindings())
+
+	assert.Equal(t, []string{"legacy", "orders", "old-payments"}, deleted)
+
+	require.Len(t, errs, 7)
+	assert.Contains(t, errs[0], "/broken.yaml: failed to parse file")
+	assert.Contains(t, errs[1], "already declared in /teams/orders.yaml")
+	assert.Contains(t, errs[2], "must have at least one partition")
+	assert.Contains(t, errs[3], "is ignored")
+	assert.Contains(t, errs[4], "deleted topic '__transaction_state' is ignored")
+	assert.Contains(t, errs[5], "/teams/retired.yaml: topic 'legacy' is already listed as deleted in /teams/payments.yaml")
+	assert.Contains(t, errs[6], "topic 'orders' is listed as deleted, but declared in /teams/orders.yaml")
+}
+
+func TestPlanTopicState(t *testing.T) {
+	desired := []DesiredTopic{
+		{
+			Name: "orders", Partitions: 6, ReplicationFactor: 3,
+			Configs: map[string]string{"retention.ms": "604800000", "cleanup.policy": "delete"},
+			ACLs:    []DesiredTopicACL{{Principal: "User:orders-svc", Operation: kmsg.ACLOperationWrite}},
+		},
+		{Name: "payments", Partitions: 2, ReplicationFactor: 1},
+		{Name: "new", Partitions: 3, ReplicationFactor: 3, Configs: map[string]string{"cleanup.policy": "compact"}},
+	}
+	live := map[string]liveTopicState{
+		"orders":             {Partitions: 3, ReplicationFactor: 3, Configs: map[string]string{"retention.ms": "1000", "segment.ms": "60000", "cleanup.policy": "delete"}},
+		"payments":           {Partitions: 4, ReplicationFactor: 3, Configs: map[string]string{}},
+		"legacy":             {Partitions: 1, ReplicationFactor: 1, Configs: map[string]string{}},
+		"unmanaged":          {Partitions: 1, ReplicationFactor: 1, Configs: map[string]string{}},
+		"__consumer_offsets": {Partitions: 50, ReplicationFactor: 3, Configs: map[string]string{}},
+	}
+	liveACLs := []ACLBinding{{
+		ResourceType:        kmsg.ACLResourceTypeTopic,
+		ResourceName:        "orders",
+		ResourcePatternType: kmsg.ACLResourcePatternTypeLiteral,
+		Principal:           "User:legacy-svc",
+		Host:                "*",
+		Operation:           kmsg.ACLOperationRead,
+		PermissionType:      kmsg.ACLPermissionTypeAllow,
+	}}
+
+	deleted := []string{"legacy", "already-deleted"}
+	opts := topicStatePlanOptions{IsIgnored: isInternalTopic, MaxDeletions: 5}
+
+	actions := planTopicState(desired, deleted, live, liveACLs, opts)
+
+	types := make([]string, len(actions))
+	for i, a := range actions {
+		types[i] = a.Type + " " + a.TopicName
+	}
+	assert.Equal(t, []string{
+		"CREATE_TOPIC new",
+		"ADD_PARTITIONS orders",
+		"ALTER_CONFIGS orders",
+		"CREATE_ACL orders",
+		"DELETE_ACL orders",
+		"DELETE_TOPIC legacy",
+		"UNDECLARED_TOPIC unmanaged",
+		"UNSUPPORTED payments",
+		"UNSUPPORTED payments",
+	}, types)
+
+	// Deletions are only flagged if not allowed, undeclared topics are never deleted
+	assert.False(t, actions[5].WillApply)
+	assert.False(t, actions[6].WillApply)
+	assert.False(t, actions[7].WillApply)
+	assert.True(t, actions[0].WillApply)
+
+	changes := actions[2].ConfigChanges
+	require.Len(t, changes, 2)
+	assert.Equal(t, "retention.ms", changes[0].Name)
+	assert.Equal(t, kmsg.IncrementalAlterConfigOpSet, changes[0].Op)
+	assert.Equal(t, "1000", *changes[0].Current)
+	assert.Equal(t, "604800000", *changes[0].Desired)
+	assert.Equal(t, "segment.ms", changes[1].Name)
+	assert.Equal(t, kmsg.IncrementalAlterConfigOpDelete, changes[1].Op)
+	assert.Nil(t, changes[1].Desired)
+
+	opts.AllowDeletions = true
+	actions = planTopicState(desired, deleted, live, liveACLs, opts)
+	assert.True(t, actions[5].WillApply)
+	assert.False(t, actions[6].WillApply)
+}
+
+func TestPlanTopicStateDeletionSafeguards(t *testing.T) {
+	desired := []DesiredTopic{{Name: "orders", Partitions: 1, ReplicationFactor: 1}}
+	live := map[string]liveTopicState{
+		"orders": {Partitions: 1, ReplicationFactor: 1, Configs: map[string]string{}},
+		"a":      {Partitions: 1, ReplicationFactor: 1, Configs: map[string]string{}},
+		"b":      {Partitions: 1, ReplicationFactor: 1, Configs: map[string]string{}},
+		"c":      {Partitions: 1, ReplicationFactor: 1, Configs: map[string]string{}},
+	}
+	deleted := []string{"a", "b", "c"}
+	willApply := func(actions []TopicStateAction) []bool {
+		res := make([]bool, 0)
+		for _, action := range actions {
+			if action.Type == TopicStateActionDeleteTopic {
+				res = append(res, action.WillApply)
+			}
+		}
+		return res
+	}
+
+	// Without any declared topics, e.g. due to an empty checkout, nothing is deleted
+	actions := planTopicState(nil, deleted, live, nil, topicStatePlanOptions{IsIgnored: isInternalTopic, AllowDeletions: true, MaxDeletions: 5})
+	assert.Equal(t, []bool{false, false, false}, willApply(actions))
+	assert.Contains(t, actions[0].Description, "no topics are declared")
+
+	// Exceeding the limit requires an explicit override
+	opts := topicStatePlanOptions{IsIgnored: isInternalTopic, AllowDeletions: true, MaxDeletions: 2}
+	actions = planTopicState(desired, deleted, live, nil, opts)
+	assert.Equal(t, []bool{false, false, false}, willApply(actions))
+	assert.Contains(t, actions[0].Description, "exceeds the limit of 2 deletions")
+
+	opts.OverrideDeletionLimit = true
+	overridden := planTopicState(desired, deleted, live, nil, opts)
+	assert.Equal(t, []bool{true, true, true}, willApply(overridden))
+	assert.NotEqual(t, topicStatePlanID(actions), topicStatePlanID(overridden))
+}
+
+func TestPlanTopicStateInSync(t *testing.T) {
+	desired := []DesiredTopic{{Name: "orders", Partitions: 3, ReplicationFactor: 3, Configs: map[string]string{"retention.ms": "1000"}}}
+	live := map[string]liveTopicState{
+		"orders": {Partitions: 3, ReplicationFactor: 3, Configs: map[string]string{"retention.ms": "1000"}},
+	}
+	// ACLs are not managed, because they are not declared
+	liveACLs := []ACLBinding{{ResourceType: kmsg.ACLResourceTypeTopic, ResourceName: "orders", Principal: "User:a"}}
+
+	actions := planTopicState(desired, nil, live, liveACLs, topicStatePlanOptions{IsIgnored: isInternalTopic})
+
+	assert.Empty(t, actions)
+	assert.Equal(t, topicStatePlanID(actions), topicStatePlanID(make([]TopicStateAction, 0)))
+}
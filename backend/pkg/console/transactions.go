@@ -0,0 +1,487 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"go.uber.org/zap"
+)
+
+// TransactionStateOngoing is the state of a transaction that has not yet been committed or aborted.
+const TransactionStateOngoing = "Ongoing"
+
+// DefaultMaxTransactionTimeout is Kafka's default for transaction.max.timeout.ms. Transactions whose
+// producer has not written for longer than this are considered as possibly hanging.
+const DefaultMaxTransactionTimeout = 15 * time.Minute
+
+// TransactionsOverview lists the described transactional IDs.
+type TransactionsOverview struct {
+	Transactions []Transaction `json:"transactions"`
+	// Errors contains errors of brokers that failed to list or describe their transactions.
+	Errors []string `json:"errors"`
+}
+
+// Transaction is the state of a single transactional ID as reported by its coordinator.
+type Transaction struct {
+	TransactionalID string `json:"transactionalId"`
+	State           string `json:"state"`
+	CoordinatorID   int32  `json:"coordinatorId"`
+	ProducerID      int64  `json:"producerId"`
+	ProducerEpoch   int16  `json:"producerEpoch"`
+	TimeoutMs       int32  `json:"timeoutMs"`
+	// StartTimestamp is the unix timestamp in ms when the current transaction started, -1 if there is none.
+	StartTimestamp int64              `json:"startTimestamp"`
+	Topics         []TransactionTopic `json:"topics"`
+	Error          string             `json:"error,omitempty"`
+}
+
+// TransactionTopic contains the partitions of a topic that are part of a transaction.
+type TransactionTopic struct {
+	TopicName  string  `json:"topicName"`
+	Partitions []int32 `json:"partitions"`
+}
+
+// ListTransactions describes all transactional IDs. If states is not empty, only transactional IDs
+// in one of the given states are returned.
+func (s *Service) ListTransactions(ctx context.Context, states []string) (*TransactionsOverview, *rest.Error) {
+	overview := &TransactionsOverview{Transactions: make([]Transaction, 0), Errors: make([]string, 0)}
+
+	listed, err := s.kafkaSvc.KafkaAdmClient.ListTransactions(ctx, nil, states)
+	if restErr := s.transactionsShardErrors(err, "list transactions", overview); restErr != nil {
+		return nil, restErr
+	}
+	if len(listed) == 0 {
+		return overview, nil
+	}
+
+	described, err := s.kafkaSvc.KafkaAdmClient.DescribeTransactions(ctx, listed.TransactionalIDs()...)
+	if restErr := s.transactionsShardErrors(err, "describe transactions", overview); restErr != nil {
+		return nil, restErr
+	}
+	for _, txn := range described.Sorted() {
+		overview.Transactions = append(overview.Transactions, newTransaction(txn))
+	}
+
+	return overview, nil
+}
+
+func newTransaction(txn kadm.DescribedTransaction) Transaction {
+	topics := make([]TransactionTopic, 0, len(txn.Topics))
+	for _, topic := range txn.Topics.Sorted() {
+		topics = append(topics, TransactionTopic{TopicName: topic.Topic, Partitions: topic.Partitions})
+	}
+	return Transaction{
+		TransactionalID: txn.TxnID,
+		State:           txn.State,
+		CoordinatorID:   txn.Coordinator,
+		ProducerID:      txn.ProducerID,
+		ProducerEpoch:   txn.ProducerEpoch,
+		TimeoutMs:       txn.TimeoutMillis,
+		StartTimestamp:  txn.StartTimestamp,
+		Topics:          topics,
+		Error:           errToString(txn.Err),
+	}
+}
+
+// transactionsShardErrors appends the errors of single brokers to the overview, so that the responses
+// of all other brokers can still be shown. All other errors are returned as REST error.
+func (s *Service) transactionsShardErrors(err error, action string, overview *TransactionsOverview) *rest.Error {
+	if err == nil {
+		return nil
+	}
+
+	var shardErrs *kadm.ShardErrors
+	if errors.As(err, &shardErrs) {
+		for _, shardErr := range shardErrs.Errs {
+			s.logger.Warn("failed to "+action+" on broker", zap.Int32("broker_id", shardErr.Broker.NodeID), zap.Error(shardErr.Err))
+			overview.Errors = append(overview.Errors, fmt.Sprintf("broker %d: %v", shardErr.Broker.NodeID, shardErr.Err.Error()))
+		}
+		return nil
+	}
+
+	status := http.StatusServiceUnavailable
+	var authErr *kadm.AuthError
+	if errors.As(err, &authErr) {
+		status = http.StatusForbidden
+	}
+	return &rest.Error{
+		Err:      fmt.Errorf("failed to %v: %w", action, err),
+		Status:   status,
+		Message:  fmt.Sprintf("Failed to %v: %v", action, err.Error()),
+		IsSilent: false,
+	}
+}
+
+// TopicProducers lists the producers that are actively writing to each partition of a topic.
+type TopicProducers struct {
+	TopicName  string               `json:"topicName"`
+	Partitions []PartitionProducers `json:"partitions"`
+	Errors     []string             `json:"errors"`
+}
+
+// PartitionProducers lists the active producers of a single partition.
+type PartitionProducers struct {
+	PartitionID     int32            `json:"partitionId"`
+	LeaderID        int32            `json:"leaderId"`
+	ActiveProducers []ActiveProducer `json:"activeProducers"`
+	Error           string           `json:"error,omitempty"`
+}
+
+// ActiveProducer is the producer state that a partition leader keeps for a single producer ID.
+type ActiveProducer struct {
+	ProducerID       int64 `json:"producerId"`
+	ProducerEpoch    int16 `json:"producerEpoch"`
+	LastSequence     int32 `json:"lastSequence"`
+	LastTimestamp    int64 `json:"lastTimestamp"`
+	CoordinatorEpoch int32 `json:"coordinatorEpoch"`
+	// CurrentTransactionStartOffset is the first offset of the producer's open transaction, -1 if there is none.
+	CurrentTransactionStartOffset int64 `json:"currentTransactionStartOffset"`
+}
+
+// GetTopicProducers describes the active producers of all partitions of the given topic.
+func (s *Service) GetTopicProducers(ctx context.Context, topicName string) (*TopicProducers, *rest.Error) {
+	overview := &TransactionsOverview{Errors: make([]string, 0)}
+
+	set := make(kadm.TopicsSet)
+	set.Add(topicName)
+	described, err := s.kafkaSvc.KafkaAdmClient.DescribeProducers(ctx, set)
+	if restErr := s.transactionsShardErrors(err, "describe producers", overview); restErr != nil {
+		return nil, restErr
+	}
+
+	res := &TopicProducers{
+		TopicName:  topicName,
+		Partitions: make([]PartitionProducers, 0),
+		Errors:     overview.Errors,
+	}
+	for _, partition := range described[topicName].Partitions.Sorted() {
+		producers := make([]ActiveProducer, 0, len(partition.ActiveProducers))
+		for _, producer := range partition.ActiveProducers.Sorted() {
+			producers = append(producers, ActiveProducer{
+				ProducerID:                    producer.ProducerID,
+				ProducerEpoch:                 producer.ProducerEpoch,
+				LastSequence:                  producer.LastSequence,
+				LastTimestamp:                 producer.LastTimestamp,
+				CoordinatorEpoch:              producer.CoordinatorEpoch,
+				CurrentTransactionStartOffset: producer.CurrentTxnStartOffset,
+			})
+		}
+		res.Partitions = append(res.Partitions, PartitionProducers{
+			PartitionID:     partition.Partition,
+			LeaderID:        partition.Leader,
+			ActiveProducers: producers,
+			Error:           errToString(partition.Err),
+		})
+	}
+
+	return res, nil
+}
+
+// Reasons why an open transaction is considered hanging.
+const (
+	HangingReasonNoTransactionalID     = "NO_TRANSACTIONAL_ID"
+	HangingReasonTransactionNotOngoing = "TRANSACTION_NOT_ONGOING"
+	HangingReasonProducerMismatch      = "PRODUCER_MISMATCH"
+	HangingReasonPartitionNotInTxn     = "PARTITION_NOT_IN_TRANSACTION"
+)
+
+// HangingTransactions is the result of searching for hanging transactions.
+type HangingTransactions struct {
+	Transactions []HangingTransaction `json:"transactions"`
+	Errors       []string             `json:"errors"`
+}
+
+// HangingTransaction is an open transaction on a partition which is not known as ongoing by the transaction
+// coordinator anymore. It blocks the last stable offset of the partition and thereby all read_committed consumers.
+type HangingTransaction struct {
+	TopicName        string `json:"topicName"`
+	PartitionID      int32  `json:"partitionId"`
+	ProducerID       int64  `json:"producerId"`
+	ProducerEpoch    int16  `json:"producerEpoch"`
+	CoordinatorEpoch int32  `json:"coordinatorEpoch"`
+	StartOffset      int64  `json:"startOffset"`
+	LastTimestamp    int64  `json:"lastTimestamp"`
+	// TransactionalID and TransactionState are empty if no transactional ID uses the producer ID.
+	TransactionalID  string `json:"transactionalId,omitempty"`
+	TransactionState string `json:"transactionState,omitempty"`
+	Reason           string `json:"reason"`
+}
+
+// FindHangingTransactions searches the given topics (or all topics, if none are given) for hanging transactions.
+// This works like `kafka-transactions.sh find-hanging`: Producers with an open transaction that haven't written
+// for longer than the max transaction timeout are looked up at their transaction coordinator. If the coordinator
+// does not know the transaction as ongoing for that partition, the transaction is hanging.
+func (s *Service) FindHangingTransactions(ctx context.Context, topicNames []string, maxTransactionTimeout time.Duration) (*HangingTransactions, *rest.Error) {
+	overview := &TransactionsOverview{Errors: make([]string, 0)}
+	res := &HangingTransactions{Transactions: make([]HangingTransaction, 0)}
+
+	// 1. Find producers with transactions that have been open for too long
+	set := make(kadm.TopicsSet)
+	for _, topicName := range topicNames {
+		set.Add(topicName)
+	}
+	described, err := s.kafkaSvc.KafkaAdmClient.DescribeProducers(ctx, set)
+	if restErr := s.transactionsShardErrors(err, "describe producers", overview); restErr != nil {
+		return nil, restErr
+	}
+	candidates := findHangingTransactionCandidates(described.SortedProducers(), time.Now(), maxTransactionTimeout)
+	if len(candidates) == 0 {
+		res.Errors = overview.Errors
+		return res, nil
+	}
+
+	// 2. Lookup the transactional IDs of the candidates' producer IDs
+	producerIDs := make([]int64, 0, len(candidates))
+	for _, candidate := range candidates {
+		producerIDs = append(producerIDs, candidate.ProducerID)
+	}
+	listed, err := s.kafkaSvc.KafkaAdmClient.ListTransactions(ctx, producerIDs, nil)
+	if restErr := s.transactionsShardErrors(err, "list transactions", overview); restErr != nil {
+		return nil, restErr
+	}
+	var transactions kadm.DescribedTransactions
+	if len(listed) > 0 {
+		transactions, err = s.kafkaSvc.KafkaAdmClient.DescribeTransactions(ctx, listed.TransactionalIDs()...)
+		if restErr := s.transactionsShardErrors(err, "describe transactions", overview); restErr != nil {
+			return nil, restErr
+		}
+	}
+
+	// 3. Check which candidates are not ongoing according to their coordinator
+	res.Transactions = findHangingTransactions(candidates, listed, transactions)
+	res.Errors = overview.Errors
+	return res, nil
+}
+
+// findHangingTransactionCandidates returns all producers with an open transaction that haven't written for
+// longer than the max transaction timeout.
+func findHangingTransactionCandidates(producers []kadm.DescribedProducer, now time.Time, maxTransactionTimeout time.Duration) []kadm.DescribedProducer {
+	candidates := make([]kadm.DescribedProducer, 0)
+	for _, producer := range producers {
+		if producer.CurrentTxnStartOffset < 0 {
+			continue
+		}
+		if now.Sub(time.UnixMilli(producer.LastTimestamp)) > maxTransactionTimeout {
+			candidates = append(candidates, producer)
+		}
+	}
+	return candidates
+}
+
+// findHangingTransactions returns all candidates whose transaction is not ongoing for the candidate's partition.
+func findHangingTransactions(candidates []kadm.DescribedProducer, listed kadm.ListedTransactions, transactions kadm.DescribedTransactions) []HangingTransaction {
+	txnIDByProducerID := make(map[int64]string, len(listed))
+	for _, txn := range listed {
+		txnIDByProducerID[txn.ProducerID] = txn.TxnID
+	}
+
+	hanging := make([]HangingTransaction, 0)
+	for _, candidate := range candidates {
+		h := HangingTransaction{
+			TopicName:        candidate.Topic,
+			PartitionID:      candidate.Partition,
+			ProducerID:       candidate.ProducerID,
+			ProducerEpoch:    candidate.ProducerEpoch,
+			CoordinatorEpoch: candidate.CoordinatorEpoch,
+			StartOffset:      candidate.CurrentTxnStartOffset,
+			LastTimestamp:    candidate.LastTimestamp,
+		}
+
+		txnID, exists := txnIDByProducerID[candidate.ProducerID]
+		txn, described := transactions[txnID]
+		switch {
+		case !exists || !described:
+			h.Reason = HangingReasonNoTransactionalID
+		case txn.ProducerID != candidate.ProducerID || txn.ProducerEpoch != candidate.ProducerEpoch:
+			h.Reason = HangingReasonProducerMismatch
+		case txn.State != TransactionStateOngoing:
+			h.Reason = HangingReasonTransactionNotOngoing
+		case !txn.Topics.Lookup(candidate.Topic, candidate.Partition):
+			h.Reason = HangingReasonPartitionNotInTxn
+		default:
+			// The transaction is still running, it will be completed or aborted by its coordinator
+			continue
+		}
+		if exists && described {
+			h.TransactionalID = txnID
+			h.TransactionState = txn.State
+		}
+		hanging = append(hanging, h)
+	}
+
+	sort.Slice(hanging, func(i, j int) bool {
+		if hanging[i].TopicName != hanging[j].TopicName {
+			return hanging[i].TopicName < hanging[j].TopicName
+		}
+		if hanging[i].PartitionID != hanging[j].PartitionID {
+			return hanging[i].PartitionID < hanging[j].PartitionID
+		}
+		return hanging[i].ProducerID < hanging[j].ProducerID
+	})
+	return hanging
+}
+
+// isHangingTransaction checks whether the open transaction of the producer is hanging. Contrary to
+// FindHangingTransactions, errors of single brokers are not tolerated, because an unreachable
+// coordinator must not make a transaction look hanging.
+func (s *Service) isHangingTransaction(ctx context.Context, producer kadm.DescribedProducer, maxTransactionTimeout time.Duration) (bool, *rest.Error) {
+	candidates := findHangingTransactionCandidates([]kadm.DescribedProducer{producer}, time.Now(), maxTransactionTimeout)
+	if len(candidates) == 0 {
+		return false, nil
+	}
+
+	listed, err := s.kafkaSvc.KafkaAdmClient.ListTransactions(ctx, []int64{producer.ProducerID}, nil)
+	if err != nil {
+		return false, &rest.Error{
+			Err:      fmt.Errorf("failed to list transactions: %w", err),
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to list transactions: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+	var transactions kadm.DescribedTransactions
+	if len(listed) > 0 {
+		transactions, err = s.kafkaSvc.KafkaAdmClient.DescribeTransactions(ctx, listed.TransactionalIDs()...)
+		if err != nil {
+			return false, &rest.Error{
+				Err:      fmt.Errorf("failed to describe transactions: %w", err),
+				Status:   http.StatusServiceUnavailable,
+				Message:  fmt.Sprintf("Failed to describe transactions: %v", err.Error()),
+				IsSilent: false,
+			}
+		}
+	}
+	return len(findHangingTransactions(candidates, listed, transactions)) > 0, nil
+}
+
+// AbortTransactionResponse is the response after aborting an open transaction on a partition.
+type AbortTransactionResponse struct {
+	TopicName     string `json:"topicName"`
+	PartitionID   int32  `json:"partitionId"`
+	ProducerID    int64  `json:"producerId"`
+	ProducerEpoch int16  `json:"producerEpoch"`
+	StartOffset   int64  `json:"startOffset"`
+}
+
+// AbortTransaction aborts the open transaction that starts at the given offset of the partition by writing
+// an abort marker, like `kafka-transactions.sh abort`. Unless force is set, the transaction must be hanging
+// by the same criteria as in FindHangingTransactions, so that in-flight transactions of live producers are
+// not aborted.
+func (s *Service) AbortTransaction(ctx context.Context, topicName string, partitionID int32, startOffset int64, maxTransactionTimeout time.Duration, force bool) (*AbortTransactionResponse, *rest.Error) {
+	// 1. Find the producer whose transaction starts at the given offset
+	set := make(kadm.TopicsSet)
+	set.Add(topicName, partitionID)
+	described, err := s.kafkaSvc.KafkaAdmClient.DescribeProducers(ctx, set)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("failed to describe producers: %w", err),
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to describe producers: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+	partition, exists := described[topicName].Partitions[partitionID]
+	if !exists {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("partition %d of topic %q was not described", partitionID, topicName),
+			Status:   http.StatusNotFound,
+			Message:  fmt.Sprintf("Partition %d of topic '%v' does not exist", partitionID, topicName),
+			IsSilent: false,
+		}
+	}
+	if partition.Err != nil {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("failed to describe producers of partition: %w", partition.Err),
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to describe producers of partition: %v", partition.Err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	var producer *kadm.DescribedProducer
+	for _, p := range partition.ActiveProducers {
+		if p.CurrentTxnStartOffset == startOffset {
+			p := p
+			producer = &p
+			break
+		}
+	}
+	if producer == nil {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("no open transaction starts at offset %d", startOffset),
+			Status:   http.StatusNotFound,
+			Message:  fmt.Sprintf("There is no open transaction that starts at offset %d", startOffset),
+			IsSilent: false,
+		}
+	}
+
+	// 2. Make sure the transaction is hanging
+	if !force {
+		isHanging, restErr := s.isHangingTransaction(ctx, *producer, maxTransactionTimeout)
+		if restErr != nil {
+			return nil, restErr
+		}
+		if !isHanging {
+			return nil, &rest.Error{
+				Err:      fmt.Errorf("transaction of producer %d starting at offset %d is not hanging", producer.ProducerID, startOffset),
+				Status:   http.StatusConflict,
+				Message:  "The transaction is not hanging, it may still be completed by its producer. Set force to abort it anyway",
+				IsSilent: false,
+			}
+		}
+	}
+
+	// 3. Write abort marker
+	markers, err := s.kafkaSvc.KafkaAdmClient.WriteTxnMarkers(ctx, kadm.TxnMarkers{
+		ProducerID:       producer.ProducerID,
+		ProducerEpoch:    producer.ProducerEpoch,
+		Commit:           false,
+		CoordinatorEpoch: producer.CoordinatorEpoch,
+		Topics:           set,
+	})
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("failed to write abort marker: %w", err),
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to abort transaction: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+	for _, marker := range markers {
+		for _, topic := range marker.Topics {
+			for _, p := range topic.Partitions {
+				if p.Err != nil {
+					return nil, &rest.Error{
+						Err:      fmt.Errorf("failed to write abort marker: %w", p.Err),
+						Status:   http.StatusServiceUnavailable,
+						Message:  fmt.Sprintf("Failed to abort transaction, kafka responded with the following error: %v", p.Err.Error()),
+						IsSilent: false,
+					}
+				}
+			}
+		}
+	}
+
+	return &AbortTransactionResponse{
+		TopicName:     topicName,
+		PartitionID:   partitionID,
+		ProducerID:    producer.ProducerID,
+		ProducerEpoch: producer.ProducerEpoch,
+		StartOffset:   startOffset,
+	}, nil
+}
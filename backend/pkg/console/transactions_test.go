@@ -0,0 +1,70 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kadm"
+)
+
+func TestFindHangingTransactionCandidates(t *testing.T) {
+	now := time.UnixMilli(100_000_000)
+	producers := []kadm.DescribedProducer{
+		{Topic: "a", Partition: 0, ProducerID: 1, CurrentTxnStartOffset: -1, LastTimestamp: 0},
+		{Topic: "a", Partition: 0, ProducerID: 2, CurrentTxnStartOffset: 10, LastTimestamp: now.Add(-time.Minute).UnixMilli()},
+		{Topic: "a", Partition: 1, ProducerID: 3, CurrentTxnStartOffset: 20, LastTimestamp: now.Add(-time.Hour).UnixMilli()},
+	}
+
+	candidates := findHangingTransactionCandidates(producers, now, DefaultMaxTransactionTimeout)
+
+	require.Len(t, candidates, 1)
+	assert.Equal(t, int64(3), candidates[0].ProducerID)
+}
+
+func TestFindHangingTransactions(t *testing.T) {
+	ongoingTopics := make(kadm.TopicsSet)
+	ongoingTopics.Add("a", 0)
+
+	candidates := []kadm.DescribedProducer{
+		{Topic: "a", Partition: 0, ProducerID: 1, ProducerEpoch: 0, CurrentTxnStartOffset: 10},
+		{Topic: "a", Partition: 1, ProducerID: 1, ProducerEpoch: 0, CurrentTxnStartOffset: 11},
+		{Topic: "a", Partition: 2, ProducerID: 2, ProducerEpoch: 3, CurrentTxnStartOffset: 12},
+		{Topic: "a", Partition: 3, ProducerID: 3, ProducerEpoch: 0, CurrentTxnStartOffset: 13},
+		{Topic: "b", Partition: 0, ProducerID: 4, ProducerEpoch: 0, CurrentTxnStartOffset: 14},
+	}
+	listed := kadm.ListedTransactions{
+		"running":  {TxnID: "running", ProducerID: 1},
+		"bumped":   {TxnID: "bumped", ProducerID: 2},
+		"complete": {TxnID: "complete", ProducerID: 3},
+	}
+	transactions := kadm.DescribedTransactions{
+		"running":  {TxnID: "running", ProducerID: 1, ProducerEpoch: 0, State: TransactionStateOngoing, Topics: ongoingTopics},
+		"bumped":   {TxnID: "bumped", ProducerID: 2, ProducerEpoch: 4, State: TransactionStateOngoing},
+		"complete": {TxnID: "complete", ProducerID: 3, ProducerEpoch: 0, State: "CompleteAbort"},
+	}
+
+	hanging := findHangingTransactions(candidates, listed, transactions)
+
+	require.Len(t, hanging, 4)
+	assert.Equal(t, HangingTransaction{
+		TopicName: "a", PartitionID: 1, ProducerID: 1, StartOffset: 11,
+		TransactionalID: "running", TransactionState: TransactionStateOngoing, Reason: HangingReasonPartitionNotInTxn,
+	}, hanging[0])
+	assert.Equal(t, HangingReasonProducerMismatch, hanging[1].Reason)
+	assert.Equal(t, HangingReasonTransactionNotOngoing, hanging[2].Reason)
+	assert.Equal(t, "complete", hanging[2].TransactionalID)
+	assert.Equal(t, HangingTransaction{
+		TopicName: "b", PartitionID: 0, ProducerID: 4, StartOffset: 14, Reason: HangingReasonNoTransactionalID,
+	}, hanging[3])
+}
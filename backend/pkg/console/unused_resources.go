@@ -0,0 +1,533 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudhut/common/rest"
+	"go.uber.org/zap"
+
+	"github.com/redpanda-data/console/backend/pkg/kafka"
+)
+
+// DefaultTopicInactivityWindow is the default window after which topics are considered unused.
+const DefaultTopicInactivityWindow = 30 * 24 * time.Hour
+
+// unusedResourcesFetchTimeout bounds the time that is spent on fetching the newest record of
+// all partitions. Unlike the time lag estimation, the analysis needs all partitions.
+const unusedResourcesFetchTimeout = 30 * time.Second
+
+// UnusedResourcesOptions configures when topics are considered unused. Consumer groups have no
+// inactivity window, because Kafka does not expose when offsets have been committed.
+type UnusedResourcesOptions struct {
+	// TopicInactivityWindow is the duration in which no records must have been produced.
+	TopicInactivityWindow time.Duration
+}
+
+// UnusedResourcesReport lists topics and consumer groups that appear to be no longer used.
+type UnusedResourcesReport struct {
+	GeneratedAt           time.Time `json:"generatedAt"`
+	TopicInactivityWindow string    `json:"topicInactivityWindow"`
+
+	UnusedTopics        []UnusedTopic        `json:"unusedTopics"`
+	StaleConsumerGroups []StaleConsumerGroup `json:"staleConsumerGroups"`
+
+	// Warnings contains resources that could not be evaluated. These are never reported as unused.
+	Warnings []UnusedResourceWarning `json:"warnings"`
+	// WarningMessages summarizes the warnings by reason.
+	WarningMessages []string `json:"warningMessages"`
+}
+
+// Reasons why a resource could not be evaluated.
+const (
+	UnusedResourceWarningUnknownLastRecord = "the newest record could not be fetched"
+)
+
+// UnusedResourceWarning is a topic or consumer group that could not be evaluated.
+type UnusedResourceWarning struct {
+	// Type is either HealthResourceTypeTopic or HealthResourceTypeConsumerGroup.
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// UnusedTopic is a topic without new records in the inactivity window, without committed
+// consumer group offsets and without Kafka connect connectors referencing it.
+type UnusedTopic struct {
+	TopicName      string `json:"topicName"`
+	PartitionCount int    `json:"partitionCount"`
+	TotalSizeBytes int64  `json:"totalSizeBytes"`
+
+	// LastRecordTimestamp is the timestamp of the newest record, nil if the topic is empty.
+	LastRecordTimestamp *time.Time `json:"lastRecordTimestamp"`
+}
+
+// StaleConsumerGroup is a consumer group without members that has never committed any
+// offsets. Kafka does not expose commit timestamps, hence groups with committed offsets are
+// never reported as stale, no matter how long ago they committed.
+type StaleConsumerGroup struct {
+	GroupID string   `json:"groupId"`
+	Topics  []string `json:"topics"`
+}
+
+// UnusedResourceDeletion is the outcome of deleting a single unused topic or consumer group.
+type UnusedResourceDeletion struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Deleted bool   `json:"deleted"`
+	// Skipped explains why the resource has not been deleted, e.g. because it is in use again.
+	Skipped string `json:"skipped,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// unusedResourcesInput contains everything the analysis is based on, so that it can be evaluated
+// without a cluster.
+type unusedResourcesInput struct {
+	Topics []*TopicSummary
+	Groups []ConsumerGroupOverview
+
+	// LastRecordByTopic contains the newest record timestamp of all topics with records.
+	LastRecordByTopic map[string]time.Time
+	// EmptyTopics contains all topics without any records.
+	EmptyTopics map[string]struct{}
+
+	// Topics referenced by connectors by name or regex.
+	ConnectorTopics       map[string]struct{}
+	ConnectorTopicRegexes []*regexp.Regexp
+}
+
+// GetUnusedResources analyzes which topics and consumer groups are no longer used.
+func (s *Service) GetUnusedResources(ctx context.Context, opts UnusedResourcesOptions) (*UnusedResourcesReport, *rest.Error) {
+	report := &UnusedResourcesReport{
+		GeneratedAt:           time.Now(),
+		TopicInactivityWindow: opts.TopicInactivityWindow.String(),
+	}
+
+	// 1. Gather topics, consumer groups and connectors
+	topics, err := s.GetTopicsOverview(ctx)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("failed to get topics overview: %w", err),
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to get topics overview: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+	groups, restErr := s.GetConsumerGroupsOverview(ctx, nil, ConsumerGroupsOverviewOptions{})
+	if restErr != nil {
+		return nil, restErr
+	}
+	in := unusedResourcesInput{Topics: topics, Groups: groups}
+
+	in.ConnectorTopics, in.ConnectorTopicRegexes, err = s.connectorTopicReferences(ctx)
+	if err != nil {
+		// Without the connector references any topic could still be used by a connector
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("failed to list connectors: %w", err),
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to list connectors, topics can't be evaluated: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	// 2. Sample the newest record of all topics
+	topicNames := make([]string, 0, len(topics))
+	for _, topic := range topics {
+		if !topic.IsInternal {
+			topicNames = append(topicNames, topic.TopicName)
+		}
+	}
+	in.LastRecordByTopic, in.EmptyTopics, err = s.lastRecordByTopic(ctx, topicNames)
+	if err != nil {
+		return nil, &rest.Error{
+			Err:      fmt.Errorf("failed to list partition offsets: %w", err),
+			Status:   http.StatusServiceUnavailable,
+			Message:  fmt.Sprintf("Failed to list partition offsets: %v", err.Error()),
+			IsSilent: false,
+		}
+	}
+
+	// 3. Evaluate
+	report.UnusedTopics, report.StaleConsumerGroups, report.Warnings = findUnusedResources(in, opts, report.GeneratedAt)
+	report.WarningMessages = unusedResourceWarningMessages(report.Warnings)
+	return report, nil
+}
+
+// FilterResources removes all topics and consumer groups, including those in warnings, that are
+// not visible. The warning messages are rebuilt from the remaining warnings.
+func (r *UnusedResourcesReport) FilterResources(isVisible func(resourceType string, name string) (bool, *rest.Error)) *rest.Error {
+	visibleTopics := make([]UnusedTopic, 0, len(r.UnusedTopics))
+	for _, topic := range r.UnusedTopics {
+		canSee, restErr := isVisible(HealthResourceTypeTopic, topic.TopicName)
+		if restErr != nil {
+			return restErr
+		}
+		if canSee {
+			visibleTopics = append(visibleTopics, topic)
+		}
+	}
+	r.UnusedTopics = visibleTopics
+
+	visibleGroups := make([]StaleConsumerGroup, 0, len(r.StaleConsumerGroups))
+	for _, group := range r.StaleConsumerGroups {
+		canSee, restErr := isVisible(HealthResourceTypeConsumerGroup, group.GroupID)
+		if restErr != nil {
+			return restErr
+		}
+		if canSee {
+			visibleGroups = append(visibleGroups, group)
+		}
+	}
+	r.StaleConsumerGroups = visibleGroups
+
+	visibleWarnings := make([]UnusedResourceWarning, 0, len(r.Warnings))
+	for _, warning := range r.Warnings {
+		canSee, restErr := isVisible(warning.Type, warning.Name)
+		if restErr != nil {
+			return restErr
+		}
+		if canSee {
+			visibleWarnings = append(visibleWarnings, warning)
+		}
+	}
+	r.Warnings = visibleWarnings
+	r.WarningMessages = unusedResourceWarningMessages(visibleWarnings)
+
+	return nil
+}
+
+// DeleteUnusedResources deletes the given topics and consumer groups, but only if they are still
+// reported as unused. With dry run only the outcome is reported.
+func (s *Service) DeleteUnusedResources(ctx context.Context, topicNames []string, groupIDs []string, opts UnusedResourcesOptions, dryRun bool) ([]UnusedResourceDeletion, *rest.Error) {
+	report, restErr := s.GetUnusedResources(ctx, opts)
+	if restErr != nil {
+		return nil, restErr
+	}
+	unusedTopics := make(map[string]struct{}, len(report.UnusedTopics))
+	for _, topic := range report.UnusedTopics {
+		unusedTopics[topic.TopicName] = struct{}{}
+	}
+	staleGroups := make(map[string]struct{}, len(report.StaleConsumerGroups))
+	for _, group := range report.StaleConsumerGroups {
+		staleGroups[group.GroupID] = struct{}{}
+	}
+
+	results := make([]UnusedResourceDeletion, 0, len(topicNames)+len(groupIDs))
+	for _, groupID := range groupIDs {
+		result := UnusedResourceDeletion{Type: HealthResourceTypeConsumerGroup, Name: groupID}
+		switch {
+		case !isInSet(staleGroups, groupID):
+			result.Skipped = "consumer group is not stale"
+		case dryRun:
+			result.Skipped = "dry run"
+		default:
+			if err := s.DeleteConsumerGroup(ctx, groupID); err != nil {
+				result.Error = err.Error()
+			} else {
+				result.Deleted = true
+			}
+		}
+		results = append(results, result)
+	}
+	for _, topicName := range topicNames {
+		result := UnusedResourceDeletion{Type: HealthResourceTypeTopic, Name: topicName}
+		switch {
+		case !isInSet(unusedTopics, topicName):
+			result.Skipped = "topic is not unused"
+		case dryRun:
+			result.Skipped = "dry run"
+		default:
+			if restErr := s.DeleteTopic(ctx, topicName); restErr != nil {
+				result.Error = restErr.Message
+			} else {
+				result.Deleted = true
+			}
+		}
+		results = append(results, result)
+	}
+
+	if !dryRun {
+		s.logger.Info("deleted unused resources",
+			zap.Strings("topics", topicNames),
+			zap.Strings("consumer_groups", groupIDs))
+	}
+	return results, nil
+}
+
+// findUnusedResources evaluates the gathered input. Resources that can't be evaluated are
+// returned as warnings rather than as unused.
+func findUnusedResources(in unusedResourcesInput, opts UnusedResourcesOptions, now time.Time) ([]UnusedTopic, []StaleConsumerGroup, []UnusedResourceWarning) {
+	warnings := make([]UnusedResourceWarning, 0)
+
+	// Topics with committed offsets of any group are in use
+	consumedTopics := make(map[string]struct{})
+	for _, group := range in.Groups {
+		for _, topic := range group.TopicOffsets {
+			for _, p := range topic.PartitionOffsets {
+				if hasCommittedOffset(p) {
+					consumedTopics[topic.Topic] = struct{}{}
+					break
+				}
+			}
+		}
+	}
+
+	unusedTopics := make([]UnusedTopic, 0)
+	for _, topic := range in.Topics {
+		if topic.IsInternal || strings.HasPrefix(topic.TopicName, "__") {
+			continue
+		}
+		if isInSet(consumedTopics, topic.TopicName) || isReferencedByConnector(topic.TopicName, in.ConnectorTopics, in.ConnectorTopicRegexes) {
+			continue
+		}
+
+		unused := UnusedTopic{
+			TopicName:      topic.TopicName,
+			PartitionCount: topic.PartitionCount,
+			TotalSizeBytes: topic.LogDirSummary.TotalSizeBytes,
+		}
+		if lastRecord, exists := in.LastRecordByTopic[topic.TopicName]; exists {
+			if now.Sub(lastRecord) < opts.TopicInactivityWindow {
+				continue
+			}
+			unused.LastRecordTimestamp = &lastRecord
+		} else if !isInSet(in.EmptyTopics, topic.TopicName) {
+			warnings = append(warnings, UnusedResourceWarning{
+				Type:   HealthResourceTypeTopic,
+				Name:   topic.TopicName,
+				Reason: UnusedResourceWarningUnknownLastRecord,
+			})
+			continue
+		}
+		unusedTopics = append(unusedTopics, unused)
+	}
+
+	staleGroups := make([]StaleConsumerGroup, 0)
+	for _, group := range in.Groups {
+		if group.State != "Empty" {
+			continue
+		}
+
+		stale := StaleConsumerGroup{GroupID: group.GroupID, Topics: make([]string, 0, len(group.TopicOffsets))}
+		hasOffsets := false
+		for _, topic := range group.TopicOffsets {
+			stale.Topics = append(stale.Topics, topic.Topic)
+			for _, p := range topic.PartitionOffsets {
+				if hasCommittedOffset(p) {
+					hasOffsets = true
+				}
+			}
+		}
+		if hasOffsets {
+			continue
+		}
+		sort.Strings(stale.Topics)
+		staleGroups = append(staleGroups, stale)
+	}
+
+	sort.Slice(unusedTopics, func(i, j int) bool { return unusedTopics[i].TopicName < unusedTopics[j].TopicName })
+	sort.Slice(staleGroups, func(i, j int) bool { return staleGroups[i].GroupID < staleGroups[j].GroupID })
+	sort.Slice(warnings, func(i, j int) bool {
+		if warnings[i].Reason != warnings[j].Reason {
+			return warnings[i].Reason < warnings[j].Reason
+		}
+		return warnings[i].Name < warnings[j].Name
+	})
+	return unusedTopics, staleGroups, warnings
+}
+
+// unusedResourceWarningMessages summarizes the warnings with one message per reason and
+// resource type, e.g. "the newest record could not be fetched for 2 topics: a, b".
+func unusedResourceWarningMessages(warnings []UnusedResourceWarning) []string {
+	type key struct {
+		Reason string
+		Type   string
+	}
+	keys := make([]key, 0)
+	namesByKey := make(map[key][]string)
+	for _, warning := range warnings {
+		k := key{Reason: warning.Reason, Type: warning.Type}
+		if _, exists := namesByKey[k]; !exists {
+			keys = append(keys, k)
+		}
+		namesByKey[k] = append(namesByKey[k], warning.Name)
+	}
+
+	messages := make([]string, 0, len(keys))
+	for _, k := range keys {
+		resources := "topics"
+		if k.Type == HealthResourceTypeConsumerGroup {
+			resources = "consumer groups"
+		}
+		names := namesByKey[k]
+		messages = append(messages, fmt.Sprintf("%v for %d %v: %v", k.Reason, len(names), resources, strings.Join(names, ", ")))
+	}
+	return messages
+}
+
+// lastRecordByTopic fetches the timestamp of the newest record in each partition. Partitions are
+// fetched in pages, so that all of them are evaluated within unusedResourcesFetchTimeout. Topics
+// without any records are returned separately, topics whose timestamps could not be fetched in
+// time are missing.
+func (s *Service) lastRecordByTopic(ctx context.Context, topicNames []string) (map[string]time.Time, map[string]struct{}, error) {
+	lastRecord := make(map[string]time.Time)
+	empty := make(map[string]struct{})
+	if len(topicNames) == 0 {
+		return lastRecord, empty, nil
+	}
+
+	startOffsets, err := s.kafkaSvc.KafkaAdmClient.ListStartOffsets(ctx, topicNames...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list start offsets: %w", err)
+	}
+	endOffsets, err := s.kafkaSvc.KafkaAdmClient.ListEndOffsets(ctx, topicNames...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list end offsets: %w", err)
+	}
+
+	required := make([]recordOffset, 0)
+	partitionsByTopic := make(map[string][]recordOffset)
+	for _, topicName := range topicNames {
+		hasRecords, hasErrors := false, false
+		for partitionID, end := range endOffsets[topicName] {
+			start, exists := startOffsets.Lookup(topicName, partitionID)
+			if end.Err != nil || !exists || start.Err != nil {
+				hasErrors = true
+				continue
+			}
+			if end.Offset <= start.Offset {
+				continue
+			}
+			hasRecords = true
+			offset := recordOffset{Topic: topicName, Partition: partitionID, Offset: end.Offset - 1}
+			required = append(required, offset)
+			partitionsByTopic[topicName] = append(partitionsByTopic[topicName], offset)
+		}
+		if !hasRecords && !hasErrors {
+			empty[topicName] = struct{}{}
+		}
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, unusedResourcesFetchTimeout)
+	defer cancel()
+	timestamps := make(map[recordOffset]kafka.RecordTimestamp, len(required))
+	for start := 0; start < len(required) && fetchCtx.Err() == nil; start += maxTimeLagLookups {
+		pageEnd := start + maxTimeLagLookups
+		if pageEnd > len(required) {
+			pageEnd = len(required)
+		}
+		page := make(map[recordOffset]struct{}, pageEnd-start)
+		for _, offset := range required[start:pageEnd] {
+			page[offset] = struct{}{}
+		}
+		for offset, ts := range s.fetchRecordTimestamps(fetchCtx, page) {
+			timestamps[offset] = ts
+		}
+	}
+
+	// A topic's newest record is only known if the timestamps of all partitions are known
+	for topicName, offsets := range partitionsByTopic {
+		var newest time.Time
+		complete := true
+		for _, offset := range offsets {
+			ts, exists := timestamps[offset]
+			if !exists {
+				complete = false
+				break
+			}
+			if recordTime := time.UnixMilli(ts.Timestamp); recordTime.After(newest) {
+				newest = recordTime
+			}
+		}
+		if complete {
+			lastRecord[topicName] = newest
+		}
+	}
+	return lastRecord, empty, nil
+}
+
+// connectorTopicReferences returns the topic names and topic regexes all connectors of all
+// connect clusters refer to. No references are returned if Kafka connect is not configured.
+func (s *Service) connectorTopicReferences(ctx context.Context) (map[string]struct{}, []*regexp.Regexp, error) {
+	names := make(map[string]struct{})
+	regexes := make([]*regexp.Regexp, 0)
+	if s.connectSvc == nil || !s.connectSvc.Cfg.Enabled {
+		return names, regexes, nil
+	}
+
+	clusters, err := s.connectSvc.GetAllClusterConnectors(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, cluster := range clusters {
+		if cluster.Error != "" {
+			return nil, nil, fmt.Errorf("connect cluster %q: %v", cluster.ClusterName, cluster.Error)
+		}
+		for _, connector := range cluster.Connectors {
+			connectorNames, connectorRegexes := connectorConfigTopics(connector.Config)
+			for _, name := range connectorNames {
+				names[name] = struct{}{}
+			}
+			regexes = append(regexes, connectorRegexes...)
+		}
+	}
+	return names, regexes, nil
+}
+
+// connectorConfigTopics extracts topic names and regexes from the commonly used connector
+// config properties. Invalid regexes are ignored.
+func connectorConfigTopics(cfg map[string]string) ([]string, []*regexp.Regexp) {
+	names := make([]string, 0)
+	for _, key := range []string{"topics", "topic", "kafka.topic"} {
+		for _, name := range strings.Split(cfg[key], ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+
+	regexes := make([]*regexp.Regexp, 0)
+	if pattern := cfg["topics.regex"]; pattern != "" {
+		if regex, err := regexp.Compile("^(?:" + pattern + ")$"); err == nil {
+			regexes = append(regexes, regex)
+		}
+	}
+	return names, regexes
+}
+
+func isReferencedByConnector(topicName string, names map[string]struct{}, regexes []*regexp.Regexp) bool {
+	if isInSet(names, topicName) {
+		return true
+	}
+	for _, regex := range regexes {
+		if regex.MatchString(topicName) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasCommittedOffset returns true if the group has committed an offset for the partition. A
+// committed offset of 0 counts as well, Kafka reports -1 for partitions without commits.
+func hasCommittedOffset(p PartitionOffsets) bool {
+	return p.GroupOffset >= 0
+}
+
+func isInSet(set map[string]struct{}, key string) bool {
+	_, exists := set[key]
+	return exists
+}
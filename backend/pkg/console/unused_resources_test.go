@@ -0,0 +1,123 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindUnusedResources(t *testing.T) {
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	opts := UnusedResourcesOptions{TopicInactivityWindow: 30 * 24 * time.Hour}
+
+	in := unusedResourcesInput{
+		Topics: []*TopicSummary{
+			{TopicName: "__consumer_offsets", IsInternal: true},
+			{TopicName: "active", PartitionCount: 3},
+			{TopicName: "abandoned", PartitionCount: 6, LogDirSummary: TopicLogDirSummary{TotalSizeBytes: 1024}},
+			{TopicName: "empty", PartitionCount: 1},
+			{TopicName: "consumed", PartitionCount: 1},
+			{TopicName: "sink-input", PartitionCount: 1},
+			{TopicName: "cdc.orders", PartitionCount: 1},
+			{TopicName: "unknown", PartitionCount: 1},
+		},
+		Groups: []ConsumerGroupOverview{
+			{GroupID: "running", State: "Stable", TopicOffsets: []GroupTopicOffsets{
+				{Topic: "consumed", PartitionOffsets: []PartitionOffsets{{PartitionID: 0, GroupOffset: 5}}},
+			}},
+			{GroupID: "recently-committed", State: "Empty", TopicOffsets: []GroupTopicOffsets{
+				{Topic: "active", PartitionOffsets: []PartitionOffsets{{PartitionID: 0, GroupOffset: 5}}},
+			}},
+			{GroupID: "stale", State: "Empty", TopicOffsets: []GroupTopicOffsets{
+				{Topic: "active", PartitionOffsets: []PartitionOffsets{{PartitionID: 1, GroupOffset: 5}}},
+			}},
+			{GroupID: "never-consumed", State: "Empty"},
+			{GroupID: "committed-zero", State: "Empty", TopicOffsets: []GroupTopicOffsets{
+				{Topic: "empty", PartitionOffsets: []PartitionOffsets{{PartitionID: 0, GroupOffset: 0}}},
+			}},
+			{GroupID: "committed-nothing", State: "Empty", TopicOffsets: []GroupTopicOffsets{
+				{Topic: "abandoned", PartitionOffsets: []PartitionOffsets{{PartitionID: 0, GroupOffset: -1}}},
+			}},
+			{GroupID: "unknown-commit", State: "Empty", TopicOffsets: []GroupTopicOffsets{
+				{Topic: "active", PartitionOffsets: []PartitionOffsets{{PartitionID: 2, GroupOffset: 5}}},
+			}},
+		},
+		LastRecordByTopic: map[string]time.Time{
+			"active":     now.Add(-time.Hour),
+			"abandoned":  now.Add(-90 * 24 * time.Hour),
+			"consumed":   now.Add(-90 * 24 * time.Hour),
+			"sink-input": now.Add(-90 * 24 * time.Hour),
+			"cdc.orders": now.Add(-90 * 24 * time.Hour),
+		},
+		EmptyTopics:           map[string]struct{}{"empty": {}},
+		ConnectorTopics:       map[string]struct{}{"sink-input": {}},
+		ConnectorTopicRegexes: []*regexp.Regexp{regexp.MustCompile(`^(?:cdc\..*)$`)},
+	}
+
+	topics, groups, warnings := findUnusedResources(in, opts, now)
+
+	// A committed offset of 0 keeps "empty" in use
+	require.Len(t, topics, 1)
+	assert.Equal(t, "abandoned", topics[0].TopicName)
+	assert.Equal(t, int64(1024), topics[0].TotalSizeBytes)
+	require.NotNil(t, topics[0].LastRecordTimestamp)
+
+	// Groups with committed offsets are never reported, because the last commit is unknown
+	require.Len(t, groups, 2)
+	assert.Equal(t, "committed-nothing", groups[0].GroupID)
+	assert.Equal(t, []string{"abandoned"}, groups[0].Topics)
+	assert.Equal(t, "never-consumed", groups[1].GroupID)
+	assert.Empty(t, groups[1].Topics)
+
+	assert.Equal(t, []UnusedResourceWarning{
+		{Type: HealthResourceTypeTopic, Name: "unknown", Reason: UnusedResourceWarningUnknownLastRecord},
+	}, warnings)
+}
+
+func TestUnusedResourcesReport_FilterResources(t *testing.T) {
+	report := &UnusedResourcesReport{
+		UnusedTopics:        []UnusedTopic{{TopicName: "abandoned"}, {TopicName: "hidden-abandoned"}},
+		StaleConsumerGroups: []StaleConsumerGroup{{GroupID: "stale"}, {GroupID: "hidden-stale"}},
+		Warnings: []UnusedResourceWarning{
+			{Type: HealthResourceTypeTopic, Name: "hidden-unknown", Reason: UnusedResourceWarningUnknownLastRecord},
+			{Type: HealthResourceTypeTopic, Name: "unknown", Reason: UnusedResourceWarningUnknownLastRecord},
+		},
+	}
+
+	restErr := report.FilterResources(func(_ string, name string) (bool, *rest.Error) {
+		return !strings.HasPrefix(name, "hidden-"), nil
+	})
+	require.Nil(t, restErr)
+
+	assert.Equal(t, []UnusedTopic{{TopicName: "abandoned"}}, report.UnusedTopics)
+	assert.Equal(t, []StaleConsumerGroup{{GroupID: "stale"}}, report.StaleConsumerGroups)
+	require.Len(t, report.Warnings, 1)
+	assert.Equal(t, []string{"the newest record could not be fetched for 1 topics: unknown"}, report.WarningMessages)
+}
+
+func TestConnectorConfigTopics(t *testing.T) {
+	names, regexes := connectorConfigTopics(map[string]string{
+		"topics":       "orders, payments",
+		"kafka.topic":  "invoices",
+		"topics.regex": "cdc\\..*",
+	})
+
+	assert.Equal(t, []string{"orders", "payments", "invoices"}, names)
+	require.Len(t, regexes, 1)
+	assert.True(t, regexes[0].MatchString("cdc.orders"))
+	assert.False(t, regexes[0].MatchString("x.cdc.orders"))
+}
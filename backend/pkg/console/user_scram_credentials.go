@@ -0,0 +1,219 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package console
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"go.uber.org/zap"
+)
+
+// Iteration bounds that Kafka accepts for SCRAM credentials.
+const (
+	SCRAMMinIterations = 4096
+	SCRAMMaxIterations = 16384
+)
+
+// SCRAMUser is a Kafka user with its SCRAM credentials.
+type SCRAMUser struct {
+	Name        string            `json:"name"`
+	Credentials []SCRAMCredential `json:"credentials"`
+	// Error is set if the credentials of the user couldn't be described. Credentials is empty then.
+	Error string `json:"error,omitempty"`
+}
+
+// SCRAMCredential describes a password that exists for a user. The password itself can't be described.
+type SCRAMCredential struct {
+	Mechanism  string `json:"mechanism"`
+	Iterations int32  `json:"iterations"`
+}
+
+// ParseSCRAMMechanism parses the mechanism name SCRAM-SHA-256 or SCRAM-SHA-512.
+func ParseSCRAMMechanism(mechanism string) (kadm.ScramMechanism, error) {
+	switch mechanism {
+	case kadm.ScramSha256.String():
+		return kadm.ScramSha256, nil
+	case kadm.ScramSha512.String():
+		return kadm.ScramSha512, nil
+	default:
+		return 0, fmt.Errorf("mechanism must be either SCRAM-SHA-256 or SCRAM-SHA-512")
+	}
+}
+
+// ListSCRAMUsers lists all users that have SCRAM credentials via the Kafka API. Users whose credentials
+// couldn't be described are returned with an error.
+func (s *Service) ListSCRAMUsers(ctx context.Context) ([]SCRAMUser, *rest.Error) {
+	described, err := s.kafkaSvc.KafkaAdmClient.DescribeUserSCRAMs(ctx)
+	if err != nil {
+		return nil, scramRestError("describe user SCRAM credentials", err)
+	}
+
+	users := make([]SCRAMUser, 0, len(described))
+	for _, user := range described.Sorted() {
+		if user.Err != nil {
+			s.logger.Warn("failed to describe SCRAM credentials of user", zap.String("user", user.User), zap.Error(user.Err))
+			users = append(users, SCRAMUser{Name: user.User, Credentials: []SCRAMCredential{}, Error: user.Err.Error()})
+			continue
+		}
+		users = append(users, newSCRAMUser(user))
+	}
+	return users, nil
+}
+
+// CreateSCRAMUser creates a user with a password for the given mechanism via the Kafka API. It fails if the
+// user already has a password for that mechanism.
+func (s *Service) CreateSCRAMUser(ctx context.Context, username, password, mechanism string, iterations int32) *rest.Error {
+	user, restErr := s.describeSCRAMUser(ctx, username)
+	if restErr != nil {
+		return restErr
+	}
+	if user != nil {
+		for _, cred := range user.Credentials {
+			if cred.Mechanism == mechanism {
+				return &rest.Error{
+					Err:      fmt.Errorf("user %q already has %v credentials", username, mechanism),
+					Status:   http.StatusConflict,
+					Message:  fmt.Sprintf("User '%v' already exists with mechanism %v", username, mechanism),
+					IsSilent: false,
+				}
+			}
+		}
+	}
+
+	return s.upsertSCRAMUser(ctx, username, password, mechanism, iterations)
+}
+
+// UpdateSCRAMUserPassword sets a new password for an existing user via the Kafka API. Existing passwords for
+// other mechanisms are kept.
+func (s *Service) UpdateSCRAMUserPassword(ctx context.Context, username, password, mechanism string, iterations int32) *rest.Error {
+	user, restErr := s.describeSCRAMUser(ctx, username)
+	if restErr != nil {
+		return restErr
+	}
+	if user == nil {
+		return scramUserNotFoundError(username)
+	}
+
+	return s.upsertSCRAMUser(ctx, username, password, mechanism, iterations)
+}
+
+// DeleteSCRAMUser deletes the passwords of all mechanisms of a user via the Kafka API.
+func (s *Service) DeleteSCRAMUser(ctx context.Context, username string) *rest.Error {
+	user, restErr := s.describeSCRAMUser(ctx, username)
+	if restErr != nil {
+		return restErr
+	}
+	if user == nil {
+		return scramUserNotFoundError(username)
+	}
+
+	deletions := make([]kadm.DeleteSCRAM, 0, len(user.Credentials))
+	for _, cred := range user.Credentials {
+		mechanism, err := ParseSCRAMMechanism(cred.Mechanism)
+		if err != nil {
+			continue
+		}
+		deletions = append(deletions, kadm.DeleteSCRAM{User: username, Mechanism: mechanism})
+	}
+
+	altered, err := s.kafkaSvc.KafkaAdmClient.AlterUserSCRAMs(ctx, deletions, nil)
+	if err == nil {
+		err = altered.Error()
+	}
+	if err != nil {
+		return scramRestError("delete user SCRAM credentials", err)
+	}
+	return nil
+}
+
+func (s *Service) upsertSCRAMUser(ctx context.Context, username, password, mechanism string, iterations int32) *rest.Error {
+	scramMechanism, err := ParseSCRAMMechanism(mechanism)
+	if err != nil {
+		return &rest.Error{
+			Err:      err,
+			Status:   http.StatusBadRequest,
+			Message:  err.Error(),
+			IsSilent: false,
+		}
+	}
+	if iterations == 0 {
+		iterations = SCRAMMinIterations
+	}
+
+	altered, err := s.kafkaSvc.KafkaAdmClient.AlterUserSCRAMs(ctx, nil, []kadm.UpsertSCRAM{{
+		User:       username,
+		Mechanism:  scramMechanism,
+		Iterations: iterations,
+		Password:   password,
+	}})
+	if err == nil {
+		err = altered.Error()
+	}
+	if err != nil {
+		return scramRestError("alter user SCRAM credentials", err)
+	}
+	return nil
+}
+
+// describeSCRAMUser returns the user's SCRAM credentials or nil if the user has none.
+func (s *Service) describeSCRAMUser(ctx context.Context, username string) (*SCRAMUser, *rest.Error) {
+	described, err := s.kafkaSvc.KafkaAdmClient.DescribeUserSCRAMs(ctx, username)
+	if err != nil {
+		return nil, scramRestError("describe user SCRAM credentials", err)
+	}
+
+	user, exists := described[username]
+	if !exists || errors.Is(user.Err, kerr.ResourceNotFound) || len(user.CredInfos) == 0 {
+		return nil, nil
+	}
+	if user.Err != nil {
+		return nil, scramRestError("describe user SCRAM credentials", user.Err)
+	}
+
+	res := newSCRAMUser(user)
+	return &res, nil
+}
+
+func newSCRAMUser(user kadm.DescribedUserSCRAM) SCRAMUser {
+	creds := make([]SCRAMCredential, len(user.CredInfos))
+	for i, cred := range user.CredInfos {
+		creds[i] = SCRAMCredential{Mechanism: cred.Mechanism.String(), Iterations: cred.Iterations}
+	}
+	return SCRAMUser{Name: user.User, Credentials: creds}
+}
+
+func scramUserNotFoundError(username string) *rest.Error {
+	return &rest.Error{
+		Err:      fmt.Errorf("user %q has no SCRAM credentials", username),
+		Status:   http.StatusNotFound,
+		Message:  fmt.Sprintf("User '%v' does not exist", username),
+		IsSilent: false,
+	}
+}
+
+func scramRestError(action string, err error) *rest.Error {
+	status := http.StatusServiceUnavailable
+	var authErr *kadm.AuthError
+	if errors.As(err, &authErr) {
+		status = http.StatusForbidden
+	}
+	return &rest.Error{
+		Err:      fmt.Errorf("failed to %v: %w", action, err),
+		Status:   status,
+		Message:  fmt.Sprintf("Failed to %v: %v", action, err.Error()),
+		IsSilent: false,
+	}
+}
@@ -0,0 +1,18 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file licenses/BSL.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package embed
+
+import _ "embed"
+
+// TopicLintRules embeds the default YAML rule file that topic configurations are linted
+// against. Users may replace it with their own rule file via the config.
+//
+//go:embed lint/topic_lint_rules.yaml
+var TopicLintRules []byte
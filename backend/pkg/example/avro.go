@@ -0,0 +1,216 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package example
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"time"
+
+	"github.com/hamba/avro/v2"
+)
+
+// Avro generates an example value for the given Avro schema. It returns the Avro encoded
+// binary and a JSON compatible representation of the same value. The generated value is
+// reproducible, however the order of map entries in the encoded binary may vary.
+func (g *Generator) Avro(schema avro.Schema) ([]byte, any, error) {
+	native, jsonValue, err := g.avroValue(schema, "", 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encoded, err := avro.Marshal(schema, native)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode generated value: %w", err)
+	}
+
+	return encoded, jsonValue, nil
+}
+
+// avroValue returns the generated value in the Go native form that is expected by the Avro
+// encoder and in a JSON compatible form that follows the Avro JSON encoding for unions.
+func (g *Generator) avroValue(schema avro.Schema, name string, depth int) (any, any, error) {
+	switch s := schema.(type) {
+	case *avro.RefSchema:
+		return g.avroValue(s.Schema(), name, depth)
+
+	case *avro.RecordSchema:
+		if depth > maxDepth {
+			return nil, nil, fmt.Errorf("record %q exceeds the maximum nesting depth, it is likely a non-terminating recursive type", s.FullName())
+		}
+		native := make(map[string]any, len(s.Fields()))
+		jsonValue := make(map[string]any, len(s.Fields()))
+		for _, field := range s.Fields() {
+			n, j, err := g.avroValue(field.Type(), field.Name(), depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			native[field.Name()] = n
+			jsonValue[field.Name()] = j
+		}
+		return native, jsonValue, nil
+
+	case *avro.EnumSchema:
+		symbol := s.Symbols()[g.rng.Intn(len(s.Symbols()))]
+		return symbol, symbol, nil
+
+	case *avro.ArraySchema:
+		size := g.collectionSize(depth)
+		native := make([]any, 0, size)
+		jsonValue := make([]any, 0, size)
+		for i := 0; i < size; i++ {
+			n, j, err := g.avroValue(s.Items(), name, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			native = append(native, n)
+			jsonValue = append(jsonValue, j)
+		}
+		return native, jsonValue, nil
+
+	case *avro.MapSchema:
+		size := g.collectionSize(depth)
+		native := make(map[string]any, size)
+		jsonValue := make(map[string]any, size)
+		for i := 0; i < size; i++ {
+			key := g.pick(words)
+			n, j, err := g.avroValue(s.Values(), name, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			native[key] = n
+			jsonValue[key] = j
+		}
+		return native, jsonValue, nil
+
+	case *avro.UnionSchema:
+		return g.avroUnion(s, name, depth)
+
+	case *avro.FixedSchema:
+		if decimal, ok := s.Logical().(*avro.DecimalLogicalSchema); ok {
+			return g.avroDecimal(decimal)
+		}
+		b := g.bytes(s.Size())
+		// The Avro encoder expects an array with the exact size of the fixed type
+		arr := reflect.New(reflect.ArrayOf(s.Size(), reflect.TypeOf(byte(0)))).Elem()
+		reflect.Copy(arr, reflect.ValueOf(b))
+		return arr.Interface(), base64.StdEncoding.EncodeToString(b), nil
+
+	case *avro.PrimitiveSchema:
+		return g.avroPrimitive(s, name)
+
+	default:
+		return nil, nil, fmt.Errorf("unsupported avro schema type %q", schema.Type())
+	}
+}
+
+func (g *Generator) avroUnion(s *avro.UnionSchema, name string, depth int) (any, any, error) {
+	types := s.Types()
+	candidates := make([]avro.Schema, 0, len(types))
+	for _, t := range types {
+		if t.Type() != avro.Null {
+			candidates = append(candidates, t)
+		}
+	}
+	if len(candidates) == 0 || (s.Nullable() && !g.includeOptional(depth)) {
+		return nil, nil, nil
+	}
+
+	chosen := candidates[g.rng.Intn(len(candidates))]
+	n, j, err := g.avroValue(chosen, name, depth)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	typeName := avroUnionTypeName(chosen)
+	return map[string]any{typeName: n}, map[string]any{typeName: j}, nil
+}
+
+// avroUnionTypeName returns the name that identifies a type within a union. It must match
+// the naming that is used by the Avro encoder.
+func avroUnionTypeName(schema avro.Schema) string {
+	if ref, ok := schema.(*avro.RefSchema); ok {
+		schema = ref.Schema()
+	}
+	if named, ok := schema.(avro.NamedSchema); ok {
+		return named.FullName()
+	}
+
+	name := string(schema.Type())
+	if logical, ok := schema.(avro.LogicalTypeSchema); ok && logical.Logical() != nil {
+		name += "." + string(logical.Logical().Type())
+	}
+	return name
+}
+
+func (g *Generator) avroDecimal(decimal *avro.DecimalLogicalSchema) (any, any, error) {
+	// Keep the unscaled value within the precision of the decimal type
+	digits := decimal.Precision()
+	if digits > 9 {
+		digits = 9
+	}
+	unscaled := g.rng.Int63n(int64(math.Pow10(digits)))
+	value := new(big.Rat).SetFrac(big.NewInt(unscaled), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimal.Scale())), nil))
+	return value, value.FloatString(decimal.Scale()), nil
+}
+
+func (g *Generator) avroPrimitive(s *avro.PrimitiveSchema, name string) (any, any, error) {
+	if logical := s.Logical(); logical != nil {
+		switch logical.Type() {
+		case avro.Decimal:
+			if decimal, ok := logical.(*avro.DecimalLogicalSchema); ok {
+				return g.avroDecimal(decimal)
+			}
+		case avro.UUID:
+			uuid := g.uuid()
+			return uuid, uuid, nil
+		case avro.Date:
+			t := g.timestamp().Truncate(24 * time.Hour)
+			return t, t.Format("2006-01-02"), nil
+		case avro.TimeMillis, avro.TimeMicros:
+			d := time.Duration(g.rng.Int63n(int64(24 * time.Hour))).Truncate(time.Millisecond)
+			return d, time.Time{}.Add(d).Format("15:04:05.000"), nil
+		case avro.TimestampMillis, avro.TimestampMicros:
+			t := g.timestamp()
+			return t, t.Format(time.RFC3339Nano), nil
+		}
+	}
+
+	switch s.Type() {
+	case avro.Null:
+		return nil, nil, nil
+	case avro.Boolean:
+		b := g.rng.Intn(2) == 1
+		return b, b, nil
+	case avro.Int:
+		i := int(g.intForName(name, math.MinInt32, math.MaxInt32))
+		return i, i, nil
+	case avro.Long:
+		i := g.intForName(name, math.MinInt64, math.MaxInt64)
+		return i, i, nil
+	case avro.Float:
+		f := float32(g.floatForName(name))
+		return f, f, nil
+	case avro.Double:
+		f := g.floatForName(name)
+		return f, f, nil
+	case avro.String:
+		str := g.stringForName(name)
+		return str, str, nil
+	case avro.Bytes:
+		b := []byte(g.stringForName(name))
+		return b, string(b), nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported avro primitive type %q", s.Type())
+	}
+}
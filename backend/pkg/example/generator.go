@@ -0,0 +1,209 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package example generates realistic example payloads that conform to an Avro schema,
+// a Protobuf message type or a JSON schema. Generated payloads are reproducible by
+// using the same seed.
+package example
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// OptionalFields controls whether optional fields are populated in generated payloads.
+// Optional fields are nullable Avro unions, proto3 optional fields, message typed
+// Protobuf fields and JSON schema properties that are not required.
+type OptionalFields string
+
+const (
+	// OptionalFieldsRandom populates optional fields randomly (default).
+	OptionalFieldsRandom OptionalFields = "random"
+	// OptionalFieldsAll populates all optional fields.
+	OptionalFieldsAll OptionalFields = "all"
+	// OptionalFieldsNone leaves all optional fields unset.
+	OptionalFieldsNone OptionalFields = "none"
+)
+
+// maxDepth is the maximum nesting depth for generated documents. Recursive types are
+// terminated by leaving optional fields empty once this depth has been reached.
+const maxDepth = 8
+
+// referenceTime is the point in time around which all timestamps are generated. We
+// don't use the current time so that payloads remain reproducible for a given seed.
+var referenceTime = time.Date(2023, time.June, 1, 12, 0, 0, 0, time.UTC)
+
+// Generator generates example values. A Generator is not safe for concurrent use.
+type Generator struct {
+	rng            *rand.Rand
+	optionalFields OptionalFields
+}
+
+// NewGenerator creates a new Generator. Two generators with the same seed generate
+// the same payloads for the same schema.
+func NewGenerator(seed int64, optionalFields OptionalFields) *Generator {
+	if optionalFields == "" {
+		optionalFields = OptionalFieldsRandom
+	}
+	//nolint:gosec // Example payloads must be reproducible, hence we use a seeded pseudo random generator
+	return &Generator{
+		rng:            rand.New(rand.NewSource(seed)),
+		optionalFields: optionalFields,
+	}
+}
+
+// includeOptional decides whether an optional field shall be populated.
+func (g *Generator) includeOptional(depth int) bool {
+	if depth >= maxDepth {
+		return false
+	}
+	switch g.optionalFields {
+	case OptionalFieldsAll:
+		return true
+	case OptionalFieldsNone:
+		return false
+	default:
+		return g.rng.Intn(4) != 0
+	}
+}
+
+// collectionSize returns the number of items that shall be generated for arrays and maps.
+func (g *Generator) collectionSize(depth int) int {
+	if depth >= maxDepth {
+		return 0
+	}
+	return 1 + g.rng.Intn(3)
+}
+
+var (
+	firstNames = []string{"Jane", "John", "Maria", "Ahmed", "Yuki", "Lena", "Carlos", "Priya", "Tom", "Amara"}
+	lastNames  = []string{"Doe", "Smith", "Garcia", "Khan", "Tanaka", "Novak", "Silva", "Patel", "Miller", "Okafor"}
+	cities     = []string{"Berlin", "London", "New York", "Tokyo", "São Paulo", "Lagos", "Mumbai", "Sydney", "Toronto", "Paris"}
+	countries  = []string{"DE", "GB", "US", "JP", "BR", "NG", "IN", "AU", "CA", "FR"}
+	currencies = []string{"EUR", "GBP", "USD", "JPY", "BRL", "NGN", "INR", "AUD", "CAD"}
+	streets    = []string{"Main Street", "High Street", "Park Avenue", "Oak Lane", "Station Road", "Church Street"}
+	words      = []string{"alpha", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel", "india", "juliet", "kilo", "lima"}
+	statuses   = []string{"ACTIVE", "PENDING", "COMPLETED", "CANCELLED"}
+)
+
+func (g *Generator) pick(values []string) string {
+	return values[g.rng.Intn(len(values))]
+}
+
+// uuid returns a random (version 4) UUID that is derived from the generator's seed.
+func (g *Generator) uuid() string {
+	b := make([]byte, 16)
+	_, _ = g.rng.Read(b) // Read on a math/rand.Rand never returns an error
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func (g *Generator) timestamp() time.Time {
+	offset := time.Duration(g.rng.Int63n(int64(365 * 24 * time.Hour)))
+	return referenceTime.Add(-offset).Truncate(time.Millisecond)
+}
+
+// stringForName returns a realistic string based on the name of the field it will be used for.
+// If the field name does not give a hint, a random word will be returned.
+func (g *Generator) stringForName(name string) string {
+	n := strings.ToLower(name)
+	switch {
+	case strings.Contains(n, "email") || strings.Contains(n, "mail"):
+		return fmt.Sprintf("%s.%s@example.com", strings.ToLower(g.pick(firstNames)), strings.ToLower(g.pick(lastNames)))
+	case strings.Contains(n, "uuid") || strings.Contains(n, "guid") || n == "id" || strings.HasSuffix(n, "_id") || strings.HasSuffix(name, "Id"):
+		return g.uuid()
+	case strings.Contains(n, "url") || strings.Contains(n, "uri") || strings.Contains(n, "link"):
+		return fmt.Sprintf("https://example.com/%s/%d", g.pick(words), g.rng.Intn(10000))
+	case strings.Contains(n, "phone") || strings.Contains(n, "mobile"):
+		return fmt.Sprintf("+1-555-%04d", g.rng.Intn(10000))
+	case strings.Contains(n, "first") && strings.Contains(n, "name"):
+		return g.pick(firstNames)
+	case (strings.Contains(n, "last") && strings.Contains(n, "name")) || strings.Contains(n, "surname"):
+		return g.pick(lastNames)
+	case strings.Contains(n, "name"):
+		return g.pick(firstNames) + " " + g.pick(lastNames)
+	case strings.Contains(n, "country"):
+		return g.pick(countries)
+	case strings.Contains(n, "city"):
+		return g.pick(cities)
+	case strings.Contains(n, "currency"):
+		return g.pick(currencies)
+	case strings.Contains(n, "street") || strings.Contains(n, "address"):
+		return fmt.Sprintf("%d %s", 1+g.rng.Intn(200), g.pick(streets))
+	case strings.Contains(n, "zip") || strings.Contains(n, "postal"):
+		return fmt.Sprintf("%05d", g.rng.Intn(100000))
+	case n == "ip" || strings.Contains(n, "ip_address") || strings.Contains(n, "ipaddress"):
+		return fmt.Sprintf("10.%d.%d.%d", g.rng.Intn(256), g.rng.Intn(256), 1+g.rng.Intn(254))
+	case strings.Contains(n, "status") || strings.Contains(n, "state"):
+		return g.pick(statuses)
+	case strings.Contains(n, "timestamp") || strings.Contains(n, "time") || strings.HasSuffix(n, "_at") || strings.HasSuffix(name, "At"):
+		return g.timestamp().Format(time.RFC3339)
+	case strings.Contains(n, "date"):
+		return g.timestamp().Format("2006-01-02")
+	default:
+		return g.pick(words) + "-" + g.pick(words)
+	}
+}
+
+// intForName returns a realistic integer in the range [minValue, maxValue] based on the name
+// of the field it will be used for.
+func (g *Generator) intForName(name string, minValue, maxValue int64) int64 {
+	n := strings.ToLower(name)
+	lo, hi := int64(0), int64(1000)
+	switch {
+	case n == "age" || strings.HasSuffix(n, "_age"):
+		lo, hi = 18, 90
+	case strings.Contains(n, "year"):
+		lo, hi = 1990, 2030
+	case strings.Contains(n, "quantity") || strings.Contains(n, "count"):
+		lo, hi = 1, 100
+	case strings.Contains(n, "timestamp") || strings.HasSuffix(n, "_at") || strings.HasSuffix(name, "At"):
+		t := g.timestamp().UnixMilli()
+		lo, hi = t, t
+	}
+	if lo < minValue {
+		lo = minValue
+	}
+	if hi > maxValue {
+		hi = maxValue
+	}
+	if lo > hi {
+		lo = hi
+	}
+	span := hi - lo + 1
+	if span <= 0 {
+		// The range overflows int64, so any value will do
+		return g.rng.Int63()
+	}
+	return lo + g.rng.Int63n(span)
+}
+
+// floatForName returns a realistic float with at most two decimal places.
+func (g *Generator) floatForName(name string) float64 {
+	n := strings.ToLower(name)
+	switch {
+	case n == "lat" || strings.Contains(n, "latitude"):
+		return float64(g.rng.Intn(18000)-9000) / 100
+	case n == "lon" || n == "lng" || strings.Contains(n, "longitude"):
+		return float64(g.rng.Intn(36000)-18000) / 100
+	case strings.Contains(n, "percent") || strings.Contains(n, "ratio") || strings.Contains(n, "rate"):
+		return float64(g.rng.Intn(10000)) / 100
+	default:
+		return float64(g.rng.Intn(100000)) / 100
+	}
+}
+
+func (g *Generator) bytes(size int) []byte {
+	b := make([]byte, size)
+	_, _ = g.rng.Read(b) // Read on a math/rand.Rand never returns an error
+	return b
+}
@@ -0,0 +1,157 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package example
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hamba/avro/v2"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"github.com/jhump/protoreflect/dynamic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAvroSchema = `{
+	"type": "record",
+	"name": "Order",
+	"namespace": "shop",
+	"fields": [
+		{"name": "id", "type": {"type": "string", "logicalType": "uuid"}},
+		{"name": "status", "type": {"type": "enum", "name": "Status", "symbols": ["NEW", "SHIPPED"]}},
+		{"name": "createdAt", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+		{"name": "amount", "type": {"type": "bytes", "logicalType": "decimal", "precision": 10, "scale": 2}},
+		{"name": "customer", "type": ["null", {
+			"type": "record",
+			"name": "Customer",
+			"fields": [
+				{"name": "email", "type": "string"},
+				{"name": "referrer", "type": ["null", "Customer"], "default": null}
+			]
+		}], "default": null},
+		{"name": "tags", "type": {"type": "map", "values": "string"}},
+		{"name": "payment", "type": ["string", "long", {"type": "fixed", "name": "Token", "size": 4}]}
+	]
+}`
+
+func TestGenerator_Avro(t *testing.T) {
+	schema, err := avro.Parse(testAvroSchema)
+	require.NoError(t, err)
+
+	encoded, jsonValue, err := NewGenerator(42, OptionalFieldsAll).Avro(schema)
+	require.NoError(t, err)
+
+	// The same seed must generate the same payload
+	encoded2, jsonValue2, err := NewGenerator(42, OptionalFieldsAll).Avro(schema)
+	require.NoError(t, err)
+	assert.Equal(t, jsonValue, jsonValue2)
+
+	// The encoded payload must be decodable with the schema
+	var decoded, decoded2 map[string]any
+	require.NoError(t, avro.Unmarshal(schema, encoded, &decoded))
+	require.NoError(t, avro.Unmarshal(schema, encoded2, &decoded2))
+	assert.Equal(t, decoded, decoded2)
+	assert.Contains(t, []string{"NEW", "SHIPPED"}, decoded["status"])
+	assert.NotNil(t, decoded["customer"])
+
+	// Recursive types must terminate
+	_, err = json.Marshal(jsonValue)
+	require.NoError(t, err)
+}
+
+func TestGenerator_Protobuf(t *testing.T) {
+	parser := protoparse.Parser{
+		Accessor: protoparse.FileContentsFromMap(map[string]string{"order.proto": `
+syntax = "proto3";
+package shop.v1;
+
+import "google/protobuf/timestamp.proto";
+
+message Order {
+  enum Status {
+    STATUS_UNSPECIFIED = 0;
+    STATUS_NEW = 1;
+  }
+  string order_id = 1;
+  Status status = 2;
+  google.protobuf.Timestamp created_at = 3;
+  repeated Item items = 4;
+  map<string, int32> quantities = 5;
+  oneof payment {
+    string card_number = 6;
+    string iban = 7;
+  }
+  optional string note = 8;
+  Order parent = 9;
+}
+
+message Item {
+  string name = 1;
+  double price = 2;
+}`}),
+	}
+	fds, err := parser.ParseFiles("order.proto")
+	require.NoError(t, err)
+	md := fds[0].FindMessage("shop.v1.Order")
+	require.NotNil(t, md)
+
+	encoded, jsonBytes, err := NewGenerator(7, OptionalFieldsAll).Protobuf(md)
+	require.NoError(t, err)
+
+	msg := dynamic.NewMessage(md)
+	require.NoError(t, msg.Unmarshal(encoded))
+	assert.Equal(t, int32(1), msg.GetFieldByName("status"))
+	assert.NotEmpty(t, msg.GetFieldByName("items"))
+
+	// Exactly one field of the oneof must be set
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal(jsonBytes, &payload))
+	_, hasCard := payload["cardNumber"]
+	_, hasIban := payload["iban"]
+	assert.True(t, hasCard != hasIban)
+}
+
+func TestGenerator_JSONSchema(t *testing.T) {
+	schema := `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type": "object",
+		"required": ["id", "address"],
+		"properties": {
+			"id": {"type": "string", "format": "uuid"},
+			"age": {"type": "integer", "minimum": 18, "maximum": 20},
+			"kind": {"enum": ["a", "b"]},
+			"address": {"$ref": "#/definitions/address"}
+		},
+		"definitions": {
+			"address": {
+				"type": "object",
+				"required": ["city"],
+				"properties": {"city": {"type": "string"}}
+			}
+		}
+	}`
+
+	jsonBytes, err := NewGenerator(1, OptionalFieldsNone).JSONSchema([]byte(schema))
+	require.NoError(t, err)
+
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal(jsonBytes, &payload))
+	assert.Len(t, payload, 2)
+	assert.Contains(t, payload["address"], "city")
+
+	jsonBytes, err = NewGenerator(1, OptionalFieldsAll).JSONSchema([]byte(schema))
+	require.NoError(t, err)
+	payload = nil
+	require.NoError(t, json.Unmarshal(jsonBytes, &payload))
+	assert.Len(t, payload, 4)
+	assert.GreaterOrEqual(t, payload["age"], float64(18))
+	assert.LessOrEqual(t, payload["age"], float64(20))
+}
@@ -0,0 +1,281 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package example
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// JSONSchema generates an example document for the given JSON schema. Only local references
+// (e.g. "#/definitions/address" or "#/$defs/address") are resolved.
+func (g *Generator) JSONSchema(schema []byte) ([]byte, error) {
+	var root any
+	if err := json.Unmarshal(schema, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON schema: %w", err)
+	}
+
+	value, err := g.jsonSchemaValue(root, root, "", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(value)
+}
+
+func (g *Generator) jsonSchemaValue(root any, schema any, name string, depth int) (any, error) {
+	if depth > maxDepth*2 {
+		return nil, fmt.Errorf("JSON schema exceeds the maximum nesting depth, it is likely a non-terminating recursive type")
+	}
+
+	s, ok := schema.(map[string]any)
+	if !ok {
+		// Boolean schemas ("true" accepts any value) and unknown schema forms
+		return nil, nil
+	}
+
+	if ref, ok := s["$ref"].(string); ok {
+		resolved, err := resolveJSONPointer(root, ref)
+		if err != nil {
+			return nil, err
+		}
+		return g.jsonSchemaValue(root, resolved, name, depth+1)
+	}
+
+	if c, ok := s["const"]; ok {
+		return c, nil
+	}
+	if enum, ok := s["enum"].([]any); ok && len(enum) > 0 {
+		return enum[g.rng.Intn(len(enum))], nil
+	}
+	if examples, ok := s["examples"].([]any); ok && len(examples) > 0 {
+		return examples[0], nil
+	}
+	if def, ok := s["default"]; ok {
+		return def, nil
+	}
+
+	for _, keyword := range []string{"oneOf", "anyOf"} {
+		if options, ok := s[keyword].([]any); ok && len(options) > 0 {
+			return g.jsonSchemaValue(root, options[g.rng.Intn(len(options))], name, depth+1)
+		}
+	}
+	if allOf, ok := s["allOf"].([]any); ok && len(allOf) > 0 {
+		return g.jsonSchemaAllOf(root, s, allOf, name, depth)
+	}
+
+	switch g.jsonSchemaType(s) {
+	case "object":
+		return g.jsonSchemaObject(root, s, depth)
+	case "array":
+		size := g.collectionSize(depth)
+		if minItems, ok := s["minItems"].(float64); ok && size < int(minItems) {
+			size = int(minItems)
+		}
+		if maxItems, ok := s["maxItems"].(float64); ok && size > int(maxItems) {
+			size = int(maxItems)
+		}
+		items := make([]any, 0, size)
+		for i := 0; i < size; i++ {
+			item, err := g.jsonSchemaValue(root, s["items"], name, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case "string":
+		return g.jsonSchemaString(s, name), nil
+	case "integer":
+		minValue, maxValue := jsonSchemaBounds(s, math.MinInt32, math.MaxInt32)
+		return g.intForName(name, int64(minValue), int64(maxValue)), nil
+	case "number":
+		minValue, maxValue := jsonSchemaBounds(s, -math.MaxFloat64, math.MaxFloat64)
+		return math.Min(math.Max(g.floatForName(name), minValue), maxValue), nil
+	case "boolean":
+		return g.rng.Intn(2) == 1, nil
+	default:
+		return nil, nil
+	}
+}
+
+// jsonSchemaType returns the type of the given schema. If multiple types are allowed the first
+// non-null type is returned. If no type is specified it is derived from the used keywords.
+func (*Generator) jsonSchemaType(s map[string]any) string {
+	switch t := s["type"].(type) {
+	case string:
+		return t
+	case []any:
+		for _, item := range t {
+			if str, ok := item.(string); ok && str != "null" {
+				return str
+			}
+		}
+		return "null"
+	}
+
+	if _, ok := s["properties"]; ok {
+		return "object"
+	}
+	if _, ok := s["items"]; ok {
+		return "array"
+	}
+	return ""
+}
+
+func (g *Generator) jsonSchemaObject(root any, s map[string]any, depth int) (any, error) {
+	properties, _ := s["properties"].(map[string]any)
+	required := make(map[string]struct{})
+	if requiredList, ok := s["required"].([]any); ok {
+		for _, r := range requiredList {
+			if str, ok := r.(string); ok {
+				required[str] = struct{}{}
+			}
+		}
+	}
+
+	// Iterate in a stable order so that the generated document is reproducible
+	names := make([]string, 0, len(properties))
+	for propName := range properties {
+		names = append(names, propName)
+	}
+	sort.Strings(names)
+
+	obj := make(map[string]any, len(properties))
+	for _, propName := range names {
+		if _, isRequired := required[propName]; !isRequired && !g.includeOptional(depth) {
+			continue
+		}
+		value, err := g.jsonSchemaValue(root, properties[propName], propName, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		obj[propName] = value
+	}
+
+	return obj, nil
+}
+
+// jsonSchemaAllOf generates a value for each of the sub schemas. Generated objects are merged,
+// for all other types the last generated value wins.
+func (g *Generator) jsonSchemaAllOf(root any, s map[string]any, allOf []any, name string, depth int) (any, error) {
+	var result any
+	merged := make(map[string]any)
+	isObject := false
+
+	// The schema may define further keywords next to allOf
+	rest := make(map[string]any, len(s))
+	for k, v := range s {
+		if k != "allOf" {
+			rest[k] = v
+		}
+	}
+	schemas := append([]any{rest}, allOf...)
+
+	for _, subSchema := range schemas {
+		value, err := g.jsonSchemaValue(root, subSchema, name, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		if obj, ok := value.(map[string]any); ok {
+			isObject = true
+			for k, v := range obj {
+				merged[k] = v
+			}
+			continue
+		}
+		if value != nil {
+			result = value
+		}
+	}
+
+	if isObject {
+		return merged, nil
+	}
+	return result, nil
+}
+
+func (g *Generator) jsonSchemaString(s map[string]any, name string) string {
+	var str string
+	switch format, _ := s["format"].(string); format {
+	case "date-time":
+		str = g.timestamp().Format(time.RFC3339)
+	case "date":
+		str = g.timestamp().Format("2006-01-02")
+	case "time":
+		str = g.timestamp().Format("15:04:05")
+	case "email":
+		str = g.stringForName("email")
+	case "uuid":
+		str = g.uuid()
+	case "uri", "url":
+		str = g.stringForName("url")
+	case "ipv4":
+		str = g.stringForName("ip")
+	case "hostname":
+		str = g.pick(words) + ".example.com"
+	default:
+		str = g.stringForName(name)
+	}
+
+	if minLength, ok := s["minLength"].(float64); ok && len(str) < int(minLength) {
+		str += strings.Repeat("x", int(minLength)-len(str))
+	}
+	if maxLength, ok := s["maxLength"].(float64); ok && len(str) > int(maxLength) {
+		str = str[:int(maxLength)]
+	}
+	return str
+}
+
+// jsonSchemaBounds returns the inclusive bounds for numeric values.
+func jsonSchemaBounds(s map[string]any, minValue, maxValue float64) (float64, float64) {
+	if v, ok := s["minimum"].(float64); ok {
+		minValue = v
+	}
+	if v, ok := s["exclusiveMinimum"].(float64); ok {
+		minValue = v + 1
+	}
+	if v, ok := s["maximum"].(float64); ok {
+		maxValue = v
+	}
+	if v, ok := s["exclusiveMaximum"].(float64); ok {
+		maxValue = v - 1
+	}
+	return minValue, maxValue
+}
+
+// resolveJSONPointer resolves a local reference such as "#/definitions/address".
+func resolveJSONPointer(root any, ref string) (any, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("unsupported JSON schema reference %q, only local references are supported", ref)
+	}
+
+	current := root
+	for _, token := range strings.Split(strings.TrimPrefix(strings.TrimPrefix(ref, "#"), "/"), "/") {
+		if token == "" {
+			continue
+		}
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		obj, ok := current.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("failed to resolve JSON schema reference %q", ref)
+		}
+		current, ok = obj[token]
+		if !ok {
+			return nil, fmt.Errorf("failed to resolve JSON schema reference %q", ref)
+		}
+	}
+
+	return current, nil
+}
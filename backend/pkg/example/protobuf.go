@@ -0,0 +1,169 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package example
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/dynamic"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// Protobuf generates an example message for the given message descriptor. It returns the
+// Protobuf encoded binary and the JSON representation of the same message.
+func (g *Generator) Protobuf(md *desc.MessageDescriptor) ([]byte, []byte, error) {
+	msg, err := g.protoMessage(md, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encoded, err := msg.Marshal()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode generated message: %w", err)
+	}
+	jsonBytes, err := msg.MarshalJSONPB(&jsonpb.Marshaler{EmitDefaults: true})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal generated message to JSON: %w", err)
+	}
+
+	return encoded, jsonBytes, nil
+}
+
+func (g *Generator) protoMessage(md *desc.MessageDescriptor, depth int) (*dynamic.Message, error) {
+	msg := dynamic.NewMessage(md)
+
+	switch md.GetFullyQualifiedName() {
+	case "google.protobuf.Timestamp":
+		t := g.timestamp()
+		msg.SetFieldByName("seconds", t.Unix())
+		msg.SetFieldByName("nanos", int32(t.Nanosecond()))
+		return msg, nil
+	case "google.protobuf.Duration":
+		msg.SetFieldByName("seconds", g.rng.Int63n(3600))
+		return msg, nil
+	case "google.protobuf.Any", "google.protobuf.Struct", "google.protobuf.Value", "google.protobuf.ListValue":
+		// These types can carry arbitrary content, an empty message is a valid example
+		return msg, nil
+	}
+
+	if depth > maxDepth {
+		return nil, fmt.Errorf("message %q exceeds the maximum nesting depth, it is likely a non-terminating recursive type", md.GetFullyQualifiedName())
+	}
+
+	// Only one field of each oneof may be set, hence we pick one of the fields upfront
+	chosenOneOfFields := make(map[int32]struct{})
+	for _, oneOf := range md.GetOneOfs() {
+		if oneOf.IsSynthetic() || !g.includeOptional(depth) {
+			continue
+		}
+		choices := oneOf.GetChoices()
+		chosen := choices[g.rng.Intn(len(choices))]
+		chosenOneOfFields[chosen.GetNumber()] = struct{}{}
+	}
+
+	for _, field := range md.GetFields() {
+		if oneOf := field.GetOneOf(); oneOf != nil && !oneOf.IsSynthetic() {
+			if _, ok := chosenOneOfFields[field.GetNumber()]; !ok {
+				continue
+			}
+		} else if field.IsProto3Optional() || (field.GetMessageType() != nil && !field.IsRepeated()) {
+			if !g.includeOptional(depth) {
+				continue
+			}
+		}
+
+		if err := g.protoField(msg, field, depth); err != nil {
+			return nil, err
+		}
+	}
+
+	return msg, nil
+}
+
+func (g *Generator) protoField(msg *dynamic.Message, field *desc.FieldDescriptor, depth int) error {
+	switch {
+	case field.IsMap():
+		size := g.collectionSize(depth)
+		for i := 0; i < size; i++ {
+			key, err := g.protoValue(field.GetMapKeyType(), depth+1)
+			if err != nil {
+				return err
+			}
+			value, err := g.protoValue(field.GetMapValueType(), depth+1)
+			if err != nil {
+				return err
+			}
+			if err := msg.TryPutMapField(field, key, value); err != nil {
+				return fmt.Errorf("failed to set map field %q: %w", field.GetName(), err)
+			}
+		}
+	case field.IsRepeated():
+		size := g.collectionSize(depth)
+		for i := 0; i < size; i++ {
+			value, err := g.protoValue(field, depth+1)
+			if err != nil {
+				return err
+			}
+			if err := msg.TryAddRepeatedField(field, value); err != nil {
+				return fmt.Errorf("failed to add repeated field %q: %w", field.GetName(), err)
+			}
+		}
+	default:
+		value, err := g.protoValue(field, depth+1)
+		if err != nil {
+			return err
+		}
+		if err := msg.TrySetField(field, value); err != nil {
+			return fmt.Errorf("failed to set field %q: %w", field.GetName(), err)
+		}
+	}
+
+	return nil
+}
+
+// protoValue returns a single value for the given field. For repeated fields a single item is returned.
+func (g *Generator) protoValue(field *desc.FieldDescriptor, depth int) (any, error) {
+	name := field.GetName()
+
+	switch field.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, descriptorpb.FieldDescriptorProto_TYPE_GROUP:
+		return g.protoMessage(field.GetMessageType(), depth)
+	case descriptorpb.FieldDescriptorProto_TYPE_ENUM:
+		values := field.GetEnumType().GetValues()
+		// Prefer values other than the zero value, which often is an "UNSPECIFIED" placeholder
+		if len(values) > 1 {
+			return values[1+g.rng.Intn(len(values)-1)].GetNumber(), nil
+		}
+		return values[0].GetNumber(), nil
+	case descriptorpb.FieldDescriptorProto_TYPE_BOOL:
+		return g.rng.Intn(2) == 1, nil
+	case descriptorpb.FieldDescriptorProto_TYPE_STRING:
+		return g.stringForName(name), nil
+	case descriptorpb.FieldDescriptorProto_TYPE_BYTES:
+		return []byte(g.stringForName(name)), nil
+	case descriptorpb.FieldDescriptorProto_TYPE_FLOAT:
+		return float32(g.floatForName(name)), nil
+	case descriptorpb.FieldDescriptorProto_TYPE_DOUBLE:
+		return g.floatForName(name), nil
+	case descriptorpb.FieldDescriptorProto_TYPE_INT32, descriptorpb.FieldDescriptorProto_TYPE_SINT32, descriptorpb.FieldDescriptorProto_TYPE_SFIXED32:
+		return int32(g.intForName(name, math.MinInt32, math.MaxInt32)), nil
+	case descriptorpb.FieldDescriptorProto_TYPE_INT64, descriptorpb.FieldDescriptorProto_TYPE_SINT64, descriptorpb.FieldDescriptorProto_TYPE_SFIXED64:
+		return g.intForName(name, math.MinInt64, math.MaxInt64), nil
+	case descriptorpb.FieldDescriptorProto_TYPE_UINT32, descriptorpb.FieldDescriptorProto_TYPE_FIXED32:
+		return uint32(g.intForName(name, 0, math.MaxUint32)), nil
+	case descriptorpb.FieldDescriptorProto_TYPE_UINT64, descriptorpb.FieldDescriptorProto_TYPE_FIXED64:
+		return uint64(g.intForName(name, 0, math.MaxInt64)), nil
+	default:
+		return nil, fmt.Errorf("unsupported protobuf field type %q", field.GetType())
+	}
+}
@@ -0,0 +1,302 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package exporter publishes cluster, topic, partition and consumer group metrics on the
+// Prometheus registry. All values are fetched via the console service and cached, so that
+// frequent scrapes don't add load to the Kafka cluster.
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/twmb/go-cache/cache"
+	"go.uber.org/zap"
+
+	"github.com/redpanda-data/console/backend/pkg/config"
+	"github.com/redpanda-data/console/backend/pkg/console"
+)
+
+const collectTimeout = 20 * time.Second
+
+var _ prometheus.Collector = (*Collector)(nil)
+
+// Collector implements prometheus.Collector. Values are fetched when Prometheus scrapes
+// the metrics endpoint, unless a cached snapshot is still valid.
+type Collector struct {
+	cfg        config.ConsoleExporter
+	logger     *zap.Logger
+	consoleSvc console.Servicer
+
+	topicFilter *filter
+	groupFilter *filter
+
+	snapshots *cache.Cache[string, *snapshot]
+
+	// Topic metrics
+	topicPartitionCount    *prometheus.Desc
+	topicReplicationFactor *prometheus.Desc
+	topicUnderReplicated   *prometheus.Desc
+	topicOffline           *prometheus.Desc
+	topicLogDirSize        *prometheus.Desc
+	partitionHighWaterMark *prometheus.Desc
+	partitionLowWaterMark  *prometheus.Desc
+
+	// Broker metrics
+	brokerLogDirSize *prometheus.Desc
+
+	// Consumer group metrics
+	groupInfo             *prometheus.Desc
+	groupMembers          *prometheus.Desc
+	groupTopicLag         *prometheus.Desc
+	groupMaxTimeLag       *prometheus.Desc
+	groupPartitionOffset  *prometheus.Desc
+	groupPartitionLag     *prometheus.Desc
+	groupPartitionTimeLag *prometheus.Desc
+
+	// Exporter health
+	collectSuccess *prometheus.Desc
+}
+
+// snapshot contains the responses of all console service queries of a single collection.
+// Queries that failed are nil.
+type snapshot struct {
+	topics       []*console.TopicSummary
+	topicDetails []console.TopicDetails
+	brokers      []console.BrokerWithLogDirs
+	groups       []console.ConsumerGroupOverview
+}
+
+// NewCollector creates a collector that must be registered on a Prometheus registry.
+func NewCollector(cfg config.ConsoleExporter, metricsNamespace string, consoleSvc console.Servicer, logger *zap.Logger) (*Collector, error) {
+	topicFilter, err := newFilter(cfg.Topics)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create topic filter: %w", err)
+	}
+	groupFilter, err := newFilter(cfg.Groups)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create group filter: %w", err)
+	}
+
+	desc := func(name, help string, labels ...string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "kafka", name), help, labels, nil)
+	}
+
+	return &Collector{
+		cfg:         cfg,
+		logger:      logger.Named("exporter"),
+		consoleSvc:  consoleSvc,
+		topicFilter: topicFilter,
+		groupFilter: groupFilter,
+		snapshots:   cache.New[string, *snapshot](cache.MaxAge(cfg.CacheMaxAge), cache.MaxErrorAge(cfg.CacheMaxAge)),
+
+		topicPartitionCount:    desc("topic_partitions", "Number of partitions of a topic", "topic_name"),
+		topicReplicationFactor: desc("topic_replication_factor", "Replication factor of a topic", "topic_name"),
+		topicUnderReplicated:   desc("topic_under_replicated_partitions", "Number of partitions with fewer in-sync replicas than replicas", "topic_name"),
+		topicOffline:           desc("topic_offline_partitions", "Number of partitions without an available leader", "topic_name"),
+		topicLogDirSize:        desc("topic_log_dir_size_total_bytes", "Size of all replicas of a topic in bytes", "topic_name"),
+		partitionHighWaterMark: desc("topic_partition_high_water_mark", "High water mark of a partition", "topic_name", "partition_id"),
+		partitionLowWaterMark:  desc("topic_partition_low_water_mark", "Low water mark of a partition", "topic_name", "partition_id"),
+
+		brokerLogDirSize: desc("broker_log_dir_size_total_bytes", "Size of all replicas stored on a broker in bytes", "broker_id"),
+
+		groupInfo:             desc("consumer_group_info", "Consumer group state, the value is always 1", "group_id", "state", "protocol_type", "protocol"),
+		groupMembers:          desc("consumer_group_members", "Number of members of a consumer group", "group_id"),
+		groupTopicLag:         desc("consumer_group_topic_lag", "Summed lag of a consumer group in a topic", "group_id", "topic_name"),
+		groupMaxTimeLag:       desc("consumer_group_max_time_lag_seconds", "Highest estimated time lag of a consumer group", "group_id"),
+		groupPartitionOffset:  desc("consumer_group_topic_partition_offset", "Committed offset of a consumer group", "group_id", "topic_name", "partition_id"),
+		groupPartitionLag:     desc("consumer_group_topic_partition_lag", "Lag of a consumer group in a partition", "group_id", "topic_name", "partition_id"),
+		groupPartitionTimeLag: desc("consumer_group_topic_partition_time_lag_seconds", "Estimated time lag of a consumer group in a partition", "group_id", "topic_name", "partition_id"),
+
+		collectSuccess: desc("exporter_collect_success", "Whether the last collection of a metric group succeeded", "collector"),
+	}, nil
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		c.topicPartitionCount, c.topicReplicationFactor, c.topicUnderReplicated, c.topicOffline,
+		c.topicLogDirSize, c.partitionHighWaterMark, c.partitionLowWaterMark, c.brokerLogDirSize,
+		c.groupInfo, c.groupMembers, c.groupTopicLag, c.groupMaxTimeLag, c.groupPartitionOffset,
+		c.groupPartitionLag, c.groupPartitionTimeLag, c.collectSuccess,
+	} {
+		ch <- d
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	snap, _, _ := c.snapshots.Get("snapshot", func() (*snapshot, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+		defer cancel()
+		return c.collect(ctx), nil
+	})
+
+	c.collectTopics(ch, snap)
+	c.collectBrokers(ch, snap)
+	c.collectGroups(ch, snap)
+}
+
+// collect queries all data from the console service. Failed queries are logged and
+// reported via the collect success metric.
+func (c *Collector) collect(ctx context.Context) *snapshot {
+	snap := &snapshot{}
+
+	topics, err := c.consoleSvc.GetTopicsOverview(ctx)
+	if err != nil {
+		c.logger.Warn("failed to get topics overview", zap.Error(err))
+	} else {
+		snap.topics = topics
+	}
+
+	// Partitions are only described for the exported topics, so that the filters limit the
+	// load on the cluster and not only the number of series.
+	if topics != nil {
+		topicNames := make([]string, 0, len(topics))
+		for _, topic := range topics {
+			if c.topicFilter.isAllowed(topic.TopicName) {
+				topicNames = append(topicNames, topic.TopicName)
+			}
+		}
+		snap.topicDetails = make([]console.TopicDetails, 0)
+		if len(topicNames) > 0 {
+			topicDetails, restErr := c.consoleSvc.GetTopicDetails(ctx, topicNames)
+			if restErr != nil {
+				c.logger.Warn("failed to get topic details", zap.Error(restErr.Err))
+				snap.topicDetails = nil
+			} else {
+				snap.topicDetails = topicDetails
+			}
+		}
+	}
+
+	brokers, err := c.consoleSvc.GetBrokersWithLogDirs(ctx)
+	if err != nil {
+		c.logger.Warn("failed to get brokers with log dirs", zap.Error(err))
+	} else {
+		snap.brokers = brokers
+	}
+
+	groups, restErr := c.consoleSvc.GetConsumerGroupsOverview(ctx, nil, console.ConsumerGroupsOverviewOptions{
+		EstimateTimeLag: true,
+		GroupFilter:     c.groupFilter.isAllowed,
+		TopicFilter:     c.topicFilter.isAllowed,
+	})
+	if restErr != nil {
+		c.logger.Warn("failed to get consumer groups overview", zap.Error(restErr.Err))
+	} else {
+		snap.groups = groups
+	}
+
+	return snap
+}
+
+func (c *Collector) success(ch chan<- prometheus.Metric, collector string, ok bool) {
+	value := 0.0
+	if ok {
+		value = 1
+	}
+	ch <- prometheus.MustNewConstMetric(c.collectSuccess, prometheus.GaugeValue, value, collector)
+}
+
+func (c *Collector) collectTopics(ch chan<- prometheus.Metric, snap *snapshot) {
+	c.success(ch, "topics", snap.topics != nil)
+	for _, topic := range snap.topics {
+		if !c.topicFilter.isAllowed(topic.TopicName) {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(c.topicPartitionCount, prometheus.GaugeValue, float64(topic.PartitionCount), topic.TopicName)
+		ch <- prometheus.MustNewConstMetric(c.topicReplicationFactor, prometheus.GaugeValue, float64(topic.ReplicationFactor), topic.TopicName)
+		if topic.LogDirSummary.TotalSizeBytes >= 0 {
+			ch <- prometheus.MustNewConstMetric(c.topicLogDirSize, prometheus.GaugeValue, float64(topic.LogDirSummary.TotalSizeBytes), topic.TopicName)
+		}
+	}
+
+	c.success(ch, "topic_partitions", snap.topicDetails != nil)
+	for _, topic := range snap.topicDetails {
+		if topic.Error != "" || !c.topicFilter.isAllowed(topic.TopicName) {
+			continue
+		}
+		underReplicated, offline := 0, 0
+		for _, p := range topic.Partitions {
+			if p.TopicPartitionMetadata == nil {
+				continue
+			}
+			if p.PartitionError != "" {
+				// Kafka responds with an error (e.g. LEADER_NOT_AVAILABLE) for offline partitions
+				offline++
+				continue
+			}
+			if p.Leader < 0 {
+				offline++
+			}
+			if len(p.InSyncReplicas) < len(p.Replicas) {
+				underReplicated++
+			}
+
+			if !c.cfg.PartitionMetrics || p.TopicPartitionMarks == nil || p.WaterMarksError != "" {
+				continue
+			}
+			partitionID := strconv.Itoa(int(p.ID))
+			ch <- prometheus.MustNewConstMetric(c.partitionHighWaterMark, prometheus.GaugeValue, float64(p.High), topic.TopicName, partitionID)
+			ch <- prometheus.MustNewConstMetric(c.partitionLowWaterMark, prometheus.GaugeValue, float64(p.Low), topic.TopicName, partitionID)
+		}
+		ch <- prometheus.MustNewConstMetric(c.topicUnderReplicated, prometheus.GaugeValue, float64(underReplicated), topic.TopicName)
+		ch <- prometheus.MustNewConstMetric(c.topicOffline, prometheus.GaugeValue, float64(offline), topic.TopicName)
+	}
+}
+
+func (c *Collector) collectBrokers(ch chan<- prometheus.Metric, snap *snapshot) {
+	c.success(ch, "brokers", snap.brokers != nil)
+	for _, broker := range snap.brokers {
+		if broker.TotalLogDirSizeBytes == nil {
+			continue
+		}
+		brokerID := strconv.Itoa(int(broker.BrokerID))
+		ch <- prometheus.MustNewConstMetric(c.brokerLogDirSize, prometheus.GaugeValue, float64(*broker.TotalLogDirSizeBytes), brokerID)
+	}
+}
+
+func (c *Collector) collectGroups(ch chan<- prometheus.Metric, snap *snapshot) {
+	c.success(ch, "consumer_groups", snap.groups != nil)
+	for _, group := range snap.groups {
+		if !c.groupFilter.isAllowed(group.GroupID) {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(c.groupInfo, prometheus.GaugeValue, 1, group.GroupID, group.State, group.ProtocolType, group.Protocol)
+		ch <- prometheus.MustNewConstMetric(c.groupMembers, prometheus.GaugeValue, float64(len(group.Members)), group.GroupID)
+		if group.MaxTimeLagSeconds != nil {
+			ch <- prometheus.MustNewConstMetric(c.groupMaxTimeLag, prometheus.GaugeValue, *group.MaxTimeLagSeconds, group.GroupID)
+		}
+
+		for _, topic := range group.TopicOffsets {
+			if !c.topicFilter.isAllowed(topic.Topic) {
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(c.groupTopicLag, prometheus.GaugeValue, float64(topic.SummedLag), group.GroupID, topic.Topic)
+			if !c.cfg.PartitionMetrics {
+				continue
+			}
+			for _, p := range topic.PartitionOffsets {
+				if p.Error != "" {
+					continue
+				}
+				partitionID := strconv.Itoa(int(p.PartitionID))
+				ch <- prometheus.MustNewConstMetric(c.groupPartitionOffset, prometheus.GaugeValue, float64(p.GroupOffset), group.GroupID, topic.Topic, partitionID)
+				ch <- prometheus.MustNewConstMetric(c.groupPartitionLag, prometheus.GaugeValue, float64(p.Lag), group.GroupID, topic.Topic, partitionID)
+				if p.TimeLagSeconds != nil {
+					ch <- prometheus.MustNewConstMetric(c.groupPartitionTimeLag, prometheus.GaugeValue, *p.TimeLagSeconds, group.GroupID, topic.Topic, partitionID)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package exporter
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cloudhut/common/rest"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/redpanda-data/console/backend/pkg/config"
+	"github.com/redpanda-data/console/backend/pkg/console"
+)
+
+// fakeConsoleService returns static responses for all queries used by the collector.
+type fakeConsoleService struct {
+	console.Servicer
+	calls int
+
+	describedTopics []string
+	listedGroups    []string
+}
+
+func (f *fakeConsoleService) GetTopicsOverview(context.Context) ([]*console.TopicSummary, error) {
+	f.calls++
+	return []*console.TopicSummary{
+		{TopicName: "orders", PartitionCount: 2, ReplicationFactor: 3, LogDirSummary: console.TopicLogDirSummary{TotalSizeBytes: 2048}},
+		{TopicName: "__consumer_offsets", PartitionCount: 50, ReplicationFactor: 3},
+	}, nil
+}
+
+func (f *fakeConsoleService) GetTopicDetails(_ context.Context, topicNames []string) ([]console.TopicDetails, *rest.Error) {
+	f.describedTopics = topicNames
+	return []console.TopicDetails{
+		{
+			TopicName: "orders",
+			Partitions: []console.TopicPartitionDetails{
+				{
+					TopicPartitionMetadata: &console.TopicPartitionMetadata{ID: 0, Leader: 1, Replicas: []int32{1, 2, 3}, InSyncReplicas: []int32{1, 2}},
+					TopicPartitionMarks:    &console.TopicPartitionMarks{PartitionID: 0, Low: 5, High: 100},
+				},
+				{
+					TopicPartitionMetadata: &console.TopicPartitionMetadata{ID: 1, PartitionError: "LEADER_NOT_AVAILABLE"},
+					TopicPartitionMarks:    &console.TopicPartitionMarks{},
+				},
+			},
+		},
+	}, nil
+}
+
+func (*fakeConsoleService) GetBrokersWithLogDirs(context.Context) ([]console.BrokerWithLogDirs, error) {
+	size := int64(4096)
+	return []console.BrokerWithLogDirs{{BrokerID: 1, TotalLogDirSizeBytes: &size}, {BrokerID: 2}}, nil
+}
+
+func (f *fakeConsoleService) GetConsumerGroupsOverview(_ context.Context, _ []string, opts console.ConsumerGroupsOverviewOptions) ([]console.ConsumerGroupOverview, *rest.Error) {
+	all := []console.ConsumerGroupOverview{
+		{
+			GroupID: "billing",
+			State:   "Stable",
+			Members: []console.GroupMemberDescription{{ID: "a"}, {ID: "b"}},
+			TopicOffsets: []console.GroupTopicOffsets{{
+				Topic:            "orders",
+				SummedLag:        10,
+				PartitionOffsets: []console.PartitionOffsets{{PartitionID: 0, GroupOffset: 90, HighWaterMark: 100, Lag: 10}},
+			}},
+		},
+		{GroupID: "ignored-group", State: "Empty"},
+	}
+
+	groups := make([]console.ConsumerGroupOverview, 0, len(all))
+	for _, group := range all {
+		if opts.GroupFilter == nil || opts.GroupFilter(group.GroupID) {
+			f.listedGroups = append(f.listedGroups, group.GroupID)
+			groups = append(groups, group)
+		}
+	}
+	return groups, nil
+}
+
+func newTestCollector(t *testing.T, svc console.Servicer) *Collector {
+	t.Helper()
+
+	cfg := config.ConsoleExporter{}
+	cfg.SetDefaults()
+	cfg.Enabled = true
+	cfg.Topics.Deny = []string{"/^__.*/"}
+	cfg.Groups.Deny = []string{"/^ignored-/"}
+
+	c, err := NewCollector(cfg, "console", svc, zap.NewNop())
+	require.NoError(t, err)
+	return c
+}
+
+func TestCollector(t *testing.T) {
+	c := newTestCollector(t, &fakeConsoleService{})
+
+	expected := `
+# HELP console_kafka_topic_partitions Number of partitions of a topic
+# TYPE console_kafka_topic_partitions gauge
+console_kafka_topic_partitions{topic_name="orders"} 2
+# HELP console_kafka_topic_under_replicated_partitions Number of partitions with fewer in-sync replicas than replicas
+# TYPE console_kafka_topic_under_replicated_partitions gauge
+console_kafka_topic_under_replicated_partitions{topic_name="orders"} 1
+# HELP console_kafka_topic_offline_partitions Number of partitions without an available leader
+# TYPE console_kafka_topic_offline_partitions gauge
+console_kafka_topic_offline_partitions{topic_name="orders"} 1
+# HELP console_kafka_topic_partition_high_water_mark High water mark of a partition
+# TYPE console_kafka_topic_partition_high_water_mark gauge
+console_kafka_topic_partition_high_water_mark{partition_id="0",topic_name="orders"} 100
+# HELP console_kafka_broker_log_dir_size_total_bytes Size of all replicas stored on a broker in bytes
+# TYPE console_kafka_broker_log_dir_size_total_bytes gauge
+console_kafka_broker_log_dir_size_total_bytes{broker_id="1"} 4096
+# HELP console_kafka_consumer_group_members Number of members of a consumer group
+# TYPE console_kafka_consumer_group_members gauge
+console_kafka_consumer_group_members{group_id="billing"} 2
+# HELP console_kafka_consumer_group_topic_partition_lag Lag of a consumer group in a partition
+# TYPE console_kafka_consumer_group_topic_partition_lag gauge
+console_kafka_consumer_group_topic_partition_lag{group_id="billing",partition_id="0",topic_name="orders"} 10
+`
+	err := testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"console_kafka_topic_partitions",
+		"console_kafka_topic_under_replicated_partitions",
+		"console_kafka_topic_offline_partitions",
+		"console_kafka_topic_partition_high_water_mark",
+		"console_kafka_broker_log_dir_size_total_bytes",
+		"console_kafka_consumer_group_members",
+		"console_kafka_consumer_group_topic_partition_lag",
+	)
+	assert.NoError(t, err)
+}
+
+func TestCollector_CachesSnapshot(t *testing.T) {
+	svc := &fakeConsoleService{}
+	c := newTestCollector(t, svc)
+
+	testutil.CollectAndCount(c)
+	testutil.CollectAndCount(c)
+	assert.Equal(t, 1, svc.calls)
+}
+
+func TestCollector_QueriesOnlyFilteredResources(t *testing.T) {
+	svc := &fakeConsoleService{}
+	c := newTestCollector(t, svc)
+
+	testutil.CollectAndCount(c)
+	assert.Equal(t, []string{"orders"}, svc.describedTopics)
+	assert.Equal(t, []string{"billing"}, svc.listedGroups)
+}
+
+func TestFilter(t *testing.T) {
+	f, err := newFilter(config.ConsoleExporterFilter{
+		Allow: []string{"/^orders-.*/", "payments"},
+		Deny:  []string{"orders-internal"},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, f.isAllowed("orders-eu"))
+	assert.True(t, f.isAllowed("payments"))
+	assert.False(t, f.isAllowed("payments-eu"))
+	assert.False(t, f.isAllowed("orders-internal"))
+}
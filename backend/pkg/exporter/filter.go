@@ -0,0 +1,53 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package exporter
+
+import (
+	"regexp"
+
+	"github.com/redpanda-data/console/backend/pkg/config"
+)
+
+// filter decides whether a topic or group shall be exported.
+type filter struct {
+	allow []*regexp.Regexp
+	deny  []*regexp.Regexp
+}
+
+func newFilter(cfg config.ConsoleExporterFilter) (*filter, error) {
+	allow, err := config.CompileRegexes(cfg.Allow)
+	if err != nil {
+		return nil, err
+	}
+	deny, err := config.CompileRegexes(cfg.Deny)
+	if err != nil {
+		return nil, err
+	}
+	return &filter{allow: allow, deny: deny}, nil
+}
+
+// isAllowed returns true if the name matches an allow expression (or no allow expressions
+// are configured) and none of the deny expressions.
+func (f *filter) isAllowed(name string) bool {
+	for _, r := range f.deny {
+		if r.MatchString(name) {
+			return false
+		}
+	}
+	if len(f.allow) == 0 {
+		return true
+	}
+	for _, r := range f.allow {
+		if r.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
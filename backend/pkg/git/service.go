@@ -48,6 +48,9 @@ type Service struct {
 	mutex       sync.RWMutex
 
 	OnFilesUpdatedHook func()
+
+	stop     chan struct{}
+	stopOnce sync.Once
 }
 
 // NewService creates a new Git service with preconfigured Auth
@@ -78,6 +81,7 @@ func NewService(cfg config.Git, logger *zap.Logger, onFilesUpdatedHook func()) (
 
 		filesByName:        make(map[string]filesystem.File),
 		OnFilesUpdatedHook: onFilesUpdatedHook,
+		stop:               make(chan struct{}),
 	}, nil
 }
 
@@ -98,6 +102,11 @@ func (c *Service) Start() error {
 	return nil
 }
 
+// Stop stops the background sync task. It is safe to call Stop multiple times.
+func (c *Service) Stop() {
+	c.stopOnce.Do(func() { close(c.stop) })
+}
+
 // CloneRepository clones the git repository
 func (c *Service) CloneRepository(ctx context.Context) error {
 	fs := memfs.New()
@@ -157,11 +166,15 @@ func (c *Service) SyncRepo() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
 	ticker := time.NewTicker(c.Cfg.RefreshInterval)
+	defer ticker.Stop()
 	for {
 		select {
 		case <-quit:
 			c.logger.Info("stopped sync", zap.String("reason", "received signal"))
 			return
+		case <-c.stop:
+			c.logger.Info("stopped sync", zap.String("reason", "service stopped"))
+			return
 		case <-ticker.C:
 			var referenceName plumbing.ReferenceName
 			if c.Cfg.Repository.Branch != "" {
@@ -0,0 +1,45 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package history
+
+// ring is a fixed size ring buffer. Once the capacity is reached, pushing a new value
+// overwrites the oldest value.
+type ring[T any] struct {
+	values []T
+	start  int
+	size   int
+}
+
+func newRing[T any](capacity int) *ring[T] {
+	return &ring[T]{values: make([]T, capacity)}
+}
+
+// push appends the value and overwrites the oldest value if the ring is full.
+func (r *ring[T]) push(v T) {
+	if len(r.values) == 0 {
+		return
+	}
+	end := (r.start + r.size) % len(r.values)
+	r.values[end] = v
+	if r.size < len(r.values) {
+		r.size++
+		return
+	}
+	r.start = (r.start + 1) % len(r.values)
+}
+
+// slice returns all values ordered from oldest to newest.
+func (r *ring[T]) slice() []T {
+	res := make([]T, r.size)
+	for i := 0; i < r.size; i++ {
+		res[i] = r.values[(r.start+i)%len(r.values)]
+	}
+	return res
+}
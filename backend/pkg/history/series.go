@@ -0,0 +1,255 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package history
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Sample is a snapshot of all topic watermarks and committed group offsets.
+type Sample struct {
+	Timestamp time.Time `json:"timestamp"`
+
+	// Topics contains the sum of all partition high watermarks by topic name.
+	Topics map[string]int64 `json:"topics"`
+
+	// Groups contains the committed offsets and lags by group id and topic name.
+	Groups map[string]map[string]GroupTopicSample `json:"groups"`
+}
+
+// GroupTopicSample is the state of a single group's offsets in a single topic.
+type GroupTopicSample struct {
+	// OffsetSum is the sum of all committed partition offsets.
+	OffsetSum int64 `json:"offsetSum"`
+	// Lag is the sum of all partition lags.
+	Lag int64 `json:"lag"`
+}
+
+// Point is a single value of a time series.
+type Point struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+}
+
+// LagDirection describes whether a group is catching up or falling behind.
+type LagDirection string
+
+const (
+	// LagDirectionCatchingUp means the lag is decreasing.
+	LagDirectionCatchingUp LagDirection = "CATCHING_UP"
+	// LagDirectionFallingBehind means the lag is increasing.
+	LagDirectionFallingBehind LagDirection = "FALLING_BEHIND"
+	// LagDirectionStable means the lag did not change significantly.
+	LagDirectionStable LagDirection = "STABLE"
+	// LagDirectionUnknown means there are not enough samples to determine a trend.
+	LagDirectionUnknown LagDirection = "UNKNOWN"
+)
+
+// LagTrend is the trend of a lag time series.
+type LagTrend struct {
+	Direction LagDirection `json:"direction"`
+
+	// ChangePerSecond is the slope of the lag (messages per second), negative if the
+	// group is catching up.
+	ChangePerSecond float64 `json:"changePerSecond"`
+
+	// EstimatedCatchUpSeconds is the estimated time until the lag is zero, only set if
+	// the group is catching up.
+	EstimatedCatchUpSeconds *float64 `json:"estimatedCatchUpSeconds,omitempty"`
+}
+
+// TopicSeries contains the time series of a single topic.
+type TopicSeries struct {
+	TopicName string `json:"topicName"`
+
+	// ProduceRate is the number of produced messages per second.
+	ProduceRate []Point `json:"produceRate"`
+}
+
+// GroupSeries contains the time series of a single consumer group.
+type GroupSeries struct {
+	GroupID string `json:"groupId"`
+
+	// ConsumeRate is the number of consumed messages per second across all topics.
+	ConsumeRate []Point `json:"consumeRate"`
+	// Lag is the summed lag across all topics.
+	Lag   []Point  `json:"lag"`
+	Trend LagTrend `json:"trend"`
+
+	Topics []GroupTopicSeries `json:"topics"`
+}
+
+// GroupTopicSeries contains the time series of a single consumer group for a single topic.
+type GroupTopicSeries struct {
+	TopicName   string   `json:"topicName"`
+	ConsumeRate []Point  `json:"consumeRate"`
+	Lag         []Point  `json:"lag"`
+	Trend       LagTrend `json:"trend"`
+}
+
+// topicSeries computes the time series of a topic from the given samples.
+func topicSeries(samples []Sample, topic string) TopicSeries {
+	return TopicSeries{
+		TopicName: topic,
+		ProduceRate: rate(samples, func(s Sample) (int64, bool) {
+			v, exists := s.Topics[topic]
+			return v, exists
+		}),
+	}
+}
+
+// groupSeries computes the time series of a consumer group from the given samples. If topic
+// is not empty, only the given topic is considered.
+func groupSeries(samples []Sample, groupID string, topic string) GroupSeries {
+	topicNames := make(map[string]struct{})
+	for _, s := range samples {
+		for t := range s.Groups[groupID] {
+			if topic == "" || t == topic {
+				topicNames[t] = struct{}{}
+			}
+		}
+	}
+	sortedTopics := make([]string, 0, len(topicNames))
+	for t := range topicNames {
+		sortedTopics = append(sortedTopics, t)
+	}
+	sort.Strings(sortedTopics)
+
+	res := GroupSeries{
+		GroupID: groupID,
+		Topics:  make([]GroupTopicSeries, 0, len(sortedTopics)),
+	}
+	for _, t := range sortedTopics {
+		t := t
+		lag := values(samples, func(s Sample) (int64, bool) {
+			v, exists := s.Groups[groupID][t]
+			return v.Lag, exists
+		})
+		res.Topics = append(res.Topics, GroupTopicSeries{
+			TopicName: t,
+			ConsumeRate: rate(samples, func(s Sample) (int64, bool) {
+				v, exists := s.Groups[groupID][t]
+				return v.OffsetSum, exists
+			}),
+			Lag:   lag,
+			Trend: lagTrend(lag),
+		})
+	}
+
+	// The summed series only considers samples that contain the group.
+	res.ConsumeRate = rate(samples, func(s Sample) (int64, bool) {
+		return sumGroup(s, groupID, topicNames, func(g GroupTopicSample) int64 { return g.OffsetSum })
+	})
+	res.Lag = values(samples, func(s Sample) (int64, bool) {
+		return sumGroup(s, groupID, topicNames, func(g GroupTopicSample) int64 { return g.Lag })
+	})
+	res.Trend = lagTrend(res.Lag)
+
+	return res
+}
+
+func sumGroup(s Sample, groupID string, topics map[string]struct{}, valueFn func(GroupTopicSample) int64) (int64, bool) {
+	group, exists := s.Groups[groupID]
+	if !exists {
+		return 0, false
+	}
+	var sum int64
+	found := false
+	for t, v := range group {
+		if _, ok := topics[t]; !ok {
+			continue
+		}
+		sum += valueFn(v)
+		found = true
+	}
+	return sum, found
+}
+
+// values returns the plain values of all samples.
+func values(samples []Sample, valueFn func(Sample) (int64, bool)) []Point {
+	points := make([]Point, 0, len(samples))
+	for _, s := range samples {
+		v, exists := valueFn(s)
+		if !exists {
+			continue
+		}
+		points = append(points, Point{Timestamp: s.Timestamp, Value: float64(v)})
+	}
+	return points
+}
+
+// rate returns the change per second between consecutive samples. Decreasing values, e.g.
+// because a topic has been recreated or offsets have been reset, are skipped.
+func rate(samples []Sample, valueFn func(Sample) (int64, bool)) []Point {
+	points := make([]Point, 0, len(samples))
+	var prev *Sample
+	var prevValue int64
+	for i := range samples {
+		s := &samples[i]
+		v, exists := valueFn(*s)
+		if !exists {
+			prev = nil
+			continue
+		}
+		if prev != nil {
+			elapsed := s.Timestamp.Sub(prev.Timestamp).Seconds()
+			if elapsed > 0 && v >= prevValue {
+				points = append(points, Point{Timestamp: s.Timestamp, Value: float64(v-prevValue) / elapsed})
+			}
+		}
+		prev = s
+		prevValue = v
+	}
+	return points
+}
+
+// lagTrend fits a line through the lag series using least squares to determine whether the
+// lag is increasing or decreasing.
+func lagTrend(lag []Point) LagTrend {
+	if len(lag) < 2 {
+		return LagTrend{Direction: LagDirectionUnknown}
+	}
+
+	origin := lag[0].Timestamp
+	var sumX, sumY, sumXY, sumXX float64
+	for _, p := range lag {
+		x := p.Timestamp.Sub(origin).Seconds()
+		sumX += x
+		sumY += p.Value
+		sumXY += x * p.Value
+		sumXX += x * x
+	}
+	n := float64(len(lag))
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return LagTrend{Direction: LagDirectionUnknown}
+	}
+	slope := (n*sumXY - sumX*sumY) / denominator
+
+	// The lag change over the whole window must be significant compared to the average lag,
+	// otherwise small fluctuations of a healthy consumer would be reported as a trend.
+	window := lag[len(lag)-1].Timestamp.Sub(origin).Seconds()
+	change := math.Abs(slope * window)
+	mean := sumY / n
+	trend := LagTrend{ChangePerSecond: slope}
+	switch {
+	case change < 1 || change < 0.05*mean:
+		trend.Direction = LagDirectionStable
+	case slope < 0:
+		trend.Direction = LagDirectionCatchingUp
+		catchUp := lag[len(lag)-1].Value / -slope
+		trend.EstimatedCatchUpSeconds = &catchUp
+	default:
+		trend.Direction = LagDirectionFallingBehind
+	}
+	return trend
+}
@@ -0,0 +1,107 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRing(t *testing.T) {
+	r := newRing[int](3)
+	assert.Empty(t, r.slice())
+
+	r.push(1)
+	r.push(2)
+	assert.Equal(t, []int{1, 2}, r.slice())
+
+	r.push(3)
+	r.push(4)
+	r.push(5)
+	assert.Equal(t, []int{3, 4, 5}, r.slice())
+}
+
+func testSamples() []Sample {
+	start := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	samples := make([]Sample, 0)
+	for i := 0; i < 5; i++ {
+		samples = append(samples, Sample{
+			Timestamp: start.Add(time.Duration(i) * 10 * time.Second),
+			// 100 messages per second are produced to orders, 10 per second to payments
+			Topics: map[string]int64{"orders": int64(i) * 1000, "payments": int64(i) * 100},
+			Groups: map[string]map[string]GroupTopicSample{
+				// The group consumes 150 messages per second and is catching up in orders,
+				// the lag in payments does not change.
+				"billing": {
+					"orders":   {OffsetSum: int64(i) * 1500, Lag: 10000 - int64(i)*500},
+					"payments": {OffsetSum: int64(i) * 100, Lag: 5},
+				},
+			},
+		})
+	}
+	return samples
+}
+
+func TestTopicSeries(t *testing.T) {
+	series := topicSeries(testSamples(), "orders")
+	require.Len(t, series.ProduceRate, 4)
+	for _, p := range series.ProduceRate {
+		assert.InDelta(t, 100, p.Value, 0.001)
+	}
+
+	assert.Empty(t, topicSeries(testSamples(), "unknown").ProduceRate)
+}
+
+func TestTopicSeries_SkipsDecreasingValues(t *testing.T) {
+	samples := testSamples()
+	// Topic has been recreated
+	samples[2].Topics["orders"] = 0
+	samples[3].Topics["orders"] = 1000
+	samples[4].Topics["orders"] = 2000
+
+	series := topicSeries(samples, "orders")
+	assert.Len(t, series.ProduceRate, 3)
+}
+
+func TestGroupSeries(t *testing.T) {
+	series := groupSeries(testSamples(), "billing", "")
+	require.Len(t, series.Topics, 2)
+	assert.Equal(t, "orders", series.Topics[0].TopicName)
+	assert.Equal(t, LagDirectionCatchingUp, series.Topics[0].Trend.Direction)
+	assert.InDelta(t, -50, series.Topics[0].Trend.ChangePerSecond, 0.001)
+	require.NotNil(t, series.Topics[0].Trend.EstimatedCatchUpSeconds)
+	assert.InDelta(t, 160, *series.Topics[0].Trend.EstimatedCatchUpSeconds, 0.001)
+	assert.Equal(t, LagDirectionStable, series.Topics[1].Trend.Direction)
+
+	require.Len(t, series.ConsumeRate, 4)
+	assert.InDelta(t, 160, series.ConsumeRate[0].Value, 0.001)
+	require.Len(t, series.Lag, 5)
+	assert.InDelta(t, 10005, series.Lag[0].Value, 0.001)
+
+	filtered := groupSeries(testSamples(), "billing", "payments")
+	require.Len(t, filtered.Topics, 1)
+	assert.InDelta(t, 10, filtered.ConsumeRate[0].Value, 0.001)
+}
+
+func TestLagTrend(t *testing.T) {
+	start := time.Now()
+	assert.Equal(t, LagDirectionUnknown, lagTrend([]Point{{Timestamp: start, Value: 1}}).Direction)
+
+	fallingBehind := lagTrend([]Point{
+		{Timestamp: start, Value: 100},
+		{Timestamp: start.Add(time.Minute), Value: 200},
+		{Timestamp: start.Add(2 * time.Minute), Value: 300},
+	})
+	assert.Equal(t, LagDirectionFallingBehind, fallingBehind.Direction)
+	assert.Nil(t, fallingBehind.EstimatedCatchUpSeconds)
+}
@@ -0,0 +1,304 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package history periodically samples partition watermarks and committed consumer group
+// offsets in the background, so that produce rates, consume rates and lag trends can be
+// served without an external metrics stack.
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"go.uber.org/zap"
+
+	"github.com/redpanda-data/console/backend/pkg/config"
+	"github.com/redpanda-data/console/backend/pkg/kafka"
+)
+
+// persistEvery is the number of collected samples after which the history is persisted.
+const persistEvery = 10
+
+// Service collects samples in a ring buffer and serves time series from them.
+type Service struct {
+	cfg      config.ConsoleHistory
+	logger   *zap.Logger
+	kafkaSvc *kafka.Service
+
+	mutex   sync.RWMutex
+	samples *ring[Sample]
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// persistedHistory is the file format of the persisted samples.
+type persistedHistory struct {
+	Version int      `json:"version"`
+	Samples []Sample `json:"samples"`
+}
+
+// NewService creates a new history service. Call Start to start collecting samples.
+func NewService(cfg config.ConsoleHistory, logger *zap.Logger, kafkaSvc *kafka.Service) *Service {
+	capacity := int(cfg.Retention / cfg.Interval)
+	return &Service{
+		cfg:      cfg,
+		logger:   logger.Named("history"),
+		kafkaSvc: kafkaSvc,
+		samples:  newRing[Sample](capacity),
+	}
+}
+
+// Start loads persisted samples and starts the background collector.
+func (s *Service) Start() error {
+	if s.cfg.PersistenceFilepath != "" {
+		if err := s.load(); err != nil {
+			return fmt.Errorf("failed to load persisted history: %w", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	go s.run(ctx)
+
+	s.logger.Info("started history collector",
+		zap.Duration("interval", s.cfg.Interval),
+		zap.Duration("retention", s.cfg.Retention))
+	return nil
+}
+
+// Stop stops the background collector and persists the collected samples.
+func (s *Service) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	<-s.done
+
+	if s.cfg.PersistenceFilepath != "" {
+		if err := s.persist(); err != nil {
+			s.logger.Warn("failed to persist history", zap.Error(err))
+		}
+	}
+}
+
+func (s *Service) run(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	collected := 0
+	for {
+		s.collectAndStore(ctx)
+		collected++
+		if s.cfg.PersistenceFilepath != "" && collected%persistEvery == 0 {
+			if err := s.persist(); err != nil {
+				s.logger.Warn("failed to persist history", zap.Error(err))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Service) collectAndStore(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Interval)
+	defer cancel()
+
+	sample, err := s.collect(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.logger.Warn("failed to collect history sample", zap.Error(err))
+		}
+		return
+	}
+
+	s.mutex.Lock()
+	s.samples.push(sample)
+	s.mutex.Unlock()
+}
+
+// collect samples the high watermarks of all topics and the committed offsets of all groups.
+func (s *Service) collect(ctx context.Context) (Sample, error) {
+	sample := Sample{
+		Timestamp: time.Now(),
+		Topics:    make(map[string]int64),
+		Groups:    make(map[string]map[string]GroupTopicSample),
+	}
+
+	// 1. Partition high watermarks
+	metadata, err := s.kafkaSvc.KafkaAdmClient.Metadata(ctx)
+	if err != nil {
+		return Sample{}, fmt.Errorf("failed to get metadata: %w", err)
+	}
+	topicPartitions := make(map[string][]int32, len(metadata.Topics))
+	for _, td := range metadata.Topics {
+		if td.Err != nil {
+			continue
+		}
+		for _, pd := range td.Partitions {
+			if pd.Err == nil {
+				topicPartitions[td.Topic] = append(topicPartitions[td.Topic], pd.Partition)
+			}
+		}
+	}
+	marks, err := s.kafkaSvc.GetPartitionMarksBulk(ctx, topicPartitions)
+	if err != nil {
+		return Sample{}, fmt.Errorf("failed to get partition marks: %w", err)
+	}
+
+	highWaterMarks := make(map[string]map[int32]int64, len(marks))
+	for topic, partitions := range marks {
+		complete := true
+		var sum int64
+		highWaterMarks[topic] = make(map[int32]int64, len(partitions))
+		for pID, mark := range partitions {
+			if mark.Error != nil || mark.High < 0 {
+				complete = false
+				continue
+			}
+			sum += mark.High
+			highWaterMarks[topic][pID] = mark.High
+		}
+		// An incomplete sum would result in wrong rates, hence we rather skip the topic
+		if complete {
+			sample.Topics[topic] = sum
+		}
+	}
+
+	// 2. Committed group offsets
+	groups, err := s.kafkaSvc.ListConsumerGroups(ctx)
+	if err != nil {
+		return Sample{}, fmt.Errorf("failed to list consumer groups: %w", err)
+	}
+	groupIDs := groups.GetGroupIDs()
+	if len(groupIDs) == 0 {
+		return sample, nil
+	}
+	offsets := s.kafkaSvc.ListConsumerGroupOffsetsBulk(ctx, groupIDs)
+	for groupID, res := range offsets {
+		if res.Err != nil {
+			continue
+		}
+		groupSample := make(map[string]GroupTopicSample)
+		res.Fetched.Each(func(o kadm.OffsetResponse) {
+			if o.Err != nil || o.At < 0 {
+				return
+			}
+			v := groupSample[o.Topic]
+			v.OffsetSum += o.At
+			if high, exists := highWaterMarks[o.Topic][o.Partition]; exists && high > o.At {
+				v.Lag += high - o.At
+			}
+			groupSample[o.Topic] = v
+		})
+		sample.Groups[groupID] = groupSample
+	}
+
+	return sample, nil
+}
+
+// snapshot returns all samples that are newer than since.
+func (s *Service) snapshot(since time.Time) []Sample {
+	s.mutex.RLock()
+	all := s.samples.slice()
+	s.mutex.RUnlock()
+
+	for i, sample := range all {
+		if !sample.Timestamp.Before(since) {
+			return all[i:]
+		}
+	}
+	return nil
+}
+
+// TopicSeries returns the time series of a topic for all samples that are newer than since.
+func (s *Service) TopicSeries(topic string, since time.Time) TopicSeries {
+	return topicSeries(s.snapshot(since), topic)
+}
+
+// GroupSeries returns the time series of a consumer group for all samples that are newer
+// than since. If topic is not empty, only the given topic is considered.
+func (s *Service) GroupSeries(groupID string, topic string, since time.Time) GroupSeries {
+	return groupSeries(s.snapshot(since), groupID, topic)
+}
+
+// Interval returns the configured sample interval.
+func (s *Service) Interval() time.Duration {
+	return s.cfg.Interval
+}
+
+// persist writes all samples to the persistence file. The file is replaced atomically.
+func (s *Service) persist() error {
+	s.mutex.RLock()
+	data, err := json.Marshal(persistedHistory{Version: 1, Samples: s.samples.slice()})
+	s.mutex.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal samples: %w", err)
+	}
+
+	dir := filepath.Dir(s.cfg.PersistenceFilepath)
+	tmp, err := os.CreateTemp(dir, filepath.Base(s.cfg.PersistenceFilepath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck // Does not exist anymore after a successful rename
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close() //nolint:errcheck,gosec // Write error takes precedence
+		return fmt.Errorf("failed to write samples: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary file: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), s.cfg.PersistenceFilepath)
+}
+
+// load reads persisted samples, samples that exceed the retention are dropped.
+func (s *Service) load() error {
+	data, err := os.ReadFile(s.cfg.PersistenceFilepath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var persisted persistedHistory
+	if err := json.Unmarshal(data, &persisted); err != nil {
+		return fmt.Errorf("failed to unmarshal samples: %w", err)
+	}
+
+	minTimestamp := time.Now().Add(-s.cfg.Retention)
+	loaded := 0
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, sample := range persisted.Samples {
+		if sample.Timestamp.Before(minTimestamp) {
+			continue
+		}
+		s.samples.push(sample)
+		loaded++
+	}
+	s.logger.Info("loaded persisted history", zap.Int("samples", loaded))
+
+	return nil
+}
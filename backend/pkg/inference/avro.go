@@ -0,0 +1,211 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package inference
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hamba/avro/v2"
+)
+
+var avroNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// AvroSchema returns the inferred Avro schema. The top-level record gets the given name and
+// namespace. Optional and nullable fields are nullable unions with a null default. Returned
+// warnings describe where the observed data can not be represented exactly, e.g. because a
+// field name is not a valid Avro name.
+func (i *Inferrer) AvroSchema(name string, namespace string) ([]byte, []string, error) {
+	if !avroNameRegexp.MatchString(name) {
+		return nil, nil, fmt.Errorf("record name %q is not a valid avro name", name)
+	}
+
+	b := &avroBuilder{
+		namespace: namespace,
+		usedNames: make(map[string]struct{}),
+		warnings:  make([]string, 0),
+	}
+	schema := b.schema(i.root, name, "")
+
+	schemaBytes, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal avro schema: %w", err)
+	}
+
+	// Make sure that we generated a valid schema. We use a dedicated cache, so that the inferred
+	// types don't end up in the global schema cache.
+	if _, err := avro.ParseWithCache(string(schemaBytes), "", &avro.SchemaCache{}); err != nil {
+		return nil, nil, fmt.Errorf("inferred avro schema is invalid: %w", err)
+	}
+
+	return schemaBytes, b.warnings, nil
+}
+
+type avroBuilder struct {
+	namespace string
+	usedNames map[string]struct{}
+	warnings  []string
+}
+
+// uniqueName returns a name for a named type that has not been used yet.
+func (b *avroBuilder) uniqueName(name string) string {
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "Type" + name
+	}
+	candidate := name
+	for i := 2; ; i++ {
+		if _, used := b.usedNames[candidate]; !used {
+			b.usedNames[candidate] = struct{}{}
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s%d", name, i)
+	}
+}
+
+// schema returns the Avro schema for the node. The name is used for named types, path is
+// used for warnings only.
+func (b *avroBuilder) schema(n *node, name string, path string) any {
+	types := make([]any, 0)
+	if n.nullCount > 0 {
+		types = append(types, "null")
+	}
+	if n.boolCount > 0 {
+		types = append(types, "boolean")
+	}
+	if n.numCount > 0 {
+		types = append(types, "double")
+	} else if n.intCount > 0 {
+		types = append(types, "long")
+	}
+	if n.strCount > 0 {
+		types = append(types, b.stringSchema(n, name))
+	}
+	if n.objCount > 0 {
+		types = append(types, b.recordSchema(n, name, path))
+	}
+	if n.arrCount > 0 {
+		var items any = "null"
+		if n.items != nil && n.items.count > 0 {
+			items = b.schema(n.items, name+"Item", path+"[]")
+		} else {
+			b.warnings = append(b.warnings, fmt.Sprintf("%s: only empty arrays have been observed, the item type is unknown", displayPath(path)))
+		}
+		types = append(types, map[string]any{"type": "array", "items": items})
+	}
+
+	switch len(types) {
+	case 0:
+		return "null"
+	case 1:
+		return types[0]
+	default:
+		return types
+	}
+}
+
+func (b *avroBuilder) stringSchema(n *node, name string) any {
+	if enum := n.enum(); enum != nil {
+		return map[string]any{
+			"type":    "enum",
+			"name":    b.uniqueName(toPascalCase(name)),
+			"symbols": enum,
+		}
+	}
+	if n.format() == FormatUUID {
+		return map[string]any{"type": "string", "logicalType": "uuid"}
+	}
+	return "string"
+}
+
+func (b *avroBuilder) recordSchema(n *node, name string, path string) any {
+	fields := make([]any, 0, len(n.propertyOrder))
+	usedFieldNames := make(map[string]struct{}, len(n.propertyOrder))
+	for _, key := range n.propertyOrder {
+		prop := n.properties[key]
+		fieldPath := key
+		if path != "" {
+			fieldPath = path + "." + key
+		}
+
+		fieldName := sanitizeAvroName(key)
+		for i := 2; ; i++ {
+			if _, used := usedFieldNames[fieldName]; !used {
+				break
+			}
+			fieldName = fmt.Sprintf("%s_%d", sanitizeAvroName(key), i)
+		}
+		usedFieldNames[fieldName] = struct{}{}
+		if fieldName != key {
+			b.warnings = append(b.warnings, fmt.Sprintf("%s: field name is not a valid avro name and has been renamed to %q", fieldPath, fieldName))
+		}
+
+		fieldType := b.schema(prop, key, fieldPath)
+		field := map[string]any{"name": fieldName}
+		if prop.isOptional(n) && prop.nullCount == 0 {
+			// The field has been missing in some objects, which we can only represent with a nullable union
+			if union, isUnion := fieldType.([]any); isUnion {
+				fieldType = append([]any{"null"}, union...)
+			} else {
+				fieldType = []any{"null", fieldType}
+			}
+		}
+		field["type"] = fieldType
+		if union, isUnion := fieldType.([]any); isUnion && union[0] == "null" {
+			field["default"] = nil
+		}
+		fields = append(fields, field)
+	}
+
+	record := map[string]any{
+		"type":   "record",
+		"fields": fields,
+	}
+	if path == "" {
+		// The name of the top-level record has been chosen by the user
+		record["name"] = b.uniqueName(name)
+		if b.namespace != "" {
+			record["namespace"] = b.namespace
+		}
+	} else {
+		record["name"] = b.uniqueName(toPascalCase(name))
+	}
+	return record
+}
+
+// sanitizeAvroName replaces all characters that are not allowed in Avro names.
+func sanitizeAvroName(name string) string {
+	if avroNameRegexp.MatchString(name) {
+		return name
+	}
+	var sb strings.Builder
+	for i, r := range name {
+		isLetter := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r == '_'
+		isDigit := r >= '0' && r <= '9'
+		switch {
+		case isLetter || (isDigit && i > 0):
+			sb.WriteRune(r)
+		case isDigit:
+			sb.WriteRune('_')
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune('_')
+		}
+	}
+	return sb.String()
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "<root>"
+	}
+	return path
+}
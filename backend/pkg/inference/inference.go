@@ -0,0 +1,362 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+// Package inference infers a JSON schema or an Avro schema from a set of observed documents.
+// Documents are the Go native representation as returned by the message deserializer.
+package inference
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// maxEnumValues is the maximum number of distinct values a string field may have to be
+	// considered an enum.
+	maxEnumValues = 10
+	// minEnumSamples is the minimum number of observed values before a string field may be
+	// considered an enum. With fewer samples every field would look like an enum.
+	minEnumSamples = 10
+)
+
+// Format is a string format that has been detected for all observed values of a field.
+type Format string
+
+const (
+	// FormatDateTime is an RFC 3339 timestamp.
+	FormatDateTime Format = "date-time"
+	// FormatDate is a full date such as 2023-06-01.
+	FormatDate Format = "date"
+	// FormatUUID is a UUID in its canonical textual form.
+	FormatUUID Format = "uuid"
+	// FormatEmail is an email address.
+	FormatEmail Format = "email"
+	// FormatURI is an absolute http(s) URI.
+	FormatURI Format = "uri"
+)
+
+var (
+	allFormats    = []Format{FormatDateTime, FormatDate, FormatUUID, FormatEmail, FormatURI}
+	uuidRegexp    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	enumRegexp    = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	dateLayout    = "2006-01-02"
+	maxEnumLength = 64
+)
+
+// Inferrer merges the structure of all observed documents. An Inferrer is not safe for
+// concurrent use.
+type Inferrer struct {
+	root      *node
+	documents int
+}
+
+// NewInferrer creates a new Inferrer.
+func NewInferrer() *Inferrer {
+	return &Inferrer{root: newNode()}
+}
+
+// Observe merges the given document into the inferred structure.
+func (i *Inferrer) Observe(document any) {
+	i.documents++
+	i.root.observe(document)
+}
+
+// Documents returns the number of observed documents.
+func (i *Inferrer) Documents() int {
+	return i.documents
+}
+
+// node is the merged structure of all values that have been observed at the same path.
+type node struct {
+	// count is the number of observed values, including nulls.
+	count     int
+	nullCount int
+	boolCount int
+	intCount  int
+	numCount  int
+	strCount  int
+	objCount  int
+	arrCount  int
+
+	// properties of objects in the order they have been observed first.
+	properties    map[string]*node
+	propertyOrder []string
+
+	// items is the merged structure of all array items.
+	items *node
+
+	// stringValues tracks distinct string values as long as the node may be an enum.
+	stringValues map[string]int
+	notEnum      bool
+
+	// formats that all observed string values satisfy.
+	formats map[Format]struct{}
+}
+
+func newNode() *node {
+	formats := make(map[Format]struct{}, len(allFormats))
+	for _, f := range allFormats {
+		formats[f] = struct{}{}
+	}
+	return &node{
+		properties:   make(map[string]*node),
+		stringValues: make(map[string]int),
+		formats:      formats,
+	}
+}
+
+func (n *node) observe(value any) {
+	n.count++
+
+	switch v := value.(type) {
+	case nil:
+		n.nullCount++
+	case bool:
+		n.boolCount++
+	case string:
+		n.observeString(v)
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			n.intCount++
+		} else {
+			n.numCount++
+		}
+	case float64:
+		n.observeFloat(v)
+	case float32:
+		n.observeFloat(float64(v))
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		n.intCount++
+	case *big.Rat:
+		n.numCount++
+	case time.Time:
+		n.observeString(v.Format(time.RFC3339Nano))
+	case time.Duration:
+		n.intCount++
+	case []byte:
+		n.observeString(base64.StdEncoding.EncodeToString(v))
+	case map[string]any:
+		n.objCount++
+		// Go maps are unordered, hence we sort the keys so that the inferred schema is stable
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			prop, exists := n.properties[key]
+			if !exists {
+				prop = newNode()
+				n.properties[key] = prop
+				n.propertyOrder = append(n.propertyOrder, key)
+			}
+			prop.observe(v[key])
+		}
+	case []any:
+		n.arrCount++
+		if n.items == nil {
+			n.items = newNode()
+		}
+		for _, item := range v {
+			n.items.observe(item)
+		}
+	default:
+		n.observeString(fmt.Sprintf("%v", v))
+	}
+}
+
+func (n *node) observeFloat(v float64) {
+	if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+		n.intCount++
+		return
+	}
+	n.numCount++
+}
+
+func (n *node) observeString(v string) {
+	n.strCount++
+
+	if !n.notEnum {
+		if len(v) > maxEnumLength || !enumRegexp.MatchString(v) {
+			n.notEnum = true
+			n.stringValues = nil
+		} else {
+			n.stringValues[v]++
+			if len(n.stringValues) > maxEnumValues {
+				n.notEnum = true
+				n.stringValues = nil
+			}
+		}
+	}
+
+	for format := range n.formats {
+		if !matchesFormat(format, v) {
+			delete(n.formats, format)
+		}
+	}
+}
+
+func matchesFormat(format Format, v string) bool {
+	switch format {
+	case FormatDateTime:
+		_, err := time.Parse(time.RFC3339Nano, v)
+		return err == nil
+	case FormatDate:
+		_, err := time.Parse(dateLayout, v)
+		return err == nil
+	case FormatUUID:
+		return uuidRegexp.MatchString(v)
+	case FormatEmail:
+		addr, err := mail.ParseAddress(v)
+		return err == nil && addr.Address == v
+	case FormatURI:
+		u, err := url.Parse(v)
+		return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+	default:
+		return false
+	}
+}
+
+// format returns the detected string format. Formats are only reported if all observed values
+// are strings.
+func (n *node) format() Format {
+	if n.strCount == 0 || n.strCount != n.count-n.nullCount {
+		return ""
+	}
+	for _, f := range allFormats {
+		if _, ok := n.formats[f]; ok {
+			return f
+		}
+	}
+	return ""
+}
+
+// enum returns the sorted enum symbols if the node looks like an enum.
+func (n *node) enum() []string {
+	if n.notEnum || n.strCount < minEnumSamples || n.strCount != n.count-n.nullCount || n.format() != "" {
+		return nil
+	}
+	// Each symbol should have been observed more than once on average, otherwise we are
+	// probably looking at identifiers.
+	if len(n.stringValues)*2 > n.strCount {
+		return nil
+	}
+	symbols := make([]string, 0, len(n.stringValues))
+	for v := range n.stringValues {
+		symbols = append(symbols, v)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// types returns the JSON schema types of all non-null observed values. Integers are merged
+// into numbers if both have been observed.
+func (n *node) types() []string {
+	types := make([]string, 0)
+	if n.boolCount > 0 {
+		types = append(types, "boolean")
+	}
+	if n.numCount > 0 {
+		types = append(types, "number")
+	} else if n.intCount > 0 {
+		types = append(types, "integer")
+	}
+	if n.strCount > 0 {
+		types = append(types, "string")
+	}
+	if n.objCount > 0 {
+		types = append(types, "object")
+	}
+	if n.arrCount > 0 {
+		types = append(types, "array")
+	}
+	return types
+}
+
+// isOptional returns true if the property has not been present in all objects of its parent.
+func (n *node) isOptional(parent *node) bool {
+	return n.count < parent.objCount
+}
+
+// FieldCoverage reports how often a field has been observed.
+type FieldCoverage struct {
+	// Path of the field, nested fields are separated by dots. Array items are denoted by "[]".
+	Path  string   `json:"path"`
+	Types []string `json:"types"`
+
+	// Present is the number of objects in which the field has been present (including null values).
+	Present int `json:"present"`
+	// Null is the number of objects in which the field has been null.
+	Null int `json:"null"`
+	// Coverage is the share of parent objects in which the field has been present, between 0 and 1.
+	Coverage float64 `json:"coverage"`
+	Optional bool    `json:"optional"`
+
+	Format Format   `json:"format,omitempty"`
+	Enum   []string `json:"enum,omitempty"`
+}
+
+// Coverage returns the coverage of all fields.
+func (i *Inferrer) Coverage() []FieldCoverage {
+	coverage := make([]FieldCoverage, 0)
+	collectCoverage(i.root, "", &coverage)
+	return coverage
+}
+
+func collectCoverage(n *node, path string, coverage *[]FieldCoverage) {
+	for _, key := range n.propertyOrder {
+		prop := n.properties[key]
+		propPath := key
+		if path != "" {
+			propPath = path + "." + key
+		}
+		*coverage = append(*coverage, FieldCoverage{
+			Path:     propPath,
+			Types:    prop.types(),
+			Present:  prop.count,
+			Null:     prop.nullCount,
+			Coverage: float64(prop.count) / float64(n.objCount),
+			Optional: prop.isOptional(n) || prop.nullCount > 0,
+			Format:   prop.format(),
+			Enum:     prop.enum(),
+		})
+		collectCoverage(prop, propPath, coverage)
+	}
+	if n.items != nil {
+		collectCoverage(n.items, path+"[]", coverage)
+	}
+}
+
+// toPascalCase converts a field name into a name suitable for a named type.
+func toPascalCase(name string) string {
+	var sb strings.Builder
+	upperNext := true
+	for _, r := range name {
+		isAlphaNum := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+		if !isAlphaNum {
+			upperNext = true
+			continue
+		}
+		if upperNext && r >= 'a' && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		upperNext = false
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
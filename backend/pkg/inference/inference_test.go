@@ -0,0 +1,119 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package inference
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hamba/avro/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func observeSamples(t *testing.T) *Inferrer {
+	t.Helper()
+
+	inferrer := NewInferrer()
+	statuses := []string{"NEW", "SHIPPED", "DELIVERED"}
+	for i := 0; i < 30; i++ {
+		doc := fmt.Sprintf(`{
+			"id": "6f1c2d3e-4b5a-4c7d-8e9f-%012d",
+			"status": %q,
+			"created_at": "2023-06-01T12:%02d:00Z",
+			"amount": %v,
+			"customer": {"email": "jane.doe@example.com", "name": "Jane"},
+			"tags": ["a", "b"],
+			"my-field": true
+		}`, i, statuses[i%len(statuses)], i, float64(i)+0.5)
+		var obj map[string]any
+		require.NoError(t, json.Unmarshal([]byte(doc), &obj))
+
+		// Some documents don't have a note, some have an explicit null value
+		switch i % 3 {
+		case 0:
+			obj["note"] = "hello world"
+		case 1:
+			obj["note"] = nil
+		}
+		// Quantity is an integer in some documents and a float in others
+		if i%2 == 0 {
+			obj["quantity"] = float64(i)
+		} else {
+			obj["quantity"] = 1.5
+		}
+		inferrer.Observe(obj)
+	}
+	return inferrer
+}
+
+func TestInferrer_Coverage(t *testing.T) {
+	inferrer := observeSamples(t)
+	assert.Equal(t, 30, inferrer.Documents())
+
+	coverageByPath := make(map[string]FieldCoverage)
+	for _, c := range inferrer.Coverage() {
+		coverageByPath[c.Path] = c
+	}
+
+	assert.Equal(t, FormatUUID, coverageByPath["id"].Format)
+	assert.Equal(t, FormatDateTime, coverageByPath["created_at"].Format)
+	assert.Equal(t, FormatEmail, coverageByPath["customer.email"].Format)
+	assert.Equal(t, []string{"DELIVERED", "NEW", "SHIPPED"}, coverageByPath["status"].Enum)
+	assert.Equal(t, []string{"number"}, coverageByPath["quantity"].Types)
+	assert.False(t, coverageByPath["status"].Optional)
+
+	note := coverageByPath["note"]
+	assert.True(t, note.Optional)
+	assert.Equal(t, 20, note.Present)
+	assert.Equal(t, 10, note.Null)
+	assert.InDelta(t, 2.0/3.0, note.Coverage, 0.001)
+}
+
+func TestInferrer_JSONSchema(t *testing.T) {
+	schemaBytes, err := observeSamples(t).JSONSchema()
+	require.NoError(t, err)
+
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(schemaBytes, &schema))
+	assert.Equal(t, "object", schema["type"])
+	assert.NotContains(t, schema["required"], "note")
+	assert.Contains(t, schema["required"], "status")
+
+	properties := schema["properties"].(map[string]any)
+	assert.Equal(t, []any{"string", "null"}, properties["note"].(map[string]any)["type"])
+	assert.Equal(t, "date-time", properties["created_at"].(map[string]any)["format"])
+	assert.Equal(t, []any{"DELIVERED", "NEW", "SHIPPED"}, properties["status"].(map[string]any)["enum"])
+}
+
+func TestInferrer_AvroSchema(t *testing.T) {
+	schemaBytes, warnings, err := observeSamples(t).AvroSchema("Order", "com.acme")
+	require.NoError(t, err)
+	assert.Len(t, warnings, 1)
+
+	schema, err := avro.ParseWithCache(string(schemaBytes), "", &avro.SchemaCache{})
+	require.NoError(t, err)
+	record := schema.(*avro.RecordSchema)
+	assert.Equal(t, "com.acme.Order", record.FullName())
+
+	fields := make(map[string]*avro.Field)
+	for _, f := range record.Fields() {
+		fields[f.Name()] = f
+	}
+	require.Contains(t, fields, "my_field")
+	assert.Equal(t, avro.Enum, fields["status"].Type().Type())
+	assert.Equal(t, avro.Double, fields["quantity"].Type().Type())
+	assert.Equal(t, avro.Record, fields["customer"].Type().Type())
+
+	note := fields["note"].Type().(*avro.UnionSchema)
+	assert.True(t, note.Nullable())
+	assert.True(t, fields["note"].HasDefault())
+}
@@ -0,0 +1,125 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package inference
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// orderedObject is a JSON object that keeps the order of its keys when marshalled, so that the
+// inferred schema lists properties in the order they have been observed.
+type orderedObject struct {
+	keys   []string
+	values map[string]any
+}
+
+func newOrderedObject() *orderedObject {
+	return &orderedObject{values: make(map[string]any)}
+}
+
+func (o *orderedObject) set(key string, value any) {
+	if _, exists := o.values[key]; !exists {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// MarshalJSON implements json.Marshaler.
+func (o *orderedObject) MarshalJSON() ([]byte, error) {
+	buf := []byte{'{'}
+	for i, key := range o.keys {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		keyBytes, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		valueBytes, err := json.Marshal(o.values[key])
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %q: %w", key, err)
+		}
+		buf = append(buf, keyBytes...)
+		buf = append(buf, ':')
+		buf = append(buf, valueBytes...)
+	}
+	return append(buf, '}'), nil
+}
+
+// JSONSchema returns the inferred JSON schema (draft-07). Properties that have been present
+// in all observed objects are required, properties that have been null are nullable.
+func (i *Inferrer) JSONSchema() ([]byte, error) {
+	schema := i.root.jsonSchema()
+	schema.set("$schema", "http://json-schema.org/draft-07/schema#")
+
+	// Move $schema to the top, which is where readers expect it
+	schema.keys = append([]string{"$schema"}, schema.keys[:len(schema.keys)-1]...)
+
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+func (n *node) jsonSchema() *orderedObject {
+	schema := newOrderedObject()
+
+	types := n.types()
+	if n.nullCount > 0 {
+		types = append(types, "null")
+	}
+	switch len(types) {
+	case 0:
+		// Nothing has been observed, any value is allowed
+		return schema
+	case 1:
+		schema.set("type", types[0])
+	default:
+		schema.set("type", types)
+	}
+
+	if format := n.format(); format != "" {
+		schema.set("format", string(format))
+	}
+	if enum := n.enum(); enum != nil {
+		values := make([]any, 0, len(enum)+1)
+		for _, v := range enum {
+			values = append(values, v)
+		}
+		if n.nullCount > 0 {
+			values = append(values, nil)
+		}
+		schema.set("enum", values)
+	}
+
+	if n.objCount > 0 {
+		properties := newOrderedObject()
+		required := make([]string, 0)
+		for _, key := range n.propertyOrder {
+			prop := n.properties[key]
+			properties.set(key, prop.jsonSchema())
+			if !prop.isOptional(n) {
+				required = append(required, key)
+			}
+		}
+		schema.set("properties", properties)
+		if len(required) > 0 {
+			schema.set("required", required)
+		}
+	}
+
+	if n.arrCount > 0 {
+		items := newOrderedObject()
+		if n.items != nil {
+			items = n.items.jsonSchema()
+		}
+		schema.set("items", items)
+	}
+
+	return schema
+}
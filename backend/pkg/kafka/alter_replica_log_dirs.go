@@ -0,0 +1,36 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package kafka
+
+import (
+	"context"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// AlterReplicaLogDirs moves partition replicas between the log dirs of the given broker. The request
+// must be sent to the broker that hosts the replicas. This request was added in KIP-113.
+func (s *Service) AlterReplicaLogDirs(ctx context.Context, brokerID int32, dirs []kmsg.AlterReplicaLogDirsRequestDir) (*kmsg.AlterReplicaLogDirsResponse, error) {
+	req := kmsg.NewAlterReplicaLogDirsRequest()
+	req.Dirs = dirs
+
+	return req.RequestWith(ctx, s.KafkaClient.Broker(int(brokerID)))
+}
+
+// DescribeBrokerLogDirs requests the log dir information of a single broker, including replicas that are
+// currently moved to a different log dir (future replicas).
+//
+// Use nil for topicPartitions to describe all topics and partitions.
+func (s *Service) DescribeBrokerLogDirs(ctx context.Context, brokerID int32, topicPartitions []kmsg.DescribeLogDirsRequestTopic) (*kmsg.DescribeLogDirsResponse, error) {
+	req := kmsg.NewDescribeLogDirsRequest()
+	req.Topics = topicPartitions
+
+	return req.RequestWith(ctx, s.KafkaClient.Broker(int(brokerID)))
+}
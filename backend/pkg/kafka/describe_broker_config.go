@@ -19,10 +19,16 @@ import (
 // DescribeBrokerConfig fetches config entries which apply at the Broker Scope (e.g. offset.retention.minutes).
 // Use nil for configNames in order to get all config entries.
 func (s *Service) DescribeBrokerConfig(ctx context.Context, brokerID int32, configNames []string) (*kmsg.DescribeConfigsResponse, error) {
+	return s.DescribeBrokerConfigResource(ctx, strconv.Itoa(int(brokerID)), configNames)
+}
+
+// DescribeBrokerConfigResource fetches config entries of the broker resource with the given name. The resource name
+// is either a broker ID or an empty string for the cluster-wide default configs, which only contains dynamic configs.
+func (s *Service) DescribeBrokerConfigResource(ctx context.Context, resourceName string, configNames []string) (*kmsg.DescribeConfigsResponse, error) {
 	resourceReq := kmsg.NewDescribeConfigsRequestResource()
 	resourceReq.ResourceType = kmsg.ConfigResourceTypeBroker
-	resourceReq.ResourceName = strconv.Itoa(int(brokerID)) // Empty string for all brokers (only works for dynamic broker configs)
-	resourceReq.ConfigNames = configNames                  // Nil requests all
+	resourceReq.ResourceName = resourceName
+	resourceReq.ConfigNames = configNames // Nil requests all
 
 	req := kmsg.NewDescribeConfigsRequest()
 	req.Resources = []kmsg.DescribeConfigsRequestResource{
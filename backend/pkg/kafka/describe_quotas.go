@@ -20,3 +20,19 @@ func (s *Service) DescribeQuotas(ctx context.Context) (*kmsg.DescribeClientQuota
 	r := kmsg.NewDescribeClientQuotasRequest()
 	return r.RequestWith(ctx, s.KafkaClient)
 }
+
+// DescribeQuotasForEntity requests the quota values that are configured for exactly the given entity.
+func (s *Service) DescribeQuotasForEntity(ctx context.Context, components []kmsg.DescribeClientQuotasRequestComponent) (*kmsg.DescribeClientQuotasResponse, error) {
+	r := kmsg.NewDescribeClientQuotasRequest()
+	r.Components = components
+	r.Strict = true
+	return r.RequestWith(ctx, s.KafkaClient)
+}
+
+// AlterQuotas creates, updates or removes quota values via the Kafka API.
+func (s *Service) AlterQuotas(ctx context.Context, entries []kmsg.AlterClientQuotasRequestEntry, validateOnly bool) (*kmsg.AlterClientQuotasResponse, error) {
+	r := kmsg.NewAlterClientQuotasRequest()
+	r.Entries = entries
+	r.ValidateOnly = validateOnly
+	return r.RequestWith(ctx, s.KafkaClient)
+}
@@ -0,0 +1,27 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package kafka
+
+import (
+	"context"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// ElectLeaders triggers a leader election of the given election type for the given topic partitions.
+// Pass nil for topics in order to trigger leader elections for all partitions in the cluster.
+func (s *Service) ElectLeaders(ctx context.Context, electionType int8, topics []kmsg.ElectLeadersRequestTopic) (*kmsg.ElectLeadersResponse, error) {
+	req := kmsg.NewElectLeadersRequest()
+	req.ElectionType = electionType
+	req.Topics = topics
+	req.TimeoutMillis = 30 * 1000
+
+	return req.RequestWith(ctx, s.KafkaClient)
+}
@@ -17,8 +17,15 @@ import (
 
 // IncrementalAlterConfigs sends a request to alter a Kafka resource's (broker, topics, ...) configuration.
 func (s *Service) IncrementalAlterConfigs(ctx context.Context, alterConfigs []kmsg.IncrementalAlterConfigsRequestResource) (*kmsg.IncrementalAlterConfigsResponse, error) {
+	return s.IncrementalAlterConfigsWithValidateOnly(ctx, alterConfigs, false)
+}
+
+// IncrementalAlterConfigsWithValidateOnly is like IncrementalAlterConfigs, but lets the brokers only validate
+// the changes without applying them if validateOnly is true.
+func (s *Service) IncrementalAlterConfigsWithValidateOnly(ctx context.Context, alterConfigs []kmsg.IncrementalAlterConfigsRequestResource, validateOnly bool) (*kmsg.IncrementalAlterConfigsResponse, error) {
 	req := kmsg.NewIncrementalAlterConfigsRequest()
 	req.Resources = alterConfigs
+	req.ValidateOnly = validateOnly
 
 	return req.RequestWith(ctx, s.KafkaClient)
 }
@@ -0,0 +1,106 @@
+// Copyright 2022 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.uber.org/zap"
+)
+
+// RecordTimestamp is the timestamp of a fetched record.
+type RecordTimestamp struct {
+	// Offset of the fetched record, which may be higher than the requested offset, e.g.
+	// due to compaction.
+	Offset int64
+
+	// Timestamp in unix milliseconds.
+	Timestamp int64
+
+	// IsControl is true if the record is a control record such as a transaction marker.
+	IsControl bool
+}
+
+// FetchRecordTimestamps returns the timestamp of the first record at or after the given
+// offset for each partition. The map is keyed by topic and partition. Control records are
+// returned too, because a partition may end with a transaction marker.
+// Partitions that did not return a record before the context is done are missing in the
+// result, so that callers can bound the cost via the context deadline.
+func (s *Service) FetchRecordTimestamps(ctx context.Context, offsets map[string]map[int32]int64) (map[string]map[int32]RecordTimestamp, error) {
+	remaining := 0
+	partitionOffsets := make(map[string]map[int32]kgo.Offset, len(offsets))
+	for topic, partitions := range offsets {
+		partitionOffsets[topic] = make(map[int32]kgo.Offset, len(partitions))
+		for partitionID, offset := range partitions {
+			partitionOffsets[topic][partitionID] = kgo.NewOffset().At(offset)
+			remaining++
+		}
+	}
+	result := make(map[string]map[int32]RecordTimestamp, len(offsets))
+	if remaining == 0 {
+		return result, nil
+	}
+
+	// We only need the first record of each partition, hence we keep the fetch sizes small
+	client, err := s.NewKgoClient(
+		kgo.ConsumePartitions(partitionOffsets),
+		kgo.FetchMaxPartitionBytes(64*1024),
+		kgo.FetchMaxBytes(4*1024*1024),
+		kgo.KeepControlRecords(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new kafka client: %w", err)
+	}
+	defer client.Close()
+
+	for remaining > 0 {
+		fetches := client.PollFetches(ctx)
+		if ctx.Err() != nil {
+			break
+		}
+		for _, fetchErr := range fetches.Errors() {
+			if errors.Is(fetchErr.Err, context.Canceled) || errors.Is(fetchErr.Err, context.DeadlineExceeded) {
+				continue
+			}
+			s.Logger.Warn("errors while fetching record timestamps",
+				zap.String("topic_name", fetchErr.Topic),
+				zap.Int32("partition", fetchErr.Partition),
+				zap.Error(fetchErr.Err))
+		}
+
+		done := make(map[string][]int32)
+		fetches.EachRecord(func(record *kgo.Record) {
+			if _, exists := result[record.Topic][record.Partition]; exists {
+				return
+			}
+			if record.Offset < offsets[record.Topic][record.Partition] {
+				return
+			}
+			if _, exists := result[record.Topic]; !exists {
+				result[record.Topic] = make(map[int32]RecordTimestamp)
+			}
+			result[record.Topic][record.Partition] = RecordTimestamp{
+				Offset:    record.Offset,
+				Timestamp: record.Timestamp.UnixMilli(),
+				IsControl: record.Attrs.IsControl(),
+			}
+			done[record.Topic] = append(done[record.Topic], record.Partition)
+			remaining--
+		})
+		if len(done) > 0 {
+			client.PauseFetchPartitions(done)
+		}
+	}
+
+	return result, nil
+}
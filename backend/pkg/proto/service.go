@@ -554,3 +554,23 @@ func (r *anyResolver) Resolve(typeURL string) (protoiface.MessageV1, error) {
 
 	return r.mr.Resolve(mname)
 }
+
+// GetMessageDescriptorByType returns the message descriptor for the given fully qualified proto
+// type (e.g. "shop.v1.Order") from the local proto registry.
+func (s *Service) GetMessageDescriptorByType(protoType string) (*desc.MessageDescriptor, error) {
+	s.registryMutex.RLock()
+	defer s.registryMutex.RUnlock()
+
+	if s.registry == nil {
+		return nil, fmt.Errorf("proto registry has not been created yet")
+	}
+	messageDescriptor, err := s.registry.FindMessageTypeByUrl(protoType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the proto type in the proto registry: %w", err)
+	}
+	if messageDescriptor == nil {
+		return nil, fmt.Errorf("proto type %q does not exist in the proto registry", protoType)
+	}
+
+	return messageDescriptor, nil
+}
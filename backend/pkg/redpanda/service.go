@@ -89,6 +89,15 @@ func (s *Service) CreateUser(ctx context.Context, username, password, mechanism
 	return nil
 }
 
+// UpdateUser changes the password and mechanism of an existing user (also known as principal) in the Redpanda cluster.
+func (s *Service) UpdateUser(ctx context.Context, username, password, mechanism string) error {
+	err := s.adminClient.UpdateUser(ctx, username, password, mechanism)
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	return nil
+}
+
 // DeleteUser deletes a user (also known as principal) from the Redpanda cluster.
 func (s *Service) DeleteUser(ctx context.Context, username string) error {
 	err := s.adminClient.DeleteUser(ctx, username)
@@ -0,0 +1,511 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// BackupFormatVersion is the version of the backup document that is written by ExportBackup.
+// Backups with a newer format version can not be imported.
+const BackupFormatVersion = 1
+
+// Backup is a portable snapshot of all subjects, schema versions and configurations
+// that are stored in a schema registry.
+type Backup struct {
+	FormatVersion int       `json:"formatVersion"`
+	CreatedAt     time.Time `json:"createdAt"`
+
+	// Mode and Compatibility are the global settings of the schema registry.
+	Mode          string `json:"mode,omitempty"`
+	Compatibility string `json:"compatibility,omitempty"`
+
+	Subjects []BackupSubject `json:"subjects"`
+}
+
+// BackupSubject contains all registered versions of a subject. Mode and Compatibility
+// are only set if they differ from the global settings.
+type BackupSubject struct {
+	Name          string                `json:"name"`
+	Mode          string                `json:"mode,omitempty"`
+	Compatibility string                `json:"compatibility,omitempty"`
+	Versions      []BackupSchemaVersion `json:"versions"`
+}
+
+// BackupSchemaVersion is a single schema version of a subject.
+type BackupSchemaVersion struct {
+	Version    int         `json:"version"`
+	SchemaID   int         `json:"schemaId"`
+	Type       string      `json:"type"`
+	Schema     string      `json:"schema"`
+	References []Reference `json:"references,omitempty"`
+}
+
+// Validate checks whether the backup can be imported.
+func (b *Backup) Validate() error {
+	if b.FormatVersion < 1 || b.FormatVersion > BackupFormatVersion {
+		return fmt.Errorf("unsupported backup format version %d, supported versions are 1-%d", b.FormatVersion, BackupFormatVersion)
+	}
+
+	for _, subject := range b.Subjects {
+		if subject.Name == "" {
+			return fmt.Errorf("backup contains a subject without a name")
+		}
+		for _, version := range subject.Versions {
+			if version.Version < 1 {
+				return fmt.Errorf("backup contains an invalid version %d for subject %q", version.Version, subject.Name)
+			}
+			if version.Schema == "" {
+				return fmt.Errorf("backup contains an empty schema for subject %q version %d", subject.Name, version.Version)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ExportBackup exports all subjects with all their registered versions, schema IDs, references
+// as well as the global and subject specific compatibility and mode settings.
+func (s *Service) ExportBackup() (*Backup, error) {
+	backup := &Backup{
+		FormatVersion: BackupFormatVersion,
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	mode, err := s.registryClient.GetMode()
+	if err != nil {
+		// Some schema registry implementations do not support this endpoint
+		s.logger.Debug("failed to get global mode for schema registry backup", zap.Error(err))
+	} else {
+		backup.Mode = mode.Mode
+	}
+
+	cfg, err := s.registryClient.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get global config: %w", err)
+	}
+	backup.Compatibility = cfg.Compatibility
+
+	subjectsRes, err := s.registryClient.GetSubjects()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subjects: %w", err)
+	}
+
+	// Export all subjects concurrently, but limit the concurrency so that we don't overload the schema registry
+	mutex := sync.Mutex{}
+	subjects := make([]BackupSubject, 0, len(subjectsRes.Subjects))
+	g := errgroup.Group{}
+	g.SetLimit(10)
+	for _, subject := range subjectsRes.Subjects {
+		subject := subject
+		g.Go(func() error {
+			backupSubject, err := s.exportSubject(subject, backup)
+			if err != nil {
+				return fmt.Errorf("failed to export subject %q: %w", subject, err)
+			}
+			mutex.Lock()
+			subjects = append(subjects, backupSubject)
+			mutex.Unlock()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(subjects, func(i, j int) bool {
+		return subjects[i].Name < subjects[j].Name
+	})
+	backup.Subjects = subjects
+
+	return backup, nil
+}
+
+func (s *Service) exportSubject(subject string, backup *Backup) (BackupSubject, error) {
+	versionsRes, err := s.registryClient.GetSubjectVersions(subject)
+	if err != nil {
+		return BackupSubject{}, fmt.Errorf("failed to get subject versions: %w", err)
+	}
+
+	versions := make([]BackupSchemaVersion, 0, len(versionsRes.Versions))
+	for _, version := range versionsRes.Versions {
+		schemaRes, err := s.registryClient.GetSchemaBySubject(subject, strconv.Itoa(version))
+		if err != nil {
+			return BackupSubject{}, fmt.Errorf("failed to get schema version %d: %w", version, err)
+		}
+		versions = append(versions, BackupSchemaVersion{
+			Version:    schemaRes.Version,
+			SchemaID:   schemaRes.SchemaID,
+			Type:       schemaRes.Type,
+			Schema:     schemaRes.Schema,
+			References: schemaRes.References,
+		})
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version < versions[j].Version
+	})
+
+	backupSubject := BackupSubject{
+		Name:     subject,
+		Versions: versions,
+	}
+
+	// Subject specific settings are only exported if they differ from the global settings. Schema registries
+	// are inconsistent in whether they fall back to the global setting, hence we can't rely on a not found error.
+	cfg, err := s.registryClient.GetSubjectConfig(subject)
+	if err == nil && cfg.Compatibility != "DEFAULT" && cfg.Compatibility != backup.Compatibility {
+		backupSubject.Compatibility = cfg.Compatibility
+	}
+	mode, err := s.registryClient.GetSubjectMode(subject)
+	if err == nil && mode.Mode != backup.Mode {
+		backupSubject.Mode = mode.Mode
+	}
+
+	return backupSubject, nil
+}
+
+// ImportAction describes what the import does with a single schema version of the backup.
+type ImportAction string
+
+const (
+	// ImportActionRegister means the schema version does not exist yet and will be registered.
+	ImportActionRegister ImportAction = "REGISTER"
+	// ImportActionSkip means the identical schema version already exists.
+	ImportActionSkip ImportAction = "SKIP"
+	// ImportActionConflict means the schema version can not be imported because the
+	// version or the schema ID is already taken by a different schema.
+	ImportActionConflict ImportAction = "CONFLICT"
+)
+
+// ImportOptions control how a backup is replayed into a schema registry.
+type ImportOptions struct {
+	// DryRun only computes the import report without changing anything in the target registry.
+	DryRun bool `json:"dryRun"`
+
+	// PreserveIDs registers all schemas with their original schema IDs and versions. This
+	// requires the target registry to support the IMPORT mode, which will be set during the import.
+	// The previous mode is restored afterwards.
+	PreserveIDs bool `json:"preserveIds"`
+
+	// AllowNonEmpty forces the IMPORT mode if the target registry refuses to switch to it, because
+	// it already contains schemas. Only used together with PreserveIDs.
+	AllowNonEmpty bool `json:"allowNonEmpty"`
+
+	// ApplyGlobalMode sets the global mode of the backup after the import. By default the global
+	// mode of the target registry is left unchanged.
+	ApplyGlobalMode bool `json:"applyGlobalMode"`
+}
+
+// ImportReport contains the planned (dry run) or taken actions for each schema version and setting.
+type ImportReport struct {
+	DryRun        bool `json:"dryRun"`
+	PreserveIDs   bool `json:"preserveIds"`
+	ConflictCount int  `json:"conflictCount"`
+	ErrorCount    int  `json:"errorCount"`
+
+	// ForcedModeChange is set if the IMPORT mode had to be forced, which bypasses the registry's
+	// check that no schemas exist yet.
+	ForcedModeChange bool `json:"forcedModeChange"`
+
+	Schemas  []ImportSchemaResult  `json:"schemas"`
+	Settings []ImportSettingResult `json:"settings"`
+}
+
+// ImportSchemaResult is the result for a single schema version of the backup.
+type ImportSchemaResult struct {
+	Subject  string       `json:"subject"`
+	Version  int          `json:"version"`
+	SchemaID int          `json:"schemaId"`
+	Action   ImportAction `json:"action"`
+	Reason   string       `json:"reason,omitempty"`
+
+	// RegisteredID is the schema ID that has been assigned by the target registry.
+	RegisteredID int    `json:"registeredId,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// ImportSettingResult is the result for applying a mode or compatibility setting. An empty
+// subject refers to the global setting.
+type ImportSettingResult struct {
+	Subject string `json:"subject,omitempty"`
+	Setting string `json:"setting"`
+	Value   string `json:"value"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ImportBackup replays a backup into the connected schema registry. Schema versions are registered
+// in an order that guarantees referenced schemas are registered before the schemas referencing them.
+// Schema versions that conflict with existing schemas are never overwritten.
+func (s *Service) ImportBackup(backup *Backup, opts ImportOptions) (*ImportReport, error) {
+	if err := backup.Validate(); err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{
+		DryRun:      opts.DryRun,
+		PreserveIDs: opts.PreserveIDs,
+		Schemas:     make([]ImportSchemaResult, 0),
+		Settings:    make([]ImportSettingResult, 0),
+	}
+
+	// 1. Plan what to do with each schema version
+	for _, item := range sortBackupForImport(backup) {
+		result, err := s.planSchemaImport(item.subject, item.version, opts)
+		if err != nil {
+			return nil, err
+		}
+		if result.Action == ImportActionConflict {
+			report.ConflictCount++
+		}
+		report.Schemas = append(report.Schemas, result)
+	}
+	for _, subject := range backup.Subjects {
+		if subject.Compatibility != "" {
+			report.Settings = append(report.Settings, ImportSettingResult{Subject: subject.Name, Setting: "compatibility", Value: subject.Compatibility})
+		}
+		if subject.Mode != "" {
+			report.Settings = append(report.Settings, ImportSettingResult{Subject: subject.Name, Setting: "mode", Value: subject.Mode})
+		}
+	}
+	if backup.Compatibility != "" {
+		report.Settings = append(report.Settings, ImportSettingResult{Setting: "compatibility", Value: backup.Compatibility})
+	}
+	if opts.ApplyGlobalMode && backup.Mode != "" && opts.DryRun {
+		report.Settings = append(report.Settings, ImportSettingResult{Setting: "mode", Value: backup.Mode})
+	}
+
+	if opts.DryRun {
+		return report, nil
+	}
+
+	// 2. Register schemas. If IDs shall be preserved, we have to switch to IMPORT mode first
+	// and switch back to the previous mode afterwards.
+	restoreMode := ""
+	if opts.PreserveIDs {
+		mode, err := s.registryClient.GetMode()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current mode: %w", err)
+		}
+		restoreMode = mode.Mode
+		if _, err := s.registryClient.PutMode("IMPORT", false); err != nil {
+			if !opts.AllowNonEmpty {
+				return nil, fmt.Errorf("failed to set mode to IMPORT, the target registry may not be empty: %w", err)
+			}
+			if _, err := s.registryClient.PutMode("IMPORT", true); err != nil {
+				return nil, fmt.Errorf("failed to force mode IMPORT: %w", err)
+			}
+			report.ForcedModeChange = true
+		}
+	}
+	if opts.ApplyGlobalMode && backup.Mode != "" {
+		restoreMode = backup.Mode
+	}
+
+	versionsBySubject := backupVersionsBySubject(backup)
+	for i := range report.Schemas {
+		result := &report.Schemas[i]
+		if result.Action != ImportActionRegister {
+			continue
+		}
+
+		version := versionsBySubject[result.Subject][result.Version]
+		req := RegisterSchemaRequest{
+			Schema:     version.Schema,
+			SchemaType: version.Type,
+			References: version.References,
+		}
+		if opts.PreserveIDs {
+			req.ID = version.SchemaID
+			req.Version = version.Version
+		}
+		res, err := s.registryClient.RegisterSchema(result.Subject, req)
+		if err != nil {
+			result.Error = err.Error()
+			report.ErrorCount++
+			continue
+		}
+		result.RegisteredID = res.ID
+	}
+
+	// 3. Apply compatibility settings and modes. Modes are applied last, because a READONLY mode
+	// would prevent all further changes.
+	for i := range report.Settings {
+		setting := &report.Settings[i]
+		var err error
+		switch {
+		case setting.Setting == "compatibility" && setting.Subject == "":
+			_, err = s.registryClient.PutConfig(setting.Value)
+		case setting.Setting == "compatibility":
+			_, err = s.registryClient.PutSubjectConfig(setting.Subject, setting.Value)
+		case setting.Setting == "mode":
+			_, err = s.registryClient.PutSubjectMode(setting.Subject, setting.Value)
+		}
+		if err != nil {
+			setting.Error = err.Error()
+			report.ErrorCount++
+		}
+	}
+	if restoreMode != "" {
+		setting := ImportSettingResult{Setting: "mode", Value: restoreMode}
+		if _, err := s.registryClient.PutMode(restoreMode, false); err != nil {
+			setting.Error = err.Error()
+			report.ErrorCount++
+		}
+		report.Settings = append(report.Settings, setting)
+	}
+
+	return report, nil
+}
+
+func (s *Service) planSchemaImport(subject string, version BackupSchemaVersion, opts ImportOptions) (ImportSchemaResult, error) {
+	result := ImportSchemaResult{
+		Subject:  subject,
+		Version:  version.Version,
+		SchemaID: version.SchemaID,
+		Action:   ImportActionRegister,
+	}
+
+	existing, err := s.registryClient.GetSchemaBySubject(subject, strconv.Itoa(version.Version))
+	switch {
+	case err == nil:
+		if !isSameSchema(existing.Type, existing.Schema, existing.References, version) {
+			result.Action = ImportActionConflict
+			result.Reason = "subject version already exists with a different schema"
+			return result, nil
+		}
+		if opts.PreserveIDs && existing.SchemaID != version.SchemaID {
+			result.Action = ImportActionConflict
+			result.Reason = fmt.Sprintf("subject version already exists with a different schema id %d", existing.SchemaID)
+			return result, nil
+		}
+		result.Action = ImportActionSkip
+		result.Reason = "identical subject version already exists"
+		return result, nil
+	case !isSubjectVersionNotFound(err):
+		return result, fmt.Errorf("failed to check for existing subject version (subject: %q, version: %d): %w", subject, version.Version, err)
+	}
+
+	if opts.PreserveIDs {
+		existingByID, err := s.registryClient.GetSchemaByID(uint32(version.SchemaID))
+		switch {
+		case err == nil:
+			if !isSameSchema(existingByID.SchemaType, existingByID.Schema, existingByID.References, version) {
+				result.Action = ImportActionConflict
+				result.Reason = "schema id is already used by a different schema"
+				return result, nil
+			}
+		case !IsSchemaNotFound(err):
+			return result, fmt.Errorf("failed to check for existing schema id %d: %w", version.SchemaID, err)
+		}
+	}
+
+	return result, nil
+}
+
+// isSameSchema compares an existing schema against a schema version from a backup. JSON based
+// schemas (Avro and JSON schema) are compared without insignificant whitespace.
+func isSameSchema(schemaType string, schema string, references []Reference, version BackupSchemaVersion) bool {
+	if schemaType == "" {
+		schemaType = "AVRO"
+	}
+	versionType := version.Type
+	if versionType == "" {
+		versionType = "AVRO"
+	}
+	if schemaType != versionType || len(references) != len(version.References) {
+		return false
+	}
+	for i, ref := range references {
+		if ref != version.References[i] {
+			return false
+		}
+	}
+
+	return compactSchema(schema) == compactSchema(version.Schema)
+}
+
+func compactSchema(schema string) string {
+	buf := bytes.Buffer{}
+	if err := json.Compact(&buf, []byte(schema)); err != nil {
+		return schema
+	}
+	return buf.String()
+}
+
+type backupImportItem struct {
+	subject string
+	version BackupSchemaVersion
+}
+
+func backupVersionsBySubject(backup *Backup) map[string]map[int]BackupSchemaVersion {
+	versionsBySubject := make(map[string]map[int]BackupSchemaVersion)
+	for _, subject := range backup.Subjects {
+		versionsBySubject[subject.Name] = make(map[int]BackupSchemaVersion)
+		for _, version := range subject.Versions {
+			versionsBySubject[subject.Name][version.Version] = version
+		}
+	}
+	return versionsBySubject
+}
+
+// sortBackupForImport returns all schema versions of a backup in an order, so that every referenced
+// schema version comes before the schema versions referencing it. Apart from that schema versions are
+// ordered by their schema id, which resembles the order they have originally been registered.
+func sortBackupForImport(backup *Backup) []backupImportItem {
+	items := make([]backupImportItem, 0)
+	for _, subject := range backup.Subjects {
+		for _, version := range subject.Versions {
+			items = append(items, backupImportItem{subject: subject.Name, version: version})
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].version.SchemaID != items[j].version.SchemaID {
+			return items[i].version.SchemaID < items[j].version.SchemaID
+		}
+		if items[i].subject != items[j].subject {
+			return items[i].subject < items[j].subject
+		}
+		return items[i].version.Version < items[j].version.Version
+	})
+
+	versionsBySubject := backupVersionsBySubject(backup)
+	sorted := make([]backupImportItem, 0, len(items))
+	added := make(map[SubjectVersion]struct{})
+	var add func(item backupImportItem)
+	add = func(item backupImportItem) {
+		sv := SubjectVersion{Subject: item.subject, Version: item.version.Version}
+		if _, exists := added[sv]; exists {
+			return
+		}
+		// Mark before descending, so that reference cycles can not cause an endless recursion
+		added[sv] = struct{}{}
+		for _, ref := range item.version.References {
+			// References that are not part of the backup must already exist in the target registry
+			if referenced, exists := versionsBySubject[ref.Subject][ref.Version]; exists {
+				add(backupImportItem{subject: ref.Subject, version: referenced})
+			}
+		}
+		sorted = append(sorted, item)
+	}
+	for _, item := range items {
+		add(item)
+	}
+
+	return sorted
+}
@@ -0,0 +1,211 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package schema
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/redpanda-data/console/backend/pkg/config"
+)
+
+func TestSortBackupForImport(t *testing.T) {
+	// The referenced schema has a higher schema id than the schema referencing it. This can
+	// happen if the referenced subject has been deleted and re-registered.
+	backup := &Backup{
+		FormatVersion: BackupFormatVersion,
+		Subjects: []BackupSubject{
+			{Name: "order", Versions: []BackupSchemaVersion{
+				{Version: 1, SchemaID: 1, Schema: "order-v1", References: []Reference{{Name: "customer", Subject: "customer", Version: 1}}},
+			}},
+			{Name: "customer", Versions: []BackupSchemaVersion{
+				{Version: 1, SchemaID: 5, Schema: "customer-v1"},
+			}},
+			{Name: "invoice", Versions: []BackupSchemaVersion{
+				{Version: 1, SchemaID: 3, Schema: "invoice-v1"},
+			}},
+		},
+	}
+
+	sorted := sortBackupForImport(backup)
+	actual := make([]string, len(sorted))
+	for i, item := range sorted {
+		actual[i] = item.subject
+	}
+	assert.Equal(t, []string{"customer", "order", "invoice"}, actual)
+}
+
+func TestService_ImportBackup_DryRun(t *testing.T) {
+	baseURL := testSchemaRegistryBaseURL
+	logger, _ := zap.NewProduction()
+	s, _ := NewService(config.Schema{
+		Enabled: true,
+		URLs:    []string{baseURL},
+	}, logger)
+
+	httpClient := (*s.registryClient.client).GetClient()
+	httpmock.ActivateNonDefault(httpClient)
+	defer httpmock.DeactivateAndReset()
+
+	notFound := func(code int) httpmock.Responder {
+		return func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewJsonResponse(http.StatusNotFound, map[string]interface{}{
+				"error_code": code,
+				"message":    "not found",
+			})
+		}
+	}
+
+	// Identical subject version exists already
+	httpmock.RegisterResponder("GET", baseURL+"/subjects/existing/versions/1",
+		func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewJsonResponse(http.StatusOK, map[string]interface{}{
+				"subject": "existing",
+				"version": 1,
+				"id":      1,
+				"schema":  "{\"type\": \"string\"}",
+			})
+		})
+
+	// Subject version does not exist, but the schema id is used by a different schema
+	httpmock.RegisterResponder("GET", baseURL+"/subjects/taken-id/versions/1", notFound(codeSubjectNotFound))
+	httpmock.RegisterResponder("GET", baseURL+"/schemas/ids/2",
+		func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewJsonResponse(http.StatusOK, map[string]interface{}{
+				"schema": "{\"type\": \"int\"}",
+			})
+		})
+
+	// Subject version and schema id are free
+	httpmock.RegisterResponder("GET", baseURL+"/subjects/new/versions/1", notFound(codeSubjectNotFound))
+	httpmock.RegisterResponder("GET", baseURL+"/schemas/ids/3", notFound(codeSchemaNotFound))
+
+	backup := &Backup{
+		FormatVersion: BackupFormatVersion,
+		Compatibility: "BACKWARD",
+		Subjects: []BackupSubject{
+			{Name: "existing", Versions: []BackupSchemaVersion{{Version: 1, SchemaID: 1, Type: "AVRO", Schema: "{\"type\":\"string\"}"}}},
+			{Name: "taken-id", Versions: []BackupSchemaVersion{{Version: 1, SchemaID: 2, Type: "AVRO", Schema: "{\"type\":\"long\"}"}}},
+			{Name: "new", Compatibility: "NONE", Versions: []BackupSchemaVersion{{Version: 1, SchemaID: 3, Type: "AVRO", Schema: "{\"type\":\"boolean\"}"}}},
+		},
+	}
+
+	report, err := s.ImportBackup(backup, ImportOptions{DryRun: true, PreserveIDs: true})
+	require.NoError(t, err)
+
+	actions := make(map[string]ImportAction)
+	for _, result := range report.Schemas {
+		actions[result.Subject] = result.Action
+	}
+	assert.Equal(t, map[string]ImportAction{
+		"existing": ImportActionSkip,
+		"taken-id": ImportActionConflict,
+		"new":      ImportActionRegister,
+	}, actions)
+	assert.Equal(t, 1, report.ConflictCount)
+	assert.Len(t, report.Settings, 2)
+
+	// A dry run must only issue the GET requests for checking existing subject versions and schema ids
+	assert.Equal(t, 5, httpmock.GetTotalCallCount())
+}
+
+func TestService_ImportBackup_RestoresMode(t *testing.T) {
+	baseURL := testSchemaRegistryBaseURL
+	logger, _ := zap.NewProduction()
+	s, _ := NewService(config.Schema{
+		Enabled: true,
+		URLs:    []string{baseURL},
+	}, logger)
+
+	httpClient := (*s.registryClient.client).GetClient()
+	httpmock.ActivateNonDefault(httpClient)
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", baseURL+"/subjects/new/versions/1",
+		func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewJsonResponse(http.StatusNotFound, map[string]interface{}{
+				"error_code": codeSubjectNotFound,
+				"message":    "not found",
+			})
+		})
+	httpmock.RegisterResponder("GET", baseURL+"/schemas/ids/3",
+		func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewJsonResponse(http.StatusNotFound, map[string]interface{}{
+				"error_code": codeSchemaNotFound,
+				"message":    "not found",
+			})
+		})
+	httpmock.RegisterResponder("POST", baseURL+"/subjects/new/versions",
+		httpmock.NewJsonResponderOrPanic(http.StatusOK, map[string]interface{}{"id": 3}))
+	httpmock.RegisterResponder("GET", baseURL+"/mode",
+		httpmock.NewJsonResponderOrPanic(http.StatusOK, map[string]interface{}{"mode": "READWRITE"}))
+
+	type modeChange struct {
+		Mode  string
+		Force string
+	}
+	modeChanges := make([]modeChange, 0)
+	registryEmpty := true
+	httpmock.RegisterResponder("PUT", baseURL+"/mode",
+		func(req *http.Request) (*http.Response, error) {
+			var body ModeResponse
+			if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+				return nil, err
+			}
+			force := req.URL.Query().Get("force")
+			modeChanges = append(modeChanges, modeChange{Mode: body.Mode, Force: force})
+			if body.Mode == "IMPORT" && !registryEmpty && force != "true" {
+				return httpmock.NewJsonResponse(http.StatusUnprocessableEntity, map[string]interface{}{
+					"error_code": 42205,
+					"message":    "Cannot import since found existing subjects",
+				})
+			}
+			return httpmock.NewJsonResponse(http.StatusOK, body)
+		})
+
+	backup := &Backup{
+		FormatVersion: BackupFormatVersion,
+		Mode:          "READONLY",
+		Subjects: []BackupSubject{
+			{Name: "new", Versions: []BackupSchemaVersion{{Version: 1, SchemaID: 3, Type: "AVRO", Schema: "{\"type\":\"boolean\"}"}}},
+		},
+	}
+
+	report, err := s.ImportBackup(backup, ImportOptions{PreserveIDs: true})
+	require.NoError(t, err)
+
+	// The mode of the backup is only applied if requested, the previous mode is restored instead
+	assert.False(t, report.ForcedModeChange)
+	assert.Equal(t, []modeChange{{Mode: "IMPORT"}, {Mode: "READWRITE"}}, modeChanges)
+
+	modeChanges = modeChanges[:0]
+	_, err = s.ImportBackup(backup, ImportOptions{PreserveIDs: true, ApplyGlobalMode: true})
+	require.NoError(t, err)
+	assert.Equal(t, []modeChange{{Mode: "IMPORT"}, {Mode: "READONLY"}}, modeChanges)
+
+	// A non-empty registry is only forced into IMPORT mode if explicitly allowed
+	registryEmpty = false
+	modeChanges = modeChanges[:0]
+	_, err = s.ImportBackup(backup, ImportOptions{PreserveIDs: true})
+	require.Error(t, err)
+	assert.Equal(t, []modeChange{{Mode: "IMPORT"}}, modeChanges)
+
+	modeChanges = modeChanges[:0]
+	report, err = s.ImportBackup(backup, ImportOptions{PreserveIDs: true, AllowNonEmpty: true})
+	require.NoError(t, err)
+	assert.True(t, report.ForcedModeChange)
+	assert.Equal(t, []modeChange{{Mode: "IMPORT"}, {Mode: "IMPORT", Force: "true"}, {Mode: "READWRITE"}}, modeChanges)
+}
@@ -442,6 +442,232 @@ func (c *Client) GetSchemasIndividually() ([]SchemaVersionedResponse, error) {
 	return schemas, nil
 }
 
+// GetSubjectMode returns the mode for a given subject. Schema registries that do not support
+// modes per subject, return an error.
+func (c *Client) GetSubjectMode(subject string) (*ModeResponse, error) {
+	res, err := c.client.R().SetResult(&ModeResponse{}).
+		SetPathParam("subject", subject).
+		Get("/mode/{subject}")
+	if err != nil {
+		return nil, fmt.Errorf("get subject mode request failed: %w", err)
+	}
+
+	if res.IsError() {
+		restErr, ok := res.Error().(*RestError)
+		if !ok {
+			return nil, fmt.Errorf("get subject mode request failed: Status code %d", res.StatusCode())
+		}
+		return nil, restErr
+	}
+
+	parsed, ok := res.Result().(*ModeResponse)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse subject mode response")
+	}
+
+	return parsed, nil
+}
+
+// PutMode sets the mode for Schema Registry at a global level. Setting the mode to IMPORT
+// requires the registry to be empty, unless force is set to true.
+func (c *Client) PutMode(mode string, force bool) (*ModeResponse, error) {
+	req := c.client.R().SetResult(&ModeResponse{}).SetBody(ModeResponse{Mode: mode})
+	if force {
+		req = req.SetQueryParam("force", "true")
+	}
+	res, err := req.Put("/mode")
+	if err != nil {
+		return nil, fmt.Errorf("put mode request failed: %w", err)
+	}
+
+	if res.IsError() {
+		restErr, ok := res.Error().(*RestError)
+		if !ok {
+			return nil, fmt.Errorf("put mode request failed: Status code %d", res.StatusCode())
+		}
+		return nil, restErr
+	}
+
+	parsed, ok := res.Result().(*ModeResponse)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse put mode response")
+	}
+
+	return parsed, nil
+}
+
+// PutSubjectMode sets the mode for a given subject.
+func (c *Client) PutSubjectMode(subject string, mode string) (*ModeResponse, error) {
+	res, err := c.client.R().SetResult(&ModeResponse{}).
+		SetPathParam("subject", subject).
+		SetBody(ModeResponse{Mode: mode}).
+		Put("/mode/{subject}")
+	if err != nil {
+		return nil, fmt.Errorf("put subject mode request failed: %w", err)
+	}
+
+	if res.IsError() {
+		restErr, ok := res.Error().(*RestError)
+		if !ok {
+			return nil, fmt.Errorf("put subject mode request failed: Status code %d", res.StatusCode())
+		}
+		return nil, restErr
+	}
+
+	parsed, ok := res.Result().(*ModeResponse)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse put subject mode response")
+	}
+
+	return parsed, nil
+}
+
+// PutConfigRequest is the request schema for the schema registry's PUT /config endpoints.
+type PutConfigRequest struct {
+	Compatibility string `json:"compatibility"`
+}
+
+// PutConfig sets the global compatibility level.
+func (c *Client) PutConfig(compatibility string) (*PutConfigRequest, error) {
+	res, err := c.client.R().SetResult(&PutConfigRequest{}).
+		SetBody(PutConfigRequest{Compatibility: compatibility}).
+		Put("/config")
+	if err != nil {
+		return nil, fmt.Errorf("put config failed: %w", err)
+	}
+
+	if res.IsError() {
+		restErr, ok := res.Error().(*RestError)
+		if !ok {
+			return nil, fmt.Errorf("put config failed: Status code %d", res.StatusCode())
+		}
+		return nil, restErr
+	}
+
+	parsed, ok := res.Result().(*PutConfigRequest)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse put config response")
+	}
+
+	return parsed, nil
+}
+
+// PutSubjectConfig sets the compatibility level for a given subject.
+func (c *Client) PutSubjectConfig(subject string, compatibility string) (*PutConfigRequest, error) {
+	res, err := c.client.R().SetResult(&PutConfigRequest{}).
+		SetPathParam("subject", subject).
+		SetBody(PutConfigRequest{Compatibility: compatibility}).
+		Put("/config/{subject}")
+	if err != nil {
+		return nil, fmt.Errorf("put config for subject failed: %w", err)
+	}
+
+	if res.IsError() {
+		restErr, ok := res.Error().(*RestError)
+		if !ok {
+			return nil, fmt.Errorf("put config for subject failed: Status code %d", res.StatusCode())
+		}
+		return nil, restErr
+	}
+
+	parsed, ok := res.Result().(*PutConfigRequest)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse put config for subject response")
+	}
+
+	return parsed, nil
+}
+
+// RegisterSchemaRequest is the request schema for registering a new schema under a subject.
+// ID and Version can only be set if the schema registry (or the subject) is in IMPORT mode.
+type RegisterSchemaRequest struct {
+	Schema     string      `json:"schema"`
+	SchemaType string      `json:"schemaType,omitempty"`
+	References []Reference `json:"references,omitempty"`
+	ID         int         `json:"id,omitempty"`
+	Version    int         `json:"version,omitempty"`
+}
+
+// RegisterSchemaResponse is the response schema of the POST /subjects/{subject}/versions endpoint.
+type RegisterSchemaResponse struct {
+	ID int `json:"id"`
+}
+
+// RegisterSchema registers a new schema under the specified subject. If the exact same schema
+// has already been registered, the ID of the existing schema will be returned.
+func (c *Client) RegisterSchema(subject string, schema RegisterSchemaRequest) (*RegisterSchemaResponse, error) {
+	res, err := c.client.R().SetResult(&RegisterSchemaResponse{}).
+		SetPathParam("subject", subject).
+		SetBody(schema).
+		Post("/subjects/{subject}/versions")
+	if err != nil {
+		return nil, fmt.Errorf("register schema request failed: %w", err)
+	}
+
+	if res.IsError() {
+		restErr, ok := res.Error().(*RestError)
+		if !ok {
+			return nil, fmt.Errorf("register schema request failed: Status code %d", res.StatusCode())
+		}
+		return nil, restErr
+	}
+
+	parsed, ok := res.Result().(*RegisterSchemaResponse)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse register schema response")
+	}
+
+	return parsed, nil
+}
+
+// GetSchemaReferencedBy returns the IDs of all schemas that reference the given subject version.
+func (c *Client) GetSchemaReferencedBy(subject string, version string) ([]int, error) {
+	var schemaIDs []int
+	res, err := c.client.R().SetResult(&schemaIDs).SetPathParams(map[string]string{
+		"subject": subject,
+		"version": version,
+	}).Get("/subjects/{subject}/versions/{version}/referencedby")
+	if err != nil {
+		return nil, fmt.Errorf("get schema referenced by failed: %w", err)
+	}
+
+	if res.IsError() {
+		restErr, ok := res.Error().(*RestError)
+		if !ok {
+			return nil, fmt.Errorf("get schema referenced by failed: Status code %d", res.StatusCode())
+		}
+		return nil, restErr
+	}
+
+	return schemaIDs, nil
+}
+
+// SubjectVersion is a tuple of subject name and version that identifies a single registered schema version.
+type SubjectVersion struct {
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+// GetSchemaSubjectVersionsByID returns all subject versions that are associated with the given schema id.
+// Multiple subjects may register the same schema, in which case all of them share the same schema id.
+func (c *Client) GetSchemaSubjectVersionsByID(id int) ([]SubjectVersion, error) {
+	var subjectVersions []SubjectVersion
+	res, err := c.client.R().SetResult(&subjectVersions).Get(fmt.Sprintf("/schemas/ids/%d/versions", id))
+	if err != nil {
+		return nil, fmt.Errorf("get schema subject versions by id failed: %w", err)
+	}
+
+	if res.IsError() {
+		restErr, ok := res.Error().(*RestError)
+		if !ok {
+			return nil, fmt.Errorf("get schema subject versions by id failed: Status code %d", res.StatusCode())
+		}
+		return nil, restErr
+	}
+
+	return subjectVersions, nil
+}
+
 // CheckConnectivity checks whether the schema registry can be access by GETing the /subjects
 func (c *Client) CheckConnectivity() error {
 	url := "subjects"
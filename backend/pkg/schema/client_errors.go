@@ -15,6 +15,7 @@ import (
 
 const (
 	codeSubjectNotFound       = 40401
+	codeVersionNotFound       = 40402
 	codeSchemaNotFound        = 40403
 	codeBackendDatastoreError = 50001
 )
@@ -26,10 +27,21 @@ func IsSchemaNotFound(err error) bool {
 		return false
 	}
 
-	var restErr RestError
+	var restErr *RestError
 	if errors.As(err, &restErr) {
 		return restErr.ErrorCode == codeSchemaNotFound
 	}
 
 	return false
 }
+
+// isSubjectVersionNotFound returns true if the given error indicates that either the
+// requested subject or the requested version of a subject does not exist.
+func isSubjectVersionNotFound(err error) bool {
+	var restErr *RestError
+	if !errors.As(err, &restErr) {
+		return false
+	}
+
+	return restErr.ErrorCode == codeSubjectNotFound || restErr.ErrorCode == codeVersionNotFound
+}
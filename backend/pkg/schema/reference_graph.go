@@ -0,0 +1,358 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"go.uber.org/zap"
+)
+
+// ReferenceGraph describes how a single schema version is connected to other schema
+// versions via schema references.
+type ReferenceGraph struct {
+	// Root is the schema version the graph has been built for.
+	Root SubjectVersion `json:"root"`
+
+	// Nodes contains all schema versions that are part of the graph, including the root.
+	Nodes []ReferenceGraphNode `json:"nodes"`
+
+	// Edges always point from the referencing schema version to the referenced schema version.
+	Edges []ReferenceGraphEdge `json:"edges"`
+
+	// References are all schema versions that are referenced by the root, either
+	// directly or transitively.
+	References []SubjectVersion `json:"references"`
+
+	// Dependents are all schema versions that reference the root, either directly
+	// or transitively. These would break if the root was deleted.
+	Dependents []SubjectVersion `json:"dependents"`
+
+	// Cycles contains every reference cycle that has been found while walking the
+	// references. The first and the last element of each cycle are the same.
+	Cycles [][]SubjectVersion `json:"cycles"`
+
+	// DanglingReferences are references that point to a subject or version that does
+	// not exist in the schema registry.
+	DanglingReferences []DanglingReference `json:"danglingReferences"`
+}
+
+// ReferenceGraphNode is a single schema version within a ReferenceGraph.
+type ReferenceGraphNode struct {
+	Subject  string `json:"subject"`
+	Version  int    `json:"version"`
+	SchemaID int    `json:"schemaId"`
+	Type     string `json:"type"`
+}
+
+// ReferenceGraphEdge is a reference from one schema version to another.
+type ReferenceGraphEdge struct {
+	From SubjectVersion `json:"from"`
+	To   SubjectVersion `json:"to"`
+
+	// Name is the name that is used to import the referenced schema (e.g. 'common.proto').
+	Name string `json:"name"`
+}
+
+// DanglingReference is a reference that can not be resolved, because the referenced
+// subject or version does not exist.
+type DanglingReference struct {
+	From      SubjectVersion `json:"from"`
+	Reference Reference      `json:"reference"`
+}
+
+// GetReferenceGraph returns the reference graph for the given subject and version. Version
+// may be the string "latest". References are resolved recursively, dependents are looked
+// up via the referencedby endpoint. If the schema registry does not support this endpoint
+// the dependents will be computed from the list of all registered schemas instead.
+func (s *Service) GetReferenceGraph(subject string, version string) (*ReferenceGraph, error) {
+	root, err := s.registryClient.GetSchemaBySubject(subject, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get root schema: %w", err)
+	}
+	rootSubjectVersion := SubjectVersion{Subject: root.Subject, Version: root.Version}
+	if rootSubjectVersion.Subject == "" {
+		// Some schema registry implementations do not return the subject
+		rootSubjectVersion.Subject = subject
+		root.Subject = subject
+	}
+
+	fetchSchema := func(sv SubjectVersion) (*SchemaVersionedResponse, error) {
+		return s.registryClient.GetSchemaBySubject(sv.Subject, strconv.Itoa(sv.Version))
+	}
+
+	// Probe the referencedby endpoint with the root schema, so that we know whether we need to fall back
+	rootDependents, err := s.getDependentsByReferencedBy(rootSubjectVersion)
+	fetchDependents := func(sv SubjectVersion) ([]SubjectVersion, error) {
+		if sv == rootSubjectVersion {
+			return rootDependents, nil
+		}
+		return s.getDependentsByReferencedBy(sv)
+	}
+	if err != nil {
+		s.logger.Debug("failed to get dependents via referencedby endpoint, falling back to indexing all schemas",
+			zap.String("subject", rootSubjectVersion.Subject),
+			zap.Int("version", rootSubjectVersion.Version),
+			zap.Error(err))
+
+		schemas, err := s.registryClient.GetSchemas()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get schemas for computing dependents: %w", err)
+		}
+		dependentsIndex := newDependentsIndex(schemas)
+		fetchDependents = func(sv SubjectVersion) ([]SubjectVersion, error) {
+			return dependentsIndex[sv], nil
+		}
+	}
+
+	b := newReferenceGraphBuilder(fetchSchema, fetchDependents)
+	return b.build(root)
+}
+
+// getDependentsByReferencedBy returns all subject versions that directly reference the given
+// subject version.
+func (s *Service) getDependentsByReferencedBy(sv SubjectVersion) ([]SubjectVersion, error) {
+	schemaIDs, err := s.registryClient.GetSchemaReferencedBy(sv.Subject, strconv.Itoa(sv.Version))
+	if err != nil {
+		return nil, err
+	}
+
+	dependents := make([]SubjectVersion, 0, len(schemaIDs))
+	for _, id := range schemaIDs {
+		subjectVersions, err := s.registryClient.GetSchemaSubjectVersionsByID(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get subject versions for schema id %d: %w", id, err)
+		}
+		dependents = append(dependents, subjectVersions...)
+	}
+
+	return dependents, nil
+}
+
+// newDependentsIndex builds a reverse index that returns all subject versions that
+// directly reference a subject version.
+func newDependentsIndex(schemas []SchemaVersionedResponse) map[SubjectVersion][]SubjectVersion {
+	index := make(map[SubjectVersion][]SubjectVersion)
+	for _, schema := range schemas {
+		for _, ref := range schema.References {
+			referenced := SubjectVersion{Subject: ref.Subject, Version: ref.Version}
+			index[referenced] = append(index[referenced], SubjectVersion{Subject: schema.Subject, Version: schema.Version})
+		}
+	}
+
+	return index
+}
+
+// referenceGraphBuilder walks the references and dependents of a schema version. Each schema
+// version is fetched at most once while building a graph.
+type referenceGraphBuilder struct {
+	fetchSchema     func(sv SubjectVersion) (*SchemaVersionedResponse, error)
+	fetchDependents func(sv SubjectVersion) ([]SubjectVersion, error)
+
+	schemas    map[SubjectVersion]*SchemaVersionedResponse
+	edges      map[ReferenceGraphEdge]struct{}
+	references map[SubjectVersion]struct{}
+	dependents map[SubjectVersion]struct{}
+	dangling   []DanglingReference
+	cycles     [][]SubjectVersion
+}
+
+func newReferenceGraphBuilder(
+	fetchSchema func(sv SubjectVersion) (*SchemaVersionedResponse, error),
+	fetchDependents func(sv SubjectVersion) ([]SubjectVersion, error),
+) *referenceGraphBuilder {
+	return &referenceGraphBuilder{
+		fetchSchema:     fetchSchema,
+		fetchDependents: fetchDependents,
+		schemas:         make(map[SubjectVersion]*SchemaVersionedResponse),
+		edges:           make(map[ReferenceGraphEdge]struct{}),
+		references:      make(map[SubjectVersion]struct{}),
+		dependents:      make(map[SubjectVersion]struct{}),
+		dangling:        make([]DanglingReference, 0),
+		cycles:          make([][]SubjectVersion, 0),
+	}
+}
+
+func (b *referenceGraphBuilder) build(root *SchemaVersionedResponse) (*ReferenceGraph, error) {
+	rootSubjectVersion := SubjectVersion{Subject: root.Subject, Version: root.Version}
+	b.schemas[rootSubjectVersion] = root
+
+	// 1. Walk all references depth first, so that we can detect cycles by tracking the current path
+	visited := make(map[SubjectVersion]struct{})
+	err := b.walkReferences(rootSubjectVersion, make([]SubjectVersion, 0), visited)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Walk all dependents breadth first
+	err = b.walkDependents(rootSubjectVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.graph(rootSubjectVersion), nil
+}
+
+func (b *referenceGraphBuilder) walkReferences(sv SubjectVersion, path []SubjectVersion, visited map[SubjectVersion]struct{}) error {
+	path = append(path, sv)
+	visited[sv] = struct{}{}
+
+	for _, ref := range b.schemas[sv].References {
+		referenced := SubjectVersion{Subject: ref.Subject, Version: ref.Version}
+		if _, err := b.getSchema(referenced); err != nil {
+			if isSubjectVersionNotFound(err) {
+				b.dangling = append(b.dangling, DanglingReference{From: sv, Reference: ref})
+				continue
+			}
+			return fmt.Errorf("failed to get referenced schema (subject: %q, version %d): %w", ref.Subject, ref.Version, err)
+		}
+		b.edges[ReferenceGraphEdge{From: sv, To: referenced, Name: ref.Name}] = struct{}{}
+		b.references[referenced] = struct{}{}
+
+		if idx := indexOfSubjectVersion(path, referenced); idx >= 0 {
+			cycle := make([]SubjectVersion, 0, len(path)-idx+1)
+			cycle = append(cycle, path[idx:]...)
+			cycle = append(cycle, referenced)
+			b.cycles = append(b.cycles, cycle)
+			continue
+		}
+		if _, isVisited := visited[referenced]; isVisited {
+			continue
+		}
+
+		if err := b.walkReferences(referenced, path, visited); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *referenceGraphBuilder) walkDependents(root SubjectVersion) error {
+	visited := map[SubjectVersion]struct{}{root: {}}
+	queue := []SubjectVersion{root}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		dependents, err := b.fetchDependents(current)
+		if err != nil {
+			return fmt.Errorf("failed to get dependents (subject: %q, version %d): %w", current.Subject, current.Version, err)
+		}
+
+		for _, dependent := range dependents {
+			schema, err := b.getSchema(dependent)
+			if err != nil {
+				return fmt.Errorf("failed to get dependent schema (subject: %q, version %d): %w", dependent.Subject, dependent.Version, err)
+			}
+			for _, ref := range schema.References {
+				if ref.Subject == current.Subject && ref.Version == current.Version {
+					b.edges[ReferenceGraphEdge{From: dependent, To: current, Name: ref.Name}] = struct{}{}
+				}
+			}
+
+			if _, isVisited := visited[dependent]; isVisited {
+				continue
+			}
+			visited[dependent] = struct{}{}
+			b.dependents[dependent] = struct{}{}
+			queue = append(queue, dependent)
+		}
+	}
+
+	return nil
+}
+
+// getSchema returns the schema for the given subject version. Each schema will only be fetched once.
+func (b *referenceGraphBuilder) getSchema(sv SubjectVersion) (*SchemaVersionedResponse, error) {
+	if schema, exists := b.schemas[sv]; exists {
+		return schema, nil
+	}
+
+	schema, err := b.fetchSchema(sv)
+	if err != nil {
+		return nil, err
+	}
+	b.schemas[sv] = schema
+
+	return schema, nil
+}
+
+func (b *referenceGraphBuilder) graph(root SubjectVersion) *ReferenceGraph {
+	// The root may be part of its own references if it is part of a cycle
+	delete(b.references, root)
+
+	nodes := make([]ReferenceGraphNode, 0, len(b.schemas))
+	for sv, schema := range b.schemas {
+		nodes = append(nodes, ReferenceGraphNode{
+			Subject:  sv.Subject,
+			Version:  sv.Version,
+			SchemaID: schema.SchemaID,
+			Type:     schema.Type,
+		})
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return lessSubjectVersion(
+			SubjectVersion{Subject: nodes[i].Subject, Version: nodes[i].Version},
+			SubjectVersion{Subject: nodes[j].Subject, Version: nodes[j].Version},
+		)
+	})
+
+	edges := make([]ReferenceGraphEdge, 0, len(b.edges))
+	for edge := range b.edges {
+		edges = append(edges, edge)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return lessSubjectVersion(edges[i].From, edges[j].From)
+		}
+		return lessSubjectVersion(edges[i].To, edges[j].To)
+	})
+
+	return &ReferenceGraph{
+		Root:               root,
+		Nodes:              nodes,
+		Edges:              edges,
+		References:         sortedSubjectVersions(b.references),
+		Dependents:         sortedSubjectVersions(b.dependents),
+		Cycles:             b.cycles,
+		DanglingReferences: b.dangling,
+	}
+}
+
+func indexOfSubjectVersion(subjectVersions []SubjectVersion, sv SubjectVersion) int {
+	for i, candidate := range subjectVersions {
+		if candidate == sv {
+			return i
+		}
+	}
+	return -1
+}
+
+func lessSubjectVersion(a, b SubjectVersion) bool {
+	if a.Subject != b.Subject {
+		return a.Subject < b.Subject
+	}
+	return a.Version < b.Version
+}
+
+func sortedSubjectVersions(set map[SubjectVersion]struct{}) []SubjectVersion {
+	subjectVersions := make([]SubjectVersion, 0, len(set))
+	for sv := range set {
+		subjectVersions = append(subjectVersions, sv)
+	}
+	sort.Slice(subjectVersions, func(i, j int) bool {
+		return lessSubjectVersion(subjectVersions[i], subjectVersions[j])
+	})
+
+	return subjectVersions
+}
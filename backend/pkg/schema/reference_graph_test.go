@@ -0,0 +1,98 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReferenceGraphBuilder(t *testing.T) {
+	// order.proto -> customer.proto -> common.proto (v1)
+	// invoice.proto -> common.proto (v1)
+	// cyclic-a -> cyclic-b -> cyclic-a
+	// customer.proto -> missing.proto (dangling)
+	schemas := []SchemaVersionedResponse{
+		{Subject: "common.proto", Version: 1, SchemaID: 1, Type: "PROTOBUF"},
+		{Subject: "customer.proto", Version: 1, SchemaID: 2, Type: "PROTOBUF", References: []Reference{
+			{Name: "common.proto", Subject: "common.proto", Version: 1},
+			{Name: "missing.proto", Subject: "missing.proto", Version: 3},
+		}},
+		{Subject: "order.proto", Version: 2, SchemaID: 3, Type: "PROTOBUF", References: []Reference{
+			{Name: "customer.proto", Subject: "customer.proto", Version: 1},
+		}},
+		{Subject: "invoice.proto", Version: 1, SchemaID: 4, Type: "PROTOBUF", References: []Reference{
+			{Name: "common.proto", Subject: "common.proto", Version: 1},
+		}},
+		{Subject: "cyclic-a", Version: 1, SchemaID: 5, Type: "PROTOBUF", References: []Reference{
+			{Name: "b", Subject: "cyclic-b", Version: 1},
+		}},
+		{Subject: "cyclic-b", Version: 1, SchemaID: 6, Type: "PROTOBUF", References: []Reference{
+			{Name: "a", Subject: "cyclic-a", Version: 1},
+		}},
+	}
+	schemasBySubjectVersion := make(map[SubjectVersion]*SchemaVersionedResponse)
+	for i := range schemas {
+		schemasBySubjectVersion[SubjectVersion{Subject: schemas[i].Subject, Version: schemas[i].Version}] = &schemas[i]
+	}
+	fetchSchema := func(sv SubjectVersion) (*SchemaVersionedResponse, error) {
+		schema, exists := schemasBySubjectVersion[sv]
+		if !exists {
+			return nil, &RestError{ErrorCode: codeSubjectNotFound, Message: "Subject not found"}
+		}
+		return schema, nil
+	}
+	dependentsIndex := newDependentsIndex(schemas)
+	fetchDependents := func(sv SubjectVersion) ([]SubjectVersion, error) {
+		return dependentsIndex[sv], nil
+	}
+
+	t.Run("references, dependents and dangling references", func(t *testing.T) {
+		b := newReferenceGraphBuilder(fetchSchema, fetchDependents)
+		graph, err := b.build(schemasBySubjectVersion[SubjectVersion{Subject: "customer.proto", Version: 1}])
+		require.NoError(t, err)
+
+		assert.Equal(t, []SubjectVersion{{Subject: "common.proto", Version: 1}}, graph.References)
+		assert.Equal(t, []SubjectVersion{{Subject: "order.proto", Version: 2}}, graph.Dependents)
+		assert.Empty(t, graph.Cycles)
+		require.Len(t, graph.DanglingReferences, 1)
+		assert.Equal(t, "missing.proto", graph.DanglingReferences[0].Reference.Subject)
+		assert.Len(t, graph.Edges, 2)
+		assert.Len(t, graph.Nodes, 3)
+	})
+
+	t.Run("transitive dependents of shared schema", func(t *testing.T) {
+		b := newReferenceGraphBuilder(fetchSchema, fetchDependents)
+		graph, err := b.build(schemasBySubjectVersion[SubjectVersion{Subject: "common.proto", Version: 1}])
+		require.NoError(t, err)
+
+		assert.Empty(t, graph.References)
+		assert.Equal(t, []SubjectVersion{
+			{Subject: "customer.proto", Version: 1},
+			{Subject: "invoice.proto", Version: 1},
+			{Subject: "order.proto", Version: 2},
+		}, graph.Dependents)
+	})
+
+	t.Run("cycles", func(t *testing.T) {
+		b := newReferenceGraphBuilder(fetchSchema, fetchDependents)
+		graph, err := b.build(schemasBySubjectVersion[SubjectVersion{Subject: "cyclic-a", Version: 1}])
+		require.NoError(t, err)
+
+		assert.Equal(t, [][]SubjectVersion{{
+			{Subject: "cyclic-a", Version: 1},
+			{Subject: "cyclic-b", Version: 1},
+			{Subject: "cyclic-a", Version: 1},
+		}}, graph.Cycles)
+		assert.Equal(t, []SubjectVersion{{Subject: "cyclic-b", Version: 1}}, graph.Dependents)
+	})
+}
@@ -0,0 +1,382 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package schema
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultSearchLimit is the maximum number of hits returned, if no limit is specified.
+	defaultSearchLimit = 1000
+	// maxSearchLimit is the maximum number of hits that can be requested.
+	maxSearchLimit = 10000
+	// searchIndexMaxAge is the duration after which the search index will be rebuilt
+	// from the schema registry.
+	searchIndexMaxAge = time.Minute
+)
+
+// SearchQuery describes what schemas shall be searched for. All set conditions must match.
+// Name, Type, Namespace and Subject support glob patterns (e.g. "com.acme.*") and are matched
+// case-insensitively.
+type SearchQuery struct {
+	// Text is matched case-insensitively against the schema content.
+	Text string `json:"text"`
+
+	// Kind restricts structural matches to elements of the given kind.
+	Kind SearchElementKind `json:"kind"`
+
+	// Name of the element, e.g. the field name "customer_id" or the message name "OrderCreated".
+	Name string `json:"name"`
+
+	// Type of fields, e.g. "string". A field with a union type matches if any of its
+	// types matches.
+	Type string `json:"type"`
+
+	// Namespace is the Avro namespace or the Protobuf package of the element.
+	Namespace string `json:"namespace"`
+
+	// SchemaType restricts the search to AVRO, PROTOBUF or JSON schemas.
+	SchemaType string `json:"schemaType"`
+
+	// Subject restricts the search to matching subjects.
+	Subject string `json:"subject"`
+
+	// Limit is the maximum number of returned hits.
+	Limit int `json:"limit"`
+}
+
+// Validate the search query.
+func (q *SearchQuery) Validate() error {
+	if q.Text == "" && !q.isStructured() {
+		return fmt.Errorf("at least one of text, kind, name, type or namespace must be set")
+	}
+
+	switch q.Kind {
+	case "", SearchElementField, SearchElementRecord, SearchElementMessage, SearchElementEnum, SearchElementFixed:
+	default:
+		return fmt.Errorf("unknown element kind %q", q.Kind)
+	}
+
+	switch strings.ToUpper(q.SchemaType) {
+	case "", "AVRO", "PROTOBUF", "JSON":
+	default:
+		return fmt.Errorf("unknown schema type %q", q.SchemaType)
+	}
+
+	for _, pattern := range []string{q.Name, q.Type, q.Namespace, q.Subject} {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+
+	if q.Limit < 0 || q.Limit > maxSearchLimit {
+		return fmt.Errorf("limit must be between 0 and %d", maxSearchLimit)
+	}
+
+	return nil
+}
+
+func (q *SearchQuery) isStructured() bool {
+	return q.Kind != "" || q.Name != "" || q.Type != "" || q.Namespace != ""
+}
+
+// SearchResult contains all schema versions and elements that matched a search query.
+type SearchResult struct {
+	Hits []SearchHit `json:"hits"`
+
+	// Truncated is true if there were more hits than the requested limit.
+	Truncated bool `json:"truncated"`
+
+	// IndexedAt is the time when the search index has been built.
+	IndexedAt time.Time `json:"indexedAt"`
+
+	// IndexedSchemas is the number of schema versions in the search index.
+	IndexedSchemas int `json:"indexedSchemas"`
+}
+
+// SearchHit is a match within a single schema version. Structural matches carry the path of the
+// matched element, free-text only matches carry the first line that contains the text.
+type SearchHit struct {
+	Subject    string `json:"subject"`
+	Version    int    `json:"version"`
+	SchemaID   int    `json:"schemaId"`
+	SchemaType string `json:"schemaType"`
+
+	Path      string            `json:"path,omitempty"`
+	Kind      SearchElementKind `json:"kind,omitempty"`
+	Name      string            `json:"name,omitempty"`
+	Type      string            `json:"type,omitempty"`
+	Namespace string            `json:"namespace,omitempty"`
+
+	Line    int    `json:"line,omitempty"`
+	Snippet string `json:"snippet,omitempty"`
+}
+
+// searchIndex holds the structural elements of all schema versions.
+type searchIndex struct {
+	createdAt time.Time
+	entries   []searchIndexEntry
+}
+
+type searchIndexEntry struct {
+	schema   SchemaVersionedResponse
+	content  string // Lower cased schema for free-text search
+	elements []searchElement
+}
+
+// SearchSchemas searches all schema versions in the schema registry. The search index is
+// rebuilt from the schema registry once it is older than a minute, or if refresh is true.
+func (s *Service) SearchSchemas(query SearchQuery, refresh bool) (*SearchResult, error) {
+	if refresh {
+		s.searchIndex.Expire("")
+	}
+	index, err, _ := s.searchIndex.Get("", s.buildSearchIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	return index.search(query), nil
+}
+
+func (s *Service) buildSearchIndex() (*searchIndex, error) {
+	schemas, err := s.registryClient.GetSchemas()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schemas from registry: %w", err)
+	}
+
+	// Index all protobuf schemas by subject and version, so that references can be resolved
+	protoSchemas := make(map[string]map[int]SchemaVersionedResponse)
+	for _, schema := range schemas {
+		if schema.Type != "PROTOBUF" {
+			continue
+		}
+		if _, exists := protoSchemas[schema.Subject]; !exists {
+			protoSchemas[schema.Subject] = make(map[int]SchemaVersionedResponse)
+		}
+		protoSchemas[schema.Subject][schema.Version] = schema
+	}
+
+	index := &searchIndex{
+		createdAt: time.Now(),
+		entries:   make([]searchIndexEntry, 0, len(schemas)),
+	}
+	for _, schema := range schemas {
+		// Schema IDs are immutable, hence extracted elements can be reused across index rebuilds
+		elements, err, _ := s.searchElementsByID.Get(schema.SchemaID, func() ([]searchElement, error) {
+			return s.extractSearchElements(schema, protoSchemas)
+		})
+		if err != nil {
+			// The schema can still be found via free-text search
+			s.logger.Debug("failed to extract search elements from schema",
+				zap.String("subject", schema.Subject),
+				zap.Int("version", schema.Version),
+				zap.Error(err))
+		}
+		index.entries = append(index.entries, searchIndexEntry{
+			schema:   schema,
+			content:  strings.ToLower(schema.Schema),
+			elements: elements,
+		})
+	}
+
+	sort.Slice(index.entries, func(i, j int) bool {
+		a, b := index.entries[i].schema, index.entries[j].schema
+		if a.Subject != b.Subject {
+			return a.Subject < b.Subject
+		}
+		return a.Version < b.Version
+	})
+
+	return index, nil
+}
+
+func (s *Service) extractSearchElements(schema SchemaVersionedResponse, protoSchemas map[string]map[int]SchemaVersionedResponse) ([]searchElement, error) {
+	switch schema.Type {
+	case "", "AVRO":
+		return extractAvroElements(schema.Schema)
+	case "PROTOBUF":
+		fd, err := s.compileProtoSchemas(schema, protoSchemas)
+		if err != nil {
+			return nil, err
+		}
+		return extractProtoElements(fd), nil
+	case "JSON":
+		return extractJSONSchemaElements(schema.Schema)
+	default:
+		return nil, fmt.Errorf("unsupported schema type %q", schema.Type)
+	}
+}
+
+func (idx *searchIndex) search(query SearchQuery) *SearchResult {
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+	text := strings.ToLower(query.Text)
+	schemaType := strings.ToUpper(query.SchemaType)
+
+	result := &SearchResult{
+		Hits:           make([]SearchHit, 0),
+		IndexedAt:      idx.createdAt,
+		IndexedSchemas: len(idx.entries),
+	}
+	addHit := func(hit SearchHit) bool {
+		if len(result.Hits) >= limit {
+			result.Truncated = true
+			return false
+		}
+		result.Hits = append(result.Hits, hit)
+		return true
+	}
+
+	for _, entry := range idx.entries {
+		entryType := entry.schema.Type
+		if entryType == "" {
+			entryType = "AVRO"
+		}
+		if schemaType != "" && entryType != schemaType {
+			continue
+		}
+		if query.Subject != "" && !matchPattern(query.Subject, entry.schema.Subject) {
+			continue
+		}
+		if text != "" && !strings.Contains(entry.content, text) {
+			continue
+		}
+
+		newHit := func() SearchHit {
+			return SearchHit{
+				Subject:    entry.schema.Subject,
+				Version:    entry.schema.Version,
+				SchemaID:   entry.schema.SchemaID,
+				SchemaType: entryType,
+			}
+		}
+
+		if !query.isStructured() {
+			hit := newHit()
+			hit.Line, hit.Snippet = findLine(entry.schema.Schema, text)
+			if !addHit(hit) {
+				return result
+			}
+			continue
+		}
+
+		for _, element := range entry.elements {
+			if !element.matches(query) {
+				continue
+			}
+			hit := newHit()
+			hit.Path = element.Path
+			hit.Kind = element.Kind
+			hit.Name = element.Name
+			hit.Type = element.Type
+			hit.Namespace = element.Namespace
+			if !addHit(hit) {
+				return result
+			}
+		}
+	}
+
+	return result
+}
+
+func (e *searchElement) matches(query SearchQuery) bool {
+	if query.Kind != "" && e.Kind != query.Kind {
+		return false
+	}
+	if query.Name != "" && !matchPattern(query.Name, e.Name) {
+		return false
+	}
+	if query.Namespace != "" && !matchPattern(query.Namespace, e.Namespace) {
+		return false
+	}
+	if query.Type != "" {
+		if e.Kind != SearchElementField {
+			return false
+		}
+		if !matchPattern(query.Type, e.Type) && !matchAnyUnionType(query.Type, e.Type) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchAnyUnionType returns true if any of the pipe separated types matches the pattern. Types
+// are also matched by their short name, so that "Customer" matches "com.acme.Customer".
+func matchAnyUnionType(pattern string, unionType string) bool {
+	for _, t := range strings.Split(unionType, "|") {
+		if matchPattern(pattern, t) || matchPattern(pattern, shortName(t)) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPattern matches the value against a glob pattern case-insensitively. Patterns have
+// been validated before, hence errors are ignored.
+func matchPattern(pattern string, value string) bool {
+	matched, _ := path.Match(strings.ToLower(pattern), strings.ToLower(value))
+	return matched
+}
+
+// findLine returns the 1-based line number of the first occurrence of text and an excerpt around
+// it. Schemas returned by the schema registry are often minified to a single line, hence we don't
+// return the whole line.
+func findLine(content string, text string) (int, string) {
+	const excerptContext = 40
+
+	textRunes := []rune(text)
+	for i, line := range strings.Split(content, "\n") {
+		// Lowercasing may change the byte length of a character, hence we compare rune by rune so
+		// that the position can be used on the original line.
+		lineRunes := []rune(line)
+		pos := indexRunesFold(lineRunes, textRunes)
+		if pos < 0 {
+			continue
+		}
+		start := pos - excerptContext
+		if start < 0 {
+			start = 0
+		}
+		end := pos + len(textRunes) + excerptContext
+		if end > len(lineRunes) {
+			end = len(lineRunes)
+		}
+		return i + 1, strings.TrimSpace(string(lineRunes[start:end]))
+	}
+	return 0, ""
+}
+
+// indexRunesFold returns the index of the first occurrence of the lowercase text in s, comparing
+// case-insensitively, or -1 if text is not present in s.
+func indexRunesFold(s []rune, text []rune) int {
+	for i := 0; i+len(text) <= len(s); i++ {
+		matched := true
+		for j, r := range text {
+			if unicode.ToLower(s[i+j]) != r {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,328 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jhump/protoreflect/desc"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// SearchElementKind is the kind of a structural element within a schema.
+type SearchElementKind string
+
+const (
+	// SearchElementField is a field of an Avro record, Protobuf message or a JSON schema property.
+	SearchElementField SearchElementKind = "FIELD"
+	// SearchElementRecord is an Avro record or a JSON schema definition.
+	SearchElementRecord SearchElementKind = "RECORD"
+	// SearchElementMessage is a Protobuf message.
+	SearchElementMessage SearchElementKind = "MESSAGE"
+	// SearchElementEnum is an Avro or Protobuf enum.
+	SearchElementEnum SearchElementKind = "ENUM"
+	// SearchElementFixed is an Avro fixed type.
+	SearchElementFixed SearchElementKind = "FIXED"
+)
+
+// searchElement is a named element within a schema that can be found via structured queries.
+type searchElement struct {
+	Kind SearchElementKind
+	Name string
+	// Type is the type of fields. Union types are separated by a pipe (e.g. "null|string").
+	Type string
+	// Namespace is the Avro namespace or Protobuf package of the element.
+	Namespace string
+	// Path uniquely identifies the element within the schema (e.g. "com.acme.Order.customer_id").
+	Path string
+}
+
+// extractAvroElements walks the JSON representation of an Avro schema. We don't use a parsed
+// avro.Schema here, because parsing requires all references to be resolved beforehand.
+func extractAvroElements(schema string) ([]searchElement, error) {
+	var root any
+	if err := json.Unmarshal([]byte(schema), &root); err != nil {
+		return nil, fmt.Errorf("failed to parse avro schema: %w", err)
+	}
+
+	elements := make([]searchElement, 0)
+	walkAvroElements(root, "", &elements)
+	return elements, nil
+}
+
+func walkAvroElements(node any, namespace string, elements *[]searchElement) {
+	switch n := node.(type) {
+	case []any:
+		for _, item := range n {
+			walkAvroElements(item, namespace, elements)
+		}
+	case map[string]any:
+		typeName, _ := n["type"].(string)
+		switch typeName {
+		case "record", "error":
+			fullName, ns := avroFullName(n, namespace)
+			*elements = append(*elements, searchElement{
+				Kind:      SearchElementRecord,
+				Name:      shortName(fullName),
+				Namespace: ns,
+				Path:      fullName,
+			})
+			fields, _ := n["fields"].([]any)
+			for _, f := range fields {
+				field, ok := f.(map[string]any)
+				if !ok {
+					continue
+				}
+				fieldName, _ := field["name"].(string)
+				*elements = append(*elements, searchElement{
+					Kind:      SearchElementField,
+					Name:      fieldName,
+					Type:      avroTypeName(field["type"]),
+					Namespace: ns,
+					Path:      fullName + "." + fieldName,
+				})
+				walkAvroElements(field["type"], ns, elements)
+			}
+		case "enum", "fixed":
+			fullName, ns := avroFullName(n, namespace)
+			kind := SearchElementEnum
+			if typeName == "fixed" {
+				kind = SearchElementFixed
+			}
+			*elements = append(*elements, searchElement{
+				Kind:      kind,
+				Name:      shortName(fullName),
+				Namespace: ns,
+				Path:      fullName,
+			})
+		case "array":
+			walkAvroElements(n["items"], namespace, elements)
+		case "map":
+			walkAvroElements(n["values"], namespace, elements)
+		default:
+			// The type itself may be a complex type, e.g. {"type": {"type": "array", ...}}
+			if _, isString := n["type"].(string); !isString {
+				walkAvroElements(n["type"], namespace, elements)
+			}
+		}
+	}
+}
+
+// avroFullName returns the full name and namespace of a named Avro type. The namespace is
+// inherited from the enclosing named type unless it is specified or part of the name.
+func avroFullName(node map[string]any, enclosingNamespace string) (string, string) {
+	name, _ := node["name"].(string)
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		return name, name[:idx]
+	}
+	namespace := enclosingNamespace
+	if ns, ok := node["namespace"].(string); ok {
+		namespace = ns
+	}
+	if namespace == "" {
+		return name, ""
+	}
+	return namespace + "." + name, namespace
+}
+
+func avroTypeName(node any) string {
+	switch n := node.(type) {
+	case string:
+		return n
+	case []any:
+		types := make([]string, 0, len(n))
+		for _, item := range n {
+			types = append(types, avroTypeName(item))
+		}
+		return strings.Join(types, "|")
+	case map[string]any:
+		typeName, isString := n["type"].(string)
+		if !isString {
+			return avroTypeName(n["type"])
+		}
+		switch typeName {
+		case "record", "error", "enum", "fixed":
+			name, _ := n["name"].(string)
+			return name
+		case "array":
+			return "array<" + avroTypeName(n["items"]) + ">"
+		case "map":
+			return "map<" + avroTypeName(n["values"]) + ">"
+		default:
+			return typeName
+		}
+	default:
+		return ""
+	}
+}
+
+// extractProtoElements returns all messages, fields and enums of the compiled proto file.
+func extractProtoElements(fd *desc.FileDescriptor) []searchElement {
+	elements := make([]searchElement, 0)
+	for _, md := range fd.GetMessageTypes() {
+		walkProtoMessage(md, fd.GetPackage(), &elements)
+	}
+	for _, ed := range fd.GetEnumTypes() {
+		elements = append(elements, protoEnumElement(ed, fd.GetPackage()))
+	}
+	return elements
+}
+
+func walkProtoMessage(md *desc.MessageDescriptor, pkg string, elements *[]searchElement) {
+	if md.IsMapEntry() {
+		return
+	}
+
+	*elements = append(*elements, searchElement{
+		Kind:      SearchElementMessage,
+		Name:      md.GetName(),
+		Namespace: pkg,
+		Path:      md.GetFullyQualifiedName(),
+	})
+	for _, field := range md.GetFields() {
+		*elements = append(*elements, searchElement{
+			Kind:      SearchElementField,
+			Name:      field.GetName(),
+			Type:      protoTypeName(field),
+			Namespace: pkg,
+			Path:      md.GetFullyQualifiedName() + "." + field.GetName(),
+		})
+	}
+	for _, nested := range md.GetNestedMessageTypes() {
+		walkProtoMessage(nested, pkg, elements)
+	}
+	for _, ed := range md.GetNestedEnumTypes() {
+		*elements = append(*elements, protoEnumElement(ed, pkg))
+	}
+}
+
+func protoEnumElement(ed *desc.EnumDescriptor, pkg string) searchElement {
+	return searchElement{
+		Kind:      SearchElementEnum,
+		Name:      ed.GetName(),
+		Namespace: pkg,
+		Path:      ed.GetFullyQualifiedName(),
+	}
+}
+
+func protoTypeName(field *desc.FieldDescriptor) string {
+	if field.IsMap() {
+		return "map<" + protoTypeName(field.GetMapKeyType()) + "," + protoTypeName(field.GetMapValueType()) + ">"
+	}
+
+	switch field.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, descriptorpb.FieldDescriptorProto_TYPE_GROUP:
+		return field.GetMessageType().GetFullyQualifiedName()
+	case descriptorpb.FieldDescriptorProto_TYPE_ENUM:
+		return field.GetEnumType().GetFullyQualifiedName()
+	default:
+		return strings.ToLower(strings.TrimPrefix(field.GetType().String(), "TYPE_"))
+	}
+}
+
+// extractJSONSchemaElements returns all properties and definitions of a JSON schema.
+func extractJSONSchemaElements(schema string) ([]searchElement, error) {
+	var root any
+	if err := json.Unmarshal([]byte(schema), &root); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON schema: %w", err)
+	}
+
+	elements := make([]searchElement, 0)
+	walkJSONSchemaElements(root, "", &elements)
+	return elements, nil
+}
+
+func walkJSONSchemaElements(node any, path string, elements *[]searchElement) {
+	n, ok := node.(map[string]any)
+	if !ok {
+		return
+	}
+
+	for _, keyword := range []string{"definitions", "$defs"} {
+		definitions, _ := n[keyword].(map[string]any)
+		for _, name := range sortedKeys(definitions) {
+			defPath := "#/" + keyword + "/" + name
+			*elements = append(*elements, searchElement{
+				Kind: SearchElementRecord,
+				Name: name,
+				Path: defPath,
+			})
+			walkJSONSchemaElements(definitions[name], defPath, elements)
+		}
+	}
+
+	properties, _ := n["properties"].(map[string]any)
+	for _, name := range sortedKeys(properties) {
+		propPath := name
+		if path != "" {
+			propPath = path + "." + name
+		}
+		*elements = append(*elements, searchElement{
+			Kind: SearchElementField,
+			Name: name,
+			Type: jsonSchemaTypeName(properties[name]),
+			Path: propPath,
+		})
+		walkJSONSchemaElements(properties[name], propPath, elements)
+	}
+
+	if items, ok := n["items"]; ok {
+		walkJSONSchemaElements(items, path+"[]", elements)
+	}
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		subSchemas, _ := n[keyword].([]any)
+		for _, subSchema := range subSchemas {
+			walkJSONSchemaElements(subSchema, path, elements)
+		}
+	}
+}
+
+func jsonSchemaTypeName(node any) string {
+	n, ok := node.(map[string]any)
+	if !ok {
+		return ""
+	}
+	if ref, ok := n["$ref"].(string); ok {
+		return ref[strings.LastIndex(ref, "/")+1:]
+	}
+
+	switch t := n["type"].(type) {
+	case string:
+		if t == "array" {
+			return "array<" + jsonSchemaTypeName(n["items"]) + ">"
+		}
+		return t
+	case []any:
+		types := make([]string, 0, len(t))
+		for _, item := range t {
+			if str, ok := item.(string); ok {
+				types = append(types, str)
+			}
+		}
+		return strings.Join(types, "|")
+	}
+	return ""
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func shortName(fullName string) string {
+	return fullName[strings.LastIndex(fullName, ".")+1:]
+}
@@ -0,0 +1,117 @@
+// Copyright 2023 Redpanda Data, Inc.
+//
+// Use of this software is governed by the Business Source License
+// included in the file https://github.com/redpanda-data/redpanda/blob/dev/licenses/bsl.md
+//
+// As of the Change Date specified in that file, in accordance with
+// the Business Source License, use of this software will be governed
+// by the Apache License, Version 2.0
+
+package schema
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/redpanda-data/console/backend/pkg/config"
+)
+
+func TestService_SearchSchemas(t *testing.T) {
+	baseURL := testSchemaRegistryBaseURL
+	logger, _ := zap.NewProduction()
+	s, _ := NewService(config.Schema{
+		Enabled: true,
+		URLs:    []string{baseURL},
+	}, logger)
+
+	httpClient := (*s.registryClient.client).GetClient()
+	httpmock.ActivateNonDefault(httpClient)
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", baseURL+"/schemas",
+		func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewJsonResponse(http.StatusOK, []map[string]interface{}{
+				{
+					"subject": "orders-value",
+					"version": 1,
+					"id":      1,
+					"schema": `{"type":"record","name":"Order","namespace":"com.acme.orders","fields":[` +
+						`{"name":"customer_id","type":["null","string"]},` +
+						`{"name":"items","type":{"type":"array","items":{"type":"record","name":"Item","fields":[{"name":"sku","type":"string"}]}}}]}`,
+				},
+				{
+					"subject":    "order-events",
+					"version":    3,
+					"id":         2,
+					"schemaType": "PROTOBUF",
+					"schema": `syntax = "proto3"; package shop.v1; ` +
+						`message OrderCreated { string customer_id = 1; int64 amount = 2; }`,
+				},
+				{
+					"subject":    "customers-value",
+					"version":    2,
+					"id":         3,
+					"schemaType": "JSON",
+					"schema":     `{"type":"object","properties":{"customer_id":{"type":"integer"},"address":{"type":"object","properties":{"city":{"type":"string"}}}}}`,
+				},
+			})
+		})
+
+	search := func(query SearchQuery) []SearchHit {
+		require.NoError(t, query.Validate())
+		result, err := s.SearchSchemas(query, false)
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.IndexedSchemas)
+		return result.Hits
+	}
+
+	// Field named customer_id of type string, including nullable unions
+	hits := search(SearchQuery{Kind: SearchElementField, Name: "customer_id", Type: "string"})
+	require.Len(t, hits, 2)
+	assert.Equal(t, "order-events", hits[0].Subject)
+	assert.Equal(t, "shop.v1.OrderCreated.customer_id", hits[0].Path)
+	assert.Equal(t, "orders-value", hits[1].Subject)
+	assert.Equal(t, "com.acme.orders.Order.customer_id", hits[1].Path)
+	assert.Equal(t, "null|string", hits[1].Type)
+
+	// Proto message
+	hits = search(SearchQuery{Kind: SearchElementMessage, Name: "OrderCreated"})
+	require.Len(t, hits, 1)
+	assert.Equal(t, 3, hits[0].Version)
+
+	// Avro namespace, nested records inherit the namespace of the enclosing record
+	hits = search(SearchQuery{Kind: SearchElementRecord, Namespace: "com.acme.*"})
+	require.Len(t, hits, 2)
+	assert.Equal(t, "com.acme.orders.Item", hits[1].Path)
+
+	// Nested JSON schema properties
+	hits = search(SearchQuery{Name: "city"})
+	require.Len(t, hits, 1)
+	assert.Equal(t, "address.city", hits[0].Path)
+
+	// Free text
+	hits = search(SearchQuery{Text: "SKU"})
+	require.Len(t, hits, 1)
+	assert.Equal(t, "orders-value", hits[0].Subject)
+	assert.Equal(t, 1, hits[0].Line)
+	assert.Contains(t, hits[0].Snippet, "sku")
+
+	// The index is only built once
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestFindLine_NonASCII(t *testing.T) {
+	// Lowercasing "İ" shrinks it from two bytes to one, which must not shift the excerpt
+	content := "{\"doc\": \"" + strings.Repeat("İ", 50) + " street\"}"
+	line, snippet := findLine(content, "street")
+	assert.Equal(t, 1, line)
+	assert.True(t, strings.HasSuffix(snippet, "street\"}"))
+	assert.True(t, utf8.ValidString(snippet))
+}
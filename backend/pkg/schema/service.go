@@ -37,6 +37,10 @@ type Service struct {
 	// by subjects is needed to lookup references in avro schemas.
 	schemaBySubjectVersion *cache.Cache[string, *SchemaVersionedResponse]
 	avroSchemaByID         *cache.Cache[uint32, avro.Schema]
+
+	// searchIndex caches the index for searching schemas. It only has a single key.
+	searchIndex        *cache.Cache[string, *searchIndex]
+	searchElementsByID *cache.Cache[int, []searchElement]
 }
 
 // NewService to access schema registry. Returns an error if connection can't be established.
@@ -53,6 +57,8 @@ func NewService(cfg config.Schema, logger *zap.Logger) (*Service, error) {
 		registryClient:         client,
 		avroSchemaByID:         cache.New[uint32, avro.Schema](cache.MaxAge(5*time.Minute), cache.MaxErrorAge(time.Second)),
 		schemaBySubjectVersion: cache.New[string, *SchemaVersionedResponse](cache.MaxAge(5*time.Minute), cache.MaxErrorAge(time.Second)),
+		searchIndex:            cache.New[string, *searchIndex](cache.MaxAge(searchIndexMaxAge), cache.MaxErrorAge(time.Second)),
+		searchElementsByID:     cache.New[int, []searchElement](cache.MaxAge(time.Hour), cache.MaxErrorAge(time.Second)),
 	}, nil
 }
 
@@ -230,6 +236,12 @@ func (s *Service) GetSubjectConfig(subject string) (*ConfigResponse, error) {
 	return s.registryClient.GetSubjectConfig(subject)
 }
 
+// RegisterSchema registers a new schema version under the given subject. If the schema has
+// already been registered under this subject, the existing schema id will be returned.
+func (s *Service) RegisterSchema(subject string, req RegisterSchemaRequest) (*RegisterSchemaResponse, error) {
+	return s.registryClient.RegisterSchema(subject, req)
+}
+
 // ParseAvroSchemaWithReferences parses an avro schema that potentially has references
 // to other schemas. References will be resolved by requesting and parsing them
 // recursively. If any of the referenced schemas can't be fetched or parsed an
@@ -278,3 +290,39 @@ func (s *Service) GetSchemaBySubjectAndVersion(subject string, version string) (
 
 	return cachedSchema, err
 }
+
+// CompileProtoSchemaWithReferences compiles a protobuf schema into a file descriptor. References
+// will be fetched from the schema registry recursively.
+func (s *Service) CompileProtoSchemaWithReferences(schema *SchemaVersionedResponse) (*desc.FileDescriptor, error) {
+	schemaRepository := make(map[string]map[int]SchemaVersionedResponse)
+	if err := s.collectReferences(schema.References, schemaRepository); err != nil {
+		return nil, err
+	}
+
+	return s.compileProtoSchemas(*schema, schemaRepository)
+}
+
+// collectReferences fetches all referenced schemas recursively and indexes them by their
+// subject and version.
+func (s *Service) collectReferences(references []Reference, schemaRepository map[string]map[int]SchemaVersionedResponse) error {
+	for _, ref := range references {
+		if _, exists := schemaRepository[ref.Subject][ref.Version]; exists {
+			continue
+		}
+
+		refSchema, err := s.GetSchemaBySubjectAndVersion(ref.Subject, strconv.Itoa(ref.Version))
+		if err != nil {
+			return fmt.Errorf("failed to resolve reference (subject: %q, version %d): %w", ref.Subject, ref.Version, err)
+		}
+		if _, exists := schemaRepository[ref.Subject]; !exists {
+			schemaRepository[ref.Subject] = make(map[int]SchemaVersionedResponse)
+		}
+		schemaRepository[ref.Subject][ref.Version] = *refSchema
+
+		if err := s.collectReferences(refSchema.References, schemaRepository); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}